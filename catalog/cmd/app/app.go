@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	grpcapp "github.com/ChernykhITMO/order-processing-platform/catalog/cmd/app/grpc"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/config"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/services"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/storage/postgres"
+)
+
+type App struct {
+	GRPCSrv *grpcapp.App
+	storage *postgres.Storage
+	log     *slog.Logger
+}
+
+func New(log *slog.Logger, grpcPort int, dbCfg config.DBConfig) (*App, error) {
+	storage, err := postgres.New(dbCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := services.New(log, storage)
+
+	return &App{
+		GRPCSrv: grpcapp.New(log, catalog, grpcPort),
+		storage: storage,
+		log:     log,
+	}, nil
+}
+
+func (a *App) Stop() {
+	const op = "app.Stop"
+	log := a.log.With(slog.String("op", op))
+	a.GRPCSrv.Stop()
+	if a.storage != nil {
+		if err := a.storage.Close(); err != nil {
+			log.Warn("storage close", slog.Any("err", err))
+		}
+	}
+}
+
+func (a *App) CheckReadiness(ctx context.Context) error {
+	if a.storage == nil {
+		return nil
+	}
+	return a.storage.Ping(ctx)
+}
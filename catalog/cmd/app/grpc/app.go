@@ -0,0 +1,66 @@
+package grpcapp
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+
+	api "github.com/ChernykhITMO/order-processing-platform/catalog/internal/controller/grpc"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type App struct {
+	log        *slog.Logger
+	gRPCServer *grpc.Server
+	port       int
+}
+
+func New(log *slog.Logger, catalog *services.Catalog, port int) *App {
+	gRPCServer := grpc.NewServer()
+
+	api.Register(gRPCServer, catalog)
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(gRPCServer, healthSrv)
+
+	return &App{
+		log:        log,
+		gRPCServer: gRPCServer,
+		port:       port,
+	}
+}
+
+func (a *App) Run() error {
+	const op = "grpcapp.Run"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("port", a.port),
+	)
+
+	log.Info("starting gRPC server")
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", a.port))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("gRPC server is running", slog.String("address", lis.Addr().String()))
+
+	if err := a.gRPCServer.Serve(lis); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a *App) Stop() {
+	const op = "grpcapp.Stop"
+
+	a.log.With(slog.String("op", op)).
+		Info("stopping gRPC server", slog.Int("port", a.port))
+	a.gRPCServer.GracefulStop()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/cmd/app"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/config"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/health"
+	"github.com/joho/godotenv"
+)
+
+const (
+	envKey        = "env"
+	gRPCAddrKey   = "CATALOG_GRPC_ADDR"
+	healthAddrKey = "CATALOG_HEALTH_ADDR"
+	dsnKey        = "CATALOG_PG_DSN"
+
+	envLocal = "local"
+	envDev   = "dev"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	if os.Getenv(envKey) == "" && os.Getenv("ENV") != "" {
+		_ = os.Setenv(envKey, os.Getenv("ENV"))
+	}
+
+	cfg, err := config.Load(envKey, gRPCAddrKey, healthAddrKey, dsnKey)
+	if err != nil {
+		log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		log.Error("config load failed", slog.Any("err", err))
+		os.Exit(1)
+	}
+
+	log := setupLogger(cfg.Env)
+
+	application, err := app.New(log, cfg.GRPC.Port, cfg.DB)
+	if err != nil {
+		log.Error("app init failed", slog.Any("err", err))
+		os.Exit(1)
+	}
+
+	log.With(
+		slog.String("dsn", sanitizeDSN(cfg.DB.DSN)),
+		slog.Int64("db_max_conns", int64(cfg.DB.MaxConns)),
+		slog.Int64("db_min_conns", int64(cfg.DB.MinConns)),
+		slog.String("health_addr", cfg.Health.Addr),
+	).Info("starting application")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- application.GRPCSrv.Run()
+	}()
+	go func() {
+		healthSrv := health.NewServer(cfg.Health.Addr, log, application.CheckReadiness)
+		errCh <- healthSrv.Run(ctx)
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+
+	select {
+	case <-stop:
+	case err := <-errCh:
+		if err != nil {
+			log.Error("gRPC server stopped with error", slog.Any("err", err))
+		}
+	}
+	cancel()
+
+	application.Stop()
+	log.Info("gracefully stopped")
+}
+
+func setupLogger(env string) *slog.Logger {
+	var log *slog.Logger
+
+	switch env {
+	case envLocal:
+		log = slog.New(slog.NewTextHandler(
+			os.Stdout,
+			&slog.HandlerOptions{Level: slog.LevelDebug}))
+	case envDev:
+		log = slog.New(slog.NewJSONHandler(
+			os.Stdout,
+			&slog.HandlerOptions{Level: slog.LevelDebug}))
+	default:
+		log = slog.New(slog.NewTextHandler(
+			os.Stdout,
+			&slog.HandlerOptions{Level: slog.LevelInfo}))
+	}
+
+	return log
+}
+
+func sanitizeDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+	if u.User != nil {
+		user := u.User.Username()
+		u.User = url.User(user)
+	}
+	return u.String()
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/mapper"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/services"
+	catalogv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/catalog/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	catalogv1.UnimplementedCatalogServiceServer
+	catalog *services.Catalog
+}
+
+func Register(gRPC *grpc.Server, catalog *services.Catalog) {
+	srvAPI := &serverAPI{catalog: catalog}
+	catalogv1.RegisterCatalogServiceServer(gRPC, srvAPI)
+}
+
+func (s *serverAPI) GetProducts(
+	ctx context.Context,
+	req *catalogv1.GetProductsRequest) (*catalogv1.GetProductsResponse, error) {
+	products, err := s.catalog.GetProducts(ctx, req.GetIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := make([]*catalogv1.Product, 0, len(products))
+	for _, p := range products {
+		res = append(res, mapper.MapToProto(p))
+	}
+
+	return &catalogv1.GetProductsResponse{Products: res}, nil
+}
+
+func toStatus(err error) error {
+	code, msg := mapper.MapDomainError(err)
+	return status.Error(code, msg)
+}
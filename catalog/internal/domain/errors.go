@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidProductID = errors.New("product id must be positive")
+	ErrEmptyProductIDs  = errors.New("product ids must not be empty")
+	ErrTooManyProducts  = errors.New("too many product ids requested")
+)
@@ -0,0 +1,16 @@
+package domain
+
+// MaxProductsPerRequest caps the ids of a single GetProducts call; an order
+// never carries more distinct products than that.
+const MaxProductsPerRequest = 1000
+
+// Product is a catalog entry. Price is in minor units of Currency.
+// Inactive products are kept so old orders still resolve, but must not be
+// ordered again.
+type Product struct {
+	ID       int64
+	Name     string
+	Price    int64
+	Currency string
+	Active   bool
+}
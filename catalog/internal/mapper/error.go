@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"errors"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/domain"
+	"google.golang.org/grpc/codes"
+)
+
+func MapDomainError(err error) (codes.Code, string) {
+	if err == nil {
+		return codes.OK, ""
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidProductID),
+		errors.Is(err, domain.ErrEmptyProductIDs),
+		errors.Is(err, domain.ErrTooManyProducts):
+		return codes.InvalidArgument, err.Error()
+	default:
+		return codes.Internal, "internal error"
+	}
+}
@@ -0,0 +1,16 @@
+package mapper
+
+import (
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/domain"
+	catalogv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/catalog/v1"
+)
+
+func MapToProto(p domain.Product) *catalogv1.Product {
+	return &catalogv1.Product{
+		Id:       p.ID,
+		Name:     p.Name,
+		Price:    p.Price,
+		Currency: p.Currency,
+		Active:   p.Active,
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/storage/postgres"
+)
+
+type Catalog struct {
+	log  *slog.Logger
+	repo postgres.Repository
+}
+
+func New(log *slog.Logger, repo postgres.Repository) *Catalog {
+	return &Catalog{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// GetProducts resolves ids to catalog entries. Duplicate ids are collapsed;
+// unknown ids are left out of the result so the caller can tell which
+// products do not exist. Inactive products are returned as is.
+func (c *Catalog) GetProducts(ctx context.Context, ids []int64) ([]domain.Product, error) {
+	const op = "services.Catalog.GetProducts"
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrEmptyProductIDs)
+	}
+
+	seen := make(map[int64]struct{}, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidProductID)
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	if len(unique) > domain.MaxProductsPerRequest {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrTooManyProducts)
+	}
+
+	products, err := c.repo.GetProducts(ctx, unique)
+	if err != nil {
+		c.log.With(slog.String("op", op)).Error("get products failed", slog.Any("err", err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return products, nil
+}
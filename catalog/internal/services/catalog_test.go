@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/domain"
+)
+
+func TestCatalogService_GetProducts(t *testing.T) {
+	errDB := errors.New("db")
+	tooMany := make([]int64, domain.MaxProductsPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+
+	tests := []struct {
+		name      string
+		ids       []int64
+		mockErr   error
+		wantErrIs error
+		wantIDs   []int64
+		wantCalls int
+	}{
+		{"ok", []int64{1, 2}, nil, nil, []int64{1, 2}, 1},
+		{"duplicates collapsed", []int64{2, 1, 2}, nil, nil, []int64{2, 1}, 1},
+		{"empty", nil, nil, domain.ErrEmptyProductIDs, nil, 0},
+		{"invalid id", []int64{1, 0}, nil, domain.ErrInvalidProductID, nil, 0},
+		{"too many", tooMany, nil, domain.ErrTooManyProducts, nil, 0},
+		{"repo error", []int64{1}, errDB, errDB, []int64{1}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &storageMock{
+				err: tt.mockErr,
+				products: []domain.Product{
+					{ID: 1, Name: "tea", Price: 100, Currency: "RUB", Active: true},
+				},
+			}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock)
+
+			got, err := svc.GetProducts(context.Background(), tt.ids)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected %v, got %v", tt.wantErrIs, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mock.calls != tt.wantCalls {
+				t.Fatalf("GetProducts calls: got %d, want %d", mock.calls, tt.wantCalls)
+			}
+			if tt.wantCalls > 0 && !slices.Equal(mock.ids, tt.wantIDs) {
+				t.Fatalf("GetProducts ids: got %v, want %v", mock.ids, tt.wantIDs)
+			}
+			if tt.wantErrIs == nil && len(got) != 1 {
+				t.Fatalf("products: got %d, want 1", len(got))
+			}
+		})
+	}
+}
+
+type storageMock struct {
+	products []domain.Product
+	err      error
+	calls    int
+	ids      []int64
+}
+
+func (m *storageMock) GetProducts(_ context.Context, ids []int64) ([]domain.Product, error) {
+	m.calls++
+	m.ids = ids
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.products, nil
+}
+
+func (m *storageMock) Ping(context.Context) error { return nil }
+
+func (m *storageMock) Close() error { return nil }
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/domain"
+)
+
+// GetProducts returns the products with the given ids ordered by id. Ids
+// that do not exist are silently omitted.
+func (s *Storage) GetProducts(ctx context.Context, ids []int64) ([]domain.Product, error) {
+	const op = "storage.postgres.GetProducts"
+
+	const query = `
+	SELECT id, name, price, currency, active
+	FROM products
+	WHERE id = ANY($1)
+	ORDER BY id;
+	`
+
+	rows, err := s.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	products := make([]domain.Product, 0, len(ids))
+	for rows.Next() {
+		var p domain.Product
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Currency, &p.Active); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return products, nil
+}
@@ -0,0 +1,13 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/domain"
+)
+
+type Repository interface {
+	GetProducts(ctx context.Context, ids []int64) ([]domain.Product, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
@@ -0,0 +1,93 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/catalog/internal/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+func getDSN(t *testing.T) string {
+	_ = godotenv.Load("../../../.env")
+	_ = godotenv.Load("../../../catalog/.env")
+	dsn := os.Getenv("CATALOG_PG_DSN_TEST")
+	if dsn == "" {
+		t.Skip("CATALOG_PG_DSN_TEST is not set")
+	}
+	return dsn
+}
+
+func cleanupTables(t *testing.T, db *pgxpool.Pool) {
+	const query = `TRUNCATE TABLE products RESTART IDENTITY CASCADE`
+
+	if _, err := db.Exec(context.Background(), query); err != nil {
+		t.Fatalf("db exec: %v", err)
+	}
+}
+
+func TestGetProducts_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+
+	// arrange
+	const insert = `
+	INSERT INTO products (name, price, currency, active)
+	VALUES ('tea', 100, 'RUB', TRUE), ('coffee', 250, 'USD', FALSE);
+	`
+	if _, err := db.Exec(ctx, insert); err != nil {
+		t.Fatalf("seed products: %v", err)
+	}
+
+	// act
+	products, err := storage.GetProducts(ctx, []int64{2, 1, 99})
+	if err != nil {
+		t.Fatalf("get products: %v", err)
+	}
+
+	// assert
+	if len(products) != 2 {
+		t.Fatalf("products: got %d, want 2", len(products))
+	}
+	tea, coffee := products[0], products[1]
+	if tea.ID != 1 || tea.Name != "tea" || tea.Price != 100 || tea.Currency != "RUB" || !tea.Active {
+		t.Fatalf("unexpected first product: %+v", tea)
+	}
+	if coffee.ID != 2 || coffee.Currency != "USD" || coffee.Active {
+		t.Fatalf("unexpected second product: %+v", coffee)
+	}
+}
+
+func configForTest(dsn string) config.DBConfig {
+	return config.DBConfig{
+		DSN:               dsn,
+		MaxConns:          4,
+		MinConns:          1,
+		MaxConnIdleTime:   time.Minute,
+		HealthCheckPeriod: time.Second,
+	}
+}
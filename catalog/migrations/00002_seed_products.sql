@@ -0,0 +1,12 @@
+-- +goose Up
+-- Demo products for the local stack; the e2e order flow orders product 10.
+INSERT INTO products (id, name, price, currency, active)
+VALUES (10, 'Demo product', 100, 'RUB', TRUE),
+       (11, 'Demo product (USD)', 5, 'USD', TRUE),
+       (12, 'Discontinued product', 50, 'RUB', FALSE)
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('products', 'id'), GREATEST((SELECT MAX(id) FROM products), 1));
+
+-- +goose Down
+DELETE FROM products WHERE id IN (10, 11, 12);
@@ -0,0 +1,119 @@
+// Package deadletter retries a consumed kafka message in place and moves it
+// to a dead-letter topic once the retries are spent. The headers it adds are
+// what payments/cmd/dlq-replay reads to re-drive any service's DLQ.
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// Dead-letter headers added to the original message.
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderError             = "x-error"
+	HeaderAttempts          = "x-attempts"
+	HeaderFailedAt          = "x-failed-at"
+
+	topicSuffix = ".dlq"
+)
+
+type Producer interface {
+	ProduceMessage(ctx context.Context, message *kafka.Message) error
+}
+
+// Config bounds in-place retries before a message is moved to Topic.
+type Config struct {
+	Topic       string
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// Topic returns the default dead-letter topic for topic.
+func Topic(topic string) string {
+	return topic + topicSuffix
+}
+
+// Handle runs handle up to cfg.MaxAttempts times and publishes msg to
+// cfg.Topic when all attempts fail. A nil error means the offset may be
+// committed; dead reports whether msg was dead-lettered.
+func Handle(
+	ctx context.Context, msg *kafka.Message, cfg Config, producer Producer, log *slog.Logger,
+	handle func(ctx context.Context) error) (dead bool, err error) {
+	const op = "deadletter.Handle"
+
+	attempts := max(cfg.MaxAttempts, 1)
+
+	var handleErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		handleErr = handle(ctx)
+		if handleErr == nil {
+			return false, nil
+		}
+
+		log.Warn("handle message failed",
+			slog.Int("attempt", attempt),
+			slog.Any("err", handleErr))
+
+		if attempt == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("%s: %w", op, ctx.Err())
+		case <-time.After(cfg.Backoff):
+		}
+	}
+
+	if ctx.Err() != nil {
+		return false, fmt.Errorf("%s: %w", op, ctx.Err())
+	}
+
+	if err := producer.ProduceMessage(ctx, message(msg, cfg.Topic, handleErr, attempts, time.Now())); err != nil {
+		return false, fmt.Errorf("%s: publish to dlq: %w", op, err)
+	}
+
+	log.Error("message moved to dlq",
+		slog.String("dlq_topic", cfg.Topic),
+		slog.Any("err", handleErr))
+
+	return true, nil
+}
+
+// message is msg addressed to topic, with the dead-letter headers appended
+// to its own.
+func message(msg *kafka.Message, topic string, handleErr error, attempts int, failedAt time.Time) *kafka.Message {
+	var originalTopic string
+	if msg.TopicPartition.Topic != nil {
+		originalTopic = *msg.TopicPartition.Topic
+	}
+
+	headers := make([]kafka.Header, 0, len(msg.Headers)+6)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: HeaderOriginalTopic, Value: []byte(originalTopic)},
+		kafka.Header{Key: HeaderOriginalPartition, Value: []byte(strconv.FormatInt(int64(msg.TopicPartition.Partition), 10))},
+		kafka.Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(int64(msg.TopicPartition.Offset), 10))},
+		kafka.Header{Key: HeaderError, Value: []byte(handleErr.Error())},
+		kafka.Header{Key: HeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(failedAt.UTC().Format(time.RFC3339Nano))},
+	)
+
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny,
+		},
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+}
@@ -0,0 +1,115 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func TestHandle(t *testing.T) {
+	errHandle := errors.New("bad payload")
+	errProduce := errors.New("broker down")
+
+	tests := []struct {
+		name          string
+		failures      int
+		produceErr    error
+		wantErr       bool
+		wantDead      bool
+		wantCalls     int
+		wantPublished int
+	}{
+		{name: "ok", failures: 0, wantCalls: 1},
+		{name: "recovers on retry", failures: 2, wantCalls: 3},
+		{name: "moved to dlq", failures: 3, wantDead: true, wantCalls: 3, wantPublished: 1},
+		{name: "dlq publish failed", failures: 3, produceErr: errProduce, wantErr: true, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handle := func(ctx context.Context) error {
+				calls++
+				if calls <= tt.failures {
+					return errHandle
+				}
+				return nil
+			}
+			producer := &producerMock{err: tt.produceErr}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+			topic := "status-topic"
+			msg := &kafka.Message{
+				TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 2, Offset: 17},
+				Value:          []byte("{"),
+			}
+
+			dead, err := Handle(context.Background(), msg, Config{Topic: Topic(topic), MaxAttempts: 3}, producer, log, handle)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if dead != tt.wantDead {
+				t.Fatalf("dead: got %t, want %t", dead, tt.wantDead)
+			}
+
+			if calls != tt.wantCalls {
+				t.Fatalf("handle calls: got %d, want %d", calls, tt.wantCalls)
+			}
+			if len(producer.published) != tt.wantPublished {
+				t.Fatalf("published: got %d, want %d", len(producer.published), tt.wantPublished)
+			}
+			if tt.wantPublished == 0 {
+				return
+			}
+
+			published := producer.published[0]
+			if *published.TopicPartition.Topic != "status-topic.dlq" {
+				t.Fatalf("dlq topic: got %s", *published.TopicPartition.Topic)
+			}
+			if string(published.Value) != "{" {
+				t.Fatalf("dlq payload must be the original bytes")
+			}
+
+			want := map[string]string{
+				HeaderOriginalTopic:     "status-topic",
+				HeaderOriginalPartition: "2",
+				HeaderOriginalOffset:    "17",
+				HeaderError:             errHandle.Error(),
+				HeaderAttempts:          "3",
+			}
+			got := make(map[string]string, len(published.Headers))
+			for _, h := range published.Headers {
+				got[h.Key] = string(h.Value)
+			}
+			for k, v := range want {
+				if got[k] != v {
+					t.Fatalf("header %s: got %q, want %q", k, got[k], v)
+				}
+			}
+			if got[HeaderFailedAt] == "" {
+				t.Fatalf("header %s must be set", HeaderFailedAt)
+			}
+		})
+	}
+}
+
+type producerMock struct {
+	err       error
+	published []*kafka.Message
+}
+
+func (m *producerMock) ProduceMessage(ctx context.Context, message *kafka.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.published = append(m.published, message)
+	return nil
+}
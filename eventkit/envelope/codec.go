@@ -0,0 +1,79 @@
+package envelope
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Content types negotiated through the content-type kafka header. A message
+// without the header is JSON.
+const (
+	HeaderContentType   = "content-type"
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Message is implemented by payloads defined in proto/opp/events/v1.
+type Message interface {
+	MarshalProto() []byte
+	UnmarshalProto(b []byte) error
+}
+
+// MarshalPayload encodes v in the given content type.
+func MarshalPayload(contentType string, v Message) ([]byte, error) {
+	const op = "envelope.MarshalPayload"
+
+	switch contentType {
+	case "", ContentTypeJSON:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return b, nil
+	case ContentTypeProtobuf:
+		return v.MarshalProto(), nil
+	default:
+		return nil, fmt.Errorf("%s: %w: %s", op, ErrUnsupportedContentType, contentType)
+	}
+}
+
+// UnmarshalPayload decodes the envelope payload into v using the content
+// type the envelope arrived with.
+func UnmarshalPayload(env Envelope, v Message) error {
+	const op = "envelope.UnmarshalPayload"
+
+	var err error
+	switch env.ContentType {
+	case "", ContentTypeJSON:
+		err = json.Unmarshal(env.Payload, v)
+	case ContentTypeProtobuf:
+		err = v.UnmarshalProto(env.Payload)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnsupportedContentType, env.ContentType)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Marshal encodes env and its already encoded payload.
+func Marshal(contentType string, env Envelope) ([]byte, error) {
+	const op = "envelope.Marshal"
+
+	switch contentType {
+	case "", ContentTypeJSON:
+		b, err := json.Marshal(&env)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return b, nil
+	case ContentTypeProtobuf:
+		return env.MarshalProto(), nil
+	default:
+		return nil, fmt.Errorf("%s: %w: %s", op, ErrUnsupportedContentType, contentType)
+	}
+}
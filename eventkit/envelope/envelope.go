@@ -0,0 +1,151 @@
+// Package envelope is the versioned event envelope every service wraps its
+// outbox messages in, with the kafka headers mirroring it and the causal
+// context events inherit.
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+)
+
+// Kafka headers mirroring the envelope, so consumers can route without
+// decoding the body.
+const (
+	HeaderEventID       = "x-event-id"
+	HeaderEventType     = "x-event-type"
+	HeaderEventVersion  = "x-event-version"
+	HeaderSource        = "x-source"
+	HeaderCorrelationID = "x-correlation-id"
+	HeaderCausationID   = "x-causation-id"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported event version")
+
+// Envelope wraps every message published from an outbox.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Source        string          `json:"source"`
+	CorrelationID string          `json:"correlation_id"`
+	CausationID   string          `json:"causation_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+
+	// ContentType is how Payload is encoded; it travels in the kafka header.
+	ContentType string `json:"-"`
+}
+
+// ID is the id of an outbox event as seen by other services.
+func ID(source string, eventID int64) string {
+	return source + ":" + strconv.FormatInt(eventID, 10)
+}
+
+// DefaultCorrelationID groups every event of one order when no upstream
+// correlation id is known.
+func DefaultCorrelationID(aggregateID int64) string {
+	return "order-" + strconv.FormatInt(aggregateID, 10)
+}
+
+// Decode reads an enveloped message of the given content type. Bare JSON
+// payloads published before the envelope existed are returned as the payload
+// of a version 0 envelope. schemaVersion is the newest payload version of
+// each event type the calling service reads; newer versions are rejected.
+func Decode(contentType string, data []byte, schemaVersion func(eventType string) int) (Envelope, error) {
+	const op = "envelope.Decode"
+
+	var env Envelope
+	switch contentType {
+	case "", ContentTypeJSON:
+		if err := json.Unmarshal(data, &env); err != nil {
+			return Envelope{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if env.Type == "" || len(env.Payload) == 0 {
+			return Envelope{Payload: data, ContentType: ContentTypeJSON}, nil
+		}
+	case ContentTypeProtobuf:
+		if err := env.UnmarshalProto(data); err != nil {
+			return Envelope{}, fmt.Errorf("%s: %w", op, err)
+		}
+	default:
+		return Envelope{}, fmt.Errorf("%s: %w: %s", op, ErrUnsupportedContentType, contentType)
+	}
+	env.ContentType = contentType
+	if env.ContentType == "" {
+		env.ContentType = ContentTypeJSON
+	}
+
+	if env.Version > schemaVersion(env.Type) {
+		return Envelope{}, fmt.Errorf("%s: %w: %s v%d", op, ErrUnsupportedVersion, env.Type, env.Version)
+	}
+
+	return env, nil
+}
+
+// Meta is the causal context events written by the current request inherit.
+type Meta struct {
+	CorrelationID string
+	CausationID   string
+}
+
+type metaKey struct{}
+
+// WithMeta marks events saved under ctx as caused by the given message.
+func WithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+// MetaFrom returns the causal context stored by WithMeta.
+func MetaFrom(ctx context.Context) Meta {
+	meta, _ := ctx.Value(metaKey{}).(Meta)
+	return meta
+}
+
+// CausedBy is the Meta for events produced in reaction to env.
+func CausedBy(env Envelope) Meta {
+	return Meta{CorrelationID: env.CorrelationID, CausationID: env.ID}
+}
+
+func (e *Envelope) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendString(b, 1, e.ID)
+	b = wire.AppendString(b, 2, e.Type)
+	b = wire.AppendInt64(b, 3, int64(e.Version))
+	b = wire.AppendTime(b, 4, e.OccurredAt)
+	b = wire.AppendString(b, 5, e.Source)
+	b = wire.AppendString(b, 6, e.CorrelationID)
+	b = wire.AppendString(b, 7, e.CausationID)
+	b = wire.AppendBytes(b, 8, e.Payload)
+	return b
+}
+
+func (e *Envelope) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.ID = f.Text()
+		case 2:
+			e.Type = f.Text()
+		case 3:
+			e.Version = int(f.Int64())
+		case 4:
+			e.OccurredAt, err = f.Time()
+		case 5:
+			e.Source = f.Text()
+		case 6:
+			e.CorrelationID = f.Text()
+		case 7:
+			e.CausationID = f.Text()
+		case 8:
+			e.Payload = f.Bytes
+		}
+		return err
+	})
+}
@@ -0,0 +1,107 @@
+package envelope
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// goldenEnvelope is the protoc encoding of testdata Envelope.txtpb; make
+// proto-golden regenerates it.
+const goldenEnvelope = "../../proto/opp/events/v1/testdata/Envelope.binpb"
+
+func TestEnvelope_Golden(t *testing.T) {
+	golden, err := os.ReadFile(goldenEnvelope)
+	if err != nil {
+		t.Fatalf("read golden: %v", err)
+	}
+	want := Envelope{
+		ID: "orders:42", Type: "order created", Version: 4,
+		OccurredAt: time.Date(2026, 1, 1, 0, 0, 0, 500000000, time.UTC), Source: "orders",
+		CorrelationID: "order-7", CausationID: "payments:3", Payload: []byte{0x08, 0x2a},
+	}
+
+	var got Envelope
+	if err := got.UnmarshalProto(golden); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unmarshal: got %+v, want %+v", got, want)
+	}
+	if encoded := want.MarshalProto(); !bytes.Equal(encoded, golden) {
+		t.Fatalf("marshal: got %x, want %x", encoded, golden)
+	}
+}
+
+// versions stands in for the schema versions of a service reading
+// "order created" up to v2.
+func versions(eventType string) int {
+	if eventType == "order created" {
+		return 2
+	}
+	return 1
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	occurredAt := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeProtobuf} {
+		message, err := Marshal(contentType, Envelope{
+			ID:            ID("orders", 10),
+			Type:          "order created",
+			Version:       2,
+			OccurredAt:    occurredAt,
+			Source:        "orders",
+			CorrelationID: DefaultCorrelationID(1),
+			Payload:       []byte(`{"order_id":1}`),
+		})
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", contentType, err)
+		}
+
+		env, err := Decode(contentType, message, versions)
+		if err != nil {
+			t.Fatalf("%s: decode: %v", contentType, err)
+		}
+		if env.ID != "orders:10" || env.CorrelationID != "order-1" || !env.OccurredAt.Equal(occurredAt) ||
+			env.ContentType != contentType || string(env.Payload) != `{"order_id":1}` {
+			t.Fatalf("%s: envelope: got %+v", contentType, env)
+		}
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	env, err := Decode("", []byte(`{"order_id":1}`), versions)
+	if err != nil {
+		t.Fatalf("legacy message: %v", err)
+	}
+	if env.Version != 0 || env.ContentType != ContentTypeJSON {
+		t.Fatalf("legacy message: got version=%d content type=%s", env.Version, env.ContentType)
+	}
+
+	if _, err := Decode("text/xml", []byte(`<order/>`), versions); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Fatalf("unknown content type: got %v, want %v", err, ErrUnsupportedContentType)
+	}
+
+	if _, err := Decode(ContentTypeProtobuf, []byte{0xff}, versions); err == nil {
+		t.Fatalf("malformed protobuf: expected error")
+	}
+
+	newer := []byte(`{"id":"orders:1","type":"order created","version":3,"payload":{}}`)
+	if _, err := Decode(ContentTypeJSON, newer, versions); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("newer version: got %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
+
+func TestMeta(t *testing.T) {
+	ctx := WithMeta(t.Context(), CausedBy(Envelope{ID: "payments:3", CorrelationID: "order-7"}))
+	if got := MetaFrom(ctx); got != (Meta{CorrelationID: "order-7", CausationID: "payments:3"}) {
+		t.Fatalf("meta: got %+v", got)
+	}
+	if got := MetaFrom(t.Context()); got != (Meta{}) {
+		t.Fatalf("meta without WithMeta: got %+v", got)
+	}
+}
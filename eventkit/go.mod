@@ -0,0 +1,9 @@
+module github.com/ChernykhITMO/order-processing-platform/eventkit
+
+go 1.25.1
+
+require (
+	github.com/confluentinc/confluent-kafka-go v1.9.2
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	google.golang.org/protobuf v1.36.8
+)
@@ -0,0 +1,135 @@
+// Package schema validates event payloads against the JSON Schema registry
+// checked in under schemas/events: one directory per event type and one
+// v<N>.json file per payload version.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var (
+	ErrUnknownSchema  = errors.New("unknown event schema")
+	ErrInvalidPayload = errors.New("payload does not match schema")
+)
+
+type key struct {
+	slug    string
+	version int
+}
+
+type Registry struct {
+	schemas map[key]*jsonschema.Schema
+}
+
+// Slug is the registry directory of an event type, "order created" is
+// stored under order-created.
+func Slug(eventType string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(eventType)), " ", "-")
+}
+
+// Version parses the version out of a v<N>.json file name.
+func Version(file string) (int, error) {
+	name := strings.TrimSuffix(filepath.Base(file), ".json")
+	if !strings.HasPrefix(name, "v") {
+		return 0, fmt.Errorf("schema file %s: name must be v<N>.json", file)
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(name, "v"))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("schema file %s: name must be v<N>.json", file)
+	}
+	return version, nil
+}
+
+// Load compiles every schema under dir.
+func Load(dir string) (*Registry, error) {
+	const op = "schema.Load"
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "v*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s: no schemas in %s", op, dir)
+	}
+
+	r := &Registry{schemas: make(map[key]*jsonschema.Schema, len(files))}
+	for _, file := range files {
+		version, err := Version(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		s, err := compile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		r.schemas[key{slug: filepath.Base(filepath.Dir(file)), version: version}] = s
+	}
+
+	return r, nil
+}
+
+func compile(file string) (*jsonschema.Schema, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	c := jsonschema.NewCompiler()
+	c.AssertFormat = true
+	if err := c.AddResource(file, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return c.Compile(file)
+}
+
+// Validate checks a JSON payload against the schema of eventType at version.
+// Version 0 marks payloads published before the envelope existed and is
+// checked against v1. A nil registry accepts everything, so validation can
+// be switched off by configuration.
+func (r *Registry) Validate(eventType string, version int, payload []byte) error {
+	const op = "schema.Registry.Validate"
+
+	if r == nil {
+		return nil
+	}
+	if version == 0 {
+		version = 1
+	}
+
+	s, ok := r.schemas[key{slug: Slug(eventType), version: version}]
+	if !ok {
+		return fmt.Errorf("%s: %w: %s v%d", op, ErrUnknownSchema, eventType, version)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%s: %w: %s v%d: %v", op, ErrInvalidPayload, eventType, version, err)
+	}
+	if err := s.Validate(doc); err != nil {
+		return fmt.Errorf("%s: %w: %s v%d: %v", op, ErrInvalidPayload, eventType, version, err)
+	}
+
+	return nil
+}
+
+// ValidateEnvelope checks the payload of a consumed message. Protobuf
+// payloads are held to their wire format instead, and bare messages from
+// before the envelope carry no type to look up, so both pass through.
+func (r *Registry) ValidateEnvelope(env envelope.Envelope) error {
+	if env.Type == "" || env.ContentType == envelope.ContentTypeProtobuf {
+		return nil
+	}
+	return r.Validate(env.Type, env.Version, env.Payload)
+}
@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+)
+
+const registryDir = "../../schemas/events"
+
+func TestRegistry_Validate(t *testing.T) {
+	r, err := Load(registryDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	status := []byte(`{"event_id":1,"order_id":2,"user_id":3,"order_status":"succeeded"}`)
+	requested := []byte(`{"event_id":1,"order_id":2,"user_id":3,"total_amount":100,"currency":"RUB","requested_at":"2026-01-01T00:00:00Z"}`)
+
+	tests := []struct {
+		name      string
+		eventType string
+		version   int
+		payload   []byte
+		wantErr   error
+	}{
+		{"valid", "payment requested", 1, requested, nil},
+		{"legacy version", "payment requested", 0, requested, nil},
+		{"missing field", "event-status", 1, []byte(`{"event_id":1,"order_id":2,"user_id":3}`), ErrInvalidPayload},
+		{"wrong type", "payment requested", 1, []byte(`{"event_id":1,"order_id":"2","user_id":3,"total_amount":1,"currency":"RUB","requested_at":"2026-01-01T00:00:00Z"}`), ErrInvalidPayload},
+		{"wrong currency", "order created", 2, []byte(`{"event_id":1,"order_id":2,"user_id":3,"total_amount":1,"currency":"rub","created_at":"2026-01-01T00:00:00Z"}`), ErrInvalidPayload},
+		{"not json", "event-status", 1, []byte(`{`), ErrInvalidPayload},
+		{"unknown type", "order shipped", 1, status, ErrUnknownSchema},
+		{"unknown version", "event-status", 99, status, ErrUnknownSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Validate(tt.eventType, tt.version, tt.payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_ValidateEnvelope(t *testing.T) {
+	r, err := Load(registryDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	invalid := []byte(`{"order_id":"x"}`)
+
+	if err := r.ValidateEnvelope(envelope.Envelope{Type: "order created", Version: 1, Payload: invalid, ContentType: envelope.ContentTypeJSON}); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("json envelope: got %v, want %v", err, ErrInvalidPayload)
+	}
+	if err := r.ValidateEnvelope(envelope.Envelope{Payload: invalid, ContentType: envelope.ContentTypeJSON}); err != nil {
+		t.Fatalf("legacy message: %v", err)
+	}
+	if err := r.ValidateEnvelope(envelope.Envelope{Type: "order created", Version: 1, Payload: []byte{0x08, 0x01}, ContentType: envelope.ContentTypeProtobuf}); err != nil {
+		t.Fatalf("protobuf envelope: %v", err)
+	}
+
+	var disabled *Registry
+	if err := disabled.Validate("order created", 1, invalid); err != nil {
+		t.Fatalf("nil registry: %v", err)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Fatalf("empty dir: expected error")
+	}
+	if _, err := Version("order-created/v0.json"); err == nil {
+		t.Fatalf("v0: expected error")
+	}
+	if got := Slug(" Order Created "); got != "order-created" {
+		t.Fatalf("slug: got %q", got)
+	}
+}
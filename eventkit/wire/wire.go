@@ -0,0 +1,111 @@
+// Package wire is the hand-rolled protobuf encoding shared by the services
+// for the messages in proto/opp/events/v1/events.proto. Zero values are
+// omitted and unknown fields are skipped, as generated code would do.
+package wire
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// AppendInt64 appends v as a varint field, omitting zero.
+func AppendInt64(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+// AppendString appends v as a length-delimited field, omitting "".
+func AppendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// AppendBytes appends v, an encoded message or raw bytes, as a
+// length-delimited field, omitting an empty v.
+func AppendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// AppendTime appends t as a google.protobuf.Timestamp, omitting the zero
+// time.
+func AppendTime(b []byte, num protowire.Number, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	var ts []byte
+	ts = AppendInt64(ts, 1, t.Unix())
+	ts = AppendInt64(ts, 2, int64(t.Nanosecond()))
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, ts)
+}
+
+// Field is one decoded field. Varint holds a varint value, Bytes a
+// length-delimited one; fields of other wire types carry neither.
+type Field struct {
+	Num    protowire.Number
+	Varint uint64
+	Bytes  []byte
+}
+
+func (f Field) Int64() int64 { return int64(f.Varint) }
+
+func (f Field) Text() string { return string(f.Bytes) }
+
+// Time decodes the field as a google.protobuf.Timestamp in UTC.
+func (f Field) Time() (time.Time, error) {
+	var seconds, nanos int64
+	err := ReadFields(f.Bytes, func(f Field) error {
+		switch f.Num {
+		case 1:
+			seconds = f.Int64()
+		case 2:
+			nanos = f.Int64()
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+// ReadFields calls fn for every field of the message in b, in wire order.
+func ReadFields(b []byte, fn func(f Field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		f := Field{Num: num}
+		switch typ {
+		case protowire.VarintType:
+			f.Varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.Bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestWire_RoundTrip(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 500000000, time.UTC)
+
+	var b []byte
+	b = AppendInt64(b, 1, 42)
+	b = AppendString(b, 2, "order created")
+	b = AppendTime(b, 3, at)
+	b = AppendBytes(b, 4, []byte{0x08, 0x2a})
+
+	var (
+		id      int64
+		typ     string
+		gotAt   time.Time
+		payload []byte
+	)
+	err := ReadFields(b, func(f Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			id = f.Int64()
+		case 2:
+			typ = f.Text()
+		case 3:
+			gotAt, err = f.Time()
+		case 4:
+			payload = f.Bytes
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("read fields: %v", err)
+	}
+	if id != 42 || typ != "order created" || !gotAt.Equal(at) || !bytes.Equal(payload, []byte{0x08, 0x2a}) {
+		t.Fatalf("got %d %q %s %x", id, typ, gotAt, payload)
+	}
+}
+
+func TestWire_ZeroValuesOmitted(t *testing.T) {
+	var b []byte
+	b = AppendInt64(b, 1, 0)
+	b = AppendString(b, 2, "")
+	b = AppendTime(b, 3, time.Time{})
+	b = AppendBytes(b, 4, nil)
+	if len(b) != 0 {
+		t.Fatalf("marshal: got %x, want no bytes", b)
+	}
+}
+
+func TestReadFields_SkipsUnknownTypes(t *testing.T) {
+	var b []byte
+	b = protowire.AppendTag(b, 9, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 7)
+	b = AppendString(b, 1, "orders:42")
+
+	var fields []Field
+	if err := ReadFields(b, func(f Field) error {
+		fields = append(fields, f)
+		return nil
+	}); err != nil {
+		t.Fatalf("read fields: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Varint != 0 || fields[0].Bytes != nil || fields[1].Text() != "orders:42" {
+		t.Fatalf("fields: got %+v", fields)
+	}
+}
+
+func TestReadFields_Errors(t *testing.T) {
+	b := AppendString(nil, 1, "orders:42")
+
+	err := ReadFields(b[:len(b)-1], func(Field) error { return nil })
+	if err == nil {
+		t.Fatalf("expected a parse error")
+	}
+
+	stop := errors.New("stop")
+	if err := ReadFields(b, func(Field) error { return stop }); !errors.Is(err, stop) {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+}
@@ -65,6 +65,7 @@ func main() {
 	apiMux.Handle("/metrics", promhttp.Handler())
 	apiMux.Handle("/orders", middleware.Instrument("gateway", "/orders", http.HandlerFunc(gw.HandleOrders)))
 	apiMux.Handle("/orders/", middleware.Instrument("gateway", "/orders/{id}", http.HandlerFunc(gw.HandleOrderById)))
+	apiMux.Handle("/orders/{id}/cancel", middleware.Instrument("gateway", "/orders/{id}/cancel", http.HandlerFunc(gw.CancelOrder)))
 	apiMux.Handle("/swagger/", middleware.Instrument("gateway", "/swagger/*", httpSwagger.WrapHandler))
 
 	apiSrv := &http.Server{
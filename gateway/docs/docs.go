@@ -16,6 +16,70 @@ const docTemplate = `{
     "basePath": "{{.BasePath}}",
     "paths": {
         "/orders": {
+            "get": {
+                "description": "Returns a user's orders newest first, one page at a time",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Order statuses, repeated or comma separated",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inclusive lower bound of created_at, RFC 3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exclusive upper bound of created_at, RFC 3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListOrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
             "post": {
                 "description": "Create a new order",
                 "consumes": [
@@ -34,6 +98,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.CreateOrderRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when a request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -48,6 +118,12 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
@@ -83,9 +159,74 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/orders/{id}/cancel": {
+            "post": {
+                "description": "Cancels the order; a captured payment is refunded, an authorized one is voided",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Cancel order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "dto.CancelOrderRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CancelOrderResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/dto.Order"
+                }
+            }
+        },
         "dto.CreateOrderRequest": {
             "type": "object",
             "properties": {
@@ -95,6 +236,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/dto.OrderItem"
                     }
                 },
+                "promo_code": {
+                    "description": "PromoCode is redeemed for a discount on the order; case insensitive.",
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "integer"
                 }
@@ -116,12 +261,36 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ListOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Order"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.Order": {
             "type": "object",
             "properties": {
+                "adjustments": {
+                    "description": "Adjustments are the discounts already taken off TotalAmount.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderAdjustment"
+                    }
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string"
+                },
                 "items": {
                     "type": "array",
                     "items": {
@@ -145,10 +314,32 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.OrderAdjustment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "dto.OrderItem": {
             "type": "object",
             "properties": {
+                "currency": {
+                    "description": "Currency is an ISO 4217 code; on create it defaults to the catalog one.",
+                    "type": "string"
+                },
                 "price": {
+                    "description": "Price is in minor units. On create it may be omitted: orders takes the\ncatalog price, and rejects a non-zero price that does not match it.",
                     "type": "integer"
                 },
                 "product_id": {
@@ -3,10 +3,11 @@ module github.com/ChernykhITMO/order-processing-platform/gateway
 go 1.25.1
 
 require (
-	github.com/ChernykhITMO/order-processing-proto v0.0.3
+	github.com/ChernykhITMO/order-processing-proto v0.0.0-00010101000000-000000000000
 	github.com/swaggo/http-swagger v1.3.4
 	github.com/swaggo/swag v1.16.6
 	google.golang.org/grpc v1.77.0
+	google.golang.org/protobuf v1.36.11
 )
 
 require (
@@ -37,6 +38,7 @@ require (
 	golang.org/x/text v0.30.0 // indirect
 	golang.org/x/tools v0.37.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251022142026-3a174f9686a8 // indirect
-	google.golang.org/protobuf v1.36.11 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
+
+replace github.com/ChernykhITMO/order-processing-proto => ../proto
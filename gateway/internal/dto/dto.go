@@ -9,12 +9,18 @@ import (
 type OrderItem struct {
 	ProductID int64 `json:"product_id"`
 	Quantity  int32 `json:"quantity"`
-	Price     int64 `json:"price"`
+	// Price is in minor units. On create it may be omitted: orders takes the
+	// catalog price, and rejects a non-zero price that does not match it.
+	Price int64 `json:"price"`
+	// Currency is an ISO 4217 code; on create it defaults to the catalog one.
+	Currency string `json:"currency,omitempty"`
 }
 
 type CreateOrderRequest struct {
 	UserID int64       `json:"user_id"`
 	Items  []OrderItem `json:"items"`
+	// PromoCode is redeemed for a discount on the order; case insensitive.
+	PromoCode string `json:"promo_code,omitempty"`
 }
 
 type Order struct {
@@ -23,8 +29,20 @@ type Order struct {
 	Status      string      `json:"status"`
 	Items       []OrderItem `json:"items"`
 	TotalAmount int64       `json:"total_amount"`
+	Currency    string      `json:"currency"`
 	CreatedAt   string      `json:"created_at"`
 	UpdatedAt   string      `json:"updated_at"`
+	// Adjustments are the discounts already taken off TotalAmount.
+	Adjustments []OrderAdjustment `json:"adjustments,omitempty"`
+}
+
+// OrderAdjustment is a promo code discount; ProductID is zero for a discount
+// on the whole order.
+type OrderAdjustment struct {
+	Code      string `json:"code"`
+	ProductID int64  `json:"product_id,omitempty"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
 }
 
 type CreateOrderResponse struct {
@@ -32,7 +50,8 @@ type CreateOrderResponse struct {
 }
 
 type Money struct {
-	Money int64 `json:"money"`
+	Money    int64  `json:"money"`
+	Currency string `json:"currency"`
 }
 
 type GetOrderRequest struct {
@@ -48,20 +67,69 @@ func ProtoGetToDTO(response *ordersv1.GetOrderResponse) GetOrderResponse {
 	if response.Order == nil {
 		return output
 	}
-	output.Order.OrderID = response.Order.OrderId
-	output.Order.UserID = response.Order.UserId
-	output.Order.Status = mapStatus(response.Order.Status)
+	output.Order = ProtoToDTOOrder(response.Order)
+	return output
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+type CancelOrderResponse struct {
+	Order Order `json:"order"`
+}
+
+func ProtoCancelToDTO(response *ordersv1.CancelOrderResponse) CancelOrderResponse {
+	var output CancelOrderResponse
+	if response.Order == nil {
+		return output
+	}
+	output.Order = ProtoToDTOOrder(response.Order)
+	return output
+}
+
+type ListOrdersResponse struct {
+	Items      []Order `json:"items"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
 
-	output.Order.Items = ProtoToDTOItems(response.Order.Items)
-	if response.Order.TotalAmount != nil {
-		output.Order.TotalAmount = response.Order.TotalAmount.Money
+func ProtoListToDTO(response *ordersv1.ListOrdersResponse) ListOrdersResponse {
+	output := ListOrdersResponse{
+		Items:      make([]Order, 0, len(response.Orders)),
+		NextCursor: response.NextCursor,
 	}
+	for _, order := range response.Orders {
+		output.Items = append(output.Items, ProtoToDTOOrder(order))
+	}
+	return output
+}
 
-	if response.Order.CreatedAt != nil {
-		output.Order.CreatedAt = response.Order.CreatedAt.AsTime().Format(time.RFC3339)
+func ProtoToDTOOrder(order *ordersv1.Order) Order {
+	var output Order
+	output.OrderID = order.OrderId
+	output.UserID = order.UserId
+	output.Status = mapStatus(order.Status)
+
+	output.Items = ProtoToDTOItems(order.Items)
+	if order.TotalAmount != nil {
+		output.TotalAmount = order.TotalAmount.Money
+		output.Currency = order.TotalAmount.Currency
+	}
+
+	if order.CreatedAt != nil {
+		output.CreatedAt = order.CreatedAt.AsTime().Format(time.RFC3339)
 	}
-	if response.Order.UpdatedAt != nil {
-		output.Order.UpdatedAt = response.Order.UpdatedAt.AsTime().Format(time.RFC3339)
+	if order.UpdatedAt != nil {
+		output.UpdatedAt = order.UpdatedAt.AsTime().Format(time.RFC3339)
+	}
+
+	for _, adj := range order.Adjustments {
+		output.Adjustments = append(output.Adjustments, OrderAdjustment{
+			Code:      adj.Code,
+			ProductID: adj.ProductId,
+			Amount:    adj.GetAmount().GetMoney(),
+			Currency:  adj.GetAmount().GetCurrency(),
+		})
 	}
 
 	return output
@@ -70,24 +138,20 @@ func ProtoGetToDTO(response *ordersv1.GetOrderResponse) GetOrderResponse {
 func ProtoToDTOItems(items []*ordersv1.OrderItem) []OrderItem {
 	out := make([]OrderItem, 0, len(items))
 	for _, it := range items {
-		var price int64
-		if it.Price != nil {
-			price = it.Price.Money
-		}
 		out = append(out, OrderItem{
 			ProductID: it.ProductId,
 			Quantity:  it.Quantity,
-			Price:     price,
+			Price:     it.GetPrice().GetMoney(),
+			Currency:  it.GetPrice().GetCurrency(),
 		})
 	}
 	return out
 }
 
 func mapStatus(status ordersv1.OrderStatus) string {
-	switch status {
-	case ordersv1.OrderStatus_new:
-		return "new"
-	default:
-		return "unspecified"
+	// enum value names in the contract match the REST status strings
+	if name, ok := ordersv1.OrderStatus_name[int32(status)]; ok {
+		return name
 	}
+	return "unspecified"
 }
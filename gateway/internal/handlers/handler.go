@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,29 +14,45 @@ import (
 	"github.com/ChernykhITMO/order-processing-platform/gateway/internal/dto"
 	ordersv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/orders/v1"
 	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// idempotencyKeyMetadata is the gRPC metadata key orders reads the
+// Idempotency-Key header from.
+const idempotencyKeyMetadata = "idempotency-key"
+
 type Gateway struct {
 	Orders         ordersv1.OrdersServiceClient
 	Health         grpc_health_v1.HealthClient
 	RequestTimeout time.Duration
 }
 
-// HandleOrders godoc
+// HandleOrders serves the /orders collection: POST creates an order, GET
+// lists a user's orders.
+func (g *Gateway) HandleOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		g.CreateOrder(w, r)
+	case http.MethodGet:
+		g.ListOrders(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{Error: "method is not post or get"})
+	}
+}
+
+// CreateOrder godoc
 // @Summary Create order
 // @Description Create a new order
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateOrderRequest true "Create order"
+// @Param Idempotency-Key header string false "Replays the original response when a request is retried with the same key"
 // @Success 200 {object} dto.CreateOrderResponse
 // @Failure 400 {string} string
+// @Failure 409 {string} string
 // @Router /orders [post]
-func (g *Gateway) HandleOrders(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeJSON(w, http.StatusMethodNotAllowed, apiError{Error: "method is not post"})
-		return
-	}
-
+func (g *Gateway) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateOrderRequest
 	if err := decodeJSONStrict(w, r, &req); err != nil {
 		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
@@ -67,16 +84,21 @@ func (g *Gateway) HandleOrders(w http.ResponseWriter, r *http.Request) {
 		items = append(items, &ordersv1.OrderItem{
 			ProductId: it.ProductID,
 			Quantity:  it.Quantity,
-			Price:     &ordersv1.Money{Money: it.Price},
+			Price:     &ordersv1.Money{Money: it.Price, Currency: it.Currency},
 		})
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), g.RequestTimeout)
 	defer cancel()
 
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, idempotencyKeyMetadata, key)
+	}
+
 	var protoReq ordersv1.CreateOrderRequest
 	protoReq.UserId = req.UserID
 	protoReq.Items = items
+	protoReq.PromoCode = req.PromoCode
 
 	resp, err := g.Orders.CreateOrder(ctx, &protoReq)
 	if err != nil {
@@ -87,6 +109,85 @@ func (g *Gateway) HandleOrders(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, dto.CreateOrderResponse{OrderID: resp.OrderId})
 }
 
+// ListOrders godoc
+// @Summary List orders
+// @Description Returns a user's orders newest first, one page at a time
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Param status query []string false "Order statuses, repeated or comma separated" collectionFormat(multi)
+// @Param created_from query string false "Inclusive lower bound of created_at, RFC 3339"
+// @Param created_to query string false "Exclusive upper bound of created_at, RFC 3339"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} dto.ListOrdersResponse
+// @Failure 400 {string} string
+// @Router /orders [get]
+func (g *Gateway) ListOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	userID, err := strconv.ParseInt(query.Get("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "user_id must be positive"})
+		return
+	}
+
+	req := ordersv1.ListOrdersRequest{
+		UserId: userID,
+		Cursor: query.Get("cursor"),
+	}
+
+	for _, param := range query["status"] {
+		for _, name := range strings.Split(param, ",") {
+			value, ok := ordersv1.OrderStatus_value[strings.TrimSpace(name)]
+			if !ok || value == int32(ordersv1.OrderStatus_unspecified) {
+				writeJSON(w, http.StatusBadRequest, apiError{Error: fmt.Sprintf("unknown status %q", name)})
+				return
+			}
+			req.Statuses = append(req.Statuses, ordersv1.OrderStatus(value))
+		}
+	}
+
+	bounds := []struct {
+		param string
+		dst   **timestamppb.Timestamp
+	}{
+		{"created_from", &req.CreatedFrom},
+		{"created_to", &req.CreatedTo},
+	}
+	for _, b := range bounds {
+		raw := query.Get(b.param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: b.param + " must be RFC 3339"})
+			return
+		}
+		*b.dst = timestamppb.New(t)
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || limit <= 0 {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: "limit must be positive"})
+			return
+		}
+		req.PageSize = int32(limit)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), g.RequestTimeout)
+	defer cancel()
+
+	resp, err := g.Orders.ListOrders(ctx, &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.ProtoListToDTO(resp))
+}
+
 // HandleOrderById godoc
 // @Summary Get order by id
 // @Description Returns order details by id
@@ -101,16 +202,11 @@ func (g *Gateway) HandleOrderById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	idStr := strings.TrimPrefix(r.URL.Path, "/orders/")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, err := orderIDFromPath(r.URL.Path, "")
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
 		return
 	}
-	if id <= 0 {
-		writeJSON(w, http.StatusBadRequest, apiError{Error: "id must be positive"})
-		return
-	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), g.RequestTimeout)
 	defer cancel()
@@ -127,6 +223,63 @@ func (g *Gateway) HandleOrderById(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, dto.ProtoGetToDTO(resp))
 }
 
+// CancelOrder godoc
+// @Summary Cancel order
+// @Description Cancels the order; a captured payment is refunded, an authorized one is voided
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body dto.CancelOrderRequest false "Cancellation reason"
+// @Success 200 {object} dto.CancelOrderResponse
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Router /orders/{id}/cancel [post]
+func (g *Gateway) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{Error: "method is not post"})
+		return
+	}
+
+	id, err := orderIDFromPath(r.URL.Path, "/cancel")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	var body dto.CancelOrderRequest
+	if err := decodeJSONStrict(w, r, &body); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), g.RequestTimeout)
+	defer cancel()
+
+	resp, err := g.Orders.CancelOrder(ctx, &ordersv1.CancelOrderRequest{
+		OrderId: id,
+		Reason:  body.Reason,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.ProtoCancelToDTO(resp))
+}
+
+// orderIDFromPath extracts the positive order id from /orders/{id}<suffix>.
+func orderIDFromPath(path, suffix string) (int64, error) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(path, "/orders/"), suffix)
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, errors.New("id must be positive")
+	}
+	return id, nil
+}
+
 func decodeJSONStrict(w http.ResponseWriter, r *http.Request, dst any) error {
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
 
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	ordersv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/orders/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -24,13 +26,21 @@ type ordersClientMock struct {
 	createErr  error
 	getResp    *ordersv1.GetOrderResponse
 	getErr     error
-
-	lastCreate *ordersv1.CreateOrderRequest
-	lastGet    *ordersv1.GetOrderRequest
+	listResp   *ordersv1.ListOrdersResponse
+	listErr    error
+	cancelResp *ordersv1.CancelOrderResponse
+	cancelErr  error
+
+	lastCreate   *ordersv1.CreateOrderRequest
+	lastCreateMD metadata.MD
+	lastGet      *ordersv1.GetOrderRequest
+	lastList     *ordersv1.ListOrdersRequest
+	lastCancel   *ordersv1.CancelOrderRequest
 }
 
 func (m *ordersClientMock) CreateOrder(ctx context.Context, req *ordersv1.CreateOrderRequest, _ ...grpc.CallOption) (*ordersv1.CreateOrderResponse, error) {
 	m.lastCreate = req
+	m.lastCreateMD, _ = metadata.FromOutgoingContext(ctx)
 	return m.createResp, m.createErr
 }
 
@@ -39,6 +49,15 @@ func (m *ordersClientMock) GetOrder(ctx context.Context, req *ordersv1.GetOrderR
 	return m.getResp, m.getErr
 }
 
+func (m *ordersClientMock) ListOrders(ctx context.Context, req *ordersv1.ListOrdersRequest, _ ...grpc.CallOption) (*ordersv1.ListOrdersResponse, error) {
+	m.lastList = req
+	return m.listResp, m.listErr
+}
+
+func (m *ordersClientMock) GetOrderSaga(ctx context.Context, req *ordersv1.GetOrderSagaRequest, _ ...grpc.CallOption) (*ordersv1.GetOrderSagaResponse, error) {
+	return nil, nil
+}
+
 func TestHandleOrders_ValidationErrors(t *testing.T) {
 	client := &ordersClientMock{}
 	gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
@@ -49,7 +68,7 @@ func TestHandleOrders_ValidationErrors(t *testing.T) {
 		body   string
 		want   int
 	}{
-		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"wrong method", http.MethodPut, "", http.StatusMethodNotAllowed},
 		{"bad json", http.MethodPost, "{", http.StatusBadRequest},
 		{"unknown field", http.MethodPost, `{"user_id":1,"items":[],"extra":1}`, http.StatusBadRequest},
 		{"invalid user", http.MethodPost, `{"user_id":0,"items":[{"product_id":1,"quantity":1,"price":1}]}`, http.StatusBadRequest},
@@ -76,8 +95,9 @@ func TestHandleOrders_Success(t *testing.T) {
 	gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
 
 	payload := dto.CreateOrderRequest{
-		UserID: 1,
-		Items:  []dto.OrderItem{{ProductID: 10, Quantity: 2, Price: 150}},
+		UserID:    1,
+		Items:     []dto.OrderItem{{ProductID: 10, Quantity: 2, Price: 150, Currency: "USD"}},
+		PromoCode: "spring",
 	}
 	body, _ := json.Marshal(payload)
 
@@ -99,6 +119,60 @@ func TestHandleOrders_Success(t *testing.T) {
 	if len(client.lastCreate.Items) != len(payload.Items) {
 		t.Fatalf("items length: got %d, want %d", len(client.lastCreate.Items), len(payload.Items))
 	}
+	if price := client.lastCreate.Items[0].GetPrice(); price.GetMoney() != 150 || price.GetCurrency() != "USD" {
+		t.Fatalf("price: got %d %s, want 150 USD", price.GetMoney(), price.GetCurrency())
+	}
+	if client.lastCreate.PromoCode != "spring" {
+		t.Fatalf("promo code: got %q, want %q", client.lastCreate.PromoCode, "spring")
+	}
+}
+
+func TestHandleOrders_IdempotencyKey(t *testing.T) {
+	cases := []struct {
+		name      string
+		key       string
+		createErr error
+		want      int
+	}{
+		{"forwarded", "key-1", nil, http.StatusOK},
+		{"absent", "", nil, http.StatusOK},
+		{"reused with other body", "key-1", status.Error(codes.AlreadyExists, "idempotency key was already used"), http.StatusConflict},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &ordersClientMock{
+				createResp: &ordersv1.CreateOrderResponse{OrderId: 42},
+				createErr:  tt.createErr,
+			}
+			gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
+
+			body := `{"user_id":1,"items":[{"product_id":10,"quantity":2,"price":150}]}`
+			req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(body))
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			gateway.HandleOrders(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status: got %d, want %d", w.Code, tt.want)
+			}
+
+			got := client.lastCreateMD.Get(idempotencyKeyMetadata)
+			if tt.key == "" && len(got) != 0 {
+				t.Fatalf("metadata: got %v, want none", got)
+			}
+			if tt.key != "" && (len(got) != 1 || got[0] != tt.key) {
+				t.Fatalf("metadata: got %v, want [%s]", got, tt.key)
+			}
+		})
+	}
+}
+
+func (m *ordersClientMock) CancelOrder(ctx context.Context, req *ordersv1.CancelOrderRequest, _ ...grpc.CallOption) (*ordersv1.CancelOrderResponse, error) {
+	m.lastCancel = req
+	return m.cancelResp, m.cancelErr
 }
 
 func TestHandleOrderById_ValidationErrors(t *testing.T) {
@@ -142,11 +216,15 @@ func TestHandleOrderById_Success(t *testing.T) {
 		Items: []*ordersv1.OrderItem{{
 			ProductId: 10,
 			Quantity:  2,
-			Price:     &ordersv1.Money{Money: 100},
+			Price:     &ordersv1.Money{Money: 100, Currency: "EUR"},
 		}},
-		TotalAmount: &ordersv1.Money{Money: 200},
+		TotalAmount: &ordersv1.Money{Money: 180, Currency: "EUR"},
 		CreatedAt:   timestamppb.New(now),
 		UpdatedAt:   timestamppb.New(now),
+		Adjustments: []*ordersv1.OrderAdjustment{{
+			Code:   "SPRING",
+			Amount: &ordersv1.Money{Money: 20, Currency: "EUR"},
+		}},
 	}}}
 
 	gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
@@ -170,6 +248,13 @@ func TestHandleOrderById_Success(t *testing.T) {
 	if resp.Order.Status != "new" {
 		t.Fatalf("status: got %s", resp.Order.Status)
 	}
+	if resp.Order.Currency != "EUR" || resp.Order.Items[0].Currency != "EUR" {
+		t.Fatalf("currency: got %+v", resp.Order)
+	}
+	want := dto.OrderAdjustment{Code: "SPRING", Amount: 20, Currency: "EUR"}
+	if len(resp.Order.Adjustments) != 1 || resp.Order.Adjustments[0] != want {
+		t.Fatalf("adjustments: got %+v, want [%+v]", resp.Order.Adjustments, want)
+	}
 }
 
 func TestHandleOrders_UpstreamError(t *testing.T) {
@@ -223,3 +308,181 @@ func TestHandleOrderById_ParseId(t *testing.T) {
 		t.Fatalf("status: got %d, body: %s", w.Code, string(data))
 	}
 }
+
+func TestHandleOrderById_PaymentStatuses(t *testing.T) {
+	statuses := []string{"paid", "payment_failed"}
+
+	for _, want := range statuses {
+		t.Run(want, func(t *testing.T) {
+			value, ok := ordersv1.OrderStatus_value[want]
+			if !ok {
+				t.Skipf("contract has no %s status", want)
+			}
+			client := &ordersClientMock{getResp: &ordersv1.GetOrderResponse{Order: &ordersv1.Order{
+				OrderId: 1,
+				UserId:  2,
+				Status:  ordersv1.OrderStatus(value),
+			}}}
+			gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
+
+			req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+			w := httptest.NewRecorder()
+
+			gateway.HandleOrderById(w, req)
+			if w.Code != http.StatusOK {
+				data, _ := io.ReadAll(w.Body)
+				t.Fatalf("status: got %d, body: %s", w.Code, string(data))
+			}
+
+			var resp dto.GetOrderResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Order.Status != want {
+				t.Fatalf("status: got %s, want %s", resp.Order.Status, want)
+			}
+		})
+	}
+}
+
+func TestHandleOrders_ListValidationErrors(t *testing.T) {
+	client := &ordersClientMock{listResp: &ordersv1.ListOrdersResponse{}}
+	gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
+
+	cases := []struct {
+		name  string
+		query string
+	}{
+		{"missing user", ""},
+		{"zero user", "?user_id=0"},
+		{"unknown status", "?user_id=1&status=shipped"},
+		{"unspecified status", "?user_id=1&status=unspecified"},
+		{"bad created_from", "?user_id=1&created_from=yesterday"},
+		{"bad created_to", "?user_id=1&created_to=2025-03-01"},
+		{"zero limit", "?user_id=1&limit=0"},
+		{"bad limit", "?user_id=1&limit=ten"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/orders"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			gateway.HandleOrders(w, req)
+			if w.Code != http.StatusBadRequest {
+				data, _ := io.ReadAll(w.Body)
+				t.Fatalf("status: got %d, want %d, body: %s", w.Code, http.StatusBadRequest, string(data))
+			}
+			if client.lastList != nil {
+				t.Fatalf("ListOrders must not be called")
+			}
+		})
+	}
+}
+
+func TestHandleOrders_List(t *testing.T) {
+	paid := ordersv1.OrderStatus(ordersv1.OrderStatus_value["paid"])
+	client := &ordersClientMock{listResp: &ordersv1.ListOrdersResponse{
+		Orders: []*ordersv1.Order{
+			{OrderId: 2, UserId: 1, Status: paid, TotalAmount: &ordersv1.Money{Money: 300}},
+			{OrderId: 1, UserId: 1, Status: ordersv1.OrderStatus_new},
+		},
+		NextCursor: "next",
+	}}
+	gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
+
+	url := "/orders?user_id=1&status=new,paid&status=cancelled" +
+		"&created_from=2025-03-01T00:00:00Z&created_to=2025-04-01T00:00:00Z&limit=2&cursor=abc"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+
+	gateway.HandleOrders(w, req)
+	if w.Code != http.StatusOK {
+		data, _ := io.ReadAll(w.Body)
+		t.Fatalf("status: got %d, body: %s", w.Code, string(data))
+	}
+
+	got := client.lastList
+	if got == nil {
+		t.Fatalf("expected ListOrders to be called")
+	}
+	if got.UserId != 1 || got.PageSize != 2 || got.Cursor != "abc" {
+		t.Fatalf("request: got %+v", got)
+	}
+	if len(got.Statuses) != 3 || got.Statuses[0] != ordersv1.OrderStatus_new || got.Statuses[1] != paid {
+		t.Fatalf("statuses: got %v", got.Statuses)
+	}
+	if got.CreatedFrom == nil || !got.CreatedFrom.AsTime().Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("created_from: got %v", got.CreatedFrom)
+	}
+	if got.CreatedTo == nil || !got.CreatedTo.AsTime().Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("created_to: got %v", got.CreatedTo)
+	}
+
+	var resp dto.ListOrdersResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[0].OrderID != 2 || resp.Items[0].Status != "paid" || resp.Items[0].TotalAmount != 300 {
+		t.Fatalf("items: got %+v", resp.Items)
+	}
+	if resp.NextCursor != "next" {
+		t.Fatalf("next_cursor: got %q, want %q", resp.NextCursor, "next")
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	cases := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		cancelErr  error
+		want       int
+		wantReason string
+	}{
+		{name: "ok", method: http.MethodPost, url: "/orders/7/cancel", body: `{"reason":"changed my mind"}`, want: http.StatusOK, wantReason: "changed my mind"},
+		{name: "no body", method: http.MethodPost, url: "/orders/7/cancel", want: http.StatusOK},
+		{name: "wrong method", method: http.MethodGet, url: "/orders/7/cancel", want: http.StatusMethodNotAllowed},
+		{name: "bad id", method: http.MethodPost, url: "/orders/x/cancel", want: http.StatusBadRequest},
+		{name: "zero id", method: http.MethodPost, url: "/orders/0/cancel", want: http.StatusBadRequest},
+		{name: "unknown field", method: http.MethodPost, url: "/orders/7/cancel", body: `{"why":"x"}`, want: http.StatusBadRequest},
+		{name: "not cancellable", method: http.MethodPost, url: "/orders/7/cancel", cancelErr: status.Error(codes.FailedPrecondition, "invalid status transition"), want: http.StatusBadRequest},
+		{name: "not found", method: http.MethodPost, url: "/orders/7/cancel", cancelErr: status.Error(codes.NotFound, "order not found"), want: http.StatusNotFound},
+	}
+
+	cancelled := ordersv1.OrderStatus(ordersv1.OrderStatus_value["cancelled"])
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &ordersClientMock{
+				cancelResp: &ordersv1.CancelOrderResponse{Order: &ordersv1.Order{OrderId: 7, Status: cancelled}},
+				cancelErr:  tt.cancelErr,
+			}
+			gateway := &Gateway{Orders: client, RequestTimeout: time.Second}
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			gateway.CancelOrder(w, req)
+			if w.Code != tt.want {
+				data, _ := io.ReadAll(w.Body)
+				t.Fatalf("status: got %d, want %d, body: %s", w.Code, tt.want, string(data))
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			if client.lastCancel.OrderId != 7 || client.lastCancel.Reason != tt.wantReason {
+				t.Fatalf("unexpected request: %+v", client.lastCancel)
+			}
+			var resp dto.CancelOrderResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Order.OrderID != 7 || resp.Order.Status != "cancelled" {
+				t.Fatalf("unexpected order: %+v", resp.Order)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/config"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/controller"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/kafka_consume"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/kafka_produce"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/services"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/services/event_sender"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/services/janitor"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/storage/postgres"
+)
+
+type App struct {
+	log          *slog.Logger
+	orders       *kafka_consume.Consumer
+	payments     *kafka_consume.Consumer
+	producer     *kafka.Producer
+	sender       *event_sender.Sender
+	senderPeriod time.Duration
+	janitor      *janitor.Janitor
+	janitorEvery time.Duration
+	storage      postgres.Repository
+}
+
+func New(log *slog.Logger, cfg config.Config) (*App, error) {
+	const op = "app.New"
+
+	storage, err := postgres.New(cfg.DB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	producer, err := kafka.NewProducer(cfg.KafkaBrokers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var schemas *schema.Registry
+	if cfg.SchemaDir != "" {
+		schemas, err = schema.Load(cfg.SchemaDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	service := services.New(storage, log)
+
+	orderDLQ := deadletter.Config{
+		Topic:       cfg.OrderDLQ.Topic,
+		MaxAttempts: cfg.OrderDLQ.MaxAttempts,
+		Backoff:     cfg.OrderDLQ.Backoff,
+	}
+	orders, err := kafka_consume.NewConsumer(
+		controller.NewController(service, log, schemas), producer, orderDLQ,
+		cfg.KafkaBrokers, cfg.TopicOrder, cfg.ConsumerGroup, log)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	statusDLQ := deadletter.Config{
+		Topic:       cfg.StatusDLQ.Topic,
+		MaxAttempts: cfg.StatusDLQ.MaxAttempts,
+		Backoff:     cfg.StatusDLQ.Backoff,
+	}
+	payments, err := kafka_consume.NewConsumer(
+		controller.NewPaymentController(service, log, schemas), producer, statusDLQ,
+		cfg.KafkaBrokers, cfg.TopicStatus, cfg.ConsumerGroup, log)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sender := event_sender.New(storage, producer, storage, log, cfg.TopicInventory, cfg.SenderBatchSize, cfg.SenderMaxAttempts, cfg.ContentType, schemas)
+	outboxJanitor := janitor.New(storage, log, janitor.Config{
+		Retention: cfg.Outbox.Retention,
+		Archive:   cfg.Outbox.Archive,
+		BatchSize: cfg.Outbox.BatchSize,
+	})
+
+	return &App{
+		log:          log,
+		orders:       orders,
+		payments:     payments,
+		producer:     producer,
+		sender:       sender,
+		senderPeriod: cfg.SenderPeriod,
+		janitor:      outboxJanitor,
+		janitorEvery: cfg.Outbox.Period,
+		storage:      storage,
+	}, nil
+}
+
+func (a *App) Run(ctx context.Context) error {
+	const op = "app.run"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("starting application")
+	var wg sync.WaitGroup
+	wg.Add(4)
+
+	go func() { defer wg.Done(); a.orders.Start(ctx) }()
+	go func() { defer wg.Done(); a.payments.Start(ctx) }()
+
+	period := a.senderPeriod
+	if period <= 0 {
+		period = time.Second
+	}
+
+	go func() {
+		defer wg.Done()
+		err := a.sender.StartProcessEvents(ctx, period)
+		if err != nil {
+			log.Error("start process events failed", slog.Any("err", err))
+			return
+		}
+	}()
+
+	janitorPeriod := a.janitorEvery
+	if janitorPeriod <= 0 {
+		janitorPeriod = time.Minute
+	}
+
+	go func() { defer wg.Done(); a.janitor.Start(ctx, janitorPeriod) }()
+
+	<-ctx.Done()
+
+	if err := a.orders.Stop(); err != nil {
+		log.Error("order consumer stopping", slog.Any("err", err))
+	}
+	if err := a.payments.Stop(); err != nil {
+		log.Error("status consumer stopping", slog.Any("err", err))
+	}
+
+	wg.Wait()
+
+	if err := a.producer.Close(); err != nil {
+		log.Warn("producer close: pending messages not delivered", slog.Any("err", err))
+	}
+
+	if a.storage != nil {
+		_ = a.storage.Close()
+	}
+	return nil
+}
+
+func (a *App) Stop() {
+	const op = "app.Stop"
+
+	a.log.With(slog.String("op", op)).
+		Info("stopping inventory server")
+}
+
+func (a *App) CheckReadiness(ctx context.Context) error {
+	if a.storage == nil {
+		return nil
+	}
+	return a.storage.Ping(ctx)
+}
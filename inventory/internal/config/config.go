@@ -0,0 +1,260 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+)
+
+type Config struct {
+	DB           DBConfig
+	HealthAddr   string
+	KafkaBrokers []string
+	TopicOrder   string
+	TopicStatus  string
+	// TopicInventory receives the stock reservation results.
+	TopicInventory string
+	ConsumerGroup  string
+	SenderPeriod   time.Duration
+	// SenderBatchSize caps how many outbox events are published per round trip.
+	SenderBatchSize int
+	// SenderMaxAttempts is how many times an outbox event that cannot be
+	// encoded is retried before it is dead-lettered.
+	SenderMaxAttempts int
+	// ContentType is the encoding of published events.
+	ContentType string
+	// SchemaDir is the event schema registry; validation is off when empty.
+	SchemaDir string
+	OrderDLQ  DLQConfig
+	StatusDLQ DLQConfig
+	Outbox    OutboxConfig
+}
+
+// OutboxConfig drives the retention janitor for sent events.
+type OutboxConfig struct {
+	Retention time.Duration
+	Archive   bool
+	BatchSize int
+	Period    time.Duration
+}
+
+type DLQConfig struct {
+	Topic       string
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+type DBConfig struct {
+	DSN               string
+	MaxConns          int32
+	MinConns          int32
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+}
+
+func Load() (Config, error) {
+	dsn, err := getEnv("INVENTORY_PG_DSN")
+	if err != nil {
+		return Config{}, err
+	}
+	brokersRaw, err := getEnv("KAFKA_BROKERS")
+	if err != nil {
+		return Config{}, err
+	}
+	kafkaBrokers := parseKafkaBrokers(brokersRaw)
+	if len(kafkaBrokers) == 0 {
+		return Config{}, errors.New("KAFKA_BROKERS is empty")
+	}
+	topicOrder, err := getEnv("KAFKA_TOPIC_ORDER")
+	if err != nil {
+		return Config{}, err
+	}
+	topicStatus, err := getEnv("KAFKA_TOPIC_STATUS")
+	if err != nil {
+		return Config{}, err
+	}
+	topicInventory := getEnvOrDefault("KAFKA_TOPIC_INVENTORY", "inventory-topic")
+	consumerGroup := getEnvOrDefault("KAFKA_CONSUMER_GROUP", "inventory-group")
+	senderPeriod, err := getEnvDurationWithDefault("KAFKA_SENDER_PERIOD", time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	maxConns, err := getEnvInt32WithDefault("INVENTORY_PG_MAX_CONNS", 10)
+	if err != nil {
+		return Config{}, err
+	}
+	minConns, err := getEnvInt32WithDefault("INVENTORY_PG_MIN_CONNS", 2)
+	if err != nil {
+		return Config{}, err
+	}
+	maxConnIdleTime, err := getEnvDurationWithDefault("INVENTORY_PG_MAX_CONN_IDLE_TIME", 5*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	healthCheckPeriod, err := getEnvDurationWithDefault("INVENTORY_PG_HEALTH_CHECK_PERIOD", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+
+	senderBatchSize, err := getEnvInt32WithDefault("KAFKA_SENDER_BATCH_SIZE", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	senderMaxAttempts, err := getEnvInt32WithDefault("OUTBOX_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	contentType := getEnvOrDefault("KAFKA_CONTENT_TYPE", envelope.ContentTypeJSON)
+	if contentType != envelope.ContentTypeJSON && contentType != envelope.ContentTypeProtobuf {
+		return Config{}, errors.New("KAFKA_CONTENT_TYPE must be " + envelope.ContentTypeJSON + " or " + envelope.ContentTypeProtobuf)
+	}
+
+	outbox, err := loadOutbox()
+	if err != nil {
+		return Config{}, err
+	}
+	dlqAttempts, err := getEnvInt32WithDefault("KAFKA_CONSUMER_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return Config{}, err
+	}
+	dlqBackoff, err := getEnvDurationWithDefault("KAFKA_CONSUMER_BACKOFF", 500*time.Millisecond)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		DB: DBConfig{
+			DSN:               dsn,
+			MaxConns:          maxConns,
+			MinConns:          minConns,
+			MaxConnIdleTime:   maxConnIdleTime,
+			HealthCheckPeriod: healthCheckPeriod,
+		},
+		HealthAddr:        getEnvOrDefault("INVENTORY_HEALTH_ADDR", ":8085"),
+		KafkaBrokers:      kafkaBrokers,
+		TopicOrder:        topicOrder,
+		TopicStatus:       topicStatus,
+		TopicInventory:    topicInventory,
+		ConsumerGroup:     consumerGroup,
+		SenderPeriod:      senderPeriod,
+		SenderBatchSize:   int(senderBatchSize),
+		SenderMaxAttempts: int(senderMaxAttempts),
+		ContentType:       contentType,
+		SchemaDir:         getEnvOrDefault("EVENT_SCHEMA_DIR", ""),
+		// payments dead-letters the order topic to order-topic.dlq; the
+		// defaults keep the dead letters of the two services apart
+		OrderDLQ: DLQConfig{
+			Topic:       getEnvOrDefault("KAFKA_TOPIC_ORDER_DLQ", topicOrder+".inventory.dlq"),
+			MaxAttempts: int(dlqAttempts),
+			Backoff:     dlqBackoff,
+		},
+		StatusDLQ: DLQConfig{
+			Topic:       getEnvOrDefault("KAFKA_TOPIC_STATUS_DLQ", topicStatus+".inventory.dlq"),
+			MaxAttempts: int(dlqAttempts),
+			Backoff:     dlqBackoff,
+		},
+		Outbox: outbox,
+	}, nil
+}
+
+func loadOutbox() (OutboxConfig, error) {
+	retention, err := getEnvDurationWithDefault("OUTBOX_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	archive, err := getEnvBoolWithDefault("OUTBOX_ARCHIVE", true)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	batchSize, err := getEnvInt32WithDefault("OUTBOX_CLEANUP_BATCH_SIZE", 1000)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	period, err := getEnvDurationWithDefault("OUTBOX_CLEANUP_PERIOD", time.Minute)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+
+	return OutboxConfig{
+		Retention: retention,
+		Archive:   archive,
+		BatchSize: int(batchSize),
+		Period:    period,
+	}, nil
+}
+
+func getEnv(key string) (string, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return "", errors.New(key + " is empty")
+	}
+	return val, nil
+}
+
+func getEnvOrDefault(key, def string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return def
+}
+
+func getEnvInt32WithDefault(key string, def int32) (int32, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseInt(val, 10, 32)
+	if err != nil {
+		return 0, errors.New(key + " is invalid int: " + err.Error())
+	}
+
+	return int32(parsed), nil
+}
+
+func getEnvBoolWithDefault(key string, def bool) (bool, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, errors.New(key + " is invalid bool: " + err.Error())
+	}
+
+	return parsed, nil
+}
+
+func getEnvDurationWithDefault(key string, def time.Duration) (time.Duration, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return def, nil
+	}
+
+	parsed, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, errors.New(key + " is invalid duration: " + err.Error())
+	}
+
+	return parsed, nil
+}
+
+func parseKafkaBrokers(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
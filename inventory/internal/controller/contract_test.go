@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/services"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/storage/postgres"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+	"github.com/jackc/pgx/v5"
+)
+
+const contractsDir = "../../../contracts"
+
+// orderCreatedContract lists the order created fields inventory relies on.
+var orderCreatedContract = contract.Contract{
+	Consumer:  "inventory",
+	Provider:  "orders",
+	EventType: events.TypeOrderCreated,
+	Examples: []contract.Example{
+		{
+			Description: "order to reserve",
+			Payload:     json.RawMessage(`{"event_id":21,"order_id":42,"user_id":7,"items":[{"product_id":10,"quantity":2}]}`),
+		},
+	},
+}
+
+// orderCancelledContract lists the order cancelled fields inventory relies on.
+var orderCancelledContract = contract.Contract{
+	Consumer:  "inventory",
+	Provider:  "orders",
+	EventType: events.TypeOrderCancelled,
+	Examples: []contract.Example{
+		{
+			Description: "reserved order cancelled",
+			Payload:     json.RawMessage(`{"event_id":22,"order_id":42,"user_id":7}`),
+		},
+	},
+}
+
+// orderExpiredContract lists the order expired fields inventory relies on.
+var orderExpiredContract = contract.Contract{
+	Consumer:  "inventory",
+	Provider:  "orders",
+	EventType: events.TypeOrderExpired,
+	Examples: []contract.Example{
+		{
+			Description: "reserved order left unpaid",
+			Payload:     json.RawMessage(`{"event_id":23,"order_id":42,"user_id":7}`),
+		},
+	},
+}
+
+// paymentStatusContract lists the payment status fields inventory relies on.
+var paymentStatusContract = contract.Contract{
+	Consumer:  "inventory",
+	Provider:  "payments",
+	EventType: "event-status",
+	Examples: []contract.Example{
+		{
+			Description: "payment failed",
+			Payload:     json.RawMessage(`{"event_id":6,"order_id":42,"user_id":7,"order_status":"failed"}`),
+		},
+	},
+}
+
+func TestController_OrderCreatedContract(t *testing.T) {
+	for _, example := range orderCreatedContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			tx := &txMock{stock: map[domain.ID]int64{10: 5}}
+			ctrl := NewController(testService(tx), testLogger(), nil)
+
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, wrap(orderCreatedContract, example)); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			if len(tx.inserted) != 1 || tx.inserted[0].OrderID != 42 || tx.inserted[0].UserID != 7 {
+				t.Fatalf("reservation: got %+v, want order=42 user=7", tx.inserted)
+			}
+			if len(tx.taken) != 1 || tx.taken[0] != (domain.Item{ProductID: 10, Quantity: 2}) {
+				t.Fatalf("taken: got %v, want product 10 x2", tx.taken)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, orderCreatedContract)
+}
+
+func TestController_OrderCancelledContract(t *testing.T) {
+	for _, example := range orderCancelledContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			tx := &txMock{existing: &domain.Reservation{
+				OrderID: 42, UserID: 7, Status: domain.ReservationReserved,
+				Items: []domain.Item{{ProductID: 10, Quantity: 2}},
+			}}
+			ctrl := NewController(testService(tx), testLogger(), nil)
+
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, wrap(orderCancelledContract, example)); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			if tx.releasedOrderID != 42 || len(tx.returned) != 1 {
+				t.Fatalf("release: got order=%d returned=%v, want order=42", tx.releasedOrderID, tx.returned)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, orderCancelledContract)
+}
+
+func TestController_OrderExpiredContract(t *testing.T) {
+	for _, example := range orderExpiredContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			tx := &txMock{existing: &domain.Reservation{
+				OrderID: 42, UserID: 7, Status: domain.ReservationReserved,
+				Items: []domain.Item{{ProductID: 10, Quantity: 2}},
+			}}
+			ctrl := NewController(testService(tx), testLogger(), nil)
+
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, wrap(orderExpiredContract, example)); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			if tx.releasedOrderID != 42 || len(tx.returned) != 1 {
+				t.Fatalf("release: got order=%d returned=%v, want order=42", tx.releasedOrderID, tx.returned)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, orderExpiredContract)
+}
+
+func TestPaymentController_PaymentStatusContract(t *testing.T) {
+	for _, example := range paymentStatusContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			tx := &txMock{existing: &domain.Reservation{
+				OrderID: 42, UserID: 7, Status: domain.ReservationReserved,
+				Items: []domain.Item{{ProductID: 10, Quantity: 2}},
+			}}
+			ctrl := NewPaymentController(testService(tx), testLogger(), nil)
+
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, wrap(paymentStatusContract, example)); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			if tx.releasedOrderID != 42 || len(tx.returned) != 1 {
+				t.Fatalf("release: got order=%d returned=%v, want order=42", tx.releasedOrderID, tx.returned)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, paymentStatusContract)
+}
+
+func TestPaymentController_SucceededKeepsStock(t *testing.T) {
+	tx := &txMock{existing: &domain.Reservation{OrderID: 42, UserID: 7, Status: domain.ReservationReserved}}
+	ctrl := NewPaymentController(testService(tx), testLogger(), nil)
+
+	message, _ := json.Marshal(envelope.Envelope{
+		ID:      "payments:5",
+		Type:    "event-status",
+		Version: events.SchemaVersion("event-status"),
+		Source:  "payments",
+		Payload: json.RawMessage(`{"event_id":5,"order_id":42,"user_id":7,"order_status":"succeeded"}`),
+	})
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+		t.Fatalf("handle message: %v", err)
+	}
+	if tx.releasedOrderID != 0 {
+		t.Fatalf("released order %d, want none", tx.releasedOrderID)
+	}
+}
+
+func wrap(c contract.Contract, example contract.Example) []byte {
+	message, _ := json.Marshal(envelope.Envelope{
+		ID:      c.Provider + ":1",
+		Type:    c.EventType,
+		Version: events.SchemaVersion(c.EventType),
+		Source:  c.Provider,
+		Payload: example.Payload,
+	})
+	return message
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+func testService(tx *txMock) *services.Service {
+	return services.New(&storageMock{tx: tx}, testLogger())
+}
+
+type storageMock struct {
+	tx *txMock
+}
+
+func (m *storageMock) RunInTx(ctx context.Context, fn func(tx postgres.TxRepository) error) error {
+	return fn(m.tx)
+}
+
+func (m *storageMock) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	return nil, nil
+}
+
+func (m *storageMock) MarkSent(ctx context.Context, ids []int64) error {
+	return nil
+}
+
+func (m *storageMock) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
+func (m *storageMock) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *storageMock) Close() error {
+	return nil
+}
+
+type txMock struct {
+	existing *domain.Reservation
+	stock    map[domain.ID]int64
+
+	inserted        []domain.Reservation
+	releasedOrderID int64
+	taken           []domain.Item
+	returned        []domain.Item
+}
+
+func (m *txMock) InsertReservation(ctx context.Context, reservation domain.Reservation) (bool, error) {
+	if m.existing != nil {
+		return false, nil
+	}
+	m.inserted = append(m.inserted, reservation)
+	return true, nil
+}
+
+func (m *txMock) GetReservationForUpdate(ctx context.Context, orderID int64) (domain.Reservation, error) {
+	if m.existing == nil {
+		return domain.Reservation{}, pgx.ErrNoRows
+	}
+	return *m.existing, nil
+}
+
+func (m *txMock) UpdateReservationStatus(ctx context.Context, orderID int64, status domain.ReservationStatus) error {
+	if status == domain.ReservationReleased {
+		m.releasedOrderID = orderID
+	}
+	return nil
+}
+
+func (m *txMock) RejectReservation(ctx context.Context, orderID int64, productID domain.ID, reason string) error {
+	return nil
+}
+
+func (m *txMock) LockStock(ctx context.Context, productIDs []domain.ID) (map[domain.ID]int64, error) {
+	return m.stock, nil
+}
+
+func (m *txMock) TakeStock(ctx context.Context, items []domain.Item) error {
+	m.taken = append(m.taken, items...)
+	return nil
+}
+
+func (m *txMock) ReturnStock(ctx context.Context, items []domain.Item) error {
+	m.returned = append(m.returned, items...)
+	return nil
+}
+
+func (m *txMock) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/services"
+)
+
+// Validator checks a consumed envelope against the schema registry.
+type Validator interface {
+	ValidateEnvelope(env envelope.Envelope) error
+}
+
+type Controller struct {
+	service *services.Service
+	log     *slog.Logger
+	schemas Validator
+}
+
+// NewController builds the handler of the order topic: order created, order
+// cancelled and order expired events. schemas may be nil to skip payload
+// validation.
+func NewController(service *services.Service, log *slog.Logger, schemas Validator) *Controller {
+	return &Controller{
+		service: service,
+		log:     log,
+		schemas: schemas,
+	}
+}
+
+func (h *Controller) HandleMessage(parentCtx context.Context, contentType string, message []byte) error {
+	const op = "controller.HandleMessage"
+	log := h.log.With(slog.String("op", op))
+
+	env, err := events.DecodeEnvelope(contentType, message)
+	if err != nil {
+		log.Error("decode envelope", slog.Any("err", err))
+		return fmt.Errorf("%s: decode envelope: %w", op, err)
+	}
+	if h.schemas != nil {
+		if err := h.schemas.ValidateEnvelope(env); err != nil {
+			log.Error("validate message", slog.Any("err", err))
+			return fmt.Errorf("%s: validate message: %w", op, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(envelope.WithMeta(parentCtx, envelope.CausedBy(env)), 5*time.Second)
+	defer cancel()
+
+	switch env.Type {
+	case events.TypeOrderCreated:
+		err = h.handleOrderCreated(ctx, env)
+	case events.TypeOrderCancelled:
+		err = h.handleOrderCancelled(ctx, env)
+	case events.TypeOrderExpired:
+		err = h.handleOrderExpired(ctx, env)
+	case events.TypePaymentRequested:
+		log.Debug("event skipped", slog.String("type", env.Type), slog.String("event_id", env.ID))
+		return nil
+	default:
+		log.Warn("unexpected event type skipped", slog.String("type", env.Type))
+		return nil
+	}
+	if err != nil {
+		log.Error("handle failed", slog.String("type", env.Type), slog.Any("err", err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (h *Controller) handleOrderCreated(ctx context.Context, env envelope.Envelope) error {
+	var event events.OrderCreated
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+
+	input := dto.ReserveStock{
+		OrderID: int64(event.OrderID),
+		UserID:  int64(event.UserID),
+		Items:   make([]dto.Item, 0, len(event.Items)),
+	}
+	for _, item := range event.Items {
+		input.Items = append(input.Items, dto.Item{ProductID: int64(item.ProductID), Quantity: item.Quantity})
+	}
+
+	if err := h.service.ReserveStock(ctx, input); err != nil {
+		return fmt.Errorf("handle message: %w", err)
+	}
+	return nil
+}
+
+func (h *Controller) handleOrderCancelled(ctx context.Context, env envelope.Envelope) error {
+	var event events.OrderCancelled
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+
+	input := dto.ReleaseStock{
+		OrderID: int64(event.OrderID),
+		UserID:  int64(event.UserID),
+		Reason:  "order cancelled",
+	}
+
+	if err := h.service.ReleaseStock(ctx, input); err != nil {
+		return fmt.Errorf("handle message: %w", err)
+	}
+	return nil
+}
+
+func (h *Controller) handleOrderExpired(ctx context.Context, env envelope.Envelope) error {
+	var event events.OrderExpired
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+
+	input := dto.ReleaseStock{
+		OrderID: int64(event.OrderID),
+		UserID:  int64(event.UserID),
+		Reason:  "order expired",
+	}
+
+	if err := h.service.ReleaseStock(ctx, input); err != nil {
+		return fmt.Errorf("handle message: %w", err)
+	}
+	return nil
+}
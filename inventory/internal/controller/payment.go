@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/services"
+)
+
+// paymentFailed is the order_status of a payment that will not be retried.
+const paymentFailed = "failed"
+
+type PaymentController struct {
+	service *services.Service
+	log     *slog.Logger
+	schemas Validator
+}
+
+// NewPaymentController builds the handler of the status topic, which
+// releases the stock of orders whose payment failed. schemas may be nil to
+// skip payload validation.
+func NewPaymentController(service *services.Service, log *slog.Logger, schemas Validator) *PaymentController {
+	return &PaymentController{
+		service: service,
+		log:     log,
+		schemas: schemas,
+	}
+}
+
+func (h *PaymentController) HandleMessage(parentCtx context.Context, contentType string, message []byte) error {
+	const op = "controller.PaymentController.HandleMessage"
+	log := h.log.With(slog.String("op", op))
+
+	env, err := events.DecodeEnvelope(contentType, message)
+	if err != nil {
+		log.Error("decode envelope", slog.Any("err", err))
+		return fmt.Errorf("%s: decode envelope: %w", op, err)
+	}
+	if h.schemas != nil {
+		if err := h.schemas.ValidateEnvelope(env); err != nil {
+			log.Error("validate message", slog.Any("err", err))
+			return fmt.Errorf("%s: validate message: %w", op, err)
+		}
+	}
+
+	switch env.Type {
+	case events.TypePaymentCancelled, events.TypeRefundSucceeded, events.TypeRefundFailed:
+		// a cancelled order is released from the order topic; refunds follow
+		// shipped orders, whose stock is gone
+		log.Debug("event skipped", slog.String("type", env.Type), slog.String("event_id", env.ID))
+		return nil
+	}
+
+	var event events.PaymentStatus
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		log.Error("decode message", slog.Any("err", err))
+		return fmt.Errorf("%s: decode message: %w", op, err)
+	}
+	if event.OrderStatus != paymentFailed {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(envelope.WithMeta(parentCtx, envelope.CausedBy(env)), 5*time.Second)
+	defer cancel()
+
+	input := dto.ReleaseStock{
+		OrderID: event.OrderID,
+		UserID:  event.UserID,
+		Reason:  "payment failed",
+	}
+
+	if err := h.service.ReleaseStock(ctx, input); err != nil {
+		log.Error("handle failed", slog.Any("err", err))
+		return fmt.Errorf("%s: handle message: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidOrderID   = errors.New("order id must be positive")
+	ErrInvalidUserID    = errors.New("user id must be positive")
+	ErrInvalidProductID = errors.New("product id must be positive")
+	ErrInvalidQuantity  = errors.New("quantity must be at least one")
+	ErrInvalidItems     = errors.New("items must not be empty")
+	ErrUnknownType      = errors.New("unknown type")
+)
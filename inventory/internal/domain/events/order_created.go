@@ -0,0 +1,82 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+)
+
+type OrderCreated struct {
+	EventID     int64     `json:"event_id"`
+	OrderID     domain.ID `json:"order_id"`
+	UserID      domain.ID `json:"user_id"`
+	TotalAmount int64     `json:"total_amount"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Items tell inventory what to reserve for the order.
+	Items []OrderCreatedItem `json:"items,omitempty"`
+}
+
+type OrderCreatedItem struct {
+	ProductID domain.ID `json:"product_id"`
+	Quantity  int32     `json:"quantity"`
+}
+
+func (e *OrderCreated) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendInt64(b, 4, e.TotalAmount)
+	b = wire.AppendTime(b, 5, e.CreatedAt)
+	b = wire.AppendString(b, 6, e.Currency)
+	for _, item := range e.Items {
+		b = wire.AppendBytes(b, 7, item.marshalProto())
+	}
+	return b
+}
+
+func (e *OrderCreated) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.TotalAmount = f.Int64()
+		case 5:
+			e.CreatedAt, err = f.Time()
+		case 6:
+			e.Currency = f.Text()
+		case 7:
+			var item OrderCreatedItem
+			err = item.unmarshalProto(f.Bytes)
+			e.Items = append(e.Items, item)
+		}
+		return err
+	})
+}
+
+func (i OrderCreatedItem) marshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, int64(i.ProductID))
+	b = wire.AppendInt64(b, 2, int64(i.Quantity))
+	return b
+}
+
+func (i *OrderCreatedItem) unmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			i.ProductID = domain.ID(f.Int64())
+		case 2:
+			i.Quantity = int32(f.Int64())
+		}
+		return nil
+	})
+}
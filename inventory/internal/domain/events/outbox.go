@@ -0,0 +1,34 @@
+package events
+
+import "time"
+
+// Event types consumed from orders and payments and published to the
+// inventory topic. Payment results are recognised by their payload, as
+// their type is configured in payments.
+const (
+	TypeOrderCreated     = "order created"
+	TypeOrderCancelled   = "order cancelled"
+	TypeOrderExpired     = "order expired"
+	TypePaymentRequested = "payment requested"
+	TypePaymentCancelled = "payment cancelled"
+	TypeRefundSucceeded  = "refund succeeded"
+	TypeRefundFailed     = "refund failed"
+	TypeStockReserved    = "stock reserved"
+	TypeStockRejected    = "stock rejected"
+
+	// TypePaymentStatus is the default type of payment results published
+	// by payments.
+	TypePaymentStatus = "event-status"
+)
+
+// Outbox is a row of the events table as handed to the sender.
+type Outbox struct {
+	EventID     int64
+	EventType   string
+	AggregateID int64
+	Payload     []byte
+
+	CreatedAt     time.Time
+	CorrelationID string
+	CausationID   string
+}
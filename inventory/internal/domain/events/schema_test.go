@@ -0,0 +1,41 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
+)
+
+const registryDir = "../../../../schemas/events"
+
+func TestSchema_Payloads(t *testing.T) {
+	r, err := schema.Load(registryDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	reserved, _ := json.Marshal(StockReserved{EventID: 1, OrderID: 2, UserID: 3, ReservedAt: time.Now()})
+	created, _ := json.Marshal(OrderCreated{
+		EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100, Currency: "RUB", CreatedAt: time.Now(),
+		Items: []OrderCreatedItem{{ProductID: 10, Quantity: 1}},
+	})
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   []byte
+	}{
+		{"published stock reserved", TypeStockReserved, reserved},
+		{"consumed order created", TypeOrderCreated, created},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Validate(tt.eventType, SchemaVersion(tt.eventType), tt.payload); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+		})
+	}
+}
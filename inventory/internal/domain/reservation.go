@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+type ID int64
+
+type ReservationStatus string
+
+// A reservation is recorded once per order and never reserved again, which
+// makes redelivered and late events harmless. Only a reserved reservation
+// holds stock; releasing it returns the stock and keeps the row.
+const (
+	ReservationReserved ReservationStatus = "reserved"
+	ReservationRejected ReservationStatus = "rejected"
+	ReservationReleased ReservationStatus = "released"
+)
+
+// Rejection reasons reported in StockRejected.
+const (
+	ReasonUnknownProduct    = "unknown product"
+	ReasonInsufficientStock = "insufficient stock"
+)
+
+type Item struct {
+	ProductID ID
+	Quantity  int32
+}
+
+type Reservation struct {
+	OrderID ID
+	UserID  ID
+	Status  ReservationStatus
+	Items   []Item
+	// ProductID and Reason tell why a rejected reservation was rejected.
+	ProductID ID
+	Reason    string
+}
+
+// NewReservation validates the items of an order and merges repeated
+// products, ordered by product id so concurrent reservations lock stock rows
+// in the same order.
+func NewReservation(orderID, userID int64, items []Item) (*Reservation, error) {
+	const op = "domain.Reservation.New"
+
+	if orderID <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidOrderID)
+	}
+	if userID <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUserID)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidItems)
+	}
+
+	quantities := make(map[ID]int64, len(items))
+	for _, item := range items {
+		if item.ProductID <= 0 {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidProductID)
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidQuantity)
+		}
+		quantities[item.ProductID] += int64(item.Quantity)
+	}
+
+	merged := make([]Item, 0, len(quantities))
+	for productID, quantity := range quantities {
+		if quantity > math.MaxInt32 {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidQuantity)
+		}
+		merged = append(merged, Item{ProductID: productID, Quantity: int32(quantity)})
+	}
+	slices.SortFunc(merged, func(a, b Item) int { return cmp.Compare(a.ProductID, b.ProductID) })
+
+	return &Reservation{
+		OrderID: ID(orderID),
+		UserID:  ID(userID),
+		Status:  ReservationReserved,
+		Items:   merged,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNewReservation(t *testing.T) {
+	tests := []struct {
+		name      string
+		orderID   int64
+		userID    int64
+		items     []Item
+		wantItems []Item
+		wantErr   error
+	}{
+		{
+			name:      "merges and sorts items",
+			orderID:   1,
+			userID:    2,
+			items:     []Item{{ProductID: 11, Quantity: 1}, {ProductID: 10, Quantity: 2}, {ProductID: 11, Quantity: 3}},
+			wantItems: []Item{{ProductID: 10, Quantity: 2}, {ProductID: 11, Quantity: 4}},
+		},
+		{
+			name:    "invalid order id",
+			userID:  2,
+			items:   []Item{{ProductID: 10, Quantity: 1}},
+			wantErr: ErrInvalidOrderID,
+		},
+		{
+			name:    "invalid user id",
+			orderID: 1,
+			items:   []Item{{ProductID: 10, Quantity: 1}},
+			wantErr: ErrInvalidUserID,
+		},
+		{
+			name:    "no items",
+			orderID: 1,
+			userID:  2,
+			wantErr: ErrInvalidItems,
+		},
+		{
+			name:    "invalid product id",
+			orderID: 1,
+			userID:  2,
+			items:   []Item{{ProductID: 0, Quantity: 1}},
+			wantErr: ErrInvalidProductID,
+		},
+		{
+			name:    "invalid quantity",
+			orderID: 1,
+			userID:  2,
+			items:   []Item{{ProductID: 10, Quantity: 0}},
+			wantErr: ErrInvalidQuantity,
+		},
+		{
+			name:    "merged quantity overflows",
+			orderID: 1,
+			userID:  2,
+			items:   []Item{{ProductID: 10, Quantity: math.MaxInt32}, {ProductID: 10, Quantity: 1}},
+			wantErr: ErrInvalidQuantity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewReservation(tt.orderID, tt.userID, tt.items)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Status != ReservationReserved || !slices.Equal(got.Items, tt.wantItems) {
+				t.Fatalf("reservation: got %+v, want items %+v", got, tt.wantItems)
+			}
+		})
+	}
+}
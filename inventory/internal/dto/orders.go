@@ -0,0 +1,21 @@
+package dto
+
+// ReserveStock asks to reserve the items of a newly created order.
+type ReserveStock struct {
+	OrderID int64
+	UserID  int64
+	Items   []Item
+}
+
+type Item struct {
+	ProductID int64
+	Quantity  int32
+}
+
+// ReleaseStock asks to return the stock of an order that will not be paid:
+// it was cancelled or its payment failed.
+type ReleaseStock struct {
+	OrderID int64
+	UserID  int64
+	Reason  string
+}
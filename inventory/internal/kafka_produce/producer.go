@@ -0,0 +1,135 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const flushTimeoutMs = 5000
+
+type Producer struct {
+	producer *kafka.Producer
+}
+
+func NewProducer(address []string) (*Producer, error) {
+	const op = "kafka_produce.NewProducer"
+	conf := &kafka.ConfigMap{
+		"bootstrap.servers":   strings.Join(address, ","),
+		"enable.idempotence":  true,
+		"retries":             10,
+		"request.timeout.ms":  15000,
+		"delivery.timeout.ms": 60000,
+	}
+
+	p, err := kafka.NewProducer(conf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Producer{producer: p}, nil
+}
+
+// Produce publishes message to topic. Messages sharing a key land on the same
+// partition, so consumers see them in publish order.
+func (p *Producer) Produce(ctx context.Context, key, message []byte, topic string) error {
+	const op = "kafka_produce.Produce"
+	kafkaMsg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny},
+		Key:   key,
+		Value: message,
+	}
+
+	if err := p.ProduceMessage(ctx, kafkaMsg); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ProduceMessage publishes a prepared message, keeping its headers, and
+// waits for the delivery report.
+func (p *Producer) ProduceMessage(ctx context.Context, kafkaMsg *kafka.Message) error {
+	const op = "kafka_produce.ProduceMessage"
+
+	kafkaChan := make(chan kafka.Event, 1)
+	if err := p.producer.Produce(kafkaMsg, kafkaChan); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", op, ctx.Err())
+	case ans := <-kafkaChan:
+		switch ev := ans.(type) {
+		case *kafka.Message:
+			if err := ev.TopicPartition.Error; err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			return nil
+		default:
+			return fmt.Errorf("%s: unknown event type: %T", op, ans)
+		}
+	}
+}
+
+// ProduceBatch enqueues all messages at once and collects their delivery
+// reports from a shared channel. errs[i] is the outcome of messages[i].
+func (p *Producer) ProduceBatch(ctx context.Context, messages []*kafka.Message) []error {
+	const op = "kafka_produce.ProduceBatch"
+
+	errs := make([]error, len(messages))
+	done := make([]bool, len(messages))
+	delivery := make(chan kafka.Event, len(messages))
+
+	pending := 0
+	for i, msg := range messages {
+		msg.Opaque = i
+		if err := p.producer.Produce(msg, delivery); err != nil {
+			errs[i] = fmt.Errorf("%s: %w", op, err)
+			done[i] = true
+			continue
+		}
+		pending++
+	}
+
+	for pending > 0 {
+		select {
+		case <-ctx.Done():
+			for i := range messages {
+				if !done[i] {
+					errs[i] = fmt.Errorf("%s: %w", op, ctx.Err())
+				}
+			}
+			return errs
+		case ans := <-delivery:
+			ev, ok := ans.(*kafka.Message)
+			if !ok {
+				continue
+			}
+			i, ok := ev.Opaque.(int)
+			if !ok || i < 0 || i >= len(messages) || done[i] {
+				continue
+			}
+			if err := ev.TopicPartition.Error; err != nil {
+				errs[i] = fmt.Errorf("%s: %w", op, err)
+			}
+			done[i] = true
+			pending--
+		}
+	}
+
+	return errs
+}
+
+func (p *Producer) Close() error {
+	remaining := p.producer.Flush(flushTimeoutMs)
+	p.producer.Close()
+	if remaining > 0 {
+		return fmt.Errorf("kafka producer: %d messages not delivered on close", remaining)
+	}
+	return nil
+}
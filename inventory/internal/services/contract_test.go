@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+)
+
+func TestService_StockResultContracts(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  int32
+		eventType string
+	}{
+		{name: "reserved", quantity: 1, eventType: events.TypeStockReserved},
+		{name: "rejected", quantity: 100, eventType: events.TypeStockRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &txMock{stock: map[domain.ID]int64{10: 5}}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(&storageMock{tx: tx}, log)
+
+			err := svc.ReserveStock(context.Background(), dto.ReserveStock{
+				OrderID: 42,
+				UserID:  7,
+				Items:   []dto.Item{{ProductID: 10, Quantity: tt.quantity}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			contract.Verify(t, "../../../contracts", "inventory", tt.eventType, tx.savedPayload)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type Service struct {
+	repo postgres.Repository
+	log  *slog.Logger
+}
+
+func New(repo postgres.Repository, log *slog.Logger) *Service {
+	return &Service{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// ReserveStock reserves every item of an order or none of them, and writes
+// StockReserved or StockRejected to the outbox. An order is reserved at most
+// once: a repeated OrderCreated, which the orders saga sends when the reply
+// is late, gets the recorded outcome again, and orders already released are
+// skipped.
+func (s *Service) ReserveStock(ctx context.Context, input dto.ReserveStock) error {
+	const op = "services.ReserveStock"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("order_id", input.OrderID),
+		slog.Int64("user_id", input.UserID),
+	)
+
+	items := make([]domain.Item, 0, len(input.Items))
+	for _, item := range input.Items {
+		items = append(items, domain.Item{ProductID: domain.ID(item.ProductID), Quantity: item.Quantity})
+	}
+	reservation, err := domain.NewReservation(input.OrderID, input.UserID, items)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		inserted, err := tx.InsertReservation(ctx, *reservation)
+		if err != nil {
+			log.Error("insert reservation failed", slog.Any("err", err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !inserted {
+			if err := replayOutcome(ctx, tx, input.OrderID, log); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			return nil
+		}
+
+		productIDs := make([]domain.ID, 0, len(reservation.Items))
+		for _, item := range reservation.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		available, err := tx.LockStock(ctx, productIDs)
+		if err != nil {
+			log.Error("lock stock failed", slog.Any("err", err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if productID, reason, ok := shortage(reservation.Items, available); !ok {
+			if err := tx.RejectReservation(ctx, input.OrderID, productID, reason); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			if err := saveEvent(ctx, tx, events.TypeStockRejected, input.OrderID, events.StockRejected{
+				OrderID:   reservation.OrderID,
+				UserID:    reservation.UserID,
+				ProductID: productID,
+				Reason:    reason,
+			}); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			log.Info("stock rejected", slog.Int64("product_id", int64(productID)), slog.String("reason", reason))
+			return nil
+		}
+
+		if err := tx.TakeStock(ctx, reservation.Items); err != nil {
+			log.Error("take stock failed", slog.Any("err", err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if err := saveEvent(ctx, tx, events.TypeStockReserved, input.OrderID, events.StockReserved{
+			OrderID:    reservation.OrderID,
+			UserID:     reservation.UserID,
+			ReservedAt: time.Now().UTC(),
+		}); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("stock reserved", slog.Int("items", len(reservation.Items)))
+		return nil
+	})
+}
+
+// replayOutcome writes the reply recorded for an order that already has a
+// reservation. A released order gets none: its saga is over.
+func replayOutcome(ctx context.Context, tx postgres.TxRepository, orderID int64, log *slog.Logger) error {
+	const op = "services.replayOutcome"
+
+	reservation, err := tx.GetReservationForUpdate(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch reservation.Status {
+	case domain.ReservationReserved:
+		err = saveEvent(ctx, tx, events.TypeStockReserved, orderID, events.StockReserved{
+			OrderID:    reservation.OrderID,
+			UserID:     reservation.UserID,
+			ReservedAt: time.Now().UTC(),
+		})
+	case domain.ReservationRejected:
+		err = saveEvent(ctx, tx, events.TypeStockRejected, orderID, events.StockRejected{
+			OrderID:   reservation.OrderID,
+			UserID:    reservation.UserID,
+			ProductID: reservation.ProductID,
+			Reason:    reservation.Reason,
+		})
+	default:
+		log.Debug("order already released, skipped")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("reservation outcome replayed", slog.String("status", string(reservation.Status)))
+	return nil
+}
+
+// shortage finds the first item that cannot be reserved.
+func shortage(items []domain.Item, available map[domain.ID]int64) (domain.ID, string, bool) {
+	for _, item := range items {
+		count, known := available[item.ProductID]
+		if !known {
+			return item.ProductID, domain.ReasonUnknownProduct, false
+		}
+		if count < int64(item.Quantity) {
+			return item.ProductID, domain.ReasonInsufficientStock, false
+		}
+	}
+	return 0, "", true
+}
+
+// ReleaseStock returns the stock held for an order. Releasing twice, or an
+// order that was rejected, changes nothing. An order not seen yet is recorded
+// as released so its OrderCreated, if it ever arrives, reserves nothing.
+func (s *Service) ReleaseStock(ctx context.Context, input dto.ReleaseStock) error {
+	const op = "services.ReleaseStock"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("order_id", input.OrderID),
+		slog.String("reason", input.Reason),
+	)
+
+	if input.OrderID <= 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidOrderID)
+	}
+
+	return s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		reservation, err := tx.GetReservationForUpdate(ctx, input.OrderID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := tx.InsertReservation(ctx, domain.Reservation{
+				OrderID: domain.ID(input.OrderID),
+				UserID:  domain.ID(input.UserID),
+				Status:  domain.ReservationReleased,
+			}); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			log.Info("order released before it was reserved")
+			return nil
+		}
+		if err != nil {
+			log.Error("get reservation failed", slog.Any("err", err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if reservation.Status != domain.ReservationReserved {
+			log.Debug("nothing to release", slog.String("status", string(reservation.Status)))
+			return nil
+		}
+
+		if err := tx.ReturnStock(ctx, reservation.Items); err != nil {
+			log.Error("return stock failed", slog.Any("err", err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if err := tx.UpdateReservationStatus(ctx, input.OrderID, domain.ReservationReleased); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("stock released", slog.Int("items", len(reservation.Items)))
+		return nil
+	})
+}
+
+// saveEvent writes event to the outbox; the sender fills in event_id on
+// publish.
+func saveEvent(ctx context.Context, tx postgres.TxRepository, eventType string, orderID int64, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return tx.SaveEvent(ctx, eventType, payload, orderID)
+}
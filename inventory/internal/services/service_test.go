@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestService_ReserveStock(t *testing.T) {
+	errDB := errors.New("db")
+	stock := map[domain.ID]int64{10: 5, 11: 1}
+
+	tests := []struct {
+		name       string
+		input      dto.ReserveStock
+		existing   *domain.Reservation
+		lockErr    error
+		wantErrIs  error
+		wantStatus domain.ReservationStatus
+		wantTaken  []domain.Item
+		wantEvents []string
+		wantReason string
+	}{
+		{
+			name: "reserved",
+			input: dto.ReserveStock{OrderID: 1, UserID: 2, Items: []dto.Item{
+				{ProductID: 11, Quantity: 1}, {ProductID: 10, Quantity: 2}, {ProductID: 10, Quantity: 3},
+			}},
+			wantTaken:  []domain.Item{{ProductID: 10, Quantity: 5}, {ProductID: 11, Quantity: 1}},
+			wantEvents: []string{events.TypeStockReserved},
+		},
+		{
+			name:       "insufficient stock",
+			input:      dto.ReserveStock{OrderID: 1, UserID: 2, Items: []dto.Item{{ProductID: 10, Quantity: 6}}},
+			wantStatus: domain.ReservationRejected,
+			wantEvents: []string{events.TypeStockRejected},
+			wantReason: domain.ReasonInsufficientStock,
+		},
+		{
+			name:       "unknown product",
+			input:      dto.ReserveStock{OrderID: 1, UserID: 2, Items: []dto.Item{{ProductID: 99, Quantity: 1}}},
+			wantStatus: domain.ReservationRejected,
+			wantEvents: []string{events.TypeStockRejected},
+			wantReason: domain.ReasonUnknownProduct,
+		},
+		{
+			name:       "already reserved",
+			input:      dto.ReserveStock{OrderID: 1, UserID: 2, Items: []dto.Item{{ProductID: 10, Quantity: 1}}},
+			existing:   &domain.Reservation{OrderID: 1, UserID: 2, Status: domain.ReservationReserved},
+			wantEvents: []string{events.TypeStockReserved},
+		},
+		{
+			name:  "already rejected",
+			input: dto.ReserveStock{OrderID: 1, UserID: 2, Items: []dto.Item{{ProductID: 10, Quantity: 9}}},
+			existing: &domain.Reservation{
+				OrderID: 1, UserID: 2, Status: domain.ReservationRejected,
+				ProductID: 10, Reason: domain.ReasonInsufficientStock,
+			},
+			wantEvents: []string{events.TypeStockRejected},
+			wantReason: domain.ReasonInsufficientStock,
+		},
+		{
+			name:     "released before created",
+			input:    dto.ReserveStock{OrderID: 1, UserID: 2, Items: []dto.Item{{ProductID: 10, Quantity: 1}}},
+			existing: &domain.Reservation{OrderID: 1, UserID: 2, Status: domain.ReservationReleased},
+		},
+		{
+			name:      "no items",
+			input:     dto.ReserveStock{OrderID: 1, UserID: 2},
+			wantErrIs: domain.ErrInvalidItems,
+		},
+		{
+			name:      "repo error",
+			input:     dto.ReserveStock{OrderID: 1, UserID: 2, Items: []dto.Item{{ProductID: 10, Quantity: 1}}},
+			lockErr:   errDB,
+			wantErrIs: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &txMock{existing: tt.existing, stock: stock, lockErr: tt.lockErr}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(&storageMock{tx: tx}, log)
+
+			err := svc.ReserveStock(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if tt.wantErrIs != nil {
+				return
+			}
+
+			if tx.updatedStatus != tt.wantStatus {
+				t.Fatalf("status: got %q, want %q", tx.updatedStatus, tt.wantStatus)
+			}
+			if !slices.Equal(tx.taken, tt.wantTaken) {
+				t.Fatalf("taken: got %v, want %v", tx.taken, tt.wantTaken)
+			}
+			if !slices.Equal(tx.savedTypes, tt.wantEvents) {
+				t.Fatalf("saved events: got %v, want %v", tx.savedTypes, tt.wantEvents)
+			}
+			if tt.wantReason != "" {
+				var rejected events.StockRejected
+				if err := json.Unmarshal(tx.savedPayload, &rejected); err != nil {
+					t.Fatalf("decode StockRejected: %v", err)
+				}
+				if rejected.Reason != tt.wantReason || rejected.ProductID == 0 {
+					t.Fatalf("StockRejected: got product %d reason %q, want reason %q",
+						rejected.ProductID, rejected.Reason, tt.wantReason)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ReleaseStock(t *testing.T) {
+	items := []domain.Item{{ProductID: 10, Quantity: 2}}
+
+	tests := []struct {
+		name         string
+		input        dto.ReleaseStock
+		existing     *domain.Reservation
+		wantErrIs    error
+		wantReturned []domain.Item
+		wantStatus   domain.ReservationStatus
+		wantInserted bool
+	}{
+		{
+			name:         "reserved",
+			input:        dto.ReleaseStock{OrderID: 1, UserID: 2, Reason: "payment failed"},
+			existing:     &domain.Reservation{OrderID: 1, UserID: 2, Status: domain.ReservationReserved, Items: items},
+			wantReturned: items,
+			wantStatus:   domain.ReservationReleased,
+		},
+		{
+			name:     "already released",
+			input:    dto.ReleaseStock{OrderID: 1, UserID: 2},
+			existing: &domain.Reservation{OrderID: 1, UserID: 2, Status: domain.ReservationReleased, Items: items},
+		},
+		{
+			name:     "rejected",
+			input:    dto.ReleaseStock{OrderID: 1, UserID: 2},
+			existing: &domain.Reservation{OrderID: 1, UserID: 2, Status: domain.ReservationRejected, Items: items},
+		},
+		{
+			name:         "not reserved yet",
+			input:        dto.ReleaseStock{OrderID: 1, UserID: 2},
+			wantInserted: true,
+		},
+		{
+			name:      "invalid order id",
+			input:     dto.ReleaseStock{UserID: 2},
+			wantErrIs: domain.ErrInvalidOrderID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &txMock{existing: tt.existing}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(&storageMock{tx: tx}, log)
+
+			err := svc.ReleaseStock(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if tt.wantErrIs != nil {
+				return
+			}
+
+			if !slices.Equal(tx.returned, tt.wantReturned) {
+				t.Fatalf("returned: got %v, want %v", tx.returned, tt.wantReturned)
+			}
+			if tx.updatedStatus != tt.wantStatus {
+				t.Fatalf("status: got %q, want %q", tx.updatedStatus, tt.wantStatus)
+			}
+			if tt.wantInserted != (len(tx.inserted) == 1) {
+				t.Fatalf("inserted: got %v, want tombstone %v", tx.inserted, tt.wantInserted)
+			}
+			if tt.wantInserted && tx.inserted[0].Status != domain.ReservationReleased {
+				t.Fatalf("tombstone status: got %q", tx.inserted[0].Status)
+			}
+			if len(tx.savedTypes) != 0 {
+				t.Fatalf("saved events: got %v, want none", tx.savedTypes)
+			}
+		})
+	}
+}
+
+type storageMock struct {
+	tx *txMock
+}
+
+func (m *storageMock) RunInTx(ctx context.Context, fn func(tx postgres.TxRepository) error) error {
+	return fn(m.tx)
+}
+
+func (m *storageMock) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	return nil, nil
+}
+
+func (m *storageMock) MarkSent(ctx context.Context, ids []int64) error {
+	return nil
+}
+
+func (m *storageMock) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
+func (m *storageMock) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *storageMock) Close() error {
+	return nil
+}
+
+type txMock struct {
+	existing *domain.Reservation
+	stock    map[domain.ID]int64
+	lockErr  error
+
+	inserted      []domain.Reservation
+	updatedStatus domain.ReservationStatus
+	taken         []domain.Item
+	returned      []domain.Item
+	savedTypes    []string
+	savedPayload  []byte
+}
+
+func (m *txMock) InsertReservation(ctx context.Context, reservation domain.Reservation) (bool, error) {
+	if m.existing != nil {
+		return false, nil
+	}
+	m.inserted = append(m.inserted, reservation)
+	return true, nil
+}
+
+func (m *txMock) GetReservationForUpdate(ctx context.Context, orderID int64) (domain.Reservation, error) {
+	if m.existing == nil {
+		return domain.Reservation{}, pgx.ErrNoRows
+	}
+	return *m.existing, nil
+}
+
+func (m *txMock) UpdateReservationStatus(ctx context.Context, orderID int64, status domain.ReservationStatus) error {
+	m.updatedStatus = status
+	return nil
+}
+
+func (m *txMock) RejectReservation(ctx context.Context, orderID int64, productID domain.ID, reason string) error {
+	m.updatedStatus = domain.ReservationRejected
+	return nil
+}
+
+func (m *txMock) LockStock(ctx context.Context, productIDs []domain.ID) (map[domain.ID]int64, error) {
+	if m.lockErr != nil {
+		return nil, m.lockErr
+	}
+	available := make(map[domain.ID]int64, len(productIDs))
+	for _, id := range productIDs {
+		if count, ok := m.stock[id]; ok {
+			available[id] = count
+		}
+	}
+	return available, nil
+}
+
+func (m *txMock) TakeStock(ctx context.Context, items []domain.Item) error {
+	m.taken = append(m.taken, items...)
+	return nil
+}
+
+func (m *txMock) ReturnStock(ctx context.Context, items []domain.Item) error {
+	m.returned = append(m.returned, items...)
+	return nil
+}
+
+func (m *txMock) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
+	m.savedTypes = append(m.savedTypes, eventType)
+	m.savedPayload = payload
+	return nil
+}
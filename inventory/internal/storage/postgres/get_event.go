@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+)
+
+// GetNewEvents locks up to limit unsent events and returns them ordered by id.
+// Rows locked by another sender are skipped; a stale lock expires after a minute.
+// Only the oldest unsent event of each aggregate is eligible, so a newer event
+// is never published while an older one for the same aggregate is in flight.
+// Dead-lettered events (see MarkFailed) are neither returned nor hold back the
+// events after them.
+func (s *Storage) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	const op = "storage.postgres.GetNewEvents"
+
+	const query = `
+		WITH batch AS (
+			SELECT e.id
+			FROM events e
+			WHERE e.sent_at IS NULL
+			  AND e.failed_at IS NULL
+			  AND (e.locked_at IS NULL OR e.locked_at < now() - interval '1 minutes')
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM events older
+				WHERE older.aggregate_id = e.aggregate_id
+				  AND older.sent_at IS NULL
+				  AND older.failed_at IS NULL
+				  AND older.id < e.id
+			  )
+			ORDER BY e.id
+			LIMIT $2
+			FOR UPDATE OF e SKIP LOCKED
+		)
+		UPDATE events e
+		SET locked_at = $1
+		FROM batch
+		WHERE e.id = batch.id
+		RETURNING e.id, e.event_type, e.aggregate_id, e.payload,
+			COALESCE(e.created_at, now()), COALESCE(e.correlation_id, ''), COALESCE(e.causation_id, '')
+	`
+
+	rows, err := s.db.Query(ctx, query, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var batch []events.Outbox
+	for rows.Next() {
+		var event events.Outbox
+		if err := rows.Scan(
+			&event.EventID, &event.EventType, &event.AggregateID, &event.Payload,
+			&event.CreatedAt, &event.CorrelationID, &event.CausationID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		batch = append(batch, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	slices.SortFunc(batch, func(a, b events.Outbox) int {
+		return cmp.Compare(a.EventID, b.EventID)
+	})
+
+	return batch, nil
+}
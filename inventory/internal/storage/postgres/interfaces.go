@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain/events"
+)
+
+type TxRepository interface {
+	InsertReservation(ctx context.Context, reservation domain.Reservation) (bool, error)
+	GetReservationForUpdate(ctx context.Context, orderID int64) (domain.Reservation, error)
+	UpdateReservationStatus(ctx context.Context, orderID int64, status domain.ReservationStatus) error
+	RejectReservation(ctx context.Context, orderID int64, productID domain.ID, reason string) error
+	LockStock(ctx context.Context, productIDs []domain.ID) (map[domain.ID]int64, error)
+	TakeStock(ctx context.Context, items []domain.Item) error
+	ReturnStock(ctx context.Context, items []domain.Item) error
+	SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error
+}
+
+type Repository interface {
+	RunInTx(ctx context.Context, fn func(tx TxRepository) error) error
+	GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (dead bool, err error)
+	Ping(ctx context.Context) error
+	Close() error
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EventsChannel is the NOTIFY channel signalled whenever an outbox event is committed.
+const EventsChannel = "outbox_events"
+
+// ListenEvents opens a dedicated connection outside the pool, LISTENs on
+// EventsChannel and signals wake once the subscription is up and then for
+// every notification. It returns nil when ctx is done and an error when the
+// connection breaks.
+func (s *Storage) ListenEvents(ctx context.Context, wake chan<- struct{}) error {
+	const op = "storage.postgres.ListenEvents"
+
+	conn, err := pgx.ConnectConfig(ctx, s.db.Config().ConnConfig)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+EventsChannel); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// events committed before LISTEN took effect would otherwise wait for the ticker
+	signal(wake)
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		signal(wake)
+	}
+}
+
+func signal(wake chan<- struct{}) {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const rowsInserted = 1
+
+type TxStorage struct {
+	tx pgx.Tx
+}
+
+func (s *Storage) RunInTx(ctx context.Context, fn func(tx TxRepository) error) error {
+	const op = "storage.postgres.RunInTx"
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		txStorage := &TxStorage{tx: tx}
+		if err := fn(txStorage); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// InsertReservation records the reservation of an order with its items. It
+// reports false, writing nothing, when the order already has one.
+func (s *TxStorage) InsertReservation(ctx context.Context, reservation domain.Reservation) (bool, error) {
+	const op = "storage.postgres.InsertReservation"
+
+	const insertReservation = `
+		INSERT INTO reservations (order_id, user_id, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (order_id) DO NOTHING;
+	`
+
+	const insertItem = `
+		INSERT INTO reservation_items (order_id, product_id, quantity)
+		VALUES ($1, $2, $3);
+	`
+
+	res, err := s.tx.Exec(ctx, insertReservation, reservation.OrderID, reservation.UserID, reservation.Status)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() != rowsInserted {
+		return false, nil
+	}
+
+	for _, item := range reservation.Items {
+		if _, err := s.tx.Exec(ctx, insertItem, reservation.OrderID, item.ProductID, item.Quantity); err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return true, nil
+}
+
+// GetReservationForUpdate locks the reservation of an order; pgx.ErrNoRows
+// means there is none.
+func (s *TxStorage) GetReservationForUpdate(ctx context.Context, orderID int64) (domain.Reservation, error) {
+	const op = "storage.postgres.GetReservationForUpdate"
+
+	const reservationQuery = `
+		SELECT order_id, user_id, status, COALESCE(rejected_product_id, 0), COALESCE(reject_reason, '')
+		FROM reservations
+		WHERE order_id = $1
+		FOR UPDATE;
+	`
+
+	const itemsQuery = `
+		SELECT product_id, quantity
+		FROM reservation_items
+		WHERE order_id = $1
+		ORDER BY product_id;
+	`
+
+	var reservation domain.Reservation
+	if err := s.tx.QueryRow(ctx, reservationQuery, orderID).Scan(
+		&reservation.OrderID, &reservation.UserID, &reservation.Status,
+		&reservation.ProductID, &reservation.Reason); err != nil {
+		return domain.Reservation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.tx.Query(ctx, itemsQuery, orderID)
+	if err != nil {
+		return domain.Reservation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.Item
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			return domain.Reservation{}, fmt.Errorf("%s: %w", op, err)
+		}
+		reservation.Items = append(reservation.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Reservation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reservation, nil
+}
+
+func (s *TxStorage) UpdateReservationStatus(ctx context.Context, orderID int64, status domain.ReservationStatus) error {
+	const op = "storage.postgres.UpdateReservationStatus"
+
+	const query = `
+		UPDATE reservations
+		SET status = $1,
+		    updated_at = now()
+		WHERE order_id = $2;
+	`
+
+	if _, err := s.tx.Exec(ctx, query, status, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// RejectReservation marks the reservation of an order rejected and keeps the
+// product and reason for replaying StockRejected.
+func (s *TxStorage) RejectReservation(ctx context.Context, orderID int64, productID domain.ID, reason string) error {
+	const op = "storage.postgres.RejectReservation"
+
+	const query = `
+		UPDATE reservations
+		SET status = $1,
+		    rejected_product_id = $2,
+		    reject_reason = $3,
+		    updated_at = now()
+		WHERE order_id = $4;
+	`
+
+	if _, err := s.tx.Exec(ctx, query, domain.ReservationRejected, productID, reason, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+)
+
+// LockStock locks the stock rows of productIDs in id order and returns what
+// is available; unknown products are missing from the map.
+func (s *TxStorage) LockStock(ctx context.Context, productIDs []domain.ID) (map[domain.ID]int64, error) {
+	const op = "storage.postgres.LockStock"
+
+	const query = `
+		SELECT product_id, available
+		FROM stock
+		WHERE product_id = ANY($1)
+		ORDER BY product_id
+		FOR UPDATE;
+	`
+
+	ids := make([]int64, 0, len(productIDs))
+	for _, id := range productIDs {
+		ids = append(ids, int64(id))
+	}
+
+	rows, err := s.tx.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	available := make(map[domain.ID]int64, len(productIDs))
+	for rows.Next() {
+		var (
+			id    domain.ID
+			count int64
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		available[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return available, nil
+}
+
+// TakeStock subtracts the items from the stock locked by LockStock.
+func (s *TxStorage) TakeStock(ctx context.Context, items []domain.Item) error {
+	const op = "storage.postgres.TakeStock"
+
+	if err := s.adjustStock(ctx, items, -1); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ReturnStock puts the items of a released reservation back.
+func (s *TxStorage) ReturnStock(ctx context.Context, items []domain.Item) error {
+	const op = "storage.postgres.ReturnStock"
+
+	if err := s.adjustStock(ctx, items, 1); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *TxStorage) adjustStock(ctx context.Context, items []domain.Item, sign int64) error {
+	const query = `
+		UPDATE stock
+		SET available = available + $1,
+		    updated_at = now()
+		WHERE product_id = $2;
+	`
+
+	for _, item := range items {
+		if _, err := s.tx.Exec(ctx, query, sign*int64(item.Quantity), item.ProductID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
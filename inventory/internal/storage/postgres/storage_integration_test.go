@@ -0,0 +1,238 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/config"
+	"github.com/ChernykhITMO/order-processing-platform/inventory/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+func getInventoryDSN(t *testing.T) string {
+	_ = godotenv.Load("../../../.env")
+	_ = godotenv.Load("../../../inventory/.env")
+	dsn := os.Getenv("INVENTORY_PG_DSN_TEST")
+	if dsn == "" {
+		t.Skip("INVENTORY_PG_DSN_TEST is not set")
+	}
+	return dsn
+}
+
+func cleanupInventoryTables(t *testing.T, db *pgxpool.Pool) {
+	const query = `TRUNCATE TABLE stock, reservations, reservation_items, events, events_archive RESTART IDENTITY CASCADE`
+	if _, err := db.Exec(context.Background(), query); err != nil {
+		t.Fatalf("db exec: %v", err)
+	}
+}
+
+func TestInventoryStorage_ReserveAndRelease_Integration(t *testing.T) {
+	dsn := getInventoryDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cleanupInventoryTables(t, db)
+	defer cleanupInventoryTables(t, db)
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, `INSERT INTO stock (product_id, available) VALUES (10, 5), (11, 1)`); err != nil {
+		t.Fatalf("seed stock: %v", err)
+	}
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	reservation := domain.Reservation{
+		OrderID: 42,
+		UserID:  7,
+		Status:  domain.ReservationReserved,
+		Items:   []domain.Item{{ProductID: 10, Quantity: 2}, {ProductID: 11, Quantity: 1}},
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		inserted, err := tx.InsertReservation(ctx, reservation)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			t.Fatalf("expected reservation to be inserted")
+		}
+
+		available, err := tx.LockStock(ctx, []domain.ID{10, 11, 99})
+		if err != nil {
+			return err
+		}
+		if len(available) != 2 || available[10] != 5 || available[11] != 1 {
+			t.Fatalf("available: got %v", available)
+		}
+
+		return tx.TakeStock(ctx, reservation.Items)
+	}); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		inserted, err := tx.InsertReservation(ctx, reservation)
+		if err != nil {
+			return err
+		}
+		if inserted {
+			t.Fatalf("expected duplicate reservation to be skipped")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("reserve again: %v", err)
+	}
+
+	assertAvailable(t, db, 10, 3)
+	assertAvailable(t, db, 11, 0)
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		got, err := tx.GetReservationForUpdate(ctx, 42)
+		if err != nil {
+			return err
+		}
+		if got.Status != domain.ReservationReserved || len(got.Items) != 2 {
+			t.Fatalf("reservation: got %+v", got)
+		}
+		if err := tx.ReturnStock(ctx, got.Items); err != nil {
+			return err
+		}
+		return tx.UpdateReservationStatus(ctx, 42, domain.ReservationReleased)
+	}); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	assertAvailable(t, db, 10, 5)
+	assertAvailable(t, db, 11, 1)
+
+	var status string
+	if err := db.QueryRow(ctx, `SELECT status FROM reservations WHERE order_id = 42`).Scan(&status); err != nil {
+		t.Fatalf("select reservation: %v", err)
+	}
+	if status != string(domain.ReservationReleased) {
+		t.Fatalf("status: got %s, want %s", status, domain.ReservationReleased)
+	}
+}
+
+func TestInventoryStorage_StockNeverNegative_Integration(t *testing.T) {
+	dsn := getInventoryDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cleanupInventoryTables(t, db)
+	defer cleanupInventoryTables(t, db)
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, `INSERT INTO stock (product_id, available) VALUES (10, 1)`); err != nil {
+		t.Fatalf("seed stock: %v", err)
+	}
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		return tx.TakeStock(ctx, []domain.Item{{ProductID: 10, Quantity: 2}})
+	})
+	if err == nil {
+		t.Fatalf("expected check violation taking more than available")
+	}
+
+	assertAvailable(t, db, 10, 1)
+}
+
+func TestInventoryStorage_RejectReservation_Integration(t *testing.T) {
+	dsn := getInventoryDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cleanupInventoryTables(t, db)
+	defer cleanupInventoryTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+	reservation := domain.Reservation{
+		OrderID: 43,
+		UserID:  7,
+		Status:  domain.ReservationReserved,
+		Items:   []domain.Item{{ProductID: 12, Quantity: 1}},
+	}
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		if _, err := tx.InsertReservation(ctx, reservation); err != nil {
+			return err
+		}
+		return tx.RejectReservation(ctx, 43, 12, domain.ReasonUnknownProduct)
+	}); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		got, err := tx.GetReservationForUpdate(ctx, 43)
+		if err != nil {
+			return err
+		}
+		if got.Status != domain.ReservationRejected || got.ProductID != 12 || got.Reason != domain.ReasonUnknownProduct {
+			t.Fatalf("reservation: got %+v", got)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("get reservation: %v", err)
+	}
+}
+
+func assertAvailable(t *testing.T, db *pgxpool.Pool, productID int64, want int64) {
+	t.Helper()
+
+	var got int64
+	if err := db.QueryRow(context.Background(), `SELECT available FROM stock WHERE product_id = $1`, productID).Scan(&got); err != nil {
+		t.Fatalf("select stock: %v", err)
+	}
+	if got != want {
+		t.Fatalf("product %d available: got %d, want %d", productID, got, want)
+	}
+}
+
+func configForTest(dsn string) config.DBConfig {
+	return config.DBConfig{
+		DSN:               dsn,
+		MaxConns:          4,
+		MinConns:          1,
+		MaxConnIdleTime:   time.Minute,
+		HealthCheckPeriod: time.Second,
+	}
+}
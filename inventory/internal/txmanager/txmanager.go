@@ -0,0 +1,39 @@
+package txmanager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Manager struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Manager {
+	return &Manager{pool: pool}
+}
+
+func (m *Manager) WithinTransaction(ctx context.Context, fn func(context.Context, pgx.Tx) error) error {
+	const op = "txmanager.WithinTransaction"
+
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS stock
+(
+    product_id BIGINT PRIMARY KEY,
+    available  BIGINT      NOT NULL CHECK (available >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS reservations
+(
+    order_id            BIGINT PRIMARY KEY,
+    user_id             BIGINT      NOT NULL,
+    status              TEXT        NOT NULL
+        CHECK (status IN ('reserved', 'rejected', 'released')),
+    rejected_product_id BIGINT,
+    reject_reason       TEXT,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS reservation_items
+(
+    order_id   BIGINT NOT NULL REFERENCES reservations (order_id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    quantity   INT    NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (order_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS events
+(
+    id             SERIAL PRIMARY KEY,
+    event_type     TEXT   NOT NULL,
+    payload        JSONB  NOT NULL,
+    aggregate_id   BIGINT NOT NULL,
+    created_at     TIMESTAMPTZ DEFAULT NOW(),
+    locked_at      TIMESTAMPTZ DEFAULT NULL,
+    sent_at        TIMESTAMPTZ DEFAULT NULL,
+    correlation_id TEXT        DEFAULT NULL,
+    causation_id   TEXT        DEFAULT NULL,
+    attempts       INT         NOT NULL DEFAULT 0,
+    last_error     TEXT        DEFAULT NULL,
+    failed_at      TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE INDEX idx_events_unsent ON events (sent_at) WHERE sent_at IS NULL;
+CREATE INDEX idx_events_unlocked ON events (locked_at) WHERE locked_at IS NULL;
+CREATE INDEX idx_events_sent ON events (sent_at) WHERE sent_at IS NOT NULL;
+CREATE INDEX idx_events_unsent_aggregate ON events (aggregate_id, id) WHERE sent_at IS NULL;
+CREATE INDEX idx_events_failed ON events (failed_at) WHERE failed_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS events_archive
+(
+    id             BIGINT PRIMARY KEY,
+    event_type     TEXT        NOT NULL,
+    payload        JSONB       NOT NULL,
+    aggregate_id   BIGINT      NOT NULL,
+    created_at     TIMESTAMPTZ,
+    sent_at        TIMESTAMPTZ NOT NULL,
+    correlation_id TEXT        DEFAULT NULL,
+    causation_id   TEXT        DEFAULT NULL,
+    archived_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS events_archive;
+DROP TABLE IF EXISTS events CASCADE;
+DROP TABLE IF EXISTS reservation_items;
+DROP TABLE IF EXISTS reservations;
+DROP TABLE IF EXISTS stock;
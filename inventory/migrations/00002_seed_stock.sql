@@ -0,0 +1,10 @@
+-- +goose Up
+-- Stock for the demo products of the catalog seed.
+INSERT INTO stock (product_id, available)
+VALUES (10, 1000),
+       (11, 100),
+       (12, 0)
+ON CONFLICT (product_id) DO NOTHING;
+
+-- +goose Down
+DELETE FROM stock WHERE product_id IN (10, 11, 12);
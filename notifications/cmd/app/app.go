@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/controller"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/kafka_consume"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/kafka_produce"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/services"
 	redis_storage "github.com/ChernykhITMO/order-processing-platform/notifications/storage/redis"
 )
@@ -15,18 +18,39 @@ import (
 type App struct {
 	log      *slog.Logger
 	consumer *kafka_consume.Consumer
+	producer *kafka_produce.Producer
 	storage  *redis_storage.Storage
 }
 
 func New(log *slog.Logger, cfg config.Config) (*App, error) {
 	const op = "app.New"
 
+	var schemas *schema.Registry
+	if cfg.SchemaDir != "" {
+		var err error
+		schemas, err = schema.Load(cfg.SchemaDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	storage := redis_storage.New(cfg)
 	uc := services.New(storage, log)
-	sender := controller.NewSender(uc, log)
+	sender := controller.NewSender(uc, log, schemas)
+
+	producer, err := kafka_produce.NewProducer(cfg.KafkaBrokers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	dlq := deadletter.Config{
+		Topic:       cfg.DLQTopic,
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     cfg.RetryBackoff,
+	}
 
 	consumer, err := kafka_consume.NewConsumer(
-		cfg.KafkaBrokers, sender,
+		cfg.KafkaBrokers, sender, producer, dlq,
 		cfg.TopicStatus, cfg.ConsumerGroup,
 		cfg.SessionTimeout, cfg.ReadTimeout, log,
 	)
@@ -38,6 +62,7 @@ func New(log *slog.Logger, cfg config.Config) (*App, error) {
 	return &App{
 		log:      log,
 		consumer: consumer,
+		producer: producer,
 		storage:  storage,
 	}, nil
 }
@@ -68,6 +93,9 @@ func (a *App) Run(ctx context.Context) error {
 	if err := a.consumer.Stop(); err != nil {
 		log.Error("consumer stopped", slog.Any("err", err))
 	}
+	if err := a.producer.Close(); err != nil {
+		log.Warn("producer close: pending messages not delivered", slog.Any("err", err))
+	}
 	if err := a.storage.Close(); err != nil {
 		log.Error("storage closed", slog.Any("err", err))
 	}
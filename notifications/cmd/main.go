@@ -11,6 +11,7 @@ import (
 	"github.com/ChernykhITMO/order-processing-platform/notifications/cmd/app"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/health"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/metrics"
 	"github.com/joho/godotenv"
 )
 
@@ -49,10 +50,13 @@ func main() {
 		slog.String("kafka_topic", cfg.TopicStatus),
 		slog.String("kafka_consumer_group", cfg.ConsumerGroup),
 		slog.Duration("session_timeout", cfg.SessionTimeout),
+		slog.String("dlq_topic", cfg.DLQTopic),
 	)
 
 	log.Debug("starting application")
 
+	metrics.Register()
+
 	application, err := app.New(log, cfg)
 	if err != nil {
 		log.Error("application failed", slog.Any("err", err))
@@ -23,6 +23,11 @@ type Config struct {
 	ConsumerGroup  string
 	SessionTimeout time.Duration
 	ReadTimeout    time.Duration
+	DLQTopic       string
+	MaxAttempts    int
+	RetryBackoff   time.Duration
+	// SchemaDir is the event schema registry; validation is off when empty.
+	SchemaDir string
 }
 
 func Load() (Config, error) {
@@ -51,6 +56,11 @@ func Load() (Config, error) {
 		return Config{}, err
 	}
 
+	maxAttempts, err := getEnvIntWithDefault("KAFKA_CONSUMER_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
 		Addr:           redisAddr,
 		DB:             redisDB,
@@ -61,6 +71,10 @@ func Load() (Config, error) {
 		TopicStatus:    topicStatus,
 		ConsumerGroup:  consumerGroup,
 		ReadTimeout:    getEnvDuration("READ_TIMEOUT", time.Second),
+		DLQTopic:       getEnvOrDefault("KAFKA_TOPIC_STATUS_DLQ", topicStatus+".dlq"),
+		MaxAttempts:    maxAttempts,
+		RetryBackoff:   getEnvDuration("KAFKA_CONSUMER_BACKOFF", 500*time.Millisecond),
+		SchemaDir:      getEnvOrDefault("EVENT_SCHEMA_DIR", ""),
 	}, nil
 }
 
@@ -84,6 +98,18 @@ func getEnvInt(key string) (int, error) {
 	return parsed, nil
 }
 
+func getEnvIntWithDefault(key string, def int) (int, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return def, nil
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, errors.New(key + " is invalid int: " + err.Error())
+	}
+	return parsed, nil
+}
+
 func getEnvDuration(key string, def time.Duration) time.Duration {
 	val := os.Getenv(key)
 	if val == "" {
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/services"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+)
+
+const contractsDir = "../../../contracts"
+
+// paymentStatusContract lists the payment status fields notifications relies on.
+var paymentStatusContract = contract.Contract{
+	Consumer:  "notifications",
+	Provider:  "payments",
+	EventType: "event-status",
+	Examples: []contract.Example{
+		{
+			Description: "payment succeeded",
+			Payload:     json.RawMessage(`{"order_id":42,"user_id":7,"order_status":"succeeded"}`),
+		},
+		{
+			Description: "payment failed",
+			Payload:     json.RawMessage(`{"order_id":43,"user_id":7,"order_status":"failed"}`),
+		},
+	},
+}
+
+// paymentCancelledContract lists the payment cancelled fields notifications
+// relies on.
+var paymentCancelledContract = contract.Contract{
+	Consumer:  "notifications",
+	Provider:  "payments",
+	EventType: events.TypePaymentCancelled,
+	Examples: []contract.Example{
+		{
+			Description: "payment voided",
+			Payload:     json.RawMessage(`{"order_id":44,"user_id":7}`),
+		},
+	},
+}
+
+func TestSender_PaymentStatusContract(t *testing.T) {
+	for _, example := range paymentStatusContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			st := &redisMock{}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			sender := NewSender(services.New(st, log), log, nil)
+
+			message, _ := json.Marshal(envelope.Envelope{
+				ID:      "payments:5",
+				Type:    paymentStatusContract.EventType,
+				Version: events.SchemaVersion(paymentStatusContract.EventType),
+				Source:  paymentStatusContract.Provider,
+				Payload: example.Payload,
+			})
+			if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			var want events.Payment
+			_ = json.Unmarshal(example.Payload, &want)
+			if st.saveCalled != 1 || st.savedValue != want {
+				t.Fatalf("saved: calls=%d value=%+v, want %+v", st.saveCalled, st.savedValue, want)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, paymentStatusContract)
+}
+
+func TestSender_PaymentCancelledContract(t *testing.T) {
+	for _, example := range paymentCancelledContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			st := &redisMock{}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			sender := NewSender(services.New(st, log), log, nil)
+
+			message, _ := json.Marshal(envelope.Envelope{
+				ID:      "payments:6",
+				Type:    paymentCancelledContract.EventType,
+				Version: events.SchemaVersion(paymentCancelledContract.EventType),
+				Source:  paymentCancelledContract.Provider,
+				Payload: example.Payload,
+			})
+			if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			var want events.Payment
+			_ = json.Unmarshal(example.Payload, &want)
+			want.OrderStatus = domain.StatusCancelled
+			if st.saveCalled != 1 || st.savedValue != want {
+				t.Fatalf("saved: calls=%d value=%+v, want %+v", st.saveCalled, st.savedValue, want)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, paymentCancelledContract)
+}
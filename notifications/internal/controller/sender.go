@@ -2,37 +2,69 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/domain/events"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/dto"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/mapper"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/services"
 )
 
+// Validator checks a consumed envelope against the schema registry.
+type Validator interface {
+	ValidateEnvelope(env envelope.Envelope) error
+}
+
 type Sender struct {
-	uc  *services.Notification
-	log *slog.Logger
+	uc      *services.Notification
+	log     *slog.Logger
+	schemas Validator
 }
 
-func NewSender(uc *services.Notification, log *slog.Logger) *Sender {
+// NewSender builds the handler of payment status and payment cancelled
+// events. schemas may be nil to skip payload validation.
+func NewSender(uc *services.Notification, log *slog.Logger, schemas Validator) *Sender {
 	return &Sender{
-		uc:  uc,
-		log: log,
+		uc:      uc,
+		log:     log,
+		schemas: schemas,
 	}
 }
 
-func (h *Sender) HandleMessage(parentCtx context.Context, message []byte) error {
+func (h *Sender) HandleMessage(parentCtx context.Context, contentType string, message []byte) error {
 	const op = "controller.HandleMessage"
 	log := h.log.With(slog.String("op", op))
 
 	log.Debug("starting handle message")
 
-	var payment dto.Payment
+	env, err := events.DecodeEnvelope(contentType, message)
+	if err != nil {
+		log.Error("decode envelope failed", slog.Any("err", err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if h.schemas != nil {
+		if err := h.schemas.ValidateEnvelope(env); err != nil {
+			log.Error("validate message failed", slog.Any("err", err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if env.CorrelationID != "" {
+		log = log.With(
+			slog.String("event_id", env.ID),
+			slog.String("correlation_id", env.CorrelationID))
+	}
 
-	if err := json.Unmarshal(message, &payment); err != nil {
+	if env.Type == events.TypeRefundSucceeded || env.Type == events.TypeRefundFailed {
+		log.Debug("refund event skipped", slog.String("type", env.Type))
+		return nil
+	}
+
+	payment, err := decodePayment(env)
+	if err != nil {
 		log.Error("unmarshal failed", slog.Any("err", err))
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -50,3 +82,29 @@ func (h *Sender) HandleMessage(parentCtx context.Context, message []byte) error
 	log.Debug("handle message successful")
 	return nil
 }
+
+// decodePayment maps a payment status or a payment cancellation to the
+// notification it produces.
+func decodePayment(env envelope.Envelope) (dto.Payment, error) {
+	if env.Type == events.TypePaymentCancelled {
+		var event events.PaymentCancelled
+		if err := envelope.UnmarshalPayload(env, &event); err != nil {
+			return dto.Payment{}, err
+		}
+		return dto.Payment{
+			OrderID:     int64(event.OrderID),
+			UserID:      int64(event.UserID),
+			OrderStatus: domain.StatusCancelled,
+		}, nil
+	}
+
+	var event events.Payment
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		return dto.Payment{}, err
+	}
+	return dto.Payment{
+		OrderID:     int64(event.OrderID),
+		UserID:      int64(event.UserID),
+		OrderStatus: string(event.OrderStatus),
+	}, nil
+}
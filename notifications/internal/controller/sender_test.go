@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"testing"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/domain/events"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/dto"
@@ -36,23 +38,154 @@ func TestSender_HandleMessage_InvalidJSON(t *testing.T) {
 	st := &redisMock{}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 	uc := services.New(st, log)
-	sender := NewSender(uc, log)
+	sender := NewSender(uc, log, nil)
 
-	if err := sender.HandleMessage(context.Background(), []byte("{")); err == nil {
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, []byte("{")); err == nil {
 		t.Fatalf("expected error")
 	}
 }
 
+func TestSender_HandleMessage_Envelope(t *testing.T) {
+	st := &redisMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	uc := services.New(st, log)
+	sender := NewSender(uc, log, nil)
+
+	body, _ := json.Marshal(dto.Payment{OrderID: 10, UserID: 20, OrderStatus: domain.StatusSucceeded})
+	message, _ := json.Marshal(envelope.Envelope{
+		ID:            "payments:5",
+		Type:          "event-status",
+		Version:       events.SchemaVersion("event-status"),
+		Source:        "payments",
+		CorrelationID: "order-10",
+		CausationID:   "orders:1",
+		Payload:       body,
+	})
+
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.saveCalled != 1 || st.savedValue.OrderID != 10 {
+		t.Fatalf("unexpected save: calls=%d value=%+v", st.saveCalled, st.savedValue)
+	}
+}
+
+func TestSender_HandleMessage_Protobuf(t *testing.T) {
+	st := &redisMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	uc := services.New(st, log)
+	sender := NewSender(uc, log, nil)
+
+	body := events.Payment{OrderID: 10, UserID: 20, OrderStatus: domain.StatusSucceeded}
+	env := envelope.Envelope{
+		ID:            "payments:5",
+		Type:          "event-status",
+		Version:       events.SchemaVersion("event-status"),
+		Source:        "payments",
+		CorrelationID: "order-10",
+		Payload:       body.MarshalProto(),
+	}
+
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeProtobuf, env.MarshalProto()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.saveCalled != 1 || st.savedValue != body {
+		t.Fatalf("unexpected save: calls=%d value=%+v", st.saveCalled, st.savedValue)
+	}
+}
+
+func TestSender_HandleMessage_PaymentCancelled(t *testing.T) {
+	st := &redisMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	uc := services.New(st, log)
+	sender := NewSender(uc, log, nil)
+
+	body := events.PaymentCancelled{OrderID: 10, UserID: 20, Outcome: "refunded", Amount: 500}
+	env := envelope.Envelope{
+		ID:      "payments:6",
+		Type:    events.TypePaymentCancelled,
+		Version: events.SchemaVersion(events.TypePaymentCancelled),
+		Source:  "payments",
+		Payload: body.MarshalProto(),
+	}
+
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeProtobuf, env.MarshalProto()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := events.Payment{OrderID: 10, UserID: 20, OrderStatus: domain.StatusCancelled}
+	if st.saveCalled != 1 || st.savedKey != "10" || st.savedValue != want {
+		t.Fatalf("unexpected save: calls=%d key=%q value=%+v", st.saveCalled, st.savedKey, st.savedValue)
+	}
+}
+
+func TestSender_HandleMessage_SkipsRefunds(t *testing.T) {
+	st := &redisMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := NewSender(services.New(st, log), log, nil)
+
+	for _, eventType := range []string{events.TypeRefundSucceeded, events.TypeRefundFailed} {
+		message, _ := json.Marshal(envelope.Envelope{
+			ID:      "payments:7",
+			Type:    eventType,
+			Version: events.SchemaVersion(eventType),
+			Source:  "payments",
+			Payload: json.RawMessage(`{"order_id":10,"user_id":20,"amount":30}`),
+		})
+		if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+			t.Fatalf("%s: unexpected error: %v", eventType, err)
+		}
+	}
+	if st.saveCalled != 0 {
+		t.Fatalf("refund events must not be saved, got %d calls", st.saveCalled)
+	}
+}
+
+func TestSender_HandleMessage_Schema(t *testing.T) {
+	schemas, err := schema.Load("../../../schemas/events")
+	if err != nil {
+		t.Fatalf("load schemas: %v", err)
+	}
+
+	st := &redisMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	uc := services.New(st, log)
+	sender := NewSender(uc, log, schemas)
+
+	wrap := func(body string) []byte {
+		message, _ := json.Marshal(envelope.Envelope{
+			ID:      "payments:5",
+			Type:    "event-status",
+			Version: events.SchemaVersion("event-status"),
+			Source:  "payments",
+			Payload: json.RawMessage(body),
+		})
+		return message
+	}
+
+	valid := wrap(`{"event_id":5,"order_id":10,"user_id":20,"order_status":"succeeded"}`)
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, valid); err != nil {
+		t.Fatalf("valid payload: %v", err)
+	}
+
+	invalid := wrap(`{"event_id":6,"order_id":10,"user_id":20}`)
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, invalid); !errors.Is(err, schema.ErrInvalidPayload) {
+		t.Fatalf("invalid payload: got %v, want %v", err, schema.ErrInvalidPayload)
+	}
+	if st.saveCalled != 1 {
+		t.Fatalf("save calls: got %d, want %d", st.saveCalled, 1)
+	}
+}
+
 func TestSender_HandleMessage_Success(t *testing.T) {
 	st := &redisMock{}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 	uc := services.New(st, log)
-	sender := NewSender(uc, log)
+	sender := NewSender(uc, log, nil)
 
 	payment := dto.Payment{OrderID: 10, UserID: 20, OrderStatus: domain.StatusSucceeded}
 	payload, _ := json.Marshal(payment)
 
-	if err := sender.HandleMessage(context.Background(), payload); err != nil {
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, payload); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -71,12 +204,12 @@ func TestSender_HandleMessage_SaveError(t *testing.T) {
 	st := &redisMock{saveErr: errors.New("redis")}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 	uc := services.New(st, log)
-	sender := NewSender(uc, log)
+	sender := NewSender(uc, log, nil)
 
 	payment := dto.Payment{OrderID: 10, UserID: 20, OrderStatus: domain.StatusSucceeded}
 	payload, _ := json.Marshal(payment)
 
-	if err := sender.HandleMessage(context.Background(), payload); err == nil {
+	if err := sender.HandleMessage(context.Background(), envelope.ContentTypeJSON, payload); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -8,7 +8,7 @@ var (
 	ErrInvalidOrderID = errors.New("order id must be positive")
 	ErrInvalidUserID  = errors.New("user id must be positive")
 
-	ErrInvalidStatus = errors.New("status must be succeeded or failed")
+	ErrInvalidStatus = errors.New("status must be succeeded, failed or cancelled")
 
 	ErrNotFound = errors.New("object found")
 
@@ -0,0 +1,23 @@
+package events
+
+import "github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+
+// schemaVersions is the newest payload version of each event type this
+// service reads; types not listed are at version 1.
+var schemaVersions = map[string]int{
+	TypePaymentStatus: 2,
+}
+
+// SchemaVersion is the newest payload version of eventType.
+func SchemaVersion(eventType string) int {
+	if version, ok := schemaVersions[eventType]; ok {
+		return version
+	}
+	return 1
+}
+
+// DecodeEnvelope reads an enveloped message of the given content type,
+// rejecting payloads newer than this service understands.
+func DecodeEnvelope(contentType string, data []byte) (envelope.Envelope, error) {
+	return envelope.Decode(contentType, data, SchemaVersion)
+}
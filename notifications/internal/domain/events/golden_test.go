@@ -0,0 +1,18 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/testkit/golden"
+)
+
+// goldenDir holds the protoc encoding of every message in
+// proto/opp/events/v1; make proto-golden regenerates it from the .txtpb files.
+const goldenDir = "../../../../proto/opp/events/v1/testdata"
+
+func TestProto_Golden(t *testing.T) {
+	golden.Check(t, goldenDir, []golden.Case{
+		{Message: "PaymentStatus", Got: &Payment{}, Want: &Payment{OrderID: 7, UserID: 3, OrderStatus: "succeeded"}, Partial: true},
+		{Message: "PaymentCancelled", Got: &PaymentCancelled{}, Want: &PaymentCancelled{OrderID: 7, UserID: 3, Outcome: "refunded", Amount: 12500}, Partial: true},
+	})
+}
@@ -1,5 +1,11 @@
 package events
 
+import "github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+
+// TypePaymentStatus is the default type of payment results published by
+// payments.
+const TypePaymentStatus = "event-status"
+
 type ID int64
 
 type Status string
@@ -9,3 +15,27 @@ type Payment struct {
 	UserID      ID     `json:"user_id"`
 	OrderStatus Status `json:"order_status"`
 }
+
+// MarshalProto encodes the payment as opp.events.v1.PaymentStatus. The event
+// id (field 1) is not kept by this service.
+func (e *Payment) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendString(b, 4, string(e.OrderStatus))
+	return b
+}
+
+func (e *Payment) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 2:
+			e.OrderID = ID(f.Int64())
+		case 3:
+			e.UserID = ID(f.Int64())
+		case 4:
+			e.OrderStatus = Status(f.Text())
+		}
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+package events
+
+import "github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+
+// TypePaymentCancelled is published by payments once the payment of a
+// cancelled order is voided or refunded.
+const TypePaymentCancelled = "payment cancelled"
+
+// Refund results share the status topic but produce no notification.
+const (
+	TypeRefundSucceeded = "refund succeeded"
+	TypeRefundFailed    = "refund failed"
+)
+
+type PaymentCancelled struct {
+	OrderID ID     `json:"order_id"`
+	UserID  ID     `json:"user_id"`
+	Outcome string `json:"outcome"`
+	Amount  int64  `json:"amount"`
+}
+
+// MarshalProto encodes the event as opp.events.v1.PaymentCancelled without
+// the event id (field 1), which this service does not keep.
+func (e *PaymentCancelled) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendString(b, 4, e.Outcome)
+	b = wire.AppendInt64(b, 5, e.Amount)
+	return b
+}
+
+func (e *PaymentCancelled) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 2:
+			e.OrderID = ID(f.Int64())
+		case 3:
+			e.UserID = ID(f.Int64())
+		case 4:
+			e.Outcome = f.Text()
+		case 5:
+			e.Amount = f.Int64()
+		}
+		return nil
+	})
+}
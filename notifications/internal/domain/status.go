@@ -3,4 +3,5 @@ package domain
 const (
 	StatusSucceeded = "succeeded"
 	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
 )
@@ -7,23 +7,35 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/metrics"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
+const seekTimeoutMs = 1000
+
+// serviceName labels the dead-letter metric.
+const serviceName = "notifications"
+
 type Handler interface {
-	HandleMessage(ctx context.Context, message []byte) error
+	HandleMessage(ctx context.Context, contentType string, message []byte) error
 }
 
 type Consumer struct {
 	consumer       *kafka.Consumer
 	handler        Handler
+	producer       deadletter.Producer
+	dlq            deadletter.Config
 	topic          string
 	sessionTimeout time.Duration
 	readTimeout    time.Duration
 	log            *slog.Logger
 }
 
-func NewConsumer(address []string, handler Handler, topic, consumerGroup string, sessionTimeout, readTimeout time.Duration, log *slog.Logger) (*Consumer, error) {
+func NewConsumer(
+	address []string, handler Handler, producer deadletter.Producer, dlq deadletter.Config,
+	topic, consumerGroup string, sessionTimeout, readTimeout time.Duration, log *slog.Logger) (*Consumer, error) {
 	const op = "kafka_consume.NewConsumer"
 
 	cfg := &kafka.ConfigMap{
@@ -43,10 +55,16 @@ func NewConsumer(address []string, handler Handler, topic, consumerGroup string,
 		return nil, fmt.Errorf("%s: subcribe to topic %w", op, err)
 	}
 
+	if dlq.Topic == "" {
+		dlq.Topic = deadletter.Topic(topic)
+	}
+
 	return &Consumer{
 		consumer:       c,
 		topic:          topic,
 		handler:        handler,
+		producer:       producer,
+		dlq:            dlq,
 		sessionTimeout: sessionTimeout,
 		readTimeout:    readTimeout,
 		log:            log}, nil
@@ -85,8 +103,18 @@ func (c *Consumer) Start(ctx context.Context) error {
 			continue
 		}
 
-		if err := c.handler.HandleMessage(ctx, kafkaMsg.Value); err != nil {
-			log.Error("handle message failed", slog.Any("err", err))
+		msgLog := log.With(
+			slog.Int("partition", int(kafkaMsg.TopicPartition.Partition)),
+			slog.Int64("offset", int64(kafkaMsg.TopicPartition.Offset)))
+
+		if err := c.handle(ctx, kafkaMsg, msgLog); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			msgLog.Error("message not handled, rewinding", slog.Any("err", err))
+			if err := c.consumer.Seek(kafkaMsg.TopicPartition, seekTimeoutMs); err != nil {
+				msgLog.Error("seek failed", slog.Any("err", err))
+			}
 			continue
 		}
 
@@ -102,3 +130,26 @@ func (c *Consumer) Stop() error {
 	log.Debug("stopping consumer")
 	return c.consumer.Close()
 }
+
+// handle runs the handler under the consumer's dead-letter policy. A nil
+// error means the offset may be committed.
+func (c *Consumer) handle(ctx context.Context, msg *kafka.Message, log *slog.Logger) error {
+	dead, err := deadletter.Handle(ctx, msg, c.dlq, c.producer, log, func(ctx context.Context) error {
+		return c.handler.HandleMessage(ctx, contentType(msg.Headers), msg.Value)
+	})
+	if dead {
+		metrics.DLQMessagesTotal.WithLabelValues(serviceName, c.topic).Inc()
+	}
+	return err
+}
+
+// contentType returns the content-type header value; producers that predate
+// the header send JSON.
+func contentType(headers []kafka.Header) string {
+	for _, h := range headers {
+		if h.Key == envelope.HeaderContentType {
+			return string(h.Value)
+		}
+	}
+	return envelope.ContentTypeJSON
+}
@@ -14,10 +14,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/controller"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/kafka_produce"
 	"github.com/ChernykhITMO/order-processing-platform/notifications/internal/services"
 	redis_storage "github.com/ChernykhITMO/order-processing-platform/notifications/storage/redis"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
@@ -158,9 +160,15 @@ func TestNotificationsKafkaConsumer_Integration(t *testing.T) {
 
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 	svc := services.New(storage, log)
-	handler := controller.NewSender(svc, log)
+	handler := controller.NewSender(svc, log, nil)
 
-	consumer, err := NewConsumer(brokers, handler, topic, "test-group-"+time.Now().Format("150405.000"), time.Second*6, time.Second*2, log)
+	dlqProducer, err := kafka_produce.NewProducer(brokers)
+	if err != nil {
+		t.Fatalf("new dlq producer: %v", err)
+	}
+	defer dlqProducer.Close()
+
+	consumer, err := NewConsumer(brokers, handler, dlqProducer, deadletter.Config{MaxAttempts: 1}, topic, "test-group-"+time.Now().Format("150405.000"), time.Second*6, time.Second*2, log)
 	if err != nil {
 		t.Fatalf("new consumer: %v", err)
 	}
@@ -195,7 +203,7 @@ func TestNotificationsKafkaConsumer_Integration(t *testing.T) {
 	deadline := time.Now().Add(10 * time.Second)
 	for time.Now().Before(deadline) {
 		got, err := storage.GetNotification(context.Background(), key)
-		if err == nil && got.OrderID == payment.OrderID {
+		if err == nil && int64(got.OrderID) == payment.OrderID {
 			return
 		}
 		time.Sleep(200 * time.Millisecond)
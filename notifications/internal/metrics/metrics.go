@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	DLQMessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "kafka",
+			Name:      "dlq_messages_total",
+			Help:      "Messages published to a dead-letter topic",
+		}, []string{"service", "topic"})
+)
+
+func Register() {
+	prometheus.MustRegister(
+		DLQMessagesTotal)
+}
@@ -89,7 +89,9 @@ func validateInput(input dto.SaveInput) error {
 		return domain.ErrInvalidOrderID
 	}
 
-	if input.Status != domain.StatusFailed && input.Status != domain.StatusSucceeded {
+	switch input.Status {
+	case domain.StatusSucceeded, domain.StatusFailed, domain.StatusCancelled:
+	default:
 		return domain.ErrInvalidStatus
 	}
 
@@ -34,6 +34,7 @@ func TestNotificationService_Save(t *testing.T) {
 		{"empty key", dto.SaveInput{Key: "", OrderID: 10, UserID: 20, Status: domain.StatusSucceeded}, nil, domain.ErrIsEmptyKey, 0, "", 0},
 		{"invalid user", dto.SaveInput{Key: "10", OrderID: 10, UserID: 0, Status: domain.StatusSucceeded}, nil, domain.ErrInvalidUserID, 0, "", 0},
 		{"invalid order", dto.SaveInput{Key: "10", OrderID: 0, UserID: 20, Status: domain.StatusSucceeded}, nil, domain.ErrInvalidOrderID, 0, "", 0},
+		{"cancelled", dto.SaveInput{Key: "10", OrderID: 10, UserID: 20, Status: domain.StatusCancelled}, nil, nil, 1, "10", 10},
 		{"invalid status", dto.SaveInput{Key: "10", OrderID: 10, UserID: 20, Status: "unknown"}, nil, domain.ErrInvalidStatus, 0, "", 0},
 		{"storage error", validInput, errDB, errDB, 1, "10", 10},
 	}
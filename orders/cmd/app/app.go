@@ -5,31 +5,52 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
 	grpcapp "github.com/ChernykhITMO/order-processing-platform/orders/cmd/app/grpc"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/catalog"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/config"
+	kafkactrl "github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/kafka"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/kafka_consume"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/kafka_produce"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services/event_sender"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services/janitor"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services/order_expirer"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services/saga_watcher"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
 )
 
 type App struct {
-	GRPCSrv       *grpcapp.App
-	EventSender   *event_sender.Sender
-	KafkaProducer *kafka_produce.Producer
-	KafkaTopic    string
-	KafkaPeriod   time.Duration
-	storage       *postgres.Storage
-	log           *slog.Logger
+	GRPCSrv        *grpcapp.App
+	EventSender    *event_sender.Sender
+	KafkaProducer  *kafka_produce.Producer
+	StatusConsumer *kafka_consume.Consumer
+	// InventoryConsumer reads stock reservation results for the order saga.
+	InventoryConsumer *kafka_consume.Consumer
+	KafkaTopics       event_sender.Topics
+	KafkaPeriod       time.Duration
+	Janitor           *janitor.Janitor
+	JanitorPeriod     time.Duration
+	SagaWatcher       *saga_watcher.Watcher
+	SagaPeriod        time.Duration
+	OrderExpirer      *order_expirer.Scheduler
+	ExpiryPeriod      time.Duration
+	storage           *postgres.Storage
+	catalog           *catalog.Client
+	log               *slog.Logger
 }
 
 func New(
 	log *slog.Logger,
 	grpcPort int,
 	dbCfg config.DBConfig,
-	kafkaBrokers []string,
-	kafkaTopic string,
-	kafkaPeriod time.Duration,
+	kafkaCfg config.KafkaConfig,
+	outboxCfg config.OutboxConfig,
+	ordersCfg config.OrdersConfig,
+	catalogCfg config.CatalogConfig,
+	sagaCfg config.SagaConfig,
+	expiryCfg config.ExpiryConfig,
 ) (*App, error) {
 
 	storage, err := postgres.New(dbCfg)
@@ -37,27 +58,98 @@ func New(
 		return nil, err
 	}
 
-	order := services.New(log, storage)
+	var schemas *schema.Registry
+	if kafkaCfg.SchemaDir != "" {
+		schemas, err = schema.Load(kafkaCfg.SchemaDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	catalogClient, err := catalog.New(catalogCfg.Addr, catalogCfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	order := services.New(log, storage, catalogClient, ordersCfg.MaxTotal, services.SagaPolicy{
+		StepTimeout: sagaCfg.StepTimeout,
+		MaxAttempts: sagaCfg.MaxAttempts,
+		PaymentTTL:  expiryCfg.PaymentTTL,
+	})
 
 	grpcApp := grpcapp.New(log, order, grpcPort)
 
+	// the producer also dead-letters messages the consumers fail to handle
 	var producer *kafka_produce.Producer
+	if len(kafkaCfg.Brokers) > 0 {
+		producer, err = kafka_produce.NewProducer(kafkaCfg.Brokers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var sender *event_sender.Sender
-	if len(kafkaBrokers) > 0 && kafkaTopic != "" {
-		producer, err = kafka_produce.NewProducer(kafkaBrokers)
+	if producer != nil && kafkaCfg.Topic != "" {
+		sender = event_sender.New(storage, producer, storage, log, kafkaCfg.BatchSize, kafkaCfg.SendMaxAttempts, kafkaCfg.ContentType, schemas)
+	}
+
+	var consumer *kafka_consume.Consumer
+	if producer != nil && kafkaCfg.StatusTopic != "" {
+		handler := kafkactrl.NewHandler(order, log, schemas)
+		dlq := deadletter.Config{
+			Topic:       kafkaCfg.StatusDLQ.Topic,
+			MaxAttempts: kafkaCfg.StatusDLQ.MaxAttempts,
+			Backoff:     kafkaCfg.StatusDLQ.Backoff,
+		}
+		consumer, err = kafka_consume.NewConsumer(handler, producer, dlq, kafkaCfg.Brokers, kafkaCfg.StatusTopic, kafkaCfg.ConsumerGroup, log)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var inventoryConsumer *kafka_consume.Consumer
+	if producer != nil && kafkaCfg.InventoryTopic != "" {
+		handler := kafkactrl.NewInventoryHandler(order, log, schemas)
+		dlq := deadletter.Config{
+			Topic:       kafkaCfg.InventoryDLQ.Topic,
+			MaxAttempts: kafkaCfg.InventoryDLQ.MaxAttempts,
+			Backoff:     kafkaCfg.InventoryDLQ.Backoff,
+		}
+		inventoryConsumer, err = kafka_consume.NewConsumer(
+			handler, producer, dlq, kafkaCfg.Brokers, kafkaCfg.InventoryTopic, kafkaCfg.InventoryConsumerGroup, log)
 		if err != nil {
 			return nil, err
 		}
-		sender = event_sender.New(storage, producer, log)
 	}
 
 	return &App{
-		GRPCSrv:       grpcApp,
-		EventSender:   sender,
-		KafkaProducer: producer,
-		KafkaTopic:    kafkaTopic,
-		KafkaPeriod:   kafkaPeriod,
+		GRPCSrv:           grpcApp,
+		EventSender:       sender,
+		KafkaProducer:     producer,
+		StatusConsumer:    consumer,
+		InventoryConsumer: inventoryConsumer,
+		KafkaTopics: event_sender.Topics{
+			OrderCreated:       kafkaCfg.Topic,
+			OrderStatusChanged: kafkaCfg.OrderStatusTopic,
+			OrderCancelled:     kafkaCfg.Topic,
+			OrderExpired:       kafkaCfg.Topic,
+			PaymentRequested:   kafkaCfg.Topic,
+		},
+		KafkaPeriod: kafkaCfg.Period,
+		Janitor: janitor.New(storage, log, janitor.Config{
+			Retention:      outboxCfg.Retention,
+			DedupWindow:    outboxCfg.DedupWindow,
+			IdempotencyTTL: outboxCfg.IdempotencyTTL,
+			Archive:        outboxCfg.Archive,
+			BatchSize:      outboxCfg.BatchSize,
+		}),
+		JanitorPeriod: outboxCfg.Period,
+		SagaWatcher:   saga_watcher.New(order, log, sagaCfg.BatchSize),
+		SagaPeriod:    sagaCfg.Period,
+		OrderExpirer:  order_expirer.New(order, log, expiryCfg.BatchSize),
+		ExpiryPeriod:  expiryCfg.Period,
 		storage:       storage,
+		catalog:       catalogClient,
 		log:           log,
 	}, nil
 }
@@ -70,18 +162,71 @@ func (a *App) StartEventSender(ctx context.Context) {
 	if period <= 0 {
 		period = time.Second
 	}
-	a.EventSender.StartProcessEvents(ctx, period, a.KafkaTopic)
+	a.EventSender.StartProcessEvents(ctx, period, a.KafkaTopics)
+}
+
+func (a *App) StartJanitor(ctx context.Context) {
+	period := a.JanitorPeriod
+	if period <= 0 {
+		period = time.Minute
+	}
+	a.Janitor.Start(ctx, period)
+}
+
+func (a *App) StartSagaWatcher(ctx context.Context) {
+	period := a.SagaPeriod
+	if period <= 0 {
+		period = 10 * time.Second
+	}
+	a.SagaWatcher.Start(ctx, period)
+}
+
+func (a *App) StartOrderExpirer(ctx context.Context) {
+	period := a.ExpiryPeriod
+	if period <= 0 {
+		period = 30 * time.Second
+	}
+	a.OrderExpirer.Start(ctx, period)
+}
+
+func (a *App) StartStatusConsumer(ctx context.Context) {
+	if a.StatusConsumer == nil {
+		return
+	}
+	a.StatusConsumer.Start(ctx)
+}
+
+func (a *App) StartInventoryConsumer(ctx context.Context) {
+	if a.InventoryConsumer == nil {
+		return
+	}
+	a.InventoryConsumer.Start(ctx)
 }
 
 func (a *App) Stop() {
 	const op = "app.Stop"
 	log := a.log.With(slog.String("op", op))
 	a.GRPCSrv.Stop()
+	if a.StatusConsumer != nil {
+		if err := a.StatusConsumer.Stop(); err != nil {
+			log.Warn("kafka consumer close", slog.Any("err", err))
+		}
+	}
+	if a.InventoryConsumer != nil {
+		if err := a.InventoryConsumer.Stop(); err != nil {
+			log.Warn("inventory consumer close", slog.Any("err", err))
+		}
+	}
 	if a.KafkaProducer != nil {
 		if err := a.KafkaProducer.Close(); err != nil {
 			log.Warn("kafka producer close", slog.Any("err", err))
 		}
 	}
+	if a.catalog != nil {
+		if err := a.catalog.Close(); err != nil {
+			log.Warn("catalog client close", slog.Any("err", err))
+		}
+	}
 	if a.storage != nil {
 		if err := a.storage.Close(); err != nil {
 			log.Warn("storage close", slog.Any("err", err))
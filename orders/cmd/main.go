@@ -12,17 +12,21 @@ import (
 	"github.com/ChernykhITMO/order-processing-platform/orders/cmd/app"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/health"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/metrics"
 	"github.com/joho/godotenv"
 )
 
 const (
-	envKey          = "env"
-	gRPCAddrKey     = "ORDERS_GRPC_ADDR"
-	healthAddrKey   = "ORDERS_HEALTH_ADDR"
-	dsnKey          = "ORDERS_PG_DSN"
-	kafkaBrokersKey = "KAFKA_BROKERS"
-	kafkaTopicKey   = "KAFKA_TOPIC"
-	kafkaPeriodKey  = "KAFKA_PERIOD"
+	envKey              = "env"
+	gRPCAddrKey         = "ORDERS_GRPC_ADDR"
+	healthAddrKey       = "ORDERS_HEALTH_ADDR"
+	dsnKey              = "ORDERS_PG_DSN"
+	kafkaBrokersKey     = "KAFKA_BROKERS"
+	kafkaTopicKey       = "KAFKA_TOPIC"
+	kafkaPeriodKey      = "KAFKA_PERIOD"
+	kafkaStatusKey      = "KAFKA_TOPIC_STATUS"
+	kafkaGroupKey       = "KAFKA_CONSUMER_GROUP"
+	kafkaOrderStatusKey = "KAFKA_TOPIC_ORDER_STATUS"
 
 	envLocal = "local"
 	envDev   = "dev"
@@ -35,7 +39,10 @@ func main() {
 		_ = os.Setenv(envKey, os.Getenv("ENV"))
 	}
 
-	cfg, err := config.Load(envKey, gRPCAddrKey, healthAddrKey, dsnKey, kafkaBrokersKey, kafkaTopicKey, kafkaPeriodKey)
+	cfg, err := config.Load(
+		envKey, gRPCAddrKey, healthAddrKey, dsnKey,
+		kafkaBrokersKey, kafkaTopicKey, kafkaPeriodKey,
+		kafkaStatusKey, kafkaGroupKey, kafkaOrderStatusKey)
 	if err != nil {
 		log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 		log.Error("config load failed", slog.Any("err", err))
@@ -43,8 +50,9 @@ func main() {
 	}
 
 	log := setupLogger(cfg.Env)
+	metrics.Register()
 
-	application, err := app.New(log, cfg.GRPC.Port, cfg.DB, cfg.Kafka.Brokers, cfg.Kafka.Topic, cfg.Kafka.Period)
+	application, err := app.New(log, cfg.GRPC.Port, cfg.DB, cfg.Kafka, cfg.Outbox, cfg.Orders, cfg.Catalog, cfg.Saga, cfg.Expiry)
 	if err != nil {
 		log.Error("app init failed", slog.Any("err", err))
 		os.Exit(1)
@@ -55,7 +63,12 @@ func main() {
 		slog.Int64("db_max_conns", int64(cfg.DB.MaxConns)),
 		slog.Int64("db_min_conns", int64(cfg.DB.MinConns)),
 		slog.String("kafka_topic", cfg.Kafka.Topic),
+		slog.String("kafka_status_topic", cfg.Kafka.StatusTopic),
+		slog.String("kafka_order_status_topic", cfg.Kafka.OrderStatusTopic),
 		slog.String("health_addr", cfg.Health.Addr),
+		slog.String("catalog_addr", cfg.Catalog.Addr),
+		slog.Duration("saga_step_timeout", cfg.Saga.StepTimeout),
+		slog.Duration("payment_ttl", cfg.Expiry.PaymentTTL),
 	).Info("starting application")
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -70,11 +83,31 @@ func main() {
 	}()
 
 	var wg sync.WaitGroup
-	wg.Add(1)
+	wg.Add(6)
 	go func() {
 		defer wg.Done()
 		application.StartEventSender(ctx)
 	}()
+	go func() {
+		defer wg.Done()
+		application.StartStatusConsumer(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		application.StartInventoryConsumer(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		application.StartJanitor(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		application.StartSagaWatcher(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		application.StartOrderExpirer(ctx)
+	}()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
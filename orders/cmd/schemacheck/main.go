@@ -0,0 +1,43 @@
+// Command schemacheck fails when the event schema registry is broken, a
+// released schema file was edited, or a schema version is not backward
+// compatible with the one before it. With -pin it pins new schema files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/schema"
+)
+
+func main() {
+	dir := flag.String("dir", "../schemas/events", "event schema registry directory")
+	pin := flag.Bool("pin", false, "pin the hashes of schema files added since the last release")
+	flag.Parse()
+
+	if *pin {
+		added, err := schema.Pin(*dir)
+		if err != nil {
+			log.Fatalf("schemacheck: %v", err)
+		}
+		for _, file := range added {
+			fmt.Println("pinned", file)
+		}
+	}
+
+	issues, err := schema.CheckDir(*dir)
+	if err != nil {
+		log.Fatalf("schemacheck: %v", err)
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintln(os.Stderr, issue)
+	}
+	if len(issues) > 0 {
+		log.Fatalf("schemacheck: %d incompatible change(s)", len(issues))
+	}
+
+	fmt.Println("schemas are backward compatible")
+}
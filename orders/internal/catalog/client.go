@@ -0,0 +1,68 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	catalogv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/catalog/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client looks up products in the catalog service over gRPC.
+type Client struct {
+	conn    *grpc.ClientConn
+	api     catalogv1.CatalogServiceClient
+	timeout time.Duration
+}
+
+func New(addr string, timeout time.Duration) (*Client, error) {
+	const op = "catalog.New"
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Client{
+		conn:    conn,
+		api:     catalogv1.NewCatalogServiceClient(conn),
+		timeout: timeout,
+	}, nil
+}
+
+func (c *Client) GetProducts(ctx context.Context, ids []int64) (map[domain.ID]domain.Product, error) {
+	const op = "catalog.Client.GetProducts"
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	resp, err := c.api.GetProducts(ctx, &catalogv1.GetProductsRequest{Ids: ids})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	products := make(map[domain.ID]domain.Product, len(resp.GetProducts()))
+	for _, p := range resp.GetProducts() {
+		price, err := domain.NewMoney(p.GetPrice(), p.GetCurrency())
+		if err != nil {
+			return nil, fmt.Errorf("%s: product %d: %w", op, p.GetId(), err)
+		}
+		products[domain.ID(p.GetId())] = domain.Product{
+			ID:     domain.ID(p.GetId()),
+			Price:  price,
+			Active: p.GetActive(),
+		}
+	}
+
+	return products, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -6,14 +6,21 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
 )
 
 type Config struct {
-	Env    string
-	GRPC   GRPCConfig
-	Health HealthConfig
-	DB     DBConfig
-	Kafka  KafkaConfig
+	Env     string
+	GRPC    GRPCConfig
+	Health  HealthConfig
+	DB      DBConfig
+	Kafka   KafkaConfig
+	Outbox  OutboxConfig
+	Orders  OrdersConfig
+	Catalog CatalogConfig
+	Saga    SagaConfig
+	Expiry  ExpiryConfig
 }
 
 type GRPCConfig struct {
@@ -33,12 +40,95 @@ type DBConfig struct {
 }
 
 type KafkaConfig struct {
-	Brokers []string
-	Topic   string
-	Period  time.Duration
+	Brokers       []string
+	Topic         string
+	Period        time.Duration
+	StatusTopic   string
+	ConsumerGroup string
+	// OrderStatusTopic receives order status change events from the outbox.
+	OrderStatusTopic string
+	// InventoryTopic carries stock reservation results from inventory.
+	InventoryTopic string
+	// InventoryConsumerGroup is the group of the inventory consumer, kept
+	// apart from ConsumerGroup so a rebalance on one topic does not stall
+	// the other.
+	InventoryConsumerGroup string
+	// BatchSize caps how many outbox events are published per round trip.
+	BatchSize int
+	// SendMaxAttempts is how many times an outbox event that cannot be
+	// encoded is retried before it is dead-lettered.
+	SendMaxAttempts int
+	// ContentType is the encoding of published events.
+	ContentType string
+	// SchemaDir is the event schema registry; validation is off when empty.
+	SchemaDir string
+	// StatusDLQ is where status messages that keep failing are moved.
+	StatusDLQ DLQConfig
+	// InventoryDLQ is where stock results that keep failing are moved.
+	InventoryDLQ DLQConfig
+}
+
+// DLQConfig bounds in-place retries of a consumed message before it is moved
+// to a dead-letter topic.
+type DLQConfig struct {
+	Topic       string
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// OutboxConfig drives the retention janitor for events, processed_events and
+// idempotency_keys.
+type OutboxConfig struct {
+	Retention      time.Duration
+	DedupWindow    time.Duration
+	IdempotencyTTL time.Duration
+	Archive        bool
+	BatchSize      int
+	Period         time.Duration
+}
+
+// OrdersConfig holds business limits of order creation.
+type OrdersConfig struct {
+	// MaxTotal caps an order total in minor units of each ISO 4217 currency
+	// it names; orders in other currencies are not limited.
+	MaxTotal map[string]int64
+}
+
+// CatalogConfig points orders at the catalog service that prices new orders.
+type CatalogConfig struct {
+	Addr string
+	// Timeout bounds a single product lookup.
+	Timeout time.Duration
+}
+
+// SagaConfig drives the order saga timeouts.
+type SagaConfig struct {
+	// StepTimeout is how long a step waits for its reply; zero disables
+	// timeouts.
+	StepTimeout time.Duration
+	// MaxAttempts caps how often a retryable step's command is issued.
+	MaxAttempts int
+	// Period is how often expired sagas are looked for.
+	Period time.Duration
+	// BatchSize bounds the sagas handled per period.
+	BatchSize int
 }
 
-func Load(envKey, grpcPortKey, healthAddrKey, pgDSNKey, kafkaBrokersKey, kafkaTopicKey, kafkaPeriodKey string) (*Config, error) {
+// ExpiryConfig drives the expiration of unpaid orders.
+type ExpiryConfig struct {
+	// PaymentTTL is how long after it was placed an order may await payment;
+	// zero disables expiration.
+	PaymentTTL time.Duration
+	// Period is how often unpaid orders are looked for.
+	Period time.Duration
+	// BatchSize bounds the orders expired per period.
+	BatchSize int
+}
+
+func Load(
+	envKey, grpcPortKey, healthAddrKey, pgDSNKey,
+	kafkaBrokersKey, kafkaTopicKey, kafkaPeriodKey,
+	kafkaStatusTopicKey, kafkaConsumerGroupKey, kafkaOrderStatusTopicKey string) (*Config, error) {
 	env := getEnv(envKey)
 	if env == "" {
 		return nil, fmt.Errorf("env %s is empty", envKey)
@@ -81,6 +171,61 @@ func Load(envKey, grpcPortKey, healthAddrKey, pgDSNKey, kafkaBrokersKey, kafkaTo
 	if err != nil {
 		return nil, fmt.Errorf("parse %s: %w", kafkaPeriodKey, err)
 	}
+	kafkaStatusTopic := getEnv(kafkaStatusTopicKey)
+	kafkaConsumerGroup := getEnvWithDefault(kafkaConsumerGroupKey, "orders-group")
+	kafkaOrderStatusTopic := getEnvWithDefault(kafkaOrderStatusTopicKey, "order-status-topic")
+	kafkaInventoryTopic := getEnvWithDefault("KAFKA_TOPIC_INVENTORY", "inventory-topic")
+	kafkaInventoryGroup := getEnvWithDefault("KAFKA_INVENTORY_CONSUMER_GROUP", kafkaConsumerGroup+"-inventory")
+	kafkaBatchSize, err := getEnvInt32WithDefault("KAFKA_BATCH_SIZE", 100)
+	if err != nil {
+		return nil, err
+	}
+	sendMaxAttempts, err := getEnvInt32WithDefault("OUTBOX_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+	kafkaContentType := getEnvWithDefault("KAFKA_CONTENT_TYPE", envelope.ContentTypeJSON)
+	if kafkaContentType != envelope.ContentTypeJSON && kafkaContentType != envelope.ContentTypeProtobuf {
+		return nil, fmt.Errorf("env KAFKA_CONTENT_TYPE: %w: %s", envelope.ErrUnsupportedContentType, kafkaContentType)
+	}
+
+	outbox, err := loadOutbox()
+	if err != nil {
+		return nil, err
+	}
+
+	dlqAttempts, err := getEnvInt32WithDefault("KAFKA_CONSUMER_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+	dlqBackoff, err := getEnvDurationWithDefault("KAFKA_CONSUMER_BACKOFF", 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+
+	maxTotal, err := parseMaxTotal(getEnv("ORDERS_MAX_TOTAL"))
+	if err != nil {
+		return nil, fmt.Errorf("env ORDERS_MAX_TOTAL: %w", err)
+	}
+
+	catalogAddr := getEnv("CATALOG_GRPC_ADDR")
+	if catalogAddr == "" {
+		return nil, fmt.Errorf("env CATALOG_GRPC_ADDR is empty")
+	}
+	catalogTimeout, err := getEnvDurationWithDefault("CATALOG_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	saga, err := loadSaga()
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, err := loadExpiry()
+	if err != nil {
+		return nil, err
+	}
 
 	return &Config{
 		Env: env,
@@ -98,10 +243,132 @@ func Load(envKey, grpcPortKey, healthAddrKey, pgDSNKey, kafkaBrokersKey, kafkaTo
 			HealthCheckPeriod: healthCheckPeriod,
 		},
 		Kafka: KafkaConfig{
-			Brokers: kafkaBrokers,
-			Topic:   kafkaTopic,
-			Period:  kafkaPeriod,
+			Brokers:       kafkaBrokers,
+			Topic:         kafkaTopic,
+			Period:        kafkaPeriod,
+			StatusTopic:   kafkaStatusTopic,
+			ConsumerGroup: kafkaConsumerGroup,
+
+			OrderStatusTopic: kafkaOrderStatusTopic,
+			InventoryTopic:   kafkaInventoryTopic,
+			BatchSize:        int(kafkaBatchSize),
+			SendMaxAttempts:  int(sendMaxAttempts),
+			ContentType:      kafkaContentType,
+			SchemaDir:        getEnv("EVENT_SCHEMA_DIR"),
+			StatusDLQ: DLQConfig{
+				Topic:       getEnvWithDefault("KAFKA_TOPIC_STATUS_DLQ", kafkaStatusTopic+".orders.dlq"),
+				MaxAttempts: int(dlqAttempts),
+				Backoff:     dlqBackoff,
+			},
+			InventoryDLQ: DLQConfig{
+				Topic:       getEnvWithDefault("KAFKA_TOPIC_INVENTORY_DLQ", kafkaInventoryTopic+".orders.dlq"),
+				MaxAttempts: int(dlqAttempts),
+				Backoff:     dlqBackoff,
+			},
+			InventoryConsumerGroup: kafkaInventoryGroup,
+		},
+		Outbox: outbox,
+		Orders: OrdersConfig{
+			MaxTotal: maxTotal,
 		},
+		Catalog: CatalogConfig{
+			Addr:    catalogAddr,
+			Timeout: catalogTimeout,
+		},
+		Saga:   saga,
+		Expiry: expiry,
+	}, nil
+}
+
+func loadOutbox() (OutboxConfig, error) {
+	retention, err := getEnvDurationWithDefault("OUTBOX_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	dedupWindow, err := getEnvDurationWithDefault("OUTBOX_DEDUP_WINDOW", 30*24*time.Hour)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	idempotencyTTL, err := getEnvDurationWithDefault("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	archive, err := getEnvBoolWithDefault("OUTBOX_ARCHIVE", true)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	batchSize, err := getEnvInt32WithDefault("OUTBOX_CLEANUP_BATCH_SIZE", 1000)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	period, err := getEnvDurationWithDefault("OUTBOX_CLEANUP_PERIOD", time.Minute)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+
+	return OutboxConfig{
+		Retention:      retention,
+		DedupWindow:    dedupWindow,
+		IdempotencyTTL: idempotencyTTL,
+		Archive:        archive,
+		BatchSize:      int(batchSize),
+		Period:         period,
+	}, nil
+}
+
+func loadSaga() (SagaConfig, error) {
+	stepTimeout, err := getEnvDurationWithDefault("SAGA_STEP_TIMEOUT", 15*time.Minute)
+	if err != nil {
+		return SagaConfig{}, err
+	}
+	if stepTimeout < 0 {
+		return SagaConfig{}, fmt.Errorf("env SAGA_STEP_TIMEOUT must not be negative")
+	}
+	maxAttempts, err := getEnvInt32WithDefault("SAGA_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return SagaConfig{}, err
+	}
+	if maxAttempts < 1 {
+		return SagaConfig{}, fmt.Errorf("env SAGA_MAX_ATTEMPTS must be at least 1")
+	}
+	period, err := getEnvDurationWithDefault("SAGA_CHECK_PERIOD", 10*time.Second)
+	if err != nil {
+		return SagaConfig{}, err
+	}
+	batchSize, err := getEnvInt32WithDefault("SAGA_BATCH_SIZE", 100)
+	if err != nil {
+		return SagaConfig{}, err
+	}
+
+	return SagaConfig{
+		StepTimeout: stepTimeout,
+		MaxAttempts: int(maxAttempts),
+		Period:      period,
+		BatchSize:   int(batchSize),
+	}, nil
+}
+
+func loadExpiry() (ExpiryConfig, error) {
+	paymentTTL, err := getEnvDurationWithDefault("ORDERS_PAYMENT_TTL", 30*time.Minute)
+	if err != nil {
+		return ExpiryConfig{}, err
+	}
+	if paymentTTL < 0 {
+		return ExpiryConfig{}, fmt.Errorf("env ORDERS_PAYMENT_TTL must not be negative")
+	}
+	period, err := getEnvDurationWithDefault("ORDERS_EXPIRY_PERIOD", 30*time.Second)
+	if err != nil {
+		return ExpiryConfig{}, err
+	}
+	batchSize, err := getEnvInt32WithDefault("ORDERS_EXPIRY_BATCH_SIZE", 100)
+	if err != nil {
+		return ExpiryConfig{}, err
+	}
+
+	return ExpiryConfig{
+		PaymentTTL: paymentTTL,
+		Period:     period,
+		BatchSize:  int(batchSize),
 	}, nil
 }
 
@@ -130,6 +397,34 @@ func getEnvInt32WithDefault(key string, def int32) (int32, error) {
 	return int32(parsed), nil
 }
 
+func getEnvInt64WithDefault(key string, def int64) (int64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	return parsed, nil
+}
+
+func getEnvBoolWithDefault(key string, def bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	return parsed, nil
+}
+
 func getEnvDurationWithDefault(key string, def time.Duration) (time.Duration, error) {
 	value := os.Getenv(key)
 	if value == "" {
@@ -151,6 +446,31 @@ func parseDuration(value string) (time.Duration, error) {
 	return time.ParseDuration(value)
 }
 
+// parseMaxTotal reads per-currency limits written as CUR:amount pairs, e.g.
+// RUB:1000000,USD:15000.
+func parseMaxTotal(value string) (map[string]int64, error) {
+	limits := make(map[string]int64)
+	for _, pair := range parseCSV(value) {
+		currency, amount, ok := strings.Cut(pair, ":")
+		currency = strings.TrimSpace(currency)
+		if !ok || len(currency) != 3 || strings.ToUpper(currency) != currency {
+			return nil, fmt.Errorf("%q: want CUR:amount with an ISO 4217 code", pair)
+		}
+		if _, dup := limits[currency]; dup {
+			return nil, fmt.Errorf("%s: limit set twice", currency)
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(amount), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", currency, err)
+		}
+		if limit <= 0 {
+			return nil, fmt.Errorf("%s: limit must be positive", currency)
+		}
+		limits[currency] = limit
+	}
+	return limits, nil
+}
+
 func parseCSV(value string) []string {
 	if value == "" {
 		return nil
@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+
+type CancelOrderInput struct {
+	ID     int64
+	Reason string
+}
+
+type CancelOrderOutput struct {
+	domain.Order
+}
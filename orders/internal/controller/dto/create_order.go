@@ -3,14 +3,22 @@ package dto
 type CreateOrderInput struct {
 	UserID int64             `json:"user_id"`
 	Items  []CreateOrderItem `json:"items"`
+	// PromoCode is the promo code to redeem; empty means none.
+	PromoCode string `json:"promo_code,omitempty"`
+
+	IdempotencyKey string `json:"-"`
 }
 
 type CreateOrderItem struct {
 	ProductID int64 `json:"product_id"`
 	Quantity  int32 `json:"quantity"`
 	Price     int64 `json:"price"`
+	// Currency is an ISO 4217 code; empty means domain.DefaultCurrency.
+	Currency string `json:"currency,omitempty"`
 }
 
 type CreateOrderOutput struct {
 	ID int64 `json:"id"`
+
+	Replayed bool `json:"-"`
 }
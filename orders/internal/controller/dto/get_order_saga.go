@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+
+type GetOrderSagaInput struct {
+	OrderID int64
+}
+
+type GetOrderSagaOutput struct {
+	domain.Saga
+}
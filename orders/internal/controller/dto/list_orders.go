@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+type ListOrdersInput struct {
+	UserID      int64
+	Statuses    []domain.Status
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	PageSize    int32
+	Cursor      string
+}
+
+type ListOrdersOutput struct {
+	Orders     []domain.Order
+	NextCursor string
+}
@@ -0,0 +1,8 @@
+package dto
+
+type PaymentStatusInput struct {
+	EventID int64
+	OrderID int64
+	UserID  int64
+	Status  string
+}
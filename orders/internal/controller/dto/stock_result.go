@@ -0,0 +1,10 @@
+package dto
+
+// StockResultInput is the outcome of the inventory reservation for an order.
+// ProductID and Reason are only set when the stock was rejected.
+type StockResultInput struct {
+	OrderID   int64
+	Reserved  bool
+	ProductID int64
+	Reason    string
+}
@@ -8,9 +8,13 @@ import (
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services"
 	ordersv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/orders/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// IdempotencyKeyMetadata carries the client's Idempotency-Key on CreateOrder.
+const IdempotencyKeyMetadata = "idempotency-key"
+
 type serverAPI struct {
 	ordersv1.UnimplementedOrdersServiceServer
 	order *services.Order
@@ -33,6 +37,12 @@ func (s *serverAPI) CreateOrder(
 
 	input.UserID = req.UserId
 	input.Items = items
+	input.PromoCode = req.PromoCode
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(IdempotencyKeyMetadata); len(keys) > 0 {
+			input.IdempotencyKey = keys[0]
+		}
+	}
 
 	output, err := s.order.CreateOrder(ctx, input)
 	if err != nil {
@@ -54,6 +64,66 @@ func (s *serverAPI) GetOrder(ctx context.Context, req *ordersv1.GetOrderRequest)
 	}, nil
 }
 
+func (s *serverAPI) CancelOrder(ctx context.Context, req *ordersv1.CancelOrderRequest) (*ordersv1.CancelOrderResponse, error) {
+	input := dto.CancelOrderInput{ID: req.OrderId, Reason: req.Reason}
+	output, err := s.order.CancelOrder(ctx, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ordersv1.CancelOrderResponse{
+		Order: mapper.MapToProto(output.Order),
+	}, nil
+}
+
+func (s *serverAPI) ListOrders(ctx context.Context, req *ordersv1.ListOrdersRequest) (*ordersv1.ListOrdersResponse, error) {
+	input := dto.ListOrdersInput{
+		UserID:   req.UserId,
+		PageSize: req.PageSize,
+		Cursor:   req.Cursor,
+	}
+	for _, st := range req.Statuses {
+		status, err := mapper.MapStatusFromProto(st)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		input.Statuses = append(input.Statuses, status)
+	}
+	if req.CreatedFrom != nil {
+		input.CreatedFrom = req.CreatedFrom.AsTime()
+	}
+	if req.CreatedTo != nil {
+		input.CreatedTo = req.CreatedTo.AsTime()
+	}
+
+	output, err := s.order.ListOrders(ctx, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	orders := make([]*ordersv1.Order, 0, len(output.Orders))
+	for _, order := range output.Orders {
+		orders = append(orders, mapper.MapToProto(order))
+	}
+
+	return &ordersv1.ListOrdersResponse{
+		Orders:     orders,
+		NextCursor: output.NextCursor,
+	}, nil
+}
+
+func (s *serverAPI) GetOrderSaga(ctx context.Context, req *ordersv1.GetOrderSagaRequest) (*ordersv1.GetOrderSagaResponse, error) {
+	input := dto.GetOrderSagaInput{OrderID: req.OrderId}
+	output, err := s.order.GetOrderSaga(ctx, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ordersv1.GetOrderSagaResponse{
+		Saga: mapper.MapSagaToProto(output.Saga),
+	}, nil
+}
+
 func toStatus(err error) error {
 	code, msg := mapper.MapDomainError(err)
 	return status.Error(code, msg)
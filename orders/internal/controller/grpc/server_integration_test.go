@@ -14,6 +14,7 @@ import (
 	"time"
 
 	orderscfg "github.com/ChernykhITMO/order-processing-platform/orders/internal/config"
+	ordersdomain "github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 	orderssvc "github.com/ChernykhITMO/order-processing-platform/orders/internal/services"
 	orderspg "github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
 	ordersv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/orders/v1"
@@ -48,17 +49,32 @@ func ordersPGTestConfig(dsn string) orderscfg.DBConfig {
 }
 
 func cleanupOrdersTables(t *testing.T, db *sql.DB) {
-	const query = `TRUNCATE TABLE events, order_items, orders RESTART IDENTITY CASCADE`
+	const query = `TRUNCATE TABLE events, order_items, orders, processed_events RESTART IDENTITY CASCADE`
 	if _, err := db.Exec(query); err != nil {
 		t.Fatalf("db exec: %v", err)
 	}
 }
 
+// staticCatalog prices every product at 100 RUB.
+type staticCatalog struct{}
+
+func (staticCatalog) GetProducts(_ context.Context, ids []int64) (map[ordersdomain.ID]ordersdomain.Product, error) {
+	res := make(map[ordersdomain.ID]ordersdomain.Product, len(ids))
+	for _, id := range ids {
+		res[ordersdomain.ID(id)] = ordersdomain.Product{
+			ID:     ordersdomain.ID(id),
+			Price:  ordersdomain.Money{Amount: 100, Currency: ordersdomain.DefaultCurrency},
+			Active: true,
+		}
+	}
+	return res, nil
+}
+
 func startOrdersGRPC(t *testing.T, storage *orderspg.Storage) (ordersv1.OrdersServiceClient, func()) {
 	t.Helper()
 
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	service := orderssvc.New(log, storage)
+	service := orderssvc.New(log, storage, staticCatalog{}, nil, orderssvc.SagaPolicy{StepTimeout: time.Minute, MaxAttempts: 3})
 
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -144,4 +160,12 @@ func TestOrdersGRPC_Integration(t *testing.T) {
 	if len(getResp.Order.Items) != 1 || getResp.Order.Items[0].ProductId != 10 {
 		t.Fatalf("items mismatch")
 	}
+
+	sagaResp, err := client.GetOrderSaga(ctx, &ordersv1.GetOrderSagaRequest{OrderId: createResp.OrderId})
+	if err != nil {
+		t.Fatalf("get order saga: %v", err)
+	}
+	if sagaResp.Saga.Step != "reserve_stock" || sagaResp.Saga.Attempts != 1 || sagaResp.Saga.DeadlineAt == nil {
+		t.Fatalf("saga mismatch: %+v", sagaResp.Saga)
+	}
 }
@@ -0,0 +1,188 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+	"github.com/jackc/pgx/v5"
+)
+
+const contractsDir = "../../../../contracts"
+
+// paymentStatusContract lists the payment status fields orders relies on.
+var paymentStatusContract = contract.Contract{
+	Consumer:  "orders",
+	Provider:  "payments",
+	EventType: "event-status",
+	Examples: []contract.Example{
+		{
+			Description: "payment succeeded",
+			Payload:     json.RawMessage(`{"event_id":5,"order_id":42,"order_status":"succeeded"}`),
+		},
+		{
+			Description: "payment failed",
+			Payload:     json.RawMessage(`{"event_id":6,"order_id":42,"order_status":"failed"}`),
+		},
+	},
+}
+
+func TestHandler_PaymentStatusContract(t *testing.T) {
+	wantStatus := map[string]domain.Status{
+		domain.PaymentStatusSucceeded: domain.StatusPaid,
+		domain.PaymentStatusFailed:    domain.StatusPaymentFailed,
+	}
+
+	for _, example := range paymentStatusContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			var want struct {
+				EventID     int64  `json:"event_id"`
+				OrderID     int64  `json:"order_id"`
+				OrderStatus string `json:"order_status"`
+			}
+			_ = json.Unmarshal(example.Payload, &want)
+
+			repo := &repoMock{order: domain.Order{ID: domain.ID(want.OrderID), UserID: 7, Status: domain.StatusAwaitingPayment}}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			handler := NewHandler(services.New(log, repo, nil, nil, services.SagaPolicy{}), log, nil)
+
+			message, _ := json.Marshal(envelope.Envelope{
+				ID:      "payments:5",
+				Type:    paymentStatusContract.EventType,
+				Version: events.SchemaVersion(paymentStatusContract.EventType),
+				Source:  paymentStatusContract.Provider,
+				Payload: example.Payload,
+			})
+			if err := handler.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			if repo.processedEventID != want.EventID {
+				t.Fatalf("event id: got %d, want %d", repo.processedEventID, want.EventID)
+			}
+			if repo.updatedOrderID != want.OrderID || repo.updatedStatus != wantStatus[want.OrderStatus] {
+				t.Fatalf("update: got order=%d status=%s, want order=%d status=%s",
+					repo.updatedOrderID, repo.updatedStatus, want.OrderID, wantStatus[want.OrderStatus])
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, paymentStatusContract)
+}
+
+type repoMock struct {
+	order domain.Order
+
+	processedEventID int64
+	updatedOrderID   int64
+	updatedStatus    domain.Status
+}
+
+func (m *repoMock) RunInTx(ctx context.Context, fn func(tx postgres.TxRepository) error) error {
+	return fn(m)
+}
+
+func (m *repoMock) CreateOrder(ctx context.Context, userID int64, items []domain.OrderItem, adjustments []domain.Adjustment, total domain.Money) (int64, error) {
+	return 0, nil
+}
+
+func (m *repoMock) GetOrderByID(ctx context.Context, id int64) (*domain.Order, error) {
+	order := m.order
+	return &order, nil
+}
+
+func (m *repoMock) UpdateOrderStatus(ctx context.Context, orderID int64, status domain.Status, expectedVersion int64) (int64, error) {
+	m.updatedOrderID = orderID
+	m.updatedStatus = status
+	return expectedVersion + 1, nil
+}
+
+func (m *repoMock) TryMarkProcessed(ctx context.Context, eventID int64) (bool, error) {
+	m.processedEventID = eventID
+	return true, nil
+}
+
+func (m *repoMock) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
+	return nil
+}
+
+func (m *repoMock) ClaimIdempotencyKey(ctx context.Context, userID int64, key, requestHash string) (*domain.IdempotencyKey, error) {
+	return nil, nil
+}
+
+func (m *repoMock) SaveIdempotencyResponse(ctx context.Context, userID int64, key string, orderID int64, response []byte) error {
+	return nil
+}
+
+func (m *repoMock) CreateSaga(ctx context.Context, saga domain.Saga) error {
+	return nil
+}
+
+func (m *repoMock) GetSagaForUpdate(ctx context.Context, orderID int64) (*domain.Saga, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (m *repoMock) UpdateSaga(ctx context.Context, saga domain.Saga) error {
+	return nil
+}
+
+func (m *repoMock) GetDueSaga(ctx context.Context, now time.Time) (domain.Saga, error) {
+	return domain.Saga{}, nil
+}
+
+func (m *repoMock) GetUnpaidOrder(ctx context.Context, createdBefore time.Time) (int64, time.Time, error) {
+	return 0, time.Time{}, nil
+}
+
+func (m *repoMock) GetPromotionForUpdate(ctx context.Context, code string) (*domain.Promotion, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (m *repoMock) CountUserRedemptions(ctx context.Context, promotionID, userID int64) (int, error) {
+	return 0, nil
+}
+
+func (m *repoMock) RedeemPromotion(ctx context.Context, promotionID, orderID, userID int64) error {
+	return nil
+}
+
+func (m *repoMock) GetSaga(ctx context.Context, orderID int64) (*domain.Saga, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (m *repoMock) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*domain.IdempotencyKey, error) {
+	return nil, nil
+}
+
+func (m *repoMock) ListOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, error) {
+	return nil, nil
+}
+
+func (m *repoMock) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	return nil, nil
+}
+
+func (m *repoMock) MarkSent(ctx context.Context, eventIDs []int64) error {
+	return nil
+}
+
+func (m *repoMock) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
+func (m *repoMock) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *repoMock) Close() error {
+	return nil
+}
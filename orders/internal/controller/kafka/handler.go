@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services"
+)
+
+// Validator checks a consumed envelope against the schema registry.
+type Validator interface {
+	ValidateEnvelope(env envelope.Envelope) error
+}
+
+type Handler struct {
+	order   *services.Order
+	log     *slog.Logger
+	schemas Validator
+}
+
+// NewHandler builds the payment status handler. schemas may be nil to skip
+// payload validation.
+func NewHandler(order *services.Order, log *slog.Logger, schemas Validator) *Handler {
+	return &Handler{
+		order:   order,
+		log:     log,
+		schemas: schemas,
+	}
+}
+
+func (h *Handler) HandleMessage(parentCtx context.Context, contentType string, message []byte) error {
+	const op = "controller.kafka.HandleMessage"
+	log := h.log.With(slog.String("op", op))
+
+	env, err := events.DecodeEnvelope(contentType, message)
+	if err != nil {
+		log.Error("decode envelope", slog.Any("err", err))
+		return fmt.Errorf("%s: decode envelope: %w", op, err)
+	}
+	if h.schemas != nil {
+		if err := h.schemas.ValidateEnvelope(env); err != nil {
+			log.Error("validate message", slog.Any("err", err))
+			return fmt.Errorf("%s: validate message: %w", op, err)
+		}
+	}
+
+	switch env.Type {
+	case events.TypePaymentCancelled, events.TypeRefundSucceeded, events.TypeRefundFailed:
+		// the order status does not follow cancellations and refunds
+		log.Debug("event skipped", slog.String("type", env.Type), slog.String("event_id", env.ID))
+		return nil
+	}
+
+	var event events.PaymentStatus
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		log.Error("decode message", slog.Any("err", err))
+		return fmt.Errorf("%s: decode message: %w", op, err)
+	}
+
+	ctx, cancel := context.WithTimeout(envelope.WithMeta(parentCtx, envelope.CausedBy(env)), 5*time.Second)
+	defer cancel()
+
+	input := dto.PaymentStatusInput{
+		EventID: event.EventID,
+		OrderID: event.OrderID,
+		UserID:  event.UserID,
+		Status:  event.OrderStatus,
+	}
+
+	if err := h.order.HandlePaymentStatus(ctx, input); err != nil {
+		log.Error("handle failed", slog.Any("err", err))
+		return fmt.Errorf("%s: handle message: %w", op, err)
+	}
+
+	return nil
+}
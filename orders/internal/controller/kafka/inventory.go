@@ -0,0 +1,82 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services"
+)
+
+// InventoryHandler feeds stock reservation results into the order saga.
+type InventoryHandler struct {
+	order   *services.Order
+	log     *slog.Logger
+	schemas Validator
+}
+
+// NewInventoryHandler builds the stock result handler. schemas may be nil
+// to skip payload validation.
+func NewInventoryHandler(order *services.Order, log *slog.Logger, schemas Validator) *InventoryHandler {
+	return &InventoryHandler{
+		order:   order,
+		log:     log,
+		schemas: schemas,
+	}
+}
+
+func (h *InventoryHandler) HandleMessage(parentCtx context.Context, contentType string, message []byte) error {
+	const op = "controller.kafka.InventoryHandler.HandleMessage"
+	log := h.log.With(slog.String("op", op))
+
+	env, err := events.DecodeEnvelope(contentType, message)
+	if err != nil {
+		log.Error("decode envelope", slog.Any("err", err))
+		return fmt.Errorf("%s: decode envelope: %w", op, err)
+	}
+	if h.schemas != nil {
+		if err := h.schemas.ValidateEnvelope(env); err != nil {
+			log.Error("validate message", slog.Any("err", err))
+			return fmt.Errorf("%s: validate message: %w", op, err)
+		}
+	}
+
+	var input dto.StockResultInput
+	switch env.Type {
+	case events.TypeStockReserved:
+		var event events.StockReserved
+		if err := envelope.UnmarshalPayload(env, &event); err != nil {
+			log.Error("decode message", slog.Any("err", err))
+			return fmt.Errorf("%s: decode message: %w", op, err)
+		}
+		input = dto.StockResultInput{OrderID: int64(event.OrderID), Reserved: true}
+	case events.TypeStockRejected:
+		var event events.StockRejected
+		if err := envelope.UnmarshalPayload(env, &event); err != nil {
+			log.Error("decode message", slog.Any("err", err))
+			return fmt.Errorf("%s: decode message: %w", op, err)
+		}
+		input = dto.StockResultInput{
+			OrderID:   int64(event.OrderID),
+			ProductID: int64(event.ProductID),
+			Reason:    event.Reason,
+		}
+	default:
+		log.Debug("event skipped", slog.String("type", env.Type), slog.String("event_id", env.ID))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(envelope.WithMeta(parentCtx, envelope.CausedBy(env)), 5*time.Second)
+	defer cancel()
+
+	if err := h.order.HandleStockResult(ctx, input); err != nil {
+		log.Error("handle failed", slog.Any("err", err))
+		return fmt.Errorf("%s: handle message: %w", op, err)
+	}
+
+	return nil
+}
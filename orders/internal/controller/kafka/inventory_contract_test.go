@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/services"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+)
+
+// stockContracts list the stock result fields orders relies on.
+var stockContracts = []struct {
+	contract contract.Contract
+	want     domain.Status
+}{
+	{
+		contract: contract.Contract{
+			Consumer:  "orders",
+			Provider:  "inventory",
+			EventType: events.TypeStockReserved,
+			Examples: []contract.Example{{
+				Description: "order reserved",
+				Payload:     json.RawMessage(`{"event_id":3,"order_id":42,"user_id":7,"reserved_at":"2026-01-02T03:04:05Z"}`),
+			}},
+		},
+		want: domain.StatusAwaitingPayment,
+	},
+	{
+		contract: contract.Contract{
+			Consumer:  "orders",
+			Provider:  "inventory",
+			EventType: events.TypeStockRejected,
+			Examples: []contract.Example{{
+				Description: "product out of stock",
+				Payload:     json.RawMessage(`{"event_id":4,"order_id":42,"user_id":7,"product_id":10,"reason":"insufficient stock"}`),
+			}},
+		},
+		want: domain.StatusOutOfStock,
+	},
+}
+
+func TestInventoryHandler_StockContracts(t *testing.T) {
+	for _, c := range stockContracts {
+		for _, example := range c.contract.Examples {
+			t.Run(example.Description, func(t *testing.T) {
+				repo := &repoMock{order: domain.Order{
+					ID: 42, UserID: 7, Status: domain.StatusAwaitingStock,
+					TotalAmount: domain.Money{Amount: 200, Currency: "RUB"},
+				}}
+				log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+				handler := NewInventoryHandler(services.New(log, repo, nil, nil, services.SagaPolicy{}), log, nil)
+
+				message, _ := json.Marshal(envelope.Envelope{
+					ID:      "inventory:3",
+					Type:    c.contract.EventType,
+					Version: events.SchemaVersion(c.contract.EventType),
+					Source:  c.contract.Provider,
+					Payload: example.Payload,
+				})
+				if err := handler.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+					t.Fatalf("handle message: %v", err)
+				}
+
+				if repo.updatedOrderID != 42 || repo.updatedStatus != c.want {
+					t.Fatalf("update: got order=%d status=%s, want order=42 status=%s",
+						repo.updatedOrderID, repo.updatedStatus, c.want)
+				}
+			})
+		}
+
+		contract.Record(t, contractsDir, c.contract)
+	}
+}
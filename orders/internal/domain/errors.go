@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -10,7 +11,48 @@ var (
 	ErrInvalidProductID = errors.New("product id must be positive")
 	ErrInvalidQuantity  = errors.New("quantity must be at least one")
 	ErrInvalidPrice     = errors.New("price must be positive")
+	ErrInvalidCurrency  = errors.New("currency must be an ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+	ErrAmountOverflow   = errors.New("amount is out of range")
+	ErrTotalTooLarge    = errors.New("order total exceeds the allowed maximum")
 	ErrInvalidItems     = errors.New("items must not be empty")
+	ErrUnknownProduct   = errors.New("product is unknown or inactive")
+	ErrPriceMismatch    = errors.New("price does not match the catalog")
 	ErrOrderNotFound    = errors.New("order not found")
+	ErrSagaNotFound     = errors.New("order saga not found")
 	ErrUnknownType      = errors.New("unknown type")
+
+	ErrInvalidEventID       = errors.New("event id must not be zero value")
+	ErrInvalidPaymentStatus = errors.New("payment status must be succeeded or failed")
+
+	ErrInvalidStatus     = errors.New("unknown order status")
+	ErrInvalidTransition = errors.New("order status transition is not allowed")
+	ErrVersionConflict   = errors.New("order was modified concurrently")
+
+	ErrInvalidIdempotencyKey = errors.New("idempotency key is too long")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+
+	ErrInvalidPromoCode       = errors.New("promo code is too long")
+	ErrUnknownPromoCode       = errors.New("promo code is unknown")
+	ErrPromoCodeInactive      = errors.New("promo code is not active")
+	ErrPromoCodeExhausted     = errors.New("promo code redemption limit reached")
+	ErrPromoCodeUsed          = errors.New("promo code was already used by the user")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to the order")
+
+	ErrInvalidCursor    = errors.New("invalid page cursor")
+	ErrInvalidPageSize  = errors.New("page size must not be negative")
+	ErrInvalidTimeRange = errors.New("created_from must not be after created_to")
 )
+
+type TransitionError struct {
+	From Status
+	To   Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	want := OrderCreated{EventID: 10, OrderID: 1, UserID: 2, TotalAmount: 1500, Currency: "EUR", CreatedAt: createdAt,
+		Items:       []OrderCreatedItem{{ProductID: 10, Quantity: 2}, {ProductID: 11, Quantity: 1}},
+		Adjustments: []OrderCreatedAdjustment{{Code: "SPRING", ProductID: 10, Amount: 200}, {Code: "SPRING", Amount: 50}}}
+
+	for _, contentType := range []string{envelope.ContentTypeJSON, envelope.ContentTypeProtobuf} {
+		payload, err := envelope.MarshalPayload(contentType, &want)
+		if err != nil {
+			t.Fatalf("%s: marshal payload: %v", contentType, err)
+		}
+		message, err := envelope.Marshal(contentType, envelope.Envelope{
+			ID:         "orders:10",
+			Type:       TypeOrderCreated,
+			Version:    SchemaVersion(TypeOrderCreated),
+			OccurredAt: createdAt,
+			Source:     "orders",
+			Payload:    payload,
+		})
+		if err != nil {
+			t.Fatalf("%s: marshal envelope: %v", contentType, err)
+		}
+
+		env, err := DecodeEnvelope(contentType, message)
+		if err != nil {
+			t.Fatalf("%s: decode envelope: %v", contentType, err)
+		}
+		if env.ID != "orders:10" || env.Type != TypeOrderCreated || !env.OccurredAt.Equal(createdAt) {
+			t.Fatalf("%s: envelope: got %+v", contentType, env)
+		}
+
+		var got OrderCreated
+		if err := envelope.UnmarshalPayload(env, &got); err != nil {
+			t.Fatalf("%s: unmarshal payload: %v", contentType, err)
+		}
+		if got.EventID != want.EventID || got.OrderID != want.OrderID || got.TotalAmount != want.TotalAmount ||
+			got.Currency != want.Currency || !got.CreatedAt.Equal(want.CreatedAt) || !slices.Equal(got.Items, want.Items) ||
+			!slices.Equal(got.Adjustments, want.Adjustments) {
+			t.Fatalf("%s: payload: got %+v, want %+v", contentType, got, want)
+		}
+	}
+}
+
+func TestCodec_ProtoMessages(t *testing.T) {
+	changed := OrderStatusChanged{EventID: 1, OrderID: 2, UserID: 3, From: domain.StatusNew, To: domain.StatusPaid, Version: 4}
+	var gotChanged OrderStatusChanged
+	if err := gotChanged.UnmarshalProto(changed.MarshalProto()); err != nil {
+		t.Fatalf("OrderStatusChanged: %v", err)
+	}
+	if gotChanged != changed {
+		t.Fatalf("OrderStatusChanged: got %+v, want %+v", gotChanged, changed)
+	}
+
+	status := PaymentStatus{EventID: 1, OrderID: 2, UserID: 3, OrderStatus: "paid"}
+	var gotStatus PaymentStatus
+	if err := gotStatus.UnmarshalProto(status.MarshalProto()); err != nil {
+		t.Fatalf("PaymentStatus: %v", err)
+	}
+	if gotStatus != status {
+		t.Fatalf("PaymentStatus: got %+v, want %+v", gotStatus, status)
+	}
+
+	deadlineAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expired := OrderExpired{EventID: 1, OrderID: 2, UserID: 3, DeadlineAt: deadlineAt, ExpiredAt: deadlineAt.Add(time.Second)}
+	var gotExpired OrderExpired
+	if err := gotExpired.UnmarshalProto(expired.MarshalProto()); err != nil {
+		t.Fatalf("OrderExpired: %v", err)
+	}
+	if gotExpired.EventID != expired.EventID || gotExpired.OrderID != expired.OrderID || gotExpired.UserID != expired.UserID ||
+		!gotExpired.DeadlineAt.Equal(expired.DeadlineAt) || !gotExpired.ExpiredAt.Equal(expired.ExpiredAt) {
+		t.Fatalf("OrderExpired: got %+v, want %+v", gotExpired, expired)
+	}
+}
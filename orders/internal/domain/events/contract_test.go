@@ -0,0 +1,26 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+)
+
+func TestOrderCreatedOf_Contracts(t *testing.T) {
+	payload, err := json.Marshal(OrderCreatedOf(&domain.Order{
+		ID:          42,
+		UserID:      7,
+		Items:       []domain.OrderItem{{ProductID: 10, Quantity: 2, Price: domain.Money{Amount: 750, Currency: "RUB"}}},
+		TotalAmount: domain.Money{Amount: 1350, Currency: "RUB"},
+		CreatedAt:   time.Now(),
+		Adjustments: []domain.Adjustment{{PromotionID: 1, Code: "SPRING", ProductID: 10, Amount: domain.Money{Amount: 150, Currency: "RUB"}}},
+	}))
+	if err != nil {
+		t.Fatalf("build payload: %v", err)
+	}
+
+	contract.Verify(t, "../../../../contracts", "orders", TypeOrderCreated, payload)
+}
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/golden"
+)
+
+// goldenDir holds the protoc encoding of every message in
+// proto/opp/events/v1; make proto-golden regenerates it from the .txtpb files.
+const goldenDir = "../../../../proto/opp/events/v1/testdata"
+
+func TestProto_Golden(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 500000000, time.UTC)
+
+	golden.Check(t, goldenDir, []golden.Case{
+		{Message: "OrderCreated", Got: &OrderCreated{}, Want: &OrderCreated{
+			EventID: 42, OrderID: 7, UserID: 3, TotalAmount: 12500, Currency: "RUB", CreatedAt: at,
+			Items:       []OrderCreatedItem{{ProductID: 10, Quantity: 2}, {ProductID: 11, Quantity: 1}},
+			Adjustments: []OrderCreatedAdjustment{{Code: "SPRING", Amount: 1000}, {Code: "SPRING", ProductID: 11, Amount: 500}},
+		}},
+		{Message: "PaymentRequested", Got: &PaymentRequested{}, Want: &PaymentRequested{
+			EventID: 42, OrderID: 7, UserID: 3, TotalAmount: 12500, Currency: "RUB", RequestedAt: at,
+		}},
+		{Message: "OrderStatusChanged", Got: &OrderStatusChanged{}, Want: &OrderStatusChanged{
+			EventID: 42, OrderID: 7, UserID: 3, From: domain.StatusAwaitingPayment, To: domain.StatusPaid, Version: 5, ChangedAt: at,
+		}},
+		{Message: "OrderCancelled", Got: &OrderCancelled{}, Want: &OrderCancelled{
+			EventID: 42, OrderID: 7, UserID: 3, From: domain.StatusPaid, Reason: "duplicate", CancelledAt: at,
+		}},
+		{Message: "OrderExpired", Got: &OrderExpired{}, Want: &OrderExpired{
+			EventID: 42, OrderID: 7, UserID: 3, DeadlineAt: time.Date(2025, 12, 31, 23, 50, 0, 0, time.UTC), ExpiredAt: at,
+		}},
+		{Message: "PaymentStatus", Got: &PaymentStatus{}, Want: &PaymentStatus{EventID: 42, OrderID: 7, UserID: 3, OrderStatus: "succeeded", Currency: "RUB"}},
+		{Message: "StockReserved", Got: &StockReserved{}, Want: &StockReserved{EventID: 42, OrderID: 7, UserID: 3, ReservedAt: at}},
+		{Message: "StockRejected", Got: &StockRejected{}, Want: &StockRejected{EventID: 42, OrderID: 7, UserID: 3, ProductID: 11, Reason: "insufficient stock"}},
+	})
+}
@@ -3,13 +3,156 @@ package events
 import (
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 )
 
 type OrderCreated struct {
-	EventID     int64        `json:"event_id"`
-	OrderID     domain.ID    `json:"order_id"`
-	UserID      domain.ID    `json:"user_id"`
-	TotalAmount domain.Money `json:"total_amount"`
-	CreatedAt   time.Time    `json:"created_at"`
+	EventID     int64     `json:"event_id"`
+	OrderID     domain.ID `json:"order_id"`
+	UserID      domain.ID `json:"user_id"`
+	TotalAmount int64     `json:"total_amount"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Items tell inventory what to reserve for the order.
+	Items []OrderCreatedItem `json:"items,omitempty"`
+	// Adjustments are the promo code discounts already taken off TotalAmount.
+	Adjustments []OrderCreatedAdjustment `json:"adjustments,omitempty"`
+}
+
+type OrderCreatedItem struct {
+	ProductID domain.ID `json:"product_id"`
+	Quantity  int32     `json:"quantity"`
+}
+
+// OrderCreatedAdjustment is a discount in the order currency; ProductID is
+// zero for a discount on the whole order.
+type OrderCreatedAdjustment struct {
+	Code      string    `json:"code"`
+	ProductID domain.ID `json:"product_id,omitempty"`
+	Amount    int64     `json:"amount"`
+}
+
+// OrderCreatedOf is the OrderCreated of order, both for a newly placed order
+// and for one whose stock reservation is reissued; the sender fills in
+// EventID on publish.
+func OrderCreatedOf(order *domain.Order) OrderCreated {
+	items := make([]OrderCreatedItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, OrderCreatedItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	return OrderCreated{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		TotalAmount: order.TotalAmount.Amount,
+		Currency:    string(order.TotalAmount.Currency),
+		CreatedAt:   order.CreatedAt,
+		Items:       items,
+		Adjustments: AdjustmentsOf(order.Adjustments),
+	}
+}
+
+// AdjustmentsOf is the event form of the adjustments of an order; nil when
+// there are none, so the field is left out.
+func AdjustmentsOf(adjustments []domain.Adjustment) []OrderCreatedAdjustment {
+	if len(adjustments) == 0 {
+		return nil
+	}
+	out := make([]OrderCreatedAdjustment, 0, len(adjustments))
+	for _, adjustment := range adjustments {
+		out = append(out, OrderCreatedAdjustment{
+			Code:      adjustment.Code,
+			ProductID: adjustment.ProductID,
+			Amount:    adjustment.Amount.Amount,
+		})
+	}
+	return out
+}
+
+func (e *OrderCreated) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendInt64(b, 4, e.TotalAmount)
+	b = wire.AppendTime(b, 5, e.CreatedAt)
+	b = wire.AppendString(b, 6, e.Currency)
+	for _, item := range e.Items {
+		b = wire.AppendBytes(b, 7, item.marshalProto())
+	}
+	for _, adjustment := range e.Adjustments {
+		b = wire.AppendBytes(b, 8, adjustment.marshalProto())
+	}
+	return b
+}
+
+func (e *OrderCreated) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.TotalAmount = f.Int64()
+		case 5:
+			e.CreatedAt, err = f.Time()
+		case 6:
+			e.Currency = f.Text()
+		case 7:
+			var item OrderCreatedItem
+			err = item.unmarshalProto(f.Bytes)
+			e.Items = append(e.Items, item)
+		case 8:
+			var adjustment OrderCreatedAdjustment
+			err = adjustment.unmarshalProto(f.Bytes)
+			e.Adjustments = append(e.Adjustments, adjustment)
+		}
+		return err
+	})
+}
+
+func (i OrderCreatedItem) marshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, int64(i.ProductID))
+	b = wire.AppendInt64(b, 2, int64(i.Quantity))
+	return b
+}
+
+func (i *OrderCreatedItem) unmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			i.ProductID = domain.ID(f.Int64())
+		case 2:
+			i.Quantity = int32(f.Int64())
+		}
+		return nil
+	})
+}
+
+func (a OrderCreatedAdjustment) marshalProto() []byte {
+	var b []byte
+	b = wire.AppendString(b, 1, a.Code)
+	b = wire.AppendInt64(b, 2, int64(a.ProductID))
+	b = wire.AppendInt64(b, 3, a.Amount)
+	return b
+}
+
+func (a *OrderCreatedAdjustment) unmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			a.Code = f.Text()
+		case 2:
+			a.ProductID = domain.ID(f.Int64())
+		case 3:
+			a.Amount = f.Int64()
+		}
+		return nil
+	})
 }
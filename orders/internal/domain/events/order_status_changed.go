@@ -0,0 +1,53 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+type OrderStatusChanged struct {
+	EventID   int64         `json:"event_id"`
+	OrderID   domain.ID     `json:"order_id"`
+	UserID    domain.ID     `json:"user_id"`
+	From      domain.Status `json:"from"`
+	To        domain.Status `json:"to"`
+	Version   int64         `json:"version"`
+	ChangedAt time.Time     `json:"changed_at"`
+}
+
+func (e *OrderStatusChanged) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendString(b, 4, string(e.From))
+	b = wire.AppendString(b, 5, string(e.To))
+	b = wire.AppendInt64(b, 6, e.Version)
+	b = wire.AppendTime(b, 7, e.ChangedAt)
+	return b
+}
+
+func (e *OrderStatusChanged) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.From = domain.Status(f.Text())
+		case 5:
+			e.To = domain.Status(f.Text())
+		case 6:
+			e.Version = f.Int64()
+		case 7:
+			e.ChangedAt, err = f.Time()
+		}
+		return err
+	})
+}
@@ -0,0 +1,28 @@
+package events
+
+import "time"
+
+const (
+	TypeOrderCreated       = "order created"
+	TypeOrderStatusChanged = "order status changed"
+	TypeOrderCancelled     = "order cancelled"
+	TypeOrderExpired       = "order expired"
+	TypePaymentRequested   = "payment requested"
+	TypeStockReserved      = "stock reserved"
+	TypeStockRejected      = "stock rejected"
+
+	// TypePaymentStatus is the default type of payment results published
+	// by payments.
+	TypePaymentStatus = "event-status"
+)
+
+type Outbox struct {
+	EventID     int64
+	EventType   string
+	AggregateID int64
+	Payload     []byte
+
+	CreatedAt     time.Time
+	CorrelationID string
+	CausationID   string
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+// PaymentRequested is sent by orders once the stock of an order is
+// reserved; payments charges on it.
+type PaymentRequested struct {
+	EventID     int64     `json:"event_id"`
+	OrderID     domain.ID `json:"order_id"`
+	UserID      domain.ID `json:"user_id"`
+	TotalAmount int64     `json:"total_amount"`
+	Currency    string    `json:"currency"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+func (e *PaymentRequested) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendInt64(b, 4, e.TotalAmount)
+	b = wire.AppendTime(b, 5, e.RequestedAt)
+	b = wire.AppendString(b, 6, e.Currency)
+	return b
+}
+
+func (e *PaymentRequested) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.TotalAmount = f.Int64()
+		case 5:
+			e.RequestedAt, err = f.Time()
+		case 6:
+			e.Currency = f.Text()
+		}
+		return err
+	})
+}
@@ -0,0 +1,48 @@
+package events
+
+import "github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+
+// Types published by payments on the status topic besides payment results.
+// TypePaymentCancelled follows a cancelled order; the refund types report
+// refunds requested by operators.
+const (
+	TypePaymentCancelled = "payment cancelled"
+	TypeRefundSucceeded  = "refund succeeded"
+	TypeRefundFailed     = "refund failed"
+)
+
+type PaymentStatus struct {
+	EventID     int64  `json:"event_id"`
+	OrderID     int64  `json:"order_id"`
+	UserID      int64  `json:"user_id"`
+	OrderStatus string `json:"order_status"`
+	Currency    string `json:"currency,omitempty"`
+}
+
+func (e *PaymentStatus) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, e.OrderID)
+	b = wire.AppendInt64(b, 3, e.UserID)
+	b = wire.AppendString(b, 4, e.OrderStatus)
+	b = wire.AppendString(b, 5, e.Currency)
+	return b
+}
+
+func (e *PaymentStatus) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = f.Int64()
+		case 3:
+			e.UserID = f.Int64()
+		case 4:
+			e.OrderStatus = f.Text()
+		case 5:
+			e.Currency = f.Text()
+		}
+		return nil
+	})
+}
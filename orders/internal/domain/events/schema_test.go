@@ -0,0 +1,48 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+const registryDir = "../../../../schemas/events"
+
+func TestSchema_Payloads(t *testing.T) {
+	r, err := schema.Load(registryDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	created, _ := json.Marshal(OrderCreated{
+		EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100, Currency: "RUB", CreatedAt: time.Now(),
+		Items:       []OrderCreatedItem{{ProductID: 10, Quantity: 1}},
+		Adjustments: []OrderCreatedAdjustment{{Code: "SPRING", Amount: 10}},
+	})
+	changed, _ := json.Marshal(OrderStatusChanged{
+		EventID: 1, OrderID: 2, UserID: 3,
+		From: domain.StatusNew, To: domain.StatusAwaitingPayment, Version: 1, ChangedAt: time.Now(),
+	})
+	status, _ := json.Marshal(PaymentStatus{EventID: 1, OrderID: 2, UserID: 3, OrderStatus: "succeeded"})
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   []byte
+	}{
+		{"published order created", TypeOrderCreated, created},
+		{"published status changed", TypeOrderStatusChanged, changed},
+		{"consumed payment status", TypePaymentStatus, status},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Validate(tt.eventType, SchemaVersion(tt.eventType), tt.payload); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+// StockReserved is published by inventory once every item of an order is
+// reserved.
+type StockReserved struct {
+	EventID    int64     `json:"event_id"`
+	OrderID    domain.ID `json:"order_id"`
+	UserID     domain.ID `json:"user_id"`
+	ReservedAt time.Time `json:"reserved_at"`
+}
+
+func (e *StockReserved) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendTime(b, 4, e.ReservedAt)
+	return b
+}
+
+func (e *StockReserved) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.ReservedAt, err = f.Time()
+		}
+		return err
+	})
+}
+
+// StockRejected is published by inventory when an order cannot be reserved
+// in full. ProductID names the first item that is short, if any.
+type StockRejected struct {
+	EventID   int64     `json:"event_id"`
+	OrderID   domain.ID `json:"order_id"`
+	UserID    domain.ID `json:"user_id"`
+	ProductID domain.ID `json:"product_id,omitempty"`
+	Reason    string    `json:"reason"`
+}
+
+func (e *StockRejected) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendInt64(b, 4, int64(e.ProductID))
+	b = wire.AppendString(b, 5, e.Reason)
+	return b
+}
+
+func (e *StockRejected) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.ProductID = domain.ID(f.Int64())
+		case 5:
+			e.Reason = f.Text()
+		}
+		return nil
+	})
+}
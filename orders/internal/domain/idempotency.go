@@ -0,0 +1,15 @@
+package domain
+
+// MaxIdempotencyKeyLen bounds client supplied Idempotency-Key values.
+const MaxIdempotencyKeyLen = 255
+
+// IdempotencyKey is the stored outcome of a request made with an
+// Idempotency-Key: the hash of the request it was first used with and the
+// response returned for it. Keys are scoped to the user that sent them.
+type IdempotencyKey struct {
+	UserID      ID
+	Key         string
+	RequestHash string
+	OrderID     ID
+	Response    []byte
+}
@@ -13,8 +13,11 @@ type Order struct {
 	Status      Status
 	Items       []OrderItem
 	TotalAmount Money
+	Version     int64
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	// Adjustments are the discounts already taken off TotalAmount.
+	Adjustments []Adjustment
 }
 
 func NewOrder(
@@ -31,7 +34,33 @@ func NewOrder(
 		UpdatedAt: updatedAt,
 	}
 
-	o.calculate()
+	if err := o.calculate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return o, nil
+}
+
+// RestoreOrder rebuilds a stored order around its persisted total instead of
+// summing the items again.
+func RestoreOrder(
+	orderID int64, userID int64, status string,
+	items []OrderItem, total Money, createdAt time.Time, updatedAt time.Time) (*Order, error) {
+	const op = "domain.Order.Restore"
+
+	o := &Order{
+		ID:          ID(orderID),
+		UserID:      ID(userID),
+		Status:      Status(status),
+		Items:       items,
+		TotalAmount: total,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
 
 	if err := o.validate(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -51,12 +80,51 @@ func (o *Order) validate() error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUserID)
 	}
 
+	if !o.Status.Valid() {
+		return fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+	}
+
+	return nil
+}
+
+func (o *Order) Transition(to Status) error {
+	const op = "domain.Order.Transition"
+
+	if !to.Valid() {
+		return fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+	}
+
+	if !o.Status.CanTransition(to) {
+		return fmt.Errorf("%s: %w", op, &TransitionError{From: o.Status, To: to})
+	}
+
+	o.Status = to
 	return nil
 }
 
-func (o *Order) calculate() {
-	o.TotalAmount = 0
-	for _, it := range o.Items {
-		o.TotalAmount += it.Price * Money(it.Quantity)
+func (o *Order) calculate() error {
+	total, err := TotalOf(o.Items)
+	if err != nil {
+		return err
+	}
+	o.TotalAmount = total
+	return nil
+}
+
+// TotalOf sums the line amounts of items; all of them must be priced in the
+// same currency.
+func TotalOf(items []OrderItem) (Money, error) {
+	const op = "domain.TotalOf"
+
+	var total Money
+	for _, it := range items {
+		line, err := it.Price.Times(it.Quantity)
+		if err != nil {
+			return Money{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if total, err = total.Add(line); err != nil {
+			return Money{}, fmt.Errorf("%s: %w", op, err)
+		}
 	}
+	return total, nil
 }
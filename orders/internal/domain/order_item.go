@@ -2,20 +2,19 @@ package domain
 
 import "fmt"
 
-type Money int64
 type OrderItem struct {
 	ProductID ID
 	Quantity  int32
 	Price     Money
 }
 
-func NewOrderItem(productID int64, quantity int32, price int64) (OrderItem, error) {
+func NewOrderItem(productID int64, quantity int32, price Money) (OrderItem, error) {
 	const op = "domain.OrderItem.New"
 
 	o := OrderItem{
 		ProductID: ID(productID),
 		Quantity:  quantity,
-		Price:     Money(price),
+		Price:     price,
 	}
 
 	if err := o.validate(); err != nil {
@@ -36,9 +35,13 @@ func (o *OrderItem) validate() error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidQuantity)
 	}
 
-	if o.Price <= 0 {
+	if o.Price.Amount <= 0 {
 		return fmt.Errorf("%s: %w", op, ErrInvalidPrice)
 	}
 
+	if !o.Price.Currency.Valid() {
+		return fmt.Errorf("%s: %w", op, ErrInvalidCurrency)
+	}
+
 	return nil
 }
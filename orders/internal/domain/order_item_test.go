@@ -6,11 +6,11 @@ import (
 )
 
 func TestNewOrderItem_OK(t *testing.T) {
-	item, err := NewOrderItem(10, 2, 100)
+	item, err := NewOrderItem(10, 2, Money{Amount: 100, Currency: "RUB"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if item.ProductID != 10 || item.Quantity != 2 || item.Price != 100 {
+	if item.ProductID != 10 || item.Quantity != 2 || item.Price.Amount != 100 {
 		t.Fatalf("unexpected item: %+v", item)
 	}
 }
@@ -20,12 +20,13 @@ func TestNewOrderItem_Invalid(t *testing.T) {
 		name      string
 		productID int64
 		quantity  int32
-		price     int64
+		price     Money
 		wantErrIs error
 	}{
-		{"invalid product", 0, 1, 10, ErrInvalidProductID},
-		{"invalid quantity", 1, 0, 10, ErrInvalidQuantity},
-		{"invalid price", 1, 1, 0, ErrInvalidPrice},
+		{"invalid product", 0, 1, Money{10, "RUB"}, ErrInvalidProductID},
+		{"invalid quantity", 1, 0, Money{10, "RUB"}, ErrInvalidQuantity},
+		{"invalid price", 1, 1, Money{0, "RUB"}, ErrInvalidPrice},
+		{"invalid currency", 1, 1, Money{10, "rub"}, ErrInvalidCurrency},
 	}
 
 	for _, tt := range tests {
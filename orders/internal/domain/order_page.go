@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// OrderCursor is the position of the last order on a page. Orders are listed
+// newest first, so the next page starts strictly below (CreatedAt, ID).
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        ID
+}
+
+// Encode renders the cursor as an opaque token for clients.
+func (c OrderCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixMicro(), 10) + ":" + strconv.FormatInt(int64(c.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeOrderCursor(token string) (OrderCursor, error) {
+	const op = "domain.DecodeOrderCursor"
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return OrderCursor{}, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+	}
+
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return OrderCursor{}, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+	}
+	us, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return OrderCursor{}, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+	}
+	orderID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || orderID <= 0 {
+		return OrderCursor{}, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+	}
+
+	return OrderCursor{CreatedAt: time.UnixMicro(us).UTC(), ID: ID(orderID)}, nil
+}
+
+// OrderFilter selects a page of a user's orders. Zero CreatedFrom/CreatedTo
+// leave the range open; an empty Statuses matches every status.
+type OrderFilter struct {
+	UserID      ID
+	Statuses    []Status
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	After       *OrderCursor
+	Limit       int
+}
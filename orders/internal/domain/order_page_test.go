@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrderCursor_RoundTrip(t *testing.T) {
+	cursor := OrderCursor{CreatedAt: time.Date(2025, 3, 1, 12, 30, 0, 123456000, time.UTC), ID: 42}
+
+	got, err := DecodeOrderCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.CreatedAt.Equal(cursor.CreatedAt) || got.ID != cursor.ID {
+		t.Fatalf("cursor mismatch: got %+v, want %+v", got, cursor)
+	}
+}
+
+func TestDecodeOrderCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "%%%"},
+		{"no separator", base64.RawURLEncoding.EncodeToString([]byte("123"))},
+		{"bad time", base64.RawURLEncoding.EncodeToString([]byte("x:1"))},
+		{"bad id", base64.RawURLEncoding.EncodeToString([]byte("1:0"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeOrderCursor(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected error %v, got %v", ErrInvalidCursor, err)
+			}
+		})
+	}
+}
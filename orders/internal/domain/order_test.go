@@ -8,8 +8,8 @@ import (
 
 func TestNewOrder_OK(t *testing.T) {
 	items := []OrderItem{
-		{ProductID: 1, Quantity: 2, Price: 100},
-		{ProductID: 2, Quantity: 1, Price: 50},
+		{ProductID: 1, Quantity: 2, Price: Money{Amount: 100, Currency: "RUB"}},
+		{ProductID: 2, Quantity: 1, Price: Money{Amount: 50, Currency: "RUB"}},
 	}
 	createdAt := time.Now().UTC()
 	updatedAt := createdAt.Add(time.Minute)
@@ -19,8 +19,8 @@ func TestNewOrder_OK(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if order.TotalAmount != 250 {
-		t.Fatalf("total amount mismatch: got %d, want %d", order.TotalAmount, 250)
+	if order.TotalAmount != (Money{Amount: 250, Currency: "RUB"}) {
+		t.Fatalf("total amount mismatch: got %s, want %s", order.TotalAmount, "250 RUB")
 	}
 	if order.CreatedAt.IsZero() || order.UpdatedAt.IsZero() {
 		t.Fatalf("timestamps should be set")
@@ -50,3 +50,110 @@ func TestNewOrder_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestNewOrder_MixedCurrencies(t *testing.T) {
+	items := []OrderItem{
+		{ProductID: 1, Quantity: 1, Price: Money{Amount: 100, Currency: "RUB"}},
+		{ProductID: 2, Quantity: 1, Price: Money{Amount: 5, Currency: "USD"}},
+	}
+
+	_, err := NewOrder(1, 2, string(StatusNew), items, time.Now(), time.Now())
+	if !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected error %v, got %v", ErrCurrencyMismatch, err)
+	}
+}
+
+func TestRestoreOrder_KeepsStoredTotal(t *testing.T) {
+	items := []OrderItem{
+		{ProductID: 1, Quantity: 2, Price: Money{Amount: 100, Currency: "RUB"}},
+	}
+	stored := Money{Amount: 150, Currency: "RUB"}
+
+	order, err := RestoreOrder(1, 2, string(StatusPaid), items, stored, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.TotalAmount != stored {
+		t.Fatalf("total amount: got %s, want %s", order.TotalAmount, stored)
+	}
+
+	if _, err := RestoreOrder(0, 2, string(StatusPaid), items, stored, time.Now(), time.Now()); !errors.Is(err, ErrInvalidOrderID) {
+		t.Fatalf("expected error %v, got %v", ErrInvalidOrderID, err)
+	}
+}
+
+func TestNewOrder_InvalidStatus(t *testing.T) {
+	_, err := NewOrder(1, 2, "shipped", nil, time.Now(), time.Now())
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("expected error %v, got %v", ErrInvalidStatus, err)
+	}
+}
+
+func TestOrder_Transition(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      Status
+		to        Status
+		wantErrIs error
+	}{
+		{"new to awaiting stock", StatusNew, StatusAwaitingStock, nil},
+		{"new to cancelled", StatusNew, StatusCancelled, nil},
+		{"stock reserved", StatusAwaitingStock, StatusAwaitingPayment, nil},
+		{"stock rejected", StatusAwaitingStock, StatusOutOfStock, nil},
+		{"awaiting stock to cancelled", StatusAwaitingStock, StatusCancelled, nil},
+		{"awaiting payment to paid", StatusAwaitingPayment, StatusPaid, nil},
+		{"awaiting payment to payment failed", StatusAwaitingPayment, StatusPaymentFailed, nil},
+		{"awaiting payment to expired", StatusAwaitingPayment, StatusExpired, nil},
+		{"payment failed retry", StatusPaymentFailed, StatusAwaitingPayment, nil},
+		{"paid to fulfilled", StatusPaid, StatusFulfilled, nil},
+		{"paid to refunded", StatusPaid, StatusRefunded, nil},
+		{"fulfilled to refunded", StatusFulfilled, StatusRefunded, nil},
+		{"new to paid", StatusNew, StatusPaid, ErrInvalidTransition},
+		{"payment before stock", StatusNew, StatusAwaitingPayment, ErrInvalidTransition},
+		{"out of stock is terminal", StatusOutOfStock, StatusAwaitingPayment, ErrInvalidTransition},
+		{"paid to awaiting payment", StatusPaid, StatusAwaitingPayment, ErrInvalidTransition},
+		{"cancelled is terminal", StatusCancelled, StatusAwaitingPayment, ErrInvalidTransition},
+		{"refunded is terminal", StatusRefunded, StatusPaid, ErrInvalidTransition},
+		{"expired is terminal", StatusExpired, StatusPaid, ErrInvalidTransition},
+		{"only unpaid orders expire", StatusAwaitingStock, StatusExpired, ErrInvalidTransition},
+		{"same status", StatusPaid, StatusPaid, ErrInvalidTransition},
+		{"unknown target", StatusNew, Status("shipped"), ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{ID: 1, UserID: 2, Status: tt.from}
+
+			err := order.Transition(tt.to)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+				if order.Status != tt.from {
+					t.Fatalf("status changed on rejected transition: %s", order.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if order.Status != tt.to {
+				t.Fatalf("status: got %s, want %s", order.Status, tt.to)
+			}
+		})
+	}
+}
+
+func TestOrder_Transition_TypedError(t *testing.T) {
+	order := &Order{ID: 1, UserID: 2, Status: StatusCancelled}
+
+	err := order.Transition(StatusPaid)
+
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("expected TransitionError, got %v", err)
+	}
+	if transitionErr.From != StatusCancelled || transitionErr.To != StatusPaid {
+		t.Fatalf("unexpected transition error: %+v", transitionErr)
+	}
+}
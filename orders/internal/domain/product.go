@@ -0,0 +1,9 @@
+package domain
+
+// Product is the catalog entry of an ordered item. Its price is
+// authoritative: orders never trust the price sent by the client.
+type Product struct {
+	ID     ID
+	Price  Money
+	Active bool
+}
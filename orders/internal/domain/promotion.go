@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxPromoCodeLen bounds a promo code as entered by the client.
+const MaxPromoCodeLen = 64
+
+type DiscountKind string
+
+// A percent discount takes Value percent off; a fixed one takes Value minor
+// units of the promotion currency off.
+const (
+	DiscountPercent DiscountKind = "percent"
+	DiscountFixed   DiscountKind = "fixed"
+)
+
+type DiscountScope string
+
+// An order discount applies once to the subtotal; an item discount applies
+// to every matching line, a fixed one per unit.
+const (
+	DiscountScopeOrder DiscountScope = "order"
+	DiscountScopeItem  DiscountScope = "item"
+)
+
+// Promotion is a promo code with its discount and redemption rules. Zero
+// limits, thresholds and window bounds mean no restriction.
+type Promotion struct {
+	ID    ID
+	Code  string
+	Kind  DiscountKind
+	Scope DiscountScope
+	// Value is a percentage from 1 to 100 or an amount in minor units.
+	Value int64
+	// ProductID restricts an item discount to one product.
+	ProductID ID
+	// Currency is the currency of a fixed Value and of MinOrder.
+	Currency Currency
+	// MinOrder is the subtotal an order must reach, in minor units.
+	MinOrder       int64
+	StartsAt       time.Time
+	EndsAt         time.Time
+	MaxRedemptions int
+	MaxPerUser     int
+	Redemptions    int
+	Active         bool
+}
+
+// Adjustment is a discount applied to an order by a promotion. ProductID is
+// zero for a discount on the whole order; Amount is the positive sum taken
+// off the total.
+type Adjustment struct {
+	PromotionID ID
+	Code        string
+	ProductID   ID
+	Amount      Money
+}
+
+// NormalizePromoCode makes promo codes case and whitespace insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Redeem checks that a user who has redeemed the promotion userRedemptions
+// times may redeem it for an order of items placed at now, and returns the
+// discounts it grants.
+func (p Promotion) Redeem(items []OrderItem, userRedemptions int, now time.Time) ([]Adjustment, error) {
+	const op = "domain.Promotion.Redeem"
+
+	if !p.Active || (!p.StartsAt.IsZero() && now.Before(p.StartsAt)) || (!p.EndsAt.IsZero() && !now.Before(p.EndsAt)) {
+		return nil, fmt.Errorf("%s: %w: %s", op, ErrPromoCodeInactive, p.Code)
+	}
+	if p.MaxRedemptions > 0 && p.Redemptions >= p.MaxRedemptions {
+		return nil, fmt.Errorf("%s: %w: %s", op, ErrPromoCodeExhausted, p.Code)
+	}
+	if p.MaxPerUser > 0 && userRedemptions >= p.MaxPerUser {
+		return nil, fmt.Errorf("%s: %w: %s", op, ErrPromoCodeUsed, p.Code)
+	}
+
+	subtotal, err := TotalOf(items)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if (p.Kind == DiscountFixed || p.MinOrder > 0) && subtotal.Currency != p.Currency {
+		return nil, fmt.Errorf("%s: %w: %s is in %s", op, ErrPromoCodeNotApplicable, p.Code, p.Currency)
+	}
+	if subtotal.Amount < p.MinOrder {
+		return nil, fmt.Errorf("%s: %w: %s needs an order of %s", op, ErrPromoCodeNotApplicable, p.Code,
+			Money{Amount: p.MinOrder, Currency: p.Currency})
+	}
+
+	var adjustments []Adjustment
+	switch p.Scope {
+	case DiscountScopeOrder:
+		adjustments = p.appendDiscount(adjustments, 0, subtotal, 1)
+	case DiscountScopeItem:
+		for _, item := range items {
+			if p.ProductID != 0 && item.ProductID != p.ProductID {
+				continue
+			}
+			line, err := item.Price.Times(item.Quantity)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			adjustments = p.appendDiscount(adjustments, item.ProductID, line, item.Quantity)
+		}
+	}
+	if len(adjustments) == 0 {
+		return nil, fmt.Errorf("%s: %w: %s", op, ErrPromoCodeNotApplicable, p.Code)
+	}
+
+	return adjustments, nil
+}
+
+// appendDiscount adds the discount on amount, which covers units of the
+// discounted thing, unless it rounds down to nothing. A discount never
+// exceeds the amount it is taken from.
+func (p Promotion) appendDiscount(adjustments []Adjustment, productID ID, amount Money, units int32) []Adjustment {
+	var off int64
+	switch p.Kind {
+	case DiscountPercent:
+		off = amount.Amount/100*p.Value + amount.Amount%100*p.Value/100
+	case DiscountFixed:
+		perUnit, err := Money{Amount: p.Value}.Times(units)
+		if err != nil || perUnit.Amount > amount.Amount {
+			off = amount.Amount
+		} else {
+			off = perUnit.Amount
+		}
+	}
+	if off <= 0 {
+		return adjustments
+	}
+
+	return append(adjustments, Adjustment{
+		PromotionID: p.ID,
+		Code:        p.Code,
+		ProductID:   productID,
+		Amount:      Money{Amount: min(off, amount.Amount), Currency: amount.Currency},
+	})
+}
+
+// Discount takes adjustments off subtotal.
+func Discount(subtotal Money, adjustments []Adjustment) (Money, error) {
+	const op = "domain.Discount"
+
+	total := subtotal
+	for _, adjustment := range adjustments {
+		var err error
+		if total, err = total.Add(Money{Amount: -adjustment.Amount.Amount, Currency: adjustment.Amount.Currency}); err != nil {
+			return Money{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if total.Amount < 0 {
+		return Money{}, fmt.Errorf("%s: %w", op, ErrAmountOverflow)
+	}
+	return total, nil
+}
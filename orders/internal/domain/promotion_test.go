@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPromotion_Redeem(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rub := func(amount int64) Money { return Money{Amount: amount, Currency: "RUB"} }
+	items := []OrderItem{
+		{ProductID: 10, Quantity: 2, Price: rub(1000)},
+		{ProductID: 11, Quantity: 1, Price: rub(333)},
+	}
+	promo := func(kind DiscountKind, scope DiscountScope, value int64) Promotion {
+		return Promotion{ID: 1, Code: "SALE", Kind: kind, Scope: scope, Value: value, Currency: "RUB", Active: true}
+	}
+
+	tests := []struct {
+		name            string
+		promo           Promotion
+		items           []OrderItem
+		userRedemptions int
+		want            []Adjustment
+		wantErrIs       error
+	}{
+		{
+			name:  "percent off the order",
+			promo: promo(DiscountPercent, DiscountScopeOrder, 10),
+			want:  []Adjustment{{PromotionID: 1, Code: "SALE", Amount: rub(233)}},
+		},
+		{
+			name:  "fixed off the order",
+			promo: promo(DiscountFixed, DiscountScopeOrder, 500),
+			want:  []Adjustment{{PromotionID: 1, Code: "SALE", Amount: rub(500)}},
+		},
+		{
+			name:  "fixed discount capped by the subtotal",
+			promo: promo(DiscountFixed, DiscountScopeOrder, 5000),
+			want:  []Adjustment{{PromotionID: 1, Code: "SALE", Amount: rub(2333)}},
+		},
+		{
+			name:  "percent off every item",
+			promo: promo(DiscountPercent, DiscountScopeItem, 50),
+			want: []Adjustment{
+				{PromotionID: 1, Code: "SALE", ProductID: 10, Amount: rub(1000)},
+				{PromotionID: 1, Code: "SALE", ProductID: 11, Amount: rub(166)},
+			},
+		},
+		{
+			name: "fixed off each unit of one product",
+			promo: func() Promotion {
+				p := promo(DiscountFixed, DiscountScopeItem, 150)
+				p.ProductID = 10
+				return p
+			}(),
+			want: []Adjustment{{PromotionID: 1, Code: "SALE", ProductID: 10, Amount: rub(300)}},
+		},
+		{
+			name: "product not in the order",
+			promo: func() Promotion {
+				p := promo(DiscountPercent, DiscountScopeItem, 10)
+				p.ProductID = 12
+				return p
+			}(),
+			wantErrIs: ErrPromoCodeNotApplicable,
+		},
+		{
+			name: "below the minimum order",
+			promo: func() Promotion {
+				p := promo(DiscountPercent, DiscountScopeOrder, 10)
+				p.MinOrder = 5000
+				return p
+			}(),
+			wantErrIs: ErrPromoCodeNotApplicable,
+		},
+		{
+			name:      "fixed discount in another currency",
+			promo:     promo(DiscountFixed, DiscountScopeOrder, 5),
+			items:     []OrderItem{{ProductID: 11, Quantity: 1, Price: Money{Amount: 500, Currency: "USD"}}},
+			wantErrIs: ErrPromoCodeNotApplicable,
+		},
+		{
+			name:      "percent rounds down to nothing",
+			promo:     promo(DiscountPercent, DiscountScopeOrder, 1),
+			items:     []OrderItem{{ProductID: 11, Quantity: 1, Price: rub(50)}},
+			wantErrIs: ErrPromoCodeNotApplicable,
+		},
+		{
+			name: "not active",
+			promo: func() Promotion {
+				p := promo(DiscountPercent, DiscountScopeOrder, 10)
+				p.Active = false
+				return p
+			}(),
+			wantErrIs: ErrPromoCodeInactive,
+		},
+		{
+			name: "not started",
+			promo: func() Promotion {
+				p := promo(DiscountPercent, DiscountScopeOrder, 10)
+				p.StartsAt = now.Add(time.Hour)
+				return p
+			}(),
+			wantErrIs: ErrPromoCodeInactive,
+		},
+		{
+			name: "ended",
+			promo: func() Promotion {
+				p := promo(DiscountPercent, DiscountScopeOrder, 10)
+				p.EndsAt = now
+				return p
+			}(),
+			wantErrIs: ErrPromoCodeInactive,
+		},
+		{
+			name: "global limit reached",
+			promo: func() Promotion {
+				p := promo(DiscountPercent, DiscountScopeOrder, 10)
+				p.MaxRedemptions, p.Redemptions = 100, 100
+				return p
+			}(),
+			wantErrIs: ErrPromoCodeExhausted,
+		},
+		{
+			name: "user limit reached",
+			promo: func() Promotion {
+				p := promo(DiscountPercent, DiscountScopeOrder, 10)
+				p.MaxPerUser = 1
+				return p
+			}(),
+			userRedemptions: 1,
+			wantErrIs:       ErrPromoCodeUsed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderItems := tt.items
+			if orderItems == nil {
+				orderItems = items
+			}
+
+			got, err := tt.promo.Redeem(orderItems, tt.userRedemptions, now)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("adjustments: got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	subtotal := Money{Amount: 1000, Currency: "RUB"}
+	adjustments := []Adjustment{
+		{ProductID: 10, Amount: Money{Amount: 150, Currency: "RUB"}},
+		{Amount: Money{Amount: 50, Currency: "RUB"}},
+	}
+
+	got, err := Discount(subtotal, adjustments)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != (Money{Amount: 800, Currency: "RUB"}) {
+		t.Fatalf("total: got %s, want 800 RUB", got)
+	}
+
+	if _, err := Discount(subtotal, []Adjustment{{Amount: Money{Amount: 1001, Currency: "RUB"}}}); !errors.Is(err, ErrAmountOverflow) {
+		t.Fatalf("discount above subtotal: got %v, want %v", err, ErrAmountOverflow)
+	}
+	if _, err := Discount(subtotal, []Adjustment{{Amount: Money{Amount: 1, Currency: "USD"}}}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("discount in another currency: got %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestNormalizePromoCode(t *testing.T) {
+	if got := NormalizePromoCode("  spring-10 "); got != "SPRING-10" {
+		t.Fatalf("code: got %q, want %q", got, "SPRING-10")
+	}
+}
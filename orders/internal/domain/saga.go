@@ -0,0 +1,109 @@
+package domain
+
+import "time"
+
+type SagaStep string
+
+// The order saga reserves stock, then charges the payment. A saga ends
+// completed once the order is paid, or compensated when it is rejected,
+// fails, is cancelled, expires or runs out of time.
+const (
+	SagaStepReserveStock  SagaStep = "reserve_stock"
+	SagaStepChargePayment SagaStep = "charge_payment"
+	SagaStepCompleted     SagaStep = "completed"
+	SagaStepCompensated   SagaStep = "compensated"
+)
+
+func (s SagaStep) Final() bool {
+	return s == SagaStepCompleted || s == SagaStepCompensated
+}
+
+// Retryable reports whether the command of the step may be issued again when
+// its reply is late. Inventory answers a repeated OrderCreated with the
+// outcome it recorded; payments would charge twice, so the payment step is
+// never retried.
+func (s SagaStep) Retryable() bool {
+	return s == SagaStepReserveStock
+}
+
+// Saga is the orchestration state of one order. DeadlineAt is zero when the
+// step has no timeout or the saga is over.
+type Saga struct {
+	OrderID    ID
+	Step       SagaStep
+	Attempts   int
+	DeadlineAt time.Time
+	LastError  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewSaga starts the saga of a new order at the stock reservation step; a
+// zero timeout leaves its steps without deadline.
+func NewSaga(orderID int64, now time.Time, timeout time.Duration) Saga {
+	saga := Saga{OrderID: ID(orderID), CreatedAt: now}
+	saga.enter(SagaStepReserveStock, now, timeout)
+	return saga
+}
+
+// Apply moves the saga along after its order reached status and reports
+// whether anything changed. Statuses past the end of the saga, such as a
+// refund of a paid order, leave it as is.
+func (s *Saga) Apply(status Status, now time.Time, timeout time.Duration) bool {
+	if s.Step.Final() {
+		return false
+	}
+
+	switch status {
+	case StatusAwaitingPayment:
+		if s.Step == SagaStepChargePayment {
+			return false
+		}
+		s.enter(SagaStepChargePayment, now, timeout)
+	case StatusPaid:
+		s.finish(SagaStepCompleted, "", now)
+	case StatusOutOfStock, StatusPaymentFailed, StatusCancelled, StatusExpired:
+		s.finish(SagaStepCompensated, "order "+string(status), now)
+	default:
+		return false
+	}
+	return true
+}
+
+// Retry records that the command of the current step was issued again.
+func (s *Saga) Retry(now time.Time, timeout time.Duration) {
+	s.Attempts++
+	s.DeadlineAt = deadline(now, timeout)
+	s.UpdatedAt = now
+}
+
+// Compensate ends the saga with reason, before the order is rolled back.
+func (s *Saga) Compensate(reason string, now time.Time) {
+	s.finish(SagaStepCompensated, reason, now)
+}
+
+// Expired reports whether the current step ran past its deadline.
+func (s Saga) Expired(now time.Time) bool {
+	return !s.Step.Final() && !s.DeadlineAt.IsZero() && !now.Before(s.DeadlineAt)
+}
+
+func (s *Saga) enter(step SagaStep, now time.Time, timeout time.Duration) {
+	s.Step = step
+	s.Attempts = 1
+	s.DeadlineAt = deadline(now, timeout)
+	s.UpdatedAt = now
+}
+
+func (s *Saga) finish(step SagaStep, reason string, now time.Time) {
+	s.Step = step
+	s.DeadlineAt = time.Time{}
+	s.LastError = reason
+	s.UpdatedAt = now
+}
+
+func deadline(now time.Time, timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return now.Add(timeout)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaga_Apply(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := now.Add(time.Minute)
+	timeout := 10 * time.Minute
+
+	tests := []struct {
+		name        string
+		step        SagaStep
+		status      Status
+		wantChanged bool
+		wantStep    SagaStep
+		wantError   string
+		wantDue     time.Time
+	}{
+		{"stock reserved", SagaStepReserveStock, StatusAwaitingPayment, true, SagaStepChargePayment, "", later.Add(timeout)},
+		{"out of stock", SagaStepReserveStock, StatusOutOfStock, true, SagaStepCompensated, "order out_of_stock", time.Time{}},
+		{"paid", SagaStepChargePayment, StatusPaid, true, SagaStepCompleted, "", time.Time{}},
+		{"payment failed", SagaStepChargePayment, StatusPaymentFailed, true, SagaStepCompensated, "order payment_failed", time.Time{}},
+		{"cancelled", SagaStepChargePayment, StatusCancelled, true, SagaStepCompensated, "order cancelled", time.Time{}},
+		{"expired", SagaStepChargePayment, StatusExpired, true, SagaStepCompensated, "order expired", time.Time{}},
+		{"payment requested twice", SagaStepChargePayment, StatusAwaitingPayment, false, SagaStepChargePayment, "", now.Add(timeout)},
+		{"refund after completion", SagaStepCompleted, StatusRefunded, false, SagaStepCompleted, "", time.Time{}},
+		{"cancel after completion", SagaStepCompleted, StatusCancelled, false, SagaStepCompleted, "", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saga := NewSaga(1, now, timeout)
+			saga.Step = tt.step
+			if tt.step.Final() {
+				saga.DeadlineAt = time.Time{}
+			}
+
+			changed := saga.Apply(tt.status, later, timeout)
+			if changed != tt.wantChanged {
+				t.Fatalf("changed: got %v, want %v", changed, tt.wantChanged)
+			}
+			if saga.Step != tt.wantStep {
+				t.Fatalf("step: got %s, want %s", saga.Step, tt.wantStep)
+			}
+			if saga.LastError != tt.wantError {
+				t.Fatalf("last error: got %q, want %q", saga.LastError, tt.wantError)
+			}
+			if !saga.DeadlineAt.Equal(tt.wantDue) {
+				t.Fatalf("deadline: got %s, want %s", saga.DeadlineAt, tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestSaga_Deadlines(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	saga := NewSaga(1, now, time.Minute)
+	if saga.Step != SagaStepReserveStock || saga.Attempts != 1 {
+		t.Fatalf("new saga: got step %s attempts %d", saga.Step, saga.Attempts)
+	}
+	if saga.Expired(now.Add(59 * time.Second)) {
+		t.Fatalf("saga expired before its deadline")
+	}
+	if !saga.Expired(now.Add(time.Minute)) {
+		t.Fatalf("saga not expired at its deadline")
+	}
+
+	saga.Retry(now.Add(time.Minute), time.Minute)
+	if saga.Attempts != 2 || !saga.DeadlineAt.Equal(now.Add(2*time.Minute)) {
+		t.Fatalf("retried saga: got attempts %d deadline %s", saga.Attempts, saga.DeadlineAt)
+	}
+
+	saga.Compensate("reserve_stock timed out", now.Add(2*time.Minute))
+	if saga.Step != SagaStepCompensated || saga.Expired(now.Add(time.Hour)) {
+		t.Fatalf("compensated saga: got step %s, expired %v", saga.Step, saga.Expired(now.Add(time.Hour)))
+	}
+
+	untimed := NewSaga(2, now, 0)
+	if !untimed.DeadlineAt.IsZero() || untimed.Expired(now.Add(24*time.Hour)) {
+		t.Fatalf("saga without timeout got deadline %s", untimed.DeadlineAt)
+	}
+}
@@ -1,7 +1,52 @@
 package domain
 
+import "slices"
+
 type Status string
 
 const (
-	StatusNew Status = "new"
+	StatusNew             Status = "new"
+	StatusAwaitingStock   Status = "awaiting_stock"
+	StatusOutOfStock      Status = "out_of_stock"
+	StatusAwaitingPayment Status = "awaiting_payment"
+	StatusPaid            Status = "paid"
+	StatusPaymentFailed   Status = "payment_failed"
+	StatusFulfilled       Status = "fulfilled"
+	StatusCancelled       Status = "cancelled"
+	StatusRefunded        Status = "refunded"
+	StatusExpired         Status = "expired"
+)
+
+const (
+	PaymentStatusSucceeded = "succeeded"
+	PaymentStatusFailed    = "failed"
 )
+
+// transitions is the order saga: a new order waits for inventory to reserve
+// its stock, and only a reserved order waits for payment. An order left
+// unpaid past its payment deadline expires.
+var transitions = map[Status][]Status{
+	StatusNew:             {StatusAwaitingStock, StatusCancelled},
+	StatusAwaitingStock:   {StatusAwaitingPayment, StatusOutOfStock, StatusCancelled},
+	StatusOutOfStock:      {},
+	StatusAwaitingPayment: {StatusPaid, StatusPaymentFailed, StatusCancelled, StatusExpired},
+	StatusPaymentFailed:   {StatusAwaitingPayment, StatusCancelled},
+	StatusPaid:            {StatusFulfilled, StatusCancelled, StatusRefunded},
+	StatusFulfilled:       {StatusRefunded},
+	StatusCancelled:       {},
+	StatusRefunded:        {},
+	StatusExpired:         {},
+}
+
+func (s Status) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+func (s Status) CanTransition(to Status) bool {
+	return slices.Contains(transitions[s], to)
+}
+
+func (s Status) Terminal() bool {
+	return s.Valid() && len(transitions[s]) == 0
+}
@@ -0,0 +1,160 @@
+package kafka_consume
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/metrics"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const (
+	sessionTimeoutMs = 6000
+	readTimeout      = time.Second
+)
+
+// serviceName labels the dead-letter metric.
+const serviceName = "orders"
+
+type Handler interface {
+	HandleMessage(ctx context.Context, contentType string, message []byte) error
+}
+
+type Consumer struct {
+	consumer *kafka.Consumer
+	handler  Handler
+	producer deadletter.Producer
+	dlq      deadletter.Config
+	log      *slog.Logger
+	topic    string
+	group    string
+}
+
+func NewConsumer(
+	handler Handler, producer deadletter.Producer, dlq deadletter.Config,
+	address []string, topic, consumerGroup string, log *slog.Logger) (*Consumer, error) {
+	const op = "kafka_consume.NewConsumer"
+	cfg := &kafka.ConfigMap{
+		"bootstrap.servers":  strings.Join(address, ","),
+		"group.id":           consumerGroup,
+		"session.timeout.ms": sessionTimeoutMs,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	}
+
+	c, err := kafka.NewConsumer(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.Subscribe(topic, nil); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if dlq.Topic == "" {
+		dlq.Topic = deadletter.Topic(topic)
+	}
+
+	return &Consumer{
+		consumer: c,
+		handler:  handler,
+		producer: producer,
+		dlq:      dlq,
+		log:      log,
+		topic:    topic,
+		group:    consumerGroup,
+	}, nil
+}
+
+func (c *Consumer) Start(ctx context.Context) {
+	const op = "kafka_consume.Consumer.Start"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("topic", c.topic),
+		slog.String("group", c.group),
+	)
+
+	log.Info("consumer started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("consumer stopping", slog.Any("err", ctx.Err()))
+			return
+		default:
+		}
+
+		kafkaMsg, err := c.consumer.ReadMessage(readTimeout)
+		if err != nil {
+			if kerr, ok := err.(kafka.Error); ok && kerr.Code() == kafka.ErrTimedOut {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			log.Error("read message failed", slog.Any("err", err))
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		if kafkaMsg == nil {
+			continue
+		}
+
+		msgLog := log.With(
+			slog.Int("partition", int(kafkaMsg.TopicPartition.Partition)),
+			slog.Int64("offset", int64(kafkaMsg.TopicPartition.Offset)))
+
+		// a message is committed only once handled or dead-lettered; otherwise
+		// the partition is rewound to it so later offsets cannot be committed
+		// past it
+		if err := c.handle(ctx, kafkaMsg, msgLog); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			msgLog.Error("message not handled, rewinding", slog.Any("err", err))
+			if err := c.consumer.Seek(kafkaMsg.TopicPartition, int(readTimeout.Milliseconds())); err != nil {
+				msgLog.Error("seek failed", slog.Any("err", err))
+			}
+			continue
+		}
+
+		if _, err := c.consumer.CommitMessage(kafkaMsg); err != nil {
+			log.Error("commit failed", slog.Any("err", err))
+		}
+	}
+}
+
+// handle runs the handler under the consumer's dead-letter policy. A nil
+// error means the offset may be committed.
+func (c *Consumer) handle(ctx context.Context, msg *kafka.Message, log *slog.Logger) error {
+	dead, err := deadletter.Handle(ctx, msg, c.dlq, c.producer, log, func(ctx context.Context) error {
+		return c.handler.HandleMessage(ctx, contentType(msg.Headers), msg.Value)
+	})
+	if dead {
+		metrics.DLQMessagesTotal.WithLabelValues(serviceName, c.topic).Inc()
+	}
+	return err
+}
+
+// contentType returns the content-type header value; producers that predate
+// the header send JSON.
+func contentType(headers []kafka.Header) string {
+	for _, h := range headers {
+		if h.Key == envelope.HeaderContentType {
+			return string(h.Value)
+		}
+	}
+	return envelope.ContentTypeJSON
+}
+
+func (c *Consumer) Stop() error {
+	c.log.Info("consumer stopping")
+	return c.consumer.Close()
+}
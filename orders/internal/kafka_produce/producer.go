@@ -33,14 +33,16 @@ func NewProducer(address []string) (*Producer, error) {
 	return &Producer{producer: p}, nil
 }
 
-func (p *Producer) Produce(ctx context.Context, message []byte, topic string) error {
+// Produce publishes message to topic. Messages sharing a key land on the same
+// partition, so consumers see them in publish order.
+func (p *Producer) Produce(ctx context.Context, key, message []byte, topic string) error {
 	const op = "kafka_produce.Produce"
 	kafkaMsg := &kafka.Message{
 		TopicPartition: kafka.TopicPartition{
 			Topic:     &topic,
 			Partition: kafka.PartitionAny},
 		Value: message,
-		Key:   nil,
+		Key:   key,
 	}
 
 	kafkaChan := make(chan kafka.Event, 1)
@@ -65,6 +67,81 @@ func (p *Producer) Produce(ctx context.Context, message []byte, topic string) er
 	}
 }
 
+// ProduceMessage publishes a prepared message, keeping its headers, and
+// waits for the delivery report.
+func (p *Producer) ProduceMessage(ctx context.Context, kafkaMsg *kafka.Message) error {
+	const op = "kafka_produce.ProduceMessage"
+
+	kafkaChan := make(chan kafka.Event, 1)
+	if err := p.producer.Produce(kafkaMsg, kafkaChan); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", op, ctx.Err())
+	case ans := <-kafkaChan:
+		switch ev := ans.(type) {
+		case *kafka.Message:
+			if err := ev.TopicPartition.Error; err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			return nil
+		default:
+			return fmt.Errorf("%s: unexpected event type: %T", op, ans)
+		}
+	}
+}
+
+// ProduceBatch enqueues all messages at once and collects their delivery
+// reports from a shared channel. errs[i] is the outcome of messages[i].
+func (p *Producer) ProduceBatch(ctx context.Context, messages []*kafka.Message) []error {
+	const op = "kafka_produce.ProduceBatch"
+
+	errs := make([]error, len(messages))
+	done := make([]bool, len(messages))
+	delivery := make(chan kafka.Event, len(messages))
+
+	pending := 0
+	for i, msg := range messages {
+		msg.Opaque = i
+		if err := p.producer.Produce(msg, delivery); err != nil {
+			errs[i] = fmt.Errorf("%s: %w", op, err)
+			done[i] = true
+			continue
+		}
+		pending++
+	}
+
+	for pending > 0 {
+		select {
+		case <-ctx.Done():
+			for i := range messages {
+				if !done[i] {
+					errs[i] = fmt.Errorf("%s: %w", op, ctx.Err())
+				}
+			}
+			return errs
+		case ans := <-delivery:
+			ev, ok := ans.(*kafka.Message)
+			if !ok {
+				continue
+			}
+			i, ok := ev.Opaque.(int)
+			if !ok || i < 0 || i >= len(messages) || done[i] {
+				continue
+			}
+			if err := ev.TopicPartition.Error; err != nil {
+				errs[i] = fmt.Errorf("%s: %w", op, err)
+			}
+			done[i] = true
+			pending--
+		}
+	}
+
+	return errs
+}
+
 func (p *Producer) Close() error {
 	remaining := p.producer.Flush(flushTimeoutMs)
 	p.producer.Close()
@@ -18,10 +18,34 @@ func MapDomainError(err error) (codes.Code, string) {
 		errors.Is(err, domain.ErrInvalidProductID),
 		errors.Is(err, domain.ErrInvalidQuantity),
 		errors.Is(err, domain.ErrInvalidPrice),
-		errors.Is(err, domain.ErrInvalidItems):
+		errors.Is(err, domain.ErrInvalidCurrency),
+		errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, domain.ErrAmountOverflow),
+		errors.Is(err, domain.ErrTotalTooLarge),
+		errors.Is(err, domain.ErrInvalidItems),
+		errors.Is(err, domain.ErrUnknownProduct),
+		errors.Is(err, domain.ErrPriceMismatch),
+		errors.Is(err, domain.ErrInvalidIdempotencyKey),
+		errors.Is(err, domain.ErrInvalidPromoCode),
+		errors.Is(err, domain.ErrUnknownPromoCode),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidPageSize),
+		errors.Is(err, domain.ErrInvalidTimeRange):
 		return codes.InvalidArgument, err.Error()
-	case errors.Is(err, domain.ErrOrderNotFound):
+	case errors.Is(err, domain.ErrOrderNotFound),
+		errors.Is(err, domain.ErrSagaNotFound):
 		return codes.NotFound, err.Error()
+	case errors.Is(err, domain.ErrInvalidTransition),
+		errors.Is(err, domain.ErrPromoCodeInactive),
+		errors.Is(err, domain.ErrPromoCodeExhausted),
+		errors.Is(err, domain.ErrPromoCodeUsed),
+		errors.Is(err, domain.ErrPromoCodeNotApplicable):
+		return codes.FailedPrecondition, err.Error()
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return codes.AlreadyExists, err.Error()
+	case errors.Is(err, domain.ErrVersionConflict):
+		return codes.Aborted, err.Error()
 	default:
 		return codes.Internal, "internal error"
 	}
@@ -13,9 +13,9 @@ func MapProtoItems(items []*ordersv1.OrderItem) ([]domain.OrderItem, error) {
 
 	res := make([]domain.OrderItem, 0, len(items))
 	for _, it := range items {
-		var price int64
-		if it.GetPrice() != nil {
-			price = it.GetPrice().GetMoney()
+		price, err := domain.NewMoney(it.GetPrice().GetMoney(), it.GetPrice().GetCurrency())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 		o, err := domain.NewOrderItem(it.ProductId, it.Quantity, price)
 		if err != nil {
@@ -31,7 +31,11 @@ func MapInputItems(inputItems []dto.CreateOrderItem) ([]domain.OrderItem, error)
 
 	res := make([]domain.OrderItem, 0, len(inputItems))
 	for _, it := range inputItems {
-		item, err := domain.NewOrderItem(it.ProductID, it.Quantity, it.Price)
+		price, err := domain.NewMoney(it.Price, it.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		item, err := domain.NewOrderItem(it.ProductID, it.Quantity, price)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -43,14 +47,11 @@ func MapInputItems(inputItems []dto.CreateOrderItem) ([]domain.OrderItem, error)
 func MapToCreateItems(items []*ordersv1.OrderItem) ([]dto.CreateOrderItem, error) {
 	res := make([]dto.CreateOrderItem, 0, len(items))
 	for _, it := range items {
-		var price int64
-		if it.GetPrice() != nil {
-			price = it.GetPrice().GetMoney()
-		}
 		o := dto.CreateOrderItem{
 			ProductID: it.ProductId,
 			Quantity:  it.Quantity,
-			Price:     price,
+			Price:     it.GetPrice().GetMoney(),
+			Currency:  it.GetPrice().GetCurrency(),
 		}
 		res = append(res, o)
 	}
@@ -11,8 +11,8 @@ import (
 
 func TestMapInputItems_OK(t *testing.T) {
 	input := []dto.CreateOrderItem{
-		{ProductID: 10, Quantity: 2, Price: 100},
-		{ProductID: 20, Quantity: 1, Price: 50},
+		{ProductID: 10, Quantity: 2, Price: 100, Currency: "USD"},
+		{ProductID: 20, Quantity: 1, Price: 50, Currency: "USD"},
 	}
 
 	items, err := MapInputItems(input)
@@ -22,7 +22,7 @@ func TestMapInputItems_OK(t *testing.T) {
 	if len(items) != 2 {
 		t.Fatalf("items count mismatch: got %d, want %d", len(items), 2)
 	}
-	if items[0].ProductID != 10 || items[0].Quantity != 2 || items[0].Price != 100 {
+	if items[0].ProductID != 10 || items[0].Quantity != 2 || items[0].Price != (domain.Money{Amount: 100, Currency: "USD"}) {
 		t.Fatalf("unexpected first item: %+v", items[0])
 	}
 }
@@ -54,7 +54,7 @@ func TestMapProtoItems_OK(t *testing.T) {
 	if len(items) != 2 {
 		t.Fatalf("items count mismatch: got %d, want %d", len(items), 2)
 	}
-	if items[1].ProductID != 2 || items[1].Quantity != 1 || items[1].Price != 50 {
+	if items[1].ProductID != 2 || items[1].Quantity != 1 || items[1].Price != (domain.Money{Amount: 50, Currency: domain.DefaultCurrency}) {
 		t.Fatalf("unexpected second item: %+v", items[1])
 	}
 }
@@ -73,9 +73,20 @@ func TestMapProtoItems_InvalidPrice(t *testing.T) {
 	}
 }
 
+func TestMapInputItems_InvalidCurrency(t *testing.T) {
+	input := []dto.CreateOrderItem{
+		{ProductID: 1, Quantity: 1, Price: 10, Currency: "dollars"},
+	}
+
+	_, err := MapInputItems(input)
+	if !errors.Is(err, domain.ErrInvalidCurrency) {
+		t.Fatalf("expected error %v, got %v", domain.ErrInvalidCurrency, err)
+	}
+}
+
 func TestMapToCreateItems_OK(t *testing.T) {
 	input := []*ordersv1.OrderItem{
-		{ProductId: 1, Quantity: 2, Price: &ordersv1.Money{Money: 100}},
+		{ProductId: 1, Quantity: 2, Price: &ordersv1.Money{Money: 100, Currency: "EUR"}},
 		{ProductId: 2, Quantity: 1, Price: nil},
 	}
 
@@ -86,6 +97,9 @@ func TestMapToCreateItems_OK(t *testing.T) {
 	if len(items) != 2 {
 		t.Fatalf("items count mismatch: got %d, want %d", len(items), 2)
 	}
+	if items[0].Currency != "EUR" {
+		t.Fatalf("currency: got %q, want %q", items[0].Currency, "EUR")
+	}
 	if items[1].Price != 0 {
 		t.Fatalf("expected zero price for nil money, got %d", items[1].Price)
 	}
@@ -12,7 +12,16 @@ func MapToProto(order domain.Order) *ordersv1.Order {
 		items = append(items, &ordersv1.OrderItem{
 			ProductId: int64(it.ProductID),
 			Quantity:  it.Quantity,
-			Price:     &ordersv1.Money{Money: int64(it.Price)},
+			Price:     MapMoneyToProto(it.Price),
+		})
+	}
+
+	var adjustments []*ordersv1.OrderAdjustment
+	for _, adj := range order.Adjustments {
+		adjustments = append(adjustments, &ordersv1.OrderAdjustment{
+			Code:      adj.Code,
+			ProductId: int64(adj.ProductID),
+			Amount:    MapMoneyToProto(adj.Amount),
 		})
 	}
 
@@ -34,8 +43,13 @@ func MapToProto(order domain.Order) *ordersv1.Order {
 		UserId:      int64(order.UserID),
 		Status:      statusProto,
 		Items:       items,
-		TotalAmount: &ordersv1.Money{Money: int64(order.TotalAmount)},
+		TotalAmount: MapMoneyToProto(order.TotalAmount),
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+		Adjustments: adjustments,
 	}
 }
+
+func MapMoneyToProto(m domain.Money) *ordersv1.Money {
+	return &ordersv1.Money{Money: m.Amount, Currency: string(m.Currency)}
+}
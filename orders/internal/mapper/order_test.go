@@ -10,7 +10,7 @@ import (
 
 func TestMapToProto_OK(t *testing.T) {
 	items := []domain.OrderItem{
-		{ProductID: 1, Quantity: 2, Price: 100},
+		{ProductID: 1, Quantity: 2, Price: domain.Money{Amount: 100, Currency: "USD"}},
 	}
 	createdAt := time.Now().UTC()
 	updatedAt := createdAt.Add(time.Minute)
@@ -30,8 +30,8 @@ func TestMapToProto_OK(t *testing.T) {
 	if len(proto.Items) != 1 {
 		t.Fatalf("items count mismatch: got %d, want %d", len(proto.Items), 1)
 	}
-	if proto.TotalAmount.GetMoney() != int64(order.TotalAmount) {
-		t.Fatalf("total mismatch: got %d, want %d", proto.TotalAmount.GetMoney(), order.TotalAmount)
+	if proto.TotalAmount.GetMoney() != 200 || proto.TotalAmount.GetCurrency() != "USD" {
+		t.Fatalf("total mismatch: got %d %s, want %s", proto.TotalAmount.GetMoney(), proto.TotalAmount.GetCurrency(), order.TotalAmount)
 	}
 	if proto.CreatedAt == nil || proto.UpdatedAt == nil {
 		t.Fatalf("expected timestamps to be set")
@@ -44,7 +44,7 @@ func TestMapToProto_ZeroTimestamps(t *testing.T) {
 		UserID: 2,
 		Status: domain.StatusNew,
 		Items: []domain.OrderItem{
-			{ProductID: 1, Quantity: 2, Price: 100},
+			{ProductID: 1, Quantity: 2, Price: domain.Money{Amount: 100, Currency: "RUB"}},
 		},
 	}
 
@@ -53,3 +53,23 @@ func TestMapToProto_ZeroTimestamps(t *testing.T) {
 		t.Fatalf("expected nil timestamps for zero time")
 	}
 }
+
+func TestMapToProto_Adjustments(t *testing.T) {
+	order := domain.Order{
+		ID:          1,
+		UserID:      2,
+		Status:      domain.StatusNew,
+		Items:       []domain.OrderItem{{ProductID: 1, Quantity: 2, Price: domain.Money{Amount: 100, Currency: "RUB"}}},
+		TotalAmount: domain.Money{Amount: 170, Currency: "RUB"},
+		Adjustments: []domain.Adjustment{{PromotionID: 5, Code: "SPRING", ProductID: 1, Amount: domain.Money{Amount: 30, Currency: "RUB"}}},
+	}
+
+	proto := MapToProto(order)
+	if len(proto.Adjustments) != 1 {
+		t.Fatalf("adjustments count mismatch: got %d, want %d", len(proto.Adjustments), 1)
+	}
+	adj := proto.Adjustments[0]
+	if adj.Code != "SPRING" || adj.ProductId != 1 || adj.GetAmount().GetMoney() != 30 || adj.GetAmount().GetCurrency() != "RUB" {
+		t.Fatalf("unexpected adjustment: %+v", adj)
+	}
+}
@@ -0,0 +1,26 @@
+package mapper
+
+import (
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	ordersv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/orders/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MapSagaToProto maps the saga state of an order; a saga without deadline
+// has no deadline_at.
+func MapSagaToProto(saga domain.Saga) *ordersv1.OrderSaga {
+	var deadlineAt *timestamppb.Timestamp
+	if !saga.DeadlineAt.IsZero() {
+		deadlineAt = timestamppb.New(saga.DeadlineAt)
+	}
+
+	return &ordersv1.OrderSaga{
+		OrderId:    int64(saga.OrderID),
+		Step:       string(saga.Step),
+		Attempts:   int32(saga.Attempts),
+		DeadlineAt: deadlineAt,
+		LastError:  saga.LastError,
+		CreatedAt:  timestamppb.New(saga.CreatedAt),
+		UpdatedAt:  timestamppb.New(saga.UpdatedAt),
+	}
+}
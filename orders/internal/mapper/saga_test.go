@@ -0,0 +1,27 @@
+package mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+func TestMapSagaToProto(t *testing.T) {
+	now := time.Now().UTC()
+
+	saga := domain.NewSaga(7, now, time.Minute)
+	proto := MapSagaToProto(saga)
+	if proto.OrderId != 7 || proto.Step != "reserve_stock" || proto.Attempts != 1 {
+		t.Fatalf("unexpected saga: %+v", proto)
+	}
+	if proto.DeadlineAt == nil || !proto.DeadlineAt.AsTime().Equal(now.Add(time.Minute)) {
+		t.Fatalf("deadline: got %v, want %s", proto.DeadlineAt, now.Add(time.Minute))
+	}
+
+	saga.Compensate("charge_payment timed out after 1 attempts", now)
+	proto = MapSagaToProto(saga)
+	if proto.DeadlineAt != nil || proto.LastError != saga.LastError {
+		t.Fatalf("finished saga: got deadline %v, last error %q", proto.DeadlineAt, proto.LastError)
+	}
+}
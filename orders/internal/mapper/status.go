@@ -1,15 +1,39 @@
 package mapper
 
 import (
+	"fmt"
+
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 	ordersv1 "github.com/ChernykhITMO/order-processing-proto/gen/go/opp/orders/v1"
 )
 
 func MapStatusToProto(status domain.Status) ordersv1.OrderStatus {
+	// enum value names in the contract mirror domain status strings
+	if value, ok := ordersv1.OrderStatus_value[string(status)]; ok {
+		return ordersv1.OrderStatus(value)
+	}
+	return ordersv1.OrderStatus_unspecified
+}
+
+func MapStatusFromProto(status ordersv1.OrderStatus) (domain.Status, error) {
+	const op = "mapper.StatusFromProto"
+
+	name, ok := ordersv1.OrderStatus_name[int32(status)]
+	if !ok || status == ordersv1.OrderStatus_unspecified || !domain.Status(name).Valid() {
+		return "", fmt.Errorf("%s: %w", op, domain.ErrInvalidStatus)
+	}
+	return domain.Status(name), nil
+}
+
+func MapPaymentStatus(status string) (domain.Status, error) {
+	const op = "mapper.PaymentStatus"
+
 	switch status {
-	case domain.StatusNew:
-		return ordersv1.OrderStatus_new
+	case domain.PaymentStatusSucceeded:
+		return domain.StatusPaid, nil
+	case domain.PaymentStatusFailed:
+		return domain.StatusPaymentFailed, nil
 	default:
+		return "", fmt.Errorf("%s: %w", op, domain.ErrInvalidPaymentStatus)
 	}
-	return ordersv1.OrderStatus_unspecified
 }
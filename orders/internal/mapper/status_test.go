@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
@@ -20,3 +21,66 @@ func TestMapStatusToProto_Unknown(t *testing.T) {
 		t.Fatalf("expected %v, got %v", ordersv1.OrderStatus_unspecified, got)
 	}
 }
+
+func TestMapPaymentStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		want      domain.Status
+		wantErrIs error
+	}{
+		{"succeeded", domain.PaymentStatusSucceeded, domain.StatusPaid, nil},
+		{"failed", domain.PaymentStatusFailed, domain.StatusPaymentFailed, nil},
+		{"unknown", "pending", "", domain.ErrInvalidPaymentStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MapPaymentStatus(tt.status)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMapStatusFromProto(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    ordersv1.OrderStatus
+		want      domain.Status
+		wantErrIs error
+	}{
+		{"new", ordersv1.OrderStatus_new, domain.StatusNew, nil},
+		{"paid", ordersv1.OrderStatus(ordersv1.OrderStatus_value["paid"]), domain.StatusPaid, nil},
+		{"unspecified", ordersv1.OrderStatus_unspecified, "", domain.ErrInvalidStatus},
+		{"out of range", ordersv1.OrderStatus(99), "", domain.ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MapStatusFromProto(tt.status)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
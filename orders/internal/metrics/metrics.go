@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	DLQMessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "kafka",
+			Name:      "dlq_messages_total",
+			Help:      "Messages published to a dead-letter topic",
+		}, []string{"service", "topic"})
+
+	OutboxPrunedRowsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "outbox",
+			Name:      "pruned_rows_total",
+			Help:      "Rows removed by the outbox retention janitor",
+		}, []string{"service", "table"})
+
+	OutboxDeadEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "outbox",
+			Name:      "dead_events_total",
+			Help:      "Outbox events dead-lettered after failing to encode too many times",
+		}, []string{"service", "event_type"})
+
+	SagaTimeoutsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "saga",
+			Name:      "timeouts_total",
+			Help:      "Order saga steps that ran past their deadline, by step and action taken",
+		}, []string{"step", "action"})
+
+	OrdersExpiredTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "orders",
+			Name:      "expired_total",
+			Help:      "Orders expired unpaid past their payment deadline",
+		})
+)
+
+func Register() {
+	prometheus.MustRegister(
+		DLQMessagesTotal,
+		OutboxPrunedRowsTotal,
+		OutboxDeadEventsTotal,
+		SagaTimeoutsTotal,
+		OrdersExpiredTotal)
+}
@@ -0,0 +1,232 @@
+// Package schema guards the JSON Schema registry under schemas/events for
+// make schemacheck: versions are pinned and stay backward compatible.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+
+	registry "github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
+)
+
+// node is the part of JSON Schema the compatibility check understands.
+type node struct {
+	Type                 json.RawMessage  `json:"type"`
+	Properties           map[string]*node `json:"properties"`
+	Required             []string         `json:"required"`
+	AdditionalProperties json.RawMessage  `json:"additionalProperties"`
+	Items                *node            `json:"items"`
+	Enum                 []any            `json:"enum"`
+	Minimum              *float64         `json:"minimum"`
+	Maximum              *float64         `json:"maximum"`
+	MinLength            *int             `json:"minLength"`
+	MaxLength            *int             `json:"maxLength"`
+	Pattern              *string          `json:"pattern"`
+	Format               *string          `json:"format"`
+}
+
+// Compatible reports why a consumer on schema next could not read payloads
+// written against prev. An empty result means next is backward compatible.
+func Compatible(prev, next []byte) ([]string, error) {
+	const op = "schema.Compatible"
+
+	var old, cur node
+	if err := json.Unmarshal(prev, &old); err != nil {
+		return nil, fmt.Errorf("%s: previous: %w", op, err)
+	}
+	if err := json.Unmarshal(next, &cur); err != nil {
+		return nil, fmt.Errorf("%s: next: %w", op, err)
+	}
+
+	return compare("$", &old, &cur), nil
+}
+
+func compare(path string, old, cur *node) []string {
+	var issues []string
+
+	oldTypes, curTypes := types(old.Type), types(cur.Type)
+	if len(curTypes) > 0 {
+		for _, t := range oldTypes {
+			if !allows(curTypes, t) {
+				issues = append(issues, fmt.Sprintf("%s: type %s is no longer accepted", path, t))
+			}
+		}
+		if len(oldTypes) == 0 {
+			issues = append(issues, fmt.Sprintf("%s: type restricted to %v", path, curTypes))
+		}
+	}
+
+	for _, name := range cur.Required {
+		if !slices.Contains(old.Required, name) {
+			issues = append(issues, fmt.Sprintf("%s: %s became required", path, name))
+		}
+	}
+
+	if closed(cur.AdditionalProperties) {
+		for _, name := range sortedKeys(old.Properties) {
+			if _, ok := cur.Properties[name]; !ok {
+				issues = append(issues, fmt.Sprintf("%s: %s removed while additional properties are forbidden", path, name))
+			}
+		}
+		if !closed(old.AdditionalProperties) {
+			issues = append(issues, fmt.Sprintf("%s: additional properties became forbidden", path))
+		}
+	}
+
+	if len(cur.Enum) > 0 {
+		if len(old.Enum) == 0 {
+			issues = append(issues, fmt.Sprintf("%s: enum added", path))
+		}
+		for _, v := range old.Enum {
+			if !slices.ContainsFunc(cur.Enum, func(c any) bool { return reflect.DeepEqual(c, v) }) {
+				issues = append(issues, fmt.Sprintf("%s: enum value %v removed", path, v))
+			}
+		}
+	}
+
+	if tightened(old.Minimum, cur.Minimum, func(o, c float64) bool { return c > o }) {
+		issues = append(issues, fmt.Sprintf("%s: minimum raised", path))
+	}
+	if tightened(old.Maximum, cur.Maximum, func(o, c float64) bool { return c < o }) {
+		issues = append(issues, fmt.Sprintf("%s: maximum lowered", path))
+	}
+	if tightened(old.MinLength, cur.MinLength, func(o, c int) bool { return c > o }) {
+		issues = append(issues, fmt.Sprintf("%s: minLength raised", path))
+	}
+	if tightened(old.MaxLength, cur.MaxLength, func(o, c int) bool { return c < o }) {
+		issues = append(issues, fmt.Sprintf("%s: maxLength lowered", path))
+	}
+
+	if changed(old.Pattern, cur.Pattern) {
+		issues = append(issues, fmt.Sprintf("%s: pattern added or changed", path))
+	}
+	if changed(old.Format, cur.Format) {
+		issues = append(issues, fmt.Sprintf("%s: format added or changed", path))
+	}
+
+	for _, name := range sortedKeys(cur.Properties) {
+		if prev, ok := old.Properties[name]; ok {
+			issues = append(issues, compare(path+"."+name, prev, cur.Properties[name])...)
+		}
+	}
+	if old.Items != nil && cur.Items != nil {
+		issues = append(issues, compare(path+"[]", old.Items, cur.Items)...)
+	}
+
+	return issues
+}
+
+// CheckDir checks every event type under dir: versions must run from v1
+// without gaps, compile, match the hashes pinned in SumsFile, and each must
+// be backward compatible with the version before it.
+func CheckDir(dir string) ([]string, error) {
+	const op = "schema.CheckDir"
+
+	if _, err := registry.Load(dir); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	issues, err := checkPins(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		eventType := entry.Name()
+
+		files, err := filepath.Glob(filepath.Join(dir, eventType, "v*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		byVersion := make(map[int]string, len(files))
+		for _, file := range files {
+			version, err := registry.Version(file)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			byVersion[version] = file
+		}
+
+		var prev []byte
+		for version := 1; version <= len(byVersion); version++ {
+			file, ok := byVersion[version]
+			if !ok {
+				issues = append(issues, fmt.Sprintf("%s: v%d is missing", eventType, version))
+				break
+			}
+			cur, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if prev != nil {
+				found, err := Compatible(prev, cur)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s v%d: %w", op, eventType, version, err)
+				}
+				for _, issue := range found {
+					issues = append(issues, fmt.Sprintf("%s v%d: %s", eventType, version, issue))
+				}
+			}
+			prev = cur
+		}
+	}
+
+	return issues, nil
+}
+
+func types(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		return []string{one}
+	}
+	var many []string
+	_ = json.Unmarshal(raw, &many)
+	return many
+}
+
+// allows reports whether a value of type t is accepted by types; every
+// integer is also a number.
+func allows(types []string, t string) bool {
+	return slices.Contains(types, t) || (t == "integer" && slices.Contains(types, "number"))
+}
+
+func closed(raw json.RawMessage) bool {
+	return string(raw) == "false"
+}
+
+func tightened[T any](old, cur *T, stricter func(o, c T) bool) bool {
+	if cur == nil {
+		return false
+	}
+	return old == nil || stricter(*old, *cur)
+}
+
+// changed reports whether cur restricts values that old did not: a pattern
+// or format that was added or replaced. Dropping one only widens the schema.
+func changed(old, cur *string) bool {
+	return cur != nil && (old == nil || *old != *cur)
+}
+
+func sortedKeys(m map[string]*node) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
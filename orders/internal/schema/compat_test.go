@@ -0,0 +1,152 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const base = `{
+  "type": "object",
+  "properties": {
+    "order_id": {"type": "integer", "minimum": 1},
+    "status": {"type": "string", "enum": ["new", "paid"]},
+    "note": {"type": "string"},
+    "currency": {"type": "string", "pattern": "^[A-Z]{3}$"}
+  },
+  "required": ["order_id"]
+}`
+
+const registryDir = "../../../schemas/events"
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		name string
+		next string
+		want string
+	}{
+		{"same schema", base, ""},
+		{"optional field added", strings.Replace(base, `"note": {"type": "string"}`, `"note": {"type": "string"}, "comment": {"type": "string"}`, 1), ""},
+		{"enum widened", strings.Replace(base, `["new", "paid"]`, `["new", "paid", "cancelled"]`, 1), ""},
+		{"integer widened to number", strings.Replace(base, `"type": "integer"`, `"type": "number"`, 1), ""},
+		{"field became required", strings.Replace(base, `["order_id"]`, `["order_id", "note"]`, 1), "note became required"},
+		{"type changed", strings.Replace(base, `"note": {"type": "string"}`, `"note": {"type": "integer"}`, 1), "$.note: type string is no longer accepted"},
+		{"enum value removed", strings.Replace(base, `["new", "paid"]`, `["new"]`, 1), "enum value paid removed"},
+		{"minimum raised", strings.Replace(base, `"minimum": 1`, `"minimum": 10`, 1), "$.order_id: minimum raised"},
+		{"pattern removed", strings.Replace(base, `, "pattern": "^[A-Z]{3}$"`, ``, 1), ""},
+		{"pattern changed", strings.Replace(base, `^[A-Z]{3}$`, `^[A-Z]{3,4}$`, 1), "$.currency: pattern added or changed"},
+		{"pattern added", strings.Replace(base, `"note": {"type": "string"}`, `"note": {"type": "string", "pattern": "^[a-z]+$"}`, 1), "$.note: pattern added or changed"},
+		{"format added", strings.Replace(base, `"note": {"type": "string"}`, `"note": {"type": "string", "format": "date-time"}`, 1), "$.note: format added or changed"},
+		{"closed with field removed", strings.Replace(base, `"pattern": "^[A-Z]{3}$"}
+  },`, `"pattern": "^[A-Z]{3}$"}
+  },
+  "additionalProperties": false,`, 1), "additional properties became forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := Compatible([]byte(base), []byte(tt.next))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				if len(issues) != 0 {
+					t.Fatalf("expected compatible, got %v", issues)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(issues, "\n"), tt.want) {
+				t.Fatalf("issues %v do not mention %q", issues, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDir(t *testing.T) {
+	issues, err := CheckDir(registryDir)
+	if err != nil {
+		t.Fatalf("check registry: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("registry has incompatible changes: %v", issues)
+	}
+
+	dir := t.TempDir()
+	writeSchema(t, dir, "order-created", "v1.json", base)
+	writeSchema(t, dir, "order-created", "v2.json", strings.Replace(base, `["order_id"]`, `["order_id", "status"]`, 1))
+	writeSchema(t, dir, "order-created", "v4.json", base)
+
+	issues, err = CheckDir(dir)
+	if err != nil {
+		t.Fatalf("check temp registry: %v", err)
+	}
+	got := strings.Join(issues, "\n")
+	if !strings.Contains(got, "order-created v2: $: status became required") || !strings.Contains(got, "order-created: v3 is missing") {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+}
+
+func TestCheckDir_Pins(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, "order-created", "v1.json", base)
+	writeSchema(t, dir, "order-cancelled", "v1.json", base)
+
+	added, err := Pin(dir)
+	if err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if strings.Join(added, ",") != "order-cancelled/v1.json,order-created/v1.json" {
+		t.Fatalf("pinned: got %v", added)
+	}
+	issues, err := CheckDir(dir)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("pinned registry: got %v, %v", issues, err)
+	}
+
+	writeSchema(t, dir, "order-created", "v1.json", strings.Replace(base, `"note": {"type": "string"}`, `"note": {"type": "string"}, "comment": {"type": "string"}`, 1))
+	writeSchema(t, dir, "order-created", "v2.json", base)
+	if err := os.RemoveAll(filepath.Join(dir, "order-cancelled")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	issues, err = CheckDir(dir)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	got := strings.Join(issues, "\n")
+	for _, want := range []string{
+		"order-created/v1.json: changed after release",
+		"order-created/v2.json: not pinned",
+		"order-cancelled/v1.json: released version was removed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("issues %v do not mention %q", issues, want)
+		}
+	}
+
+	added, err = Pin(dir)
+	if err != nil {
+		t.Fatalf("pin again: %v", err)
+	}
+	if len(added) != 1 || added[0] != "order-created/v2.json" {
+		t.Fatalf("pinned again: got %v", added)
+	}
+	issues, err = CheckDir(dir)
+	if err != nil {
+		t.Fatalf("check again: %v", err)
+	}
+	if !strings.Contains(strings.Join(issues, "\n"), "order-created/v1.json: changed after release") {
+		t.Fatalf("pin rewrote a released hash: %v", issues)
+	}
+}
+
+func writeSchema(t *testing.T, dir, eventType, name, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, eventType), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, eventType, name), []byte(body), 0o644); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package schema
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SumsFile pins every released schema file of a registry by its sha256, in
+// sha256sum format with paths relative to the registry directory. A pinned
+// file must never change: a new field goes into the next version instead.
+const SumsFile = "SHA256SUMS"
+
+// Pin adds the hash of every schema file under dir that is not pinned yet
+// and returns the files it pinned. Existing pins are never rewritten.
+func Pin(dir string) ([]string, error) {
+	const op = "schema.Pin"
+
+	pins, err := readPins(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	files, err := schemaFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var added []string
+	for _, file := range files {
+		if _, ok := pins[file]; ok {
+			continue
+		}
+		sum, err := fileSum(dir, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		pins[file] = sum
+		added = append(added, file)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if err := writePins(dir, pins); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return added, nil
+}
+
+// checkPins reports released files that were edited or deleted, and schema
+// files that were added without a pin.
+func checkPins(dir string) ([]string, error) {
+	pins, err := readPins(dir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := schemaFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []string
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		present[file] = true
+
+		want, ok := pins[file]
+		if !ok {
+			issues = append(issues, fmt.Sprintf("%s: not pinned in %s, run schemacheck -pin", file, SumsFile))
+			continue
+		}
+		sum, err := fileSum(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		if sum != want {
+			issues = append(issues, fmt.Sprintf("%s: changed after release, add a new version instead", file))
+		}
+	}
+	for _, file := range sortedPins(pins) {
+		if !present[file] {
+			issues = append(issues, fmt.Sprintf("%s: released version was removed", file))
+		}
+	}
+
+	return issues, nil
+}
+
+func readPins(dir string) (map[string]string, error) {
+	pins := make(map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(dir, SumsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return pins, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want \"<sha256>  <file>\"", SumsFile, line)
+		}
+		pins[fields[1]] = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return pins, nil
+}
+
+func writePins(dir string, pins map[string]string) error {
+	var b strings.Builder
+	for _, file := range sortedPins(pins) {
+		fmt.Fprintf(&b, "%s  %s\n", pins[file], file)
+	}
+	return os.WriteFile(filepath.Join(dir, SumsFile), []byte(b.String()), 0o644)
+}
+
+// schemaFiles lists the schema files of dir as slash-separated paths
+// relative to it, e.g. order-created/v1.json.
+func schemaFiles(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*", "v*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		rel, err := filepath.Rel(dir, match)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func fileSum(dir, file string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func sortedPins(pins map[string]string) []string {
+	files := make([]string, 0, len(pins))
+	for file := range pins {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// CancelOrder moves the order to cancelled if its state allows it and writes
+// OrderCancelled for payments to void or refund the charge.
+func (o *Order) CancelOrder(ctx context.Context, input dto.CancelOrderInput) (dto.CancelOrderOutput, error) {
+	const op = "services.Order.CancelOrder"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("order_id", input.ID))
+
+	var output dto.CancelOrderOutput
+
+	if input.ID <= 0 {
+		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidOrderID)
+	}
+
+	var order *domain.Order
+	err := o.runWithRetry(ctx, func(tx postgres.TxRepository) error {
+		if err := o.cancel(ctx, tx, input.ID, input.Reason); err != nil {
+			return err
+		}
+
+		var err error
+		order, err = tx.GetOrderByID(ctx, input.ID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return output, fmt.Errorf("%s: %w", op, domain.ErrOrderNotFound)
+		}
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			return output, fmt.Errorf("%s: %w", op, err)
+		}
+		log.Error("cancel order failed", slog.Any("err", err))
+		return output, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("order cancelled")
+
+	output.Order = *order
+	return output, nil
+}
+
+// cancel moves the order to cancelled and writes OrderCancelled with reason.
+func (o *Order) cancel(ctx context.Context, tx postgres.TxRepository, orderID int64, reason string) error {
+	const op = "services.Order.cancel"
+
+	changed, err := o.transition(ctx, tx, orderID, domain.StatusCancelled)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	event := events.OrderCancelled{
+		OrderID:     changed.OrderID,
+		UserID:      changed.UserID,
+		From:        changed.From,
+		Reason:      reason,
+		CancelledAt: changed.ChangedAt,
+	}
+	payload, err := json.Marshal(&event)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.SaveEvent(ctx, events.TypeOrderCancelled, payload, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
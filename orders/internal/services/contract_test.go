@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+)
+
+func TestOrderCancelledPayload_Contracts(t *testing.T) {
+	mock := &postgresMock{
+		getOrder: &domain.Order{ID: 42, UserID: 7, Status: domain.StatusAwaitingPayment},
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
+
+	if _, err := svc.CancelOrder(context.Background(), dto.CancelOrderInput{ID: 42}); err != nil {
+		t.Fatalf("cancel order: %v", err)
+	}
+
+	contract.Verify(t, "../../../contracts", "orders", events.TypeOrderCancelled, mock.savedPayloads[1])
+}
+
+func TestPaymentRequestedPayload_Contracts(t *testing.T) {
+	mock := &postgresMock{
+		getOrder: &domain.Order{
+			ID: 42, UserID: 7, Status: domain.StatusAwaitingStock,
+			TotalAmount: domain.Money{Amount: 1500, Currency: "RUB"},
+		},
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
+
+	if err := svc.HandleStockResult(context.Background(), dto.StockResultInput{OrderID: 42, Reserved: true}); err != nil {
+		t.Fatalf("handle stock result: %v", err)
+	}
+
+	contract.Verify(t, "../../../contracts", "orders", events.TypePaymentRequested, mock.savedPayloads[1])
+}
+
+func TestReissuedOrderCreatedPayload_Contracts(t *testing.T) {
+	mock := &postgresMock{
+		getOrder: &domain.Order{
+			ID: 42, UserID: 7, Status: domain.StatusAwaitingStock,
+			Items:       []domain.OrderItem{{ProductID: 3, Quantity: 2, Price: domain.Money{Amount: 750, Currency: "RUB"}}},
+			TotalAmount: domain.Money{Amount: 1500, Currency: "RUB"},
+			CreatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		dueSagas: []domain.Saga{{OrderID: 42, Step: domain.SagaStepReserveStock, Attempts: 1}},
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(log, mock, testCatalog(), nil, SagaPolicy{StepTimeout: time.Minute, MaxAttempts: 2})
+
+	if _, err := svc.ExpireSagas(context.Background(), 1); err != nil {
+		t.Fatalf("expire sagas: %v", err)
+	}
+
+	contract.Verify(t, "../../../contracts", "orders", events.TypeOrderCreated, mock.savedPayloads[0])
+}
+
+func TestOrderExpiredPayload_Contracts(t *testing.T) {
+	mock := &postgresMock{
+		getOrder:        &domain.Order{ID: 42, UserID: 7, Status: domain.StatusAwaitingPayment},
+		unpaidOrders:    []int64{42},
+		unpaidCreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(log, mock, testCatalog(), nil, SagaPolicy{PaymentTTL: time.Hour})
+
+	if _, err := svc.ExpireOrders(context.Background(), 1); err != nil {
+		t.Fatalf("expire orders: %v", err)
+	}
+
+	contract.Verify(t, "../../../contracts", "orders", events.TypeOrderExpired, mock.savedPayloads[1])
+}
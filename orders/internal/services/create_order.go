@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/mapper"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
 )
 
 func (o *Order) CreateOrder(ctx context.Context, input dto.CreateOrderInput) (dto.CreateOrderOutput, error) {
@@ -26,19 +28,112 @@ func (o *Order) CreateOrder(ctx context.Context, input dto.CreateOrderInput) (dt
 		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidItems)
 	}
 
-	items, err := mapper.MapInputItems(input.Items)
+	if len(input.IdempotencyKey) > domain.MaxIdempotencyKeyLen {
+		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidIdempotencyKey)
+	}
+
+	promoCode := domain.NormalizePromoCode(input.PromoCode)
+	if len(promoCode) > domain.MaxPromoCodeLen {
+		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidPromoCode)
+	}
+
+	var hash string
+	if input.IdempotencyKey != "" {
+		var err error
+		if hash, err = requestHash(input); err != nil {
+			return output, fmt.Errorf("%s: %w", op, err)
+		}
+		replay, err := o.replay(ctx, input.UserID, input.IdempotencyKey, hash)
+		if err != nil {
+			return output, fmt.Errorf("%s: %w", op, err)
+		}
+		if replay != nil {
+			log.Info("order creation replayed", slog.Int64("order_id", replay.ID))
+			return *replay, nil
+		}
+	}
+
+	priced, err := o.priceItems(ctx, input.Items)
 	if err != nil {
 		return output, fmt.Errorf("%s: %w", op, err)
 	}
 
-	orderID, err := o.repo.CreateOrder(ctx, input.UserID, items)
+	items, err := mapper.MapInputItems(priced)
+	if err != nil {
+		return output, fmt.Errorf("%s: %w", op, err)
+	}
+
+	subtotal, err := domain.TotalOf(items)
 	if err != nil {
-		log.Error("create order failed", slog.Any("err", err))
 		return output, fmt.Errorf("%s: %w", op, err)
 	}
 
-	log.Info("order created", slog.Int64("order_id", orderID))
+	if limit, ok := o.maxTotal[string(subtotal.Currency)]; ok && subtotal.Amount > limit {
+		return output, fmt.Errorf("%s: %w: %s", op, domain.ErrTotalTooLarge, subtotal)
+	}
+
+	err = o.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		if input.IdempotencyKey != "" {
+			stored, err := tx.ClaimIdempotencyKey(ctx, input.UserID, input.IdempotencyKey, hash)
+			if err != nil {
+				return err
+			}
+			if stored != nil {
+				output, err = storedOutput(stored, hash)
+				return err
+			}
+		}
+
+		var (
+			promotion   *domain.Promotion
+			adjustments []domain.Adjustment
+		)
+		if promoCode != "" {
+			if promotion, adjustments, err = o.redeemPromotion(ctx, tx, input.UserID, promoCode, items); err != nil {
+				return err
+			}
+		}
+		total, err := domain.Discount(subtotal, adjustments)
+		if err != nil {
+			return err
+		}
+
+		id, err := tx.CreateOrder(ctx, input.UserID, items, adjustments, total)
+		if err != nil {
+			return err
+		}
+		output.ID = id
+
+		if promotion != nil {
+			if err := tx.RedeemPromotion(ctx, int64(promotion.ID), id, input.UserID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := o.transition(ctx, tx, id, domain.StatusAwaitingStock); err != nil {
+			return err
+		}
+
+		if input.IdempotencyKey == "" {
+			return nil
+		}
+		response, err := json.Marshal(output)
+		if err != nil {
+			return err
+		}
+		return tx.SaveIdempotencyResponse(ctx, input.UserID, input.IdempotencyKey, id, response)
+	})
+	if err != nil {
+		log.Error("create order failed", slog.Any("err", err))
+		return dto.CreateOrderOutput{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if output.Replayed {
+		log.Info("order creation replayed", slog.Int64("order_id", output.ID))
+		return output, nil
+	}
+
+	log.Info("order created", slog.Int64("order_id", output.ID))
 
-	output.ID = orderID
 	return output, nil
 }
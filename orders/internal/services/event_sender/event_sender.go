@@ -3,37 +3,98 @@ package event_sender
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/metrics"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
 type Kafka interface {
-	Produce(ctx context.Context, message []byte, topic string) error
+	ProduceBatch(ctx context.Context, messages []*kafka.Message) []error
 }
 
+// Listener wakes the sender as soon as new outbox events are committed.
+type Listener interface {
+	ListenEvents(ctx context.Context, wake chan<- struct{}) error
+}
+
+// Validator checks an outgoing JSON payload against the schema registry.
+type Validator interface {
+	Validate(eventType string, version int, payload []byte) error
+}
+
+// Topics routes outbox events to kafka topics by event type.
+type Topics struct {
+	OrderCreated       string
+	OrderStatusChanged string
+	// OrderCancelled should share the OrderCreated topic: both are keyed by
+	// order id, so payments reads a cancellation after the order it cancels.
+	OrderCancelled string
+	// OrderExpired shares the topic for the same reason.
+	OrderExpired string
+	// PaymentRequested shares the topic too, so payments sees a cancellation
+	// after the request it cancels.
+	PaymentRequested string
+}
+
+// source names this service in the envelope of every published event.
+const source = "orders"
+
 type Sender struct {
-	repo     postgres.Repository
-	producer Kafka
-	log      *slog.Logger
+	repo        postgres.Repository
+	producer    Kafka
+	listener    Listener
+	log         *slog.Logger
+	batchSize   int
+	maxAttempts int
+	contentType string
+	schemas     Validator
 }
 
-func New(repo postgres.Repository, producer Kafka, log *slog.Logger) *Sender {
+// New builds a sender. listener may be nil, in which case the sender only
+// polls. An event that cannot be encoded is dead-lettered after maxAttempts
+// tries. contentType selects the wire encoding, JSON when empty. schemas may
+// be nil to publish without validation.
+func New(repo postgres.Repository, producer Kafka, listener Listener, log *slog.Logger, batchSize, maxAttempts int, contentType string, schemas Validator) *Sender {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	if contentType == "" {
+		contentType = envelope.ContentTypeJSON
+	}
 	return &Sender{
-		repo:     repo,
-		producer: producer,
-		log:      log,
+		repo:        repo,
+		producer:    producer,
+		listener:    listener,
+		log:         log,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		contentType: contentType,
+		schemas:     schemas,
 	}
 }
 
-func (s *Sender) StartProcessEvents(ctx context.Context, handlePeriod time.Duration, topic string) {
+func (s *Sender) StartProcessEvents(ctx context.Context, handlePeriod time.Duration, topics Topics) {
 	const op = "services.event_sender.StartProcessEvents"
 
-	log := s.log.With(
-		slog.String("op", op),
-		slog.String("topic", topic))
+	log := s.log.With(slog.String("op", op))
 
+	wake := make(chan struct{}, 1)
+	if s.listener != nil {
+		go s.listen(ctx, wake, handlePeriod, log)
+	}
+
+	// with a listener the ticker is only a safety net for lost notifications
 	ticker := time.NewTicker(handlePeriod)
 
 	for {
@@ -43,32 +104,246 @@ func (s *Sender) StartProcessEvents(ctx context.Context, handlePeriod time.Durat
 			log.Info("stopping event processing")
 			return
 		case <-ticker.C:
+		case <-wake:
+		}
+
+		s.drain(ctx, topics, log)
+	}
+}
+
+// listen keeps the LISTEN connection up, reconnecting after retryDelay when it drops.
+func (s *Sender) listen(ctx context.Context, wake chan<- struct{}, retryDelay time.Duration, log *slog.Logger) {
+	for {
+		err := s.listener.ListenEvents(ctx, wake)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("outbox listener dropped, falling back to polling", slog.Any("err", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
 		}
+	}
+}
 
-		event, eventID, err := s.repo.GetNewEvent(ctx)
+// drain publishes batches back to back until the outbox is empty. The fetch
+// hands out one event per aggregate at a time, so a chain of events for the
+// same order is drained in one go instead of one event per tick.
+func (s *Sender) drain(ctx context.Context, topics Topics, log *slog.Logger) {
+	for ctx.Err() == nil {
+		fetched, sent, err := s.processBatch(ctx, topics, log)
 		if err != nil {
-			log.Error("failed to get new event", slog.Any("err", err))
-			continue
+			log.Error("process batch failed", slog.Any("err", err))
+			return
 		}
-		event.EventID = eventID
-		if event.EventID == 0 {
-			continue
+		if fetched == 0 || sent == 0 {
+			return
 		}
+	}
+}
+
+func (s *Sender) processBatch(ctx context.Context, topics Topics, log *slog.Logger) (int, int, error) {
+	const op = "services.event_sender.processBatch"
+
+	batch, err := s.repo.GetNewEvents(ctx, s.batchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(batch) == 0 {
+		return 0, 0, nil
+	}
 
-		message, err := json.Marshal(&event)
+	messages := make([]*kafka.Message, 0, len(batch))
+	ids := make([]int64, 0, len(batch))
+	for _, event := range batch {
+		message, err := s.message(event, topics)
 		if err != nil {
-			log.Error("marshal event failed", slog.Any("err", err))
+			if err := s.fail(ctx, event, err, log); err != nil {
+				return len(batch), 0, fmt.Errorf("%s: %w", op, err)
+			}
 			continue
 		}
+		messages = append(messages, message)
+		ids = append(ids, event.EventID)
+	}
 
-		if err := s.producer.Produce(ctx, message, topic); err != nil {
-			log.Error("kafka_produce produce failed", slog.Any("err", err))
+	delivered := make([]int64, 0, len(messages))
+	for i, err := range s.producer.ProduceBatch(ctx, messages) {
+		if err != nil {
+			log.Error("kafka_produce produce failed",
+				slog.Int64("event_id", ids[i]),
+				slog.String("topic", *messages[i].TopicPartition.Topic),
+				slog.Any("err", err))
 			continue
 		}
+		delivered = append(delivered, ids[i])
+	}
 
-		if err := s.repo.MarkSent(ctx, eventID); err != nil {
-			log.Error("mark event sent failed", slog.Any("err", err))
-			continue
+	if err := s.repo.MarkSent(ctx, delivered); err != nil {
+		return len(batch), 0, fmt.Errorf("%s: mark sent: %w", op, err)
+	}
+
+	return len(batch), len(delivered), nil
+}
+
+// message builds the kafka message of an outbox event, keyed by aggregate
+// id so the events of one order stay in order.
+func (s *Sender) message(event events.Outbox, topics Topics) (*kafka.Message, error) {
+	payload, topic, err := encode(event, topics, s.contentType, s.schemas)
+	if err != nil {
+		return nil, err
+	}
+	value, headers, err := wrap(event, payload, s.contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny,
+		},
+		Key:     []byte(strconv.FormatInt(event.AggregateID, 10)),
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
+
+// fail records that event could not be encoded. After maxAttempts failures
+// the event is dead-lettered, so it no longer blocks later events of its
+// aggregate.
+func (s *Sender) fail(ctx context.Context, event events.Outbox, cause error, log *slog.Logger) error {
+	dead, err := s.repo.MarkFailed(ctx, event.EventID, cause.Error(), s.maxAttempts)
+	if err != nil {
+		return fmt.Errorf("mark failed: %w", err)
+	}
+
+	if !dead {
+		log.Warn("encode event failed, will retry",
+			slog.Int64("event_id", event.EventID),
+			slog.String("event_type", event.EventType),
+			slog.Any("err", cause))
+		return nil
+	}
+
+	metrics.OutboxDeadEventsTotal.WithLabelValues(source, event.EventType).Inc()
+	log.Error("event dead-lettered",
+		slog.Int64("event_id", event.EventID),
+		slog.String("event_type", event.EventType),
+		slog.Int("attempts", s.maxAttempts),
+		slog.Any("err", cause))
+	return nil
+}
+
+// encode turns the stored JSON payload into the message payload in the
+// requested content type. The JSON form is checked against the registry
+// whatever the wire encoding is.
+func encode(event events.Outbox, topics Topics, contentType string, schemas Validator) ([]byte, string, error) {
+	const op = "services.event_sender.encode"
+
+	var (
+		payload envelope.Message
+		topic   string
+	)
+
+	switch event.EventType {
+	case events.TypeOrderCreated:
+		var created events.OrderCreated
+		if err := json.Unmarshal(event.Payload, &created); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+		created.EventID = event.EventID
+		payload, topic = &created, topics.OrderCreated
+	case events.TypeOrderStatusChanged:
+		var changed events.OrderStatusChanged
+		if err := json.Unmarshal(event.Payload, &changed); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+		changed.EventID = event.EventID
+		payload, topic = &changed, topics.OrderStatusChanged
+	case events.TypeOrderCancelled:
+		var cancelled events.OrderCancelled
+		if err := json.Unmarshal(event.Payload, &cancelled); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+		cancelled.EventID = event.EventID
+		payload, topic = &cancelled, topics.OrderCancelled
+	case events.TypeOrderExpired:
+		var expired events.OrderExpired
+		if err := json.Unmarshal(event.Payload, &expired); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
 		}
+		expired.EventID = event.EventID
+		payload, topic = &expired, topics.OrderExpired
+	case events.TypePaymentRequested:
+		var requested events.PaymentRequested
+		if err := json.Unmarshal(event.Payload, &requested); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+		requested.EventID = event.EventID
+		payload, topic = &requested, topics.PaymentRequested
+	default:
+		return nil, "", fmt.Errorf("%s: %w: %s", op, domain.ErrUnknownType, event.EventType)
+	}
+	if topic == "" {
+		return nil, "", fmt.Errorf("%s: no topic for %s", op, event.EventType)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if schemas != nil {
+		if err := schemas.Validate(event.EventType, events.SchemaVersion(event.EventType), body); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if contentType == envelope.ContentTypeJSON {
+		return body, topic, nil
+	}
+
+	message, err := envelope.MarshalPayload(contentType, payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return message, topic, nil
+}
+
+// wrap puts an encoded payload into the envelope and mirrors its routing
+// fields into kafka headers.
+func wrap(event events.Outbox, payload []byte, contentType string) ([]byte, []kafka.Header, error) {
+	const op = "services.event_sender.wrap"
+
+	env := envelope.Envelope{
+		ID:            envelope.ID(source, event.EventID),
+		Type:          event.EventType,
+		Version:       events.SchemaVersion(event.EventType),
+		OccurredAt:    event.CreatedAt.UTC(),
+		Source:        source,
+		CorrelationID: event.CorrelationID,
+		CausationID:   event.CausationID,
+		Payload:       payload,
+	}
+
+	message, err := envelope.Marshal(contentType, env)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	headers := []kafka.Header{
+		{Key: envelope.HeaderContentType, Value: []byte(contentType)},
+		{Key: envelope.HeaderEventID, Value: []byte(env.ID)},
+		{Key: envelope.HeaderEventType, Value: []byte(env.Type)},
+		{Key: envelope.HeaderEventVersion, Value: []byte(strconv.Itoa(env.Version))},
+		{Key: envelope.HeaderSource, Value: []byte(env.Source)},
+		{Key: envelope.HeaderCorrelationID, Value: []byte(env.CorrelationID)},
 	}
+	if env.CausationID != "" {
+		headers = append(headers, kafka.Header{Key: envelope.HeaderCausationID, Value: []byte(env.CausationID)})
+	}
+
+	return message, headers, nil
 }
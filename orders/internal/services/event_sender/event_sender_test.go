@@ -0,0 +1,434 @@
+package event_sender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+var testTopics = Topics{
+	OrderCreated:       "order-topic",
+	OrderStatusChanged: "order-status-topic",
+	OrderCancelled:     "order-topic",
+	OrderExpired:       "order-topic",
+	PaymentRequested:   "order-topic",
+}
+
+func TestSender_Drain(t *testing.T) {
+	created, _ := json.Marshal(events.OrderCreated{OrderID: 1, UserID: 1})
+	changed, _ := json.Marshal(events.OrderStatusChanged{OrderID: 1, UserID: 1, To: domain.StatusAwaitingPayment})
+
+	backlog := []events.Outbox{
+		{EventID: 1, EventType: events.TypeOrderCreated, AggregateID: 1, Payload: created, CorrelationID: "order-1", CausationID: "payments:7"},
+		{EventID: 2, EventType: events.TypeOrderStatusChanged, AggregateID: 1, Payload: changed},
+		{EventID: 3, EventType: "unknown", AggregateID: 1, Payload: created},
+		{EventID: 4, EventType: events.TypeOrderCreated, AggregateID: 2, Payload: created},
+		{EventID: 5, EventType: events.TypeOrderCreated, AggregateID: 3, Payload: created},
+	}
+
+	repo := &repoMock{backlog: backlog}
+	producer := &producerMock{fail: map[int64]bool{5: true}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, producer, nil, log, 2, 3, "", nil)
+
+	sender.drain(context.Background(), testTopics, log)
+
+	if repo.fetches != 3 {
+		t.Fatalf("GetNewEvents calls: got %d, want %d", repo.fetches, 3)
+	}
+
+	want := []int64{1, 2, 4}
+	if len(repo.sent) != len(want) {
+		t.Fatalf("sent ids: got %v, want %v", repo.sent, want)
+	}
+	for i := range want {
+		if repo.sent[i] != want[i] {
+			t.Fatalf("sent ids: got %v, want %v", repo.sent, want)
+		}
+	}
+
+	if producer.topics[2] != testTopics.OrderStatusChanged {
+		t.Fatalf("status event topic: got %s, want %s", producer.topics[2], testTopics.OrderStatusChanged)
+	}
+
+	if producer.keys[4] != "2" {
+		t.Fatalf("message key: got %q, want aggregate id %q", producer.keys[4], "2")
+	}
+
+	env, err := events.DecodeEnvelope(envelope.ContentTypeJSON, producer.values[1])
+	if err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	if env.ID != "orders:1" || env.Type != events.TypeOrderCreated || env.Version != events.SchemaVersion(events.TypeOrderCreated) {
+		t.Fatalf("envelope: got id=%s type=%s version=%d", env.ID, env.Type, env.Version)
+	}
+	if env.CorrelationID != "order-1" || env.CausationID != "payments:7" {
+		t.Fatalf("envelope meta: got correlation=%s causation=%s", env.CorrelationID, env.CausationID)
+	}
+
+	var got events.OrderCreated
+	if err := json.Unmarshal(env.Payload, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.EventID != 1 {
+		t.Fatalf("event id: got %d, want %d", got.EventID, 1)
+	}
+
+	headers := make(map[string]string)
+	for _, h := range producer.headers[1] {
+		headers[h.Key] = string(h.Value)
+	}
+	wantHeaders := map[string]string{
+		envelope.HeaderContentType:   envelope.ContentTypeJSON,
+		envelope.HeaderEventID:       "orders:1",
+		envelope.HeaderEventType:     events.TypeOrderCreated,
+		envelope.HeaderEventVersion:  strconv.Itoa(events.SchemaVersion(events.TypeOrderCreated)),
+		envelope.HeaderSource:        "orders",
+		envelope.HeaderCorrelationID: "order-1",
+		envelope.HeaderCausationID:   "payments:7",
+	}
+	for key, value := range wantHeaders {
+		if headers[key] != value {
+			t.Fatalf("header %s: got %q, want %q", key, headers[key], value)
+		}
+	}
+}
+
+func TestSender_OrderCancelled(t *testing.T) {
+	cancelled, _ := json.Marshal(events.OrderCancelled{OrderID: 1, UserID: 1, From: domain.StatusPaid, Reason: "duplicate"})
+
+	repo := &repoMock{backlog: []events.Outbox{
+		{EventID: 6, EventType: events.TypeOrderCancelled, AggregateID: 1, Payload: cancelled},
+	}}
+	producer := &producerMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, producer, nil, log, 10, 3, "", nil)
+
+	sender.drain(context.Background(), testTopics, log)
+
+	if producer.topics[6] != testTopics.OrderCancelled {
+		t.Fatalf("topic: got %q, want %q", producer.topics[6], testTopics.OrderCancelled)
+	}
+
+	env, err := events.DecodeEnvelope(envelope.ContentTypeJSON, producer.values[6])
+	if err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	var got events.OrderCancelled
+	if err := json.Unmarshal(env.Payload, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if env.Type != events.TypeOrderCancelled || got.EventID != 6 || got.Reason != "duplicate" {
+		t.Fatalf("unexpected event: type=%s %+v", env.Type, got)
+	}
+}
+
+func TestSender_OrderExpired(t *testing.T) {
+	deadlineAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expired, _ := json.Marshal(events.OrderExpired{OrderID: 1, UserID: 1, DeadlineAt: deadlineAt, ExpiredAt: deadlineAt.Add(time.Minute)})
+
+	repo := &repoMock{backlog: []events.Outbox{
+		{EventID: 8, EventType: events.TypeOrderExpired, AggregateID: 1, Payload: expired},
+	}}
+	producer := &producerMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, producer, nil, log, 10, 3, envelope.ContentTypeProtobuf, nil)
+
+	sender.drain(context.Background(), testTopics, log)
+
+	if producer.topics[8] != testTopics.OrderExpired {
+		t.Fatalf("topic: got %q, want %q", producer.topics[8], testTopics.OrderExpired)
+	}
+
+	env, err := events.DecodeEnvelope(envelope.ContentTypeProtobuf, producer.values[8])
+	if err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	var got events.OrderExpired
+	if err := envelope.UnmarshalPayload(env, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if env.Type != events.TypeOrderExpired || got.EventID != 8 || got.OrderID != 1 || !got.DeadlineAt.Equal(deadlineAt) {
+		t.Fatalf("unexpected event: type=%s %+v", env.Type, got)
+	}
+}
+
+func TestSender_PaymentRequested(t *testing.T) {
+	requested, _ := json.Marshal(events.PaymentRequested{OrderID: 1, UserID: 1, TotalAmount: 300, Currency: "RUB", RequestedAt: time.Now()})
+
+	repo := &repoMock{backlog: []events.Outbox{
+		{EventID: 8, EventType: events.TypePaymentRequested, AggregateID: 1, Payload: requested},
+	}}
+	producer := &producerMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, producer, nil, log, 10, 3, "", nil)
+
+	sender.drain(context.Background(), testTopics, log)
+
+	if producer.topics[8] != testTopics.PaymentRequested {
+		t.Fatalf("topic: got %q, want %q", producer.topics[8], testTopics.PaymentRequested)
+	}
+
+	env, err := events.DecodeEnvelope(envelope.ContentTypeJSON, producer.values[8])
+	if err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	var got events.PaymentRequested
+	if err := json.Unmarshal(env.Payload, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if env.Type != events.TypePaymentRequested || got.EventID != 8 || got.TotalAmount != 300 {
+		t.Fatalf("unexpected event: type=%s %+v", env.Type, got)
+	}
+}
+
+func TestSender_Protobuf(t *testing.T) {
+	changed, _ := json.Marshal(events.OrderStatusChanged{OrderID: 7, UserID: 3, From: domain.StatusNew, To: domain.StatusAwaitingPayment, Version: 2})
+
+	repo := &repoMock{backlog: []events.Outbox{
+		{EventID: 1, EventType: events.TypeOrderStatusChanged, AggregateID: 7, Payload: changed, CorrelationID: "order-7"},
+	}}
+	producer := &producerMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, producer, nil, log, 10, 3, envelope.ContentTypeProtobuf, nil)
+
+	sender.drain(context.Background(), testTopics, log)
+
+	if len(repo.sent) != 1 {
+		t.Fatalf("sent ids: got %v, want [1]", repo.sent)
+	}
+
+	env, err := events.DecodeEnvelope(envelope.ContentTypeProtobuf, producer.values[1])
+	if err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	if env.ID != "orders:1" || env.Type != events.TypeOrderStatusChanged || env.CorrelationID != "order-7" {
+		t.Fatalf("envelope: got id=%s type=%s correlation=%s", env.ID, env.Type, env.CorrelationID)
+	}
+
+	var got events.OrderStatusChanged
+	if err := envelope.UnmarshalPayload(env, &got); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if got.EventID != 1 || got.OrderID != 7 || got.To != domain.StatusAwaitingPayment || got.Version != 2 {
+		t.Fatalf("payload: got %+v", got)
+	}
+
+	var contentType string
+	for _, h := range producer.headers[1] {
+		if h.Key == envelope.HeaderContentType {
+			contentType = string(h.Value)
+		}
+	}
+	if contentType != envelope.ContentTypeProtobuf {
+		t.Fatalf("content type header: got %q, want %q", contentType, envelope.ContentTypeProtobuf)
+	}
+}
+
+type schemasMock struct {
+	reject string
+}
+
+func (m *schemasMock) Validate(eventType string, version int, payload []byte) error {
+	if eventType == m.reject {
+		return errors.New("payload does not match schema")
+	}
+	return nil
+}
+
+func TestSender_SkipsInvalidPayload(t *testing.T) {
+	created, _ := json.Marshal(events.OrderCreated{OrderID: 1, UserID: 1})
+	changed, _ := json.Marshal(events.OrderStatusChanged{OrderID: 2, UserID: 1, To: domain.StatusAwaitingPayment})
+
+	repo := &repoMock{backlog: []events.Outbox{
+		{EventID: 1, EventType: events.TypeOrderCreated, AggregateID: 1, Payload: created},
+		{EventID: 2, EventType: events.TypeOrderStatusChanged, AggregateID: 2, Payload: changed},
+	}}
+	producer := &producerMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, producer, nil, log, 10, 3, envelope.ContentTypeJSON, &schemasMock{reject: events.TypeOrderCreated})
+
+	sender.drain(context.Background(), testTopics, log)
+
+	if len(repo.sent) != 1 || repo.sent[0] != 2 {
+		t.Fatalf("sent ids: got %v, want [2]", repo.sent)
+	}
+	if _, ok := producer.values[1]; ok {
+		t.Fatalf("invalid event was published")
+	}
+	if repo.attempts[1] != 1 {
+		t.Fatalf("failed attempts of the invalid event: got %d, want 1", repo.attempts[1])
+	}
+}
+
+func TestSender_DeadLettersPoisonEvent(t *testing.T) {
+	poison := events.Outbox{EventID: 1, EventType: events.TypeOrderCreated, AggregateID: 1, Payload: []byte(`{`)}
+
+	repo := &repoMock{}
+	producer := &producerMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, producer, nil, log, 10, 3, envelope.ContentTypeJSON, nil)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		repo.backlog = []events.Outbox{poison}
+		if _, _, err := sender.processBatch(context.Background(), testTopics, log); err != nil {
+			t.Fatalf("attempt %d: %v", attempt, err)
+		}
+		if repo.attempts[1] != attempt {
+			t.Fatalf("attempt %d: got %d recorded attempts", attempt, repo.attempts[1])
+		}
+		if dead := attempt == 3; repo.dead[1] != dead {
+			t.Fatalf("attempt %d: dead-lettered %v, want %v", attempt, repo.dead[1], dead)
+		}
+	}
+	if len(repo.sent) != 0 || len(producer.values) != 0 {
+		t.Fatalf("poison event was published: sent %v", repo.sent)
+	}
+
+	repo.backlog = []events.Outbox{poison}
+	repo.failErr = errors.New("connection reset")
+	if _, _, err := sender.processBatch(context.Background(), testTopics, log); err == nil {
+		t.Fatalf("expected an error when the failure cannot be recorded")
+	}
+}
+
+func TestSender_WakesOnNotification(t *testing.T) {
+	created, _ := json.Marshal(events.OrderCreated{OrderID: 1, UserID: 1})
+
+	repo := &repoMock{backlog: []events.Outbox{
+		{EventID: 1, EventType: events.TypeOrderCreated, AggregateID: 1, Payload: created},
+	}}
+	listener := &listenerMock{}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	sender := New(repo, &producerMock{}, listener, log, 10, 3, envelope.ContentTypeJSON, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sender.StartProcessEvents(ctx, time.Hour, testTopics)
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		repo.mu.Lock()
+		sent := len(repo.sent)
+		repo.mu.Unlock()
+		if sent == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			cancel()
+			t.Fatalf("event was not published before the ticker fired")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-done
+}
+
+type listenerMock struct{}
+
+func (m *listenerMock) ListenEvents(ctx context.Context, wake chan<- struct{}) error {
+	wake <- struct{}{}
+	<-ctx.Done()
+	return nil
+}
+
+type repoMock struct {
+	postgres.Repository
+
+	mu       sync.Mutex
+	backlog  []events.Outbox
+	fetches  int
+	sent     []int64
+	attempts map[int64]int
+	dead     map[int64]bool
+	failErr  error
+}
+
+func (m *repoMock) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetches++
+	n := min(limit, len(m.backlog))
+	batch := m.backlog[:n]
+	m.backlog = m.backlog[n:]
+	return batch, nil
+}
+
+func (m *repoMock) MarkSent(ctx context.Context, eventIDs []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, eventIDs...)
+	return nil
+}
+
+func (m *repoMock) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failErr != nil {
+		return false, m.failErr
+	}
+	if m.attempts == nil {
+		m.attempts = make(map[int64]int)
+		m.dead = make(map[int64]bool)
+	}
+	m.attempts[eventID]++
+	m.dead[eventID] = m.attempts[eventID] >= maxAttempts
+	return m.dead[eventID], nil
+}
+
+type producerMock struct {
+	fail    map[int64]bool
+	topics  map[int64]string
+	keys    map[int64]string
+	values  map[int64][]byte
+	headers map[int64][]kafka.Header
+}
+
+func (m *producerMock) ProduceBatch(ctx context.Context, messages []*kafka.Message) []error {
+	if m.topics == nil {
+		m.topics = make(map[int64]string)
+		m.keys = make(map[int64]string)
+		m.headers = make(map[int64][]kafka.Header)
+		m.values = make(map[int64][]byte)
+	}
+
+	errs := make([]error, len(messages))
+	for i, msg := range messages {
+		var contentType string
+		for _, h := range msg.Headers {
+			if h.Key == envelope.HeaderContentType {
+				contentType = string(h.Value)
+			}
+		}
+		env, _ := events.DecodeEnvelope(contentType, msg.Value)
+		eventID, _ := strconv.ParseInt(strings.TrimPrefix(env.ID, "orders:"), 10, 64)
+
+		if m.fail[eventID] {
+			errs[i] = errors.New("delivery failed")
+			continue
+		}
+		m.topics[eventID] = *msg.TopicPartition.Topic
+		m.keys[eventID] = string(msg.Key)
+		m.headers[eventID] = msg.Headers
+		m.values[eventID] = msg.Value
+	}
+	return errs
+}
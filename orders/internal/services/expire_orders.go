@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/metrics"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+)
+
+// ExpireOrders expires up to limit orders still awaiting payment past their
+// payment deadline, each in its own transaction, and returns how many were
+// expired. OrderExpired makes payments void the pending payment and
+// inventory release the stock.
+func (o *Order) ExpireOrders(ctx context.Context, limit int) (int, error) {
+	const op = "services.Order.ExpireOrders"
+
+	if o.saga.PaymentTTL <= 0 {
+		return 0, nil
+	}
+
+	expired := 0
+	for expired < limit {
+		var (
+			orderID    int64
+			deadlineAt time.Time
+		)
+		err := o.runWithRetry(ctx, func(tx postgres.TxRepository) error {
+			var (
+				createdAt time.Time
+				err       error
+			)
+			orderID, createdAt, err = tx.GetUnpaidOrder(ctx, time.Now().UTC().Add(-o.saga.PaymentTTL))
+			if err != nil {
+				return err
+			}
+			if orderID == 0 {
+				return nil
+			}
+
+			deadlineAt = createdAt.Add(o.saga.PaymentTTL)
+			return o.expire(ctx, tx, orderID, deadlineAt)
+		})
+		if err != nil {
+			return expired, fmt.Errorf("%s: %w", op, err)
+		}
+		if orderID == 0 {
+			break
+		}
+
+		metrics.OrdersExpiredTotal.Inc()
+		o.log.Info("order expired",
+			slog.String("op", op),
+			slog.Int64("order_id", orderID),
+			slog.Time("deadline_at", deadlineAt))
+		expired++
+	}
+
+	return expired, nil
+}
+
+// expire moves the order to expired and writes OrderExpired.
+func (o *Order) expire(ctx context.Context, tx postgres.TxRepository, orderID int64, deadlineAt time.Time) error {
+	const op = "services.Order.expire"
+
+	changed, err := o.transition(ctx, tx, orderID, domain.StatusExpired)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	event := events.OrderExpired{
+		OrderID:    changed.OrderID,
+		UserID:     changed.UserID,
+		DeadlineAt: deadlineAt.UTC(),
+		ExpiredAt:  changed.ChangedAt,
+	}
+	payload, err := json.Marshal(&event)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.SaveEvent(ctx, events.TypeOrderExpired, payload, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
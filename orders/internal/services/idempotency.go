@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+// requestHash fingerprints the part of a create request that decides its
+// outcome, so a reused Idempotency-Key can be told apart from a retry.
+func requestHash(input dto.CreateOrderInput) (string, error) {
+	body, err := json.Marshal(struct {
+		UserID    int64                 `json:"user_id"`
+		Items     []dto.CreateOrderItem `json:"items"`
+		PromoCode string                `json:"promo_code,omitempty"`
+	}{input.UserID, input.Items, domain.NormalizePromoCode(input.PromoCode)})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replay answers a retry from the stored response before the items are
+// priced. A key being claimed by a request still in flight yields nil and is
+// settled by ClaimIdempotencyKey once that request commits.
+func (o *Order) replay(ctx context.Context, userID int64, key, hash string) (*dto.CreateOrderOutput, error) {
+	stored, err := o.repo.GetIdempotencyKey(ctx, userID, key)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.Response == nil {
+		return nil, nil
+	}
+
+	output, err := storedOutput(stored, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// storedOutput returns the response stored for a request with the same hash.
+func storedOutput(stored *domain.IdempotencyKey, hash string) (dto.CreateOrderOutput, error) {
+	var output dto.CreateOrderOutput
+	if stored.RequestHash != hash {
+		return output, domain.ErrIdempotencyKeyReused
+	}
+	if err := json.Unmarshal(stored.Response, &output); err != nil {
+		return output, err
+	}
+	output.Replayed = true
+	return output, nil
+}
@@ -0,0 +1,144 @@
+package janitor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestJanitor_RunOnce(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		events        []int64
+		processed     []int64
+		keys          []int64
+		eventsErr     error
+		wantErr       bool
+		wantEventRuns int
+		wantProcRuns  int
+		wantKeyRuns   int
+	}{
+		{
+			name:          "drains full batches",
+			events:        []int64{3, 3, 1},
+			processed:     []int64{3, 0},
+			keys:          []int64{3, 2},
+			wantEventRuns: 3,
+			wantProcRuns:  2,
+			wantKeyRuns:   2,
+		},
+		{
+			name:          "nothing to prune",
+			events:        []int64{0},
+			processed:     []int64{0},
+			keys:          []int64{0},
+			wantEventRuns: 1,
+			wantProcRuns:  1,
+			wantKeyRuns:   1,
+		},
+		{
+			name:          "events error stops run",
+			events:        []int64{3},
+			eventsErr:     errors.New("db down"),
+			wantErr:       true,
+			wantEventRuns: 2,
+			wantProcRuns:  0,
+			wantKeyRuns:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMock{events: tt.events, processed: tt.processed, keys: tt.keys, eventsErr: tt.eventsErr}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			j := New(repo, log, Config{
+				Retention:      24 * time.Hour,
+				DedupWindow:    7 * 24 * time.Hour,
+				IdempotencyTTL: 48 * time.Hour,
+				Archive:        true,
+				BatchSize:      3,
+			})
+			j.now = func() time.Time { return now }
+
+			err := j.RunOnce(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err: got %v, wantErr %v", err, tt.wantErr)
+			}
+			if repo.eventRuns != tt.wantEventRuns {
+				t.Fatalf("events batches: got %d, want %d", repo.eventRuns, tt.wantEventRuns)
+			}
+			if repo.procRuns != tt.wantProcRuns {
+				t.Fatalf("processed_events batches: got %d, want %d", repo.procRuns, tt.wantProcRuns)
+			}
+			if repo.keyRuns != tt.wantKeyRuns {
+				t.Fatalf("idempotency_keys batches: got %d, want %d", repo.keyRuns, tt.wantKeyRuns)
+			}
+			if repo.keyRuns > 0 && !repo.keysBefore.Equal(now.Add(-48*time.Hour)) {
+				t.Fatalf("idempotency_keys cutoff: got %v", repo.keysBefore)
+			}
+			if repo.eventRuns > 0 && !repo.eventsBefore.Equal(now.Add(-24*time.Hour)) {
+				t.Fatalf("events cutoff: got %v", repo.eventsBefore)
+			}
+			if repo.procRuns > 0 && !repo.procBefore.Equal(now.Add(-7*24*time.Hour)) {
+				t.Fatalf("processed_events cutoff: got %v", repo.procBefore)
+			}
+			if repo.eventRuns > 0 && !repo.archive {
+				t.Fatalf("expected archive mode to be passed through")
+			}
+		})
+	}
+}
+
+type repoMock struct {
+	events    []int64
+	processed []int64
+	keys      []int64
+	eventsErr error
+
+	eventRuns    int
+	procRuns     int
+	keyRuns      int
+	eventsBefore time.Time
+	procBefore   time.Time
+	keysBefore   time.Time
+	archive      bool
+}
+
+func (m *repoMock) PruneSentEvents(ctx context.Context, before time.Time, limit int, archive bool) (int64, error) {
+	m.eventRuns++
+	m.eventsBefore = before
+	m.archive = archive
+	if len(m.events) == 0 {
+		return 0, m.eventsErr
+	}
+	n := m.events[0]
+	m.events = m.events[1:]
+	return n, nil
+}
+
+func (m *repoMock) PruneProcessedEvents(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.procRuns++
+	m.procBefore = before
+	if len(m.processed) == 0 {
+		return 0, nil
+	}
+	n := m.processed[0]
+	m.processed = m.processed[1:]
+	return n, nil
+}
+
+func (m *repoMock) PruneIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.keyRuns++
+	m.keysBefore = before
+	if len(m.keys) == 0 {
+		return 0, nil
+	}
+	n := m.keys[0]
+	m.keys = m.keys[1:]
+	return n, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+func (o *Order) ListOrders(ctx context.Context, input dto.ListOrdersInput) (dto.ListOrdersOutput, error) {
+	const op = "services.Order.ListOrders"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", input.UserID))
+
+	var output dto.ListOrdersOutput
+
+	if input.UserID <= 0 {
+		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidUserID)
+	}
+	if input.PageSize < 0 {
+		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidPageSize)
+	}
+	for _, status := range input.Statuses {
+		if !status.Valid() {
+			return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidStatus)
+		}
+	}
+	if !input.CreatedFrom.IsZero() && !input.CreatedTo.IsZero() && input.CreatedFrom.After(input.CreatedTo) {
+		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidTimeRange)
+	}
+
+	pageSize := int(input.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = domain.DefaultPageSize
+	case pageSize > domain.MaxPageSize:
+		pageSize = domain.MaxPageSize
+	}
+
+	filter := domain.OrderFilter{
+		UserID:      domain.ID(input.UserID),
+		Statuses:    input.Statuses,
+		CreatedFrom: input.CreatedFrom.UTC(),
+		CreatedTo:   input.CreatedTo.UTC(),
+		// one extra row tells whether there is a next page
+		Limit: pageSize + 1,
+	}
+	if input.Cursor != "" {
+		cursor, err := domain.DecodeOrderCursor(input.Cursor)
+		if err != nil {
+			return output, fmt.Errorf("%s: %w", op, err)
+		}
+		filter.After = &cursor
+	}
+
+	orders, err := o.repo.ListOrders(ctx, filter)
+	if err != nil {
+		log.Error("list orders failed", slog.Any("err", err))
+		return output, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(orders) > pageSize {
+		orders = orders[:pageSize]
+		last := orders[len(orders)-1]
+		output.NextCursor = domain.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	output.Orders = make([]domain.Order, 0, len(orders))
+	for _, order := range orders {
+		output.Orders = append(output.Orders, *order)
+	}
+
+	return output, nil
+}
@@ -1,19 +1,35 @@
 package services
 
 import (
+	"context"
 	"log/slog"
 
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
 )
 
+// Catalog resolves product ids to their current catalog entries. Ids that
+// are not in the catalog are missing from the result.
+type Catalog interface {
+	GetProducts(ctx context.Context, ids []int64) (map[domain.ID]domain.Product, error)
+}
+
 type Order struct {
-	log  *slog.Logger
-	repo postgres.Repository
+	log     *slog.Logger
+	repo    postgres.Repository
+	catalog Catalog
+	// maxTotal caps the total of a new order in minor units, keyed by ISO
+	// 4217 code; a currency without an entry is not limited.
+	maxTotal map[string]int64
+	saga     SagaPolicy
 }
 
-func New(log *slog.Logger, repo postgres.Repository) *Order {
+func New(log *slog.Logger, repo postgres.Repository, catalog Catalog, maxTotal map[string]int64, saga SagaPolicy) *Order {
 	return &Order{
-		log:  log,
-		repo: repo,
+		log:      log,
+		repo:     repo,
+		catalog:  catalog,
+		maxTotal: maxTotal,
+		saga:     saga,
 	}
 }
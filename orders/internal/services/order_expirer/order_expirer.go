@@ -0,0 +1,57 @@
+package order_expirer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Expirer interface {
+	ExpireOrders(ctx context.Context, limit int) (int, error)
+}
+
+// Scheduler periodically expires orders left unpaid past their payment
+// deadline.
+type Scheduler struct {
+	expirer   Expirer
+	log       *slog.Logger
+	batchSize int
+}
+
+func New(expirer Expirer, log *slog.Logger, batchSize int) *Scheduler {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &Scheduler{
+		expirer:   expirer,
+		log:       log,
+		batchSize: batchSize,
+	}
+}
+
+func (s *Scheduler) Start(ctx context.Context, period time.Duration) {
+	const op = "services.order_expirer.Start"
+
+	log := s.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(period)
+
+	for {
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			log.Info("stopping order expirer")
+			return
+		case <-ticker.C:
+		}
+
+		expired, err := s.expirer.ExpireOrders(ctx, s.batchSize)
+		if err != nil {
+			log.Error("expire orders failed", slog.Any("err", err))
+			continue
+		}
+		if expired > 0 {
+			log.Debug("unpaid orders expired", slog.Int("count", expired))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/mapper"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+func (o *Order) HandlePaymentStatus(ctx context.Context, input dto.PaymentStatusInput) error {
+	const op = "services.Order.HandlePaymentStatus"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("event_id", input.EventID),
+		slog.Int64("order_id", input.OrderID),
+		slog.String("payment_status", input.Status))
+
+	if input.EventID == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidEventID)
+	}
+
+	if input.OrderID <= 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidOrderID)
+	}
+
+	status, err := mapper.MapPaymentStatus(input.Status)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var applied bool
+	err = o.runWithRetry(ctx, func(tx postgres.TxRepository) error {
+		applied = false
+
+		ok, err := tx.TryMarkProcessed(ctx, input.EventID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		if _, err := o.transition(ctx, tx, input.OrderID, status); err != nil {
+			if errors.Is(err, domain.ErrInvalidTransition) {
+				log.Warn("payment status ignored", slog.Any("err", err))
+				return nil
+			}
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, domain.ErrOrderNotFound)
+		}
+		log.Error("apply payment status failed", slog.Any("err", err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !applied {
+		log.Debug("payment status already processed")
+		return nil
+	}
+
+	log.Info("order status updated", slog.String("status", string(status)))
+	return nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+// priceItems replaces client prices with catalog ones. A client may leave
+// the price at zero; a non-zero price or an explicit currency must match the
+// catalog, so a stale cart is rejected instead of silently repriced.
+func (o *Order) priceItems(ctx context.Context, items []dto.CreateOrderItem) ([]dto.CreateOrderItem, error) {
+	const op = "services.Order.priceItems"
+
+	ids := make([]int64, 0, len(items))
+	for _, it := range items {
+		if it.ProductID <= 0 {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidProductID)
+		}
+		ids = append(ids, it.ProductID)
+	}
+
+	products, err := o.catalog.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	priced := make([]dto.CreateOrderItem, 0, len(items))
+	for _, it := range items {
+		product, ok := products[domain.ID(it.ProductID)]
+		if !ok || !product.Active {
+			return nil, fmt.Errorf("%s: %w: %d", op, domain.ErrUnknownProduct, it.ProductID)
+		}
+
+		if (it.Price != 0 && it.Price != product.Price.Amount) ||
+			(it.Currency != "" && domain.Currency(it.Currency) != product.Price.Currency) {
+			return nil, fmt.Errorf("%s: %w: product %d costs %s", op, domain.ErrPriceMismatch, it.ProductID, product.Price)
+		}
+
+		priced = append(priced, dto.CreateOrderItem{
+			ProductID: it.ProductID,
+			Quantity:  it.Quantity,
+			Price:     product.Price.Amount,
+			Currency:  string(product.Price.Currency),
+		})
+	}
+
+	return priced, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// redeemPromotion checks that userID may redeem the promo code for an order of
+// items and returns the promotion together with the discounts it grants. The
+// promotion stays locked until the transaction ends, so its redemption limits
+// hold under concurrent orders.
+func (o *Order) redeemPromotion(
+	ctx context.Context,
+	tx postgres.TxRepository,
+	userID int64,
+	code string,
+	items []domain.OrderItem) (*domain.Promotion, []domain.Adjustment, error) {
+	const op = "services.Order.redeemPromotion"
+
+	promotion, err := tx.GetPromotionForUpdate(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("%s: %w: %s", op, domain.ErrUnknownPromoCode, code)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	redeemed := 0
+	if promotion.MaxPerUser > 0 {
+		if redeemed, err = tx.CountUserRedemptions(ctx, int64(promotion.ID), userID); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	adjustments, err := promotion.Redeem(items, redeemed, time.Now().UTC())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return promotion, adjustments, nil
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/metrics"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// Actions taken on a saga step that ran past its deadline.
+const (
+	sagaActionRetry      = "retry"
+	sagaActionCompensate = "compensate"
+)
+
+// SagaPolicy bounds how long each step of the order saga waits for its reply.
+type SagaPolicy struct {
+	// StepTimeout is the deadline of a step; zero disables saga timeouts.
+	StepTimeout time.Duration
+	// MaxAttempts caps how often the command of a retryable step is issued
+	// before the saga compensates.
+	MaxAttempts int
+	// PaymentTTL is how long after it was placed an order may await payment
+	// before it expires; zero disables expiration. It replaces the step
+	// timeout of the payment step.
+	PaymentTTL time.Duration
+}
+
+// stepTimeout is the deadline of the step entered when the order reaches
+// status.
+func (p SagaPolicy) stepTimeout(status domain.Status) time.Duration {
+	if status == domain.StatusAwaitingPayment && p.PaymentTTL > 0 {
+		return 0
+	}
+	return p.StepTimeout
+}
+
+// lockSaga locks the saga of an order about to move to status to. It is nil
+// for a new order, whose saga starts with this transition, and for orders
+// placed before sagas were recorded.
+func (o *Order) lockSaga(ctx context.Context, tx postgres.TxRepository, orderID int64, to domain.Status) (*domain.Saga, error) {
+	const op = "services.Order.lockSaga"
+
+	if to == domain.StatusAwaitingStock {
+		return nil, nil
+	}
+
+	saga, err := tx.GetSagaForUpdate(ctx, orderID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return saga, nil
+}
+
+// advanceSaga is the orchestrator's reaction to an order status change: it
+// issues the command of the step the order entered and records the step in
+// the saga. The stock reservation command is the OrderCreated written along
+// with the order.
+func (o *Order) advanceSaga(ctx context.Context, tx postgres.TxRepository, saga *domain.Saga, changed events.OrderStatusChanged) error {
+	const op = "services.Order.advanceSaga"
+
+	orderID := int64(changed.OrderID)
+
+	switch changed.To {
+	case domain.StatusAwaitingStock:
+		if err := tx.CreateSaga(ctx, domain.NewSaga(orderID, changed.ChangedAt, o.saga.StepTimeout)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	case domain.StatusAwaitingPayment:
+		if err := o.requestPayment(ctx, tx, orderID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if saga == nil || !saga.Apply(changed.To, changed.ChangedAt, o.saga.stepTimeout(changed.To)) {
+		return nil
+	}
+	if err := tx.UpdateSaga(ctx, *saga); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ExpireSagas handles up to limit sagas whose step deadline has passed, each
+// in its own transaction, and returns how many were handled. A retryable step
+// gets its command issued again while attempts remain. Otherwise the saga is
+// compensated by cancelling the order: inventory releases the stock and
+// payments voids or refunds the charge on OrderCancelled.
+func (o *Order) ExpireSagas(ctx context.Context, limit int) (int, error) {
+	const op = "services.Order.ExpireSagas"
+
+	handled := 0
+	for handled < limit {
+		var (
+			saga   domain.Saga
+			action string
+		)
+		err := o.runWithRetry(ctx, func(tx postgres.TxRepository) error {
+			var err error
+			now := time.Now().UTC()
+
+			saga, err = tx.GetDueSaga(ctx, now)
+			if err != nil {
+				return err
+			}
+			if saga.OrderID == 0 {
+				return nil
+			}
+
+			action, err = o.expireSaga(ctx, tx, saga, now)
+			return err
+		})
+		if err != nil {
+			return handled, fmt.Errorf("%s: %w", op, err)
+		}
+		if saga.OrderID == 0 {
+			break
+		}
+
+		metrics.SagaTimeoutsTotal.WithLabelValues(string(saga.Step), action).Inc()
+		o.log.Warn("saga step timed out",
+			slog.String("op", op),
+			slog.Int64("order_id", int64(saga.OrderID)),
+			slog.String("step", string(saga.Step)),
+			slog.Int("attempts", saga.Attempts),
+			slog.String("action", action))
+		handled++
+	}
+
+	return handled, nil
+}
+
+// expireSaga retries or compensates one expired saga and returns the action
+// taken.
+func (o *Order) expireSaga(ctx context.Context, tx postgres.TxRepository, saga domain.Saga, now time.Time) (string, error) {
+	const op = "services.Order.expireSaga"
+
+	orderID := int64(saga.OrderID)
+
+	if saga.Step.Retryable() && saga.Attempts < o.saga.MaxAttempts {
+		if err := o.reserveStock(ctx, tx, orderID); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		saga.Retry(now, o.saga.StepTimeout)
+		if err := tx.UpdateSaga(ctx, saga); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		return sagaActionRetry, nil
+	}
+
+	reason := fmt.Sprintf("%s timed out after %d attempts", saga.Step, saga.Attempts)
+	saga.Compensate(reason, now)
+	if err := tx.UpdateSaga(ctx, saga); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The order may have ended meanwhile without its saga noticing, e.g. one
+	// placed before sagas were recorded; there is nothing left to undo then.
+	if err := o.cancel(ctx, tx, orderID, reason); err != nil && !errors.Is(err, domain.ErrInvalidTransition) {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return sagaActionCompensate, nil
+}
+
+// reserveStock issues the stock reservation command again: an OrderCreated
+// carrying the order as stored.
+func (o *Order) reserveStock(ctx context.Context, tx postgres.TxRepository, orderID int64) error {
+	const op = "services.Order.reserveStock"
+
+	order, err := tx.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	payload, err := json.Marshal(events.OrderCreatedOf(order))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.SaveEvent(ctx, events.TypeOrderCreated, payload, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetOrderSaga returns the saga state of an order, for debugging stuck
+// orders.
+func (o *Order) GetOrderSaga(ctx context.Context, input dto.GetOrderSagaInput) (dto.GetOrderSagaOutput, error) {
+	const op = "services.Order.GetOrderSaga"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("order_id", input.OrderID))
+
+	var output dto.GetOrderSagaOutput
+
+	if input.OrderID <= 0 {
+		return output, fmt.Errorf("%s: %w", op, domain.ErrInvalidOrderID)
+	}
+
+	saga, err := o.repo.GetSaga(ctx, input.OrderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return output, fmt.Errorf("%s: %w", op, domain.ErrSagaNotFound)
+		}
+		log.Error("get saga failed", slog.Any("err", err))
+		return output, fmt.Errorf("%s: %w", op, err)
+	}
+
+	output.Saga = *saga
+	return output, nil
+}
@@ -0,0 +1,57 @@
+package saga_watcher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Expirer interface {
+	ExpireSagas(ctx context.Context, limit int) (int, error)
+}
+
+// Watcher periodically hands sagas whose step deadline has passed to the
+// orchestrator.
+type Watcher struct {
+	expirer   Expirer
+	log       *slog.Logger
+	batchSize int
+}
+
+func New(expirer Expirer, log *slog.Logger, batchSize int) *Watcher {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &Watcher{
+		expirer:   expirer,
+		log:       log,
+		batchSize: batchSize,
+	}
+}
+
+func (w *Watcher) Start(ctx context.Context, period time.Duration) {
+	const op = "services.saga_watcher.Start"
+
+	log := w.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(period)
+
+	for {
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			log.Info("stopping saga watcher")
+			return
+		case <-ticker.C:
+		}
+
+		expired, err := w.expirer.ExpireSagas(ctx, w.batchSize)
+		if err != nil {
+			log.Error("expire sagas failed", slog.Any("err", err))
+			continue
+		}
+		if expired > 0 {
+			log.Debug("expired sagas handled", slog.Int("count", expired))
+		}
+	}
+}
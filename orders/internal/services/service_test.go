@@ -2,14 +2,20 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
+	"math"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	dto2 "github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -25,6 +31,13 @@ func TestOrdersService_Create(t *testing.T) {
 		UserID: 1,
 		Items:  []dto2.CreateOrderItem{},
 	}
+	mixedCurrencies := dto2.CreateOrderInput{
+		UserID: 1,
+		Items: []dto2.CreateOrderItem{
+			{ProductID: 10, Quantity: 1, Price: 100, Currency: "RUB"},
+			{ProductID: 11, Quantity: 1, Price: 5, Currency: "USD"},
+		},
+	}
 
 	tests := []struct {
 		name            string
@@ -36,6 +49,7 @@ func TestOrdersService_Create(t *testing.T) {
 	}{
 		{"ok", validInput, nil, false, 1, 42},
 		{"validation error", invalidInput, nil, true, 0, 0},
+		{"mixed currencies", mixedCurrencies, nil, true, 0, 0},
 		{"repo error", validInput, errDB, true, 1, 0},
 	}
 
@@ -44,9 +58,10 @@ func TestOrdersService_Create(t *testing.T) {
 			mock := &postgresMock{
 				createErr:     tt.mockErr,
 				createOrderID: 42,
+				getOrder:      &domain.Order{ID: 42, UserID: 1, Status: domain.StatusNew, Version: 1},
 			}
 			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-			svc := New(log, mock)
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
 
 			got, err := svc.CreateOrder(context.Background(), tt.input)
 			if tt.wantErr {
@@ -64,6 +79,290 @@ func TestOrdersService_Create(t *testing.T) {
 			if !tt.wantErr && got.ID != tt.wantID {
 				t.Fatalf("CreateOrder ID: got %d, want %d", got.ID, tt.wantID)
 			}
+
+			if !tt.wantErr && mock.updateStatus != domain.StatusAwaitingStock {
+				t.Fatalf("status after create: got %s, want %s", mock.updateStatus, domain.StatusAwaitingStock)
+			}
+
+			if !tt.wantErr && (len(mock.createdSagas) != 1 || mock.createdSagas[0].Step != domain.SagaStepReserveStock) {
+				t.Fatalf("sagas created: got %+v, want one at %s", mock.createdSagas, domain.SagaStepReserveStock)
+			}
+
+			if !tt.wantErr && mock.createTotal != (domain.Money{Amount: 200, Currency: domain.DefaultCurrency}) {
+				t.Fatalf("CreateOrder total: got %s, want %s", mock.createTotal, "200 RUB")
+			}
+		})
+	}
+}
+
+func TestOrdersService_Create_TotalLimits(t *testing.T) {
+	limits := map[string]int64{"RUB": 300, "USD": 50}
+
+	tests := []struct {
+		name      string
+		maxTotal  map[string]int64
+		currency  domain.Currency
+		price     int64
+		quantity  int32
+		wantErrIs error
+	}{
+		{"no limit", nil, "RUB", 1_000_000, 3, nil},
+		{"at limit", limits, "RUB", 100, 3, nil},
+		{"above limit", map[string]int64{"RUB": 299}, "RUB", 100, 3, domain.ErrTotalTooLarge},
+		{"limit of the order currency", limits, "USD", 20, 3, domain.ErrTotalTooLarge},
+		{"same amount under the other currency limit", limits, "RUB", 20, 3, nil},
+		{"currency without a limit", limits, "EUR", 1_000_000, 3, nil},
+		{"line overflow", nil, "RUB", math.MaxInt64, 2, domain.ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{
+				createOrderID: 42,
+				getOrder:      &domain.Order{ID: 42, UserID: 1, Status: domain.StatusNew, Version: 1},
+			}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			catalog := &catalogMock{products: map[domain.ID]domain.Product{
+				10: {ID: 10, Price: domain.Money{Amount: tt.price, Currency: tt.currency}, Active: true},
+			}}
+			svc := New(log, mock, catalog, tt.maxTotal, SagaPolicy{})
+
+			_, err := svc.CreateOrder(context.Background(), dto2.CreateOrderInput{
+				UserID: 1,
+				Items: []dto2.CreateOrderItem{
+					{ProductID: 10, Quantity: tt.quantity, Price: tt.price, Currency: string(tt.currency)},
+				},
+			})
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if tt.wantErrIs != nil && mock.createCalled != 0 {
+				t.Fatalf("CreateOrder calls: got %d, want 0", mock.createCalled)
+			}
+		})
+	}
+}
+
+func TestOrdersService_Create_CatalogPrices(t *testing.T) {
+	errCatalog := errors.New("catalog unavailable")
+	tests := []struct {
+		name       string
+		item       dto2.CreateOrderItem
+		catalogErr error
+		wantErrIs  error
+		wantTotal  domain.Money
+	}{
+		{"price from catalog", dto2.CreateOrderItem{ProductID: 10, Quantity: 2}, nil, nil, domain.Money{Amount: 200, Currency: "RUB"}},
+		{"matching price", dto2.CreateOrderItem{ProductID: 11, Quantity: 3, Price: 5, Currency: "USD"}, nil, nil, domain.Money{Amount: 15, Currency: "USD"}},
+		{"stale price", dto2.CreateOrderItem{ProductID: 10, Quantity: 1, Price: 90}, nil, domain.ErrPriceMismatch, domain.Money{}},
+		{"other currency", dto2.CreateOrderItem{ProductID: 10, Quantity: 1, Currency: "USD"}, nil, domain.ErrPriceMismatch, domain.Money{}},
+		{"unknown product", dto2.CreateOrderItem{ProductID: 99, Quantity: 1}, nil, domain.ErrUnknownProduct, domain.Money{}},
+		{"inactive product", dto2.CreateOrderItem{ProductID: 12, Quantity: 1}, nil, domain.ErrUnknownProduct, domain.Money{}},
+		{"invalid product id", dto2.CreateOrderItem{ProductID: 0, Quantity: 1}, nil, domain.ErrInvalidProductID, domain.Money{}},
+		{"catalog error", dto2.CreateOrderItem{ProductID: 10, Quantity: 1}, errCatalog, errCatalog, domain.Money{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{
+				createOrderID: 42,
+				getOrder:      &domain.Order{ID: 42, UserID: 1, Status: domain.StatusNew, Version: 1},
+			}
+			catalog := testCatalog()
+			catalog.err = tt.catalogErr
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, catalog, nil, SagaPolicy{})
+
+			_, err := svc.CreateOrder(context.Background(), dto2.CreateOrderInput{
+				UserID: 1,
+				Items:  []dto2.CreateOrderItem{tt.item},
+			})
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if tt.wantErrIs != nil {
+				if mock.createCalled != 0 {
+					t.Fatalf("CreateOrder calls: got %d, want 0", mock.createCalled)
+				}
+				return
+			}
+			if mock.createTotal != tt.wantTotal {
+				t.Fatalf("CreateOrder total: got %s, want %s", mock.createTotal, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestOrdersService_Create_IdempotencyKey(t *testing.T) {
+	input := dto2.CreateOrderInput{
+		UserID:         1,
+		Items:          []dto2.CreateOrderItem{{ProductID: 10, Quantity: 2, Price: 100}},
+		IdempotencyKey: "key-1",
+	}
+	mock := &postgresMock{
+		createOrderID: 42,
+		getOrder:      &domain.Order{ID: 42, UserID: 1, Status: domain.StatusNew, Version: 1},
+	}
+	catalog := testCatalog()
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(log, mock, catalog, nil, SagaPolicy{})
+
+	first, err := svc.CreateOrder(context.Background(), input)
+	if err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	if first.Replayed {
+		t.Fatalf("first create must not be a replay")
+	}
+
+	replay, err := svc.CreateOrder(context.Background(), input)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !replay.Replayed || replay.ID != first.ID {
+		t.Fatalf("replay: got %+v, want id %d replayed", replay, first.ID)
+	}
+	if mock.createCalled != 1 {
+		t.Fatalf("CreateOrder calls: got %d, want %d", mock.createCalled, 1)
+	}
+	if catalog.calls != 1 {
+		t.Fatalf("a replay must not price the items again: catalog calls %d", catalog.calls)
+	}
+
+	other := input
+	other.Items = []dto2.CreateOrderItem{{ProductID: 10, Quantity: 3, Price: 100}}
+	_, err = svc.CreateOrder(context.Background(), other)
+	if !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Fatalf("reused key: got %v, want %v", err, domain.ErrIdempotencyKeyReused)
+	}
+	if mock.createCalled != 1 || catalog.calls != 1 {
+		t.Fatalf("calls after reuse: create %d, catalog %d, want 1 and 1", mock.createCalled, catalog.calls)
+	}
+
+	promo := input
+	promo.PromoCode = "TEN"
+	if _, err := svc.CreateOrder(context.Background(), promo); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Fatalf("key reused with a promo code: got %v, want %v", err, domain.ErrIdempotencyKeyReused)
+	}
+
+	otherUser := input
+	otherUser.UserID = 2
+	created, err := svc.CreateOrder(context.Background(), otherUser)
+	if err != nil {
+		t.Fatalf("same key of another user: %v", err)
+	}
+	if created.Replayed || mock.createCalled != 2 {
+		t.Fatalf("another user's key must create an order: got %+v, CreateOrder calls %d", created, mock.createCalled)
+	}
+
+	long := input
+	long.IdempotencyKey = strings.Repeat("k", domain.MaxIdempotencyKeyLen+1)
+	_, err = svc.CreateOrder(context.Background(), long)
+	if !errors.Is(err, domain.ErrInvalidIdempotencyKey) {
+		t.Fatalf("long key: got %v, want %v", err, domain.ErrInvalidIdempotencyKey)
+	}
+}
+
+func TestOrdersService_Create_IdempotencyKeyReplayWithoutCatalog(t *testing.T) {
+	input := dto2.CreateOrderInput{
+		UserID:         1,
+		Items:          []dto2.CreateOrderItem{{ProductID: 10, Quantity: 2, Price: 100}},
+		IdempotencyKey: "key-1",
+	}
+	hash, err := requestHash(input)
+	if err != nil {
+		t.Fatalf("request hash: %v", err)
+	}
+	mock := &postgresMock{idempotencyKeys: map[idempotencyKey]*domain.IdempotencyKey{
+		{1, "key-1"}: {UserID: 1, Key: "key-1", RequestHash: hash, OrderID: 42, Response: []byte(`{"id":42}`)},
+	}}
+	catalog := testCatalog()
+	catalog.err = errors.New("catalog unavailable")
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(log, mock, catalog, map[string]int64{"RUB": 1}, SagaPolicy{})
+
+	replay, err := svc.CreateOrder(context.Background(), input)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !replay.Replayed || replay.ID != 42 {
+		t.Fatalf("replay: got %+v, want id 42 replayed", replay)
+	}
+	if catalog.calls != 0 || mock.txCalled != 0 {
+		t.Fatalf("a replay must not price or write: catalog %d, tx %d", catalog.calls, mock.txCalled)
+	}
+}
+
+func TestOrdersService_Create_PromoCode(t *testing.T) {
+	rub := func(amount int64) domain.Money { return domain.Money{Amount: amount, Currency: "RUB"} }
+	promotions := map[string]*domain.Promotion{
+		"TEN": {ID: 1, Code: "TEN", Kind: domain.DiscountPercent, Scope: domain.DiscountScopeOrder, Value: 10,
+			Currency: "RUB", Active: true},
+		"ONCE": {ID: 2, Code: "ONCE", Kind: domain.DiscountFixed, Scope: domain.DiscountScopeItem, Value: 30,
+			ProductID: 10, Currency: "RUB", MaxPerUser: 1, Active: true},
+		"GONE": {ID: 3, Code: "GONE", Kind: domain.DiscountPercent, Scope: domain.DiscountScopeOrder, Value: 10,
+			Currency: "RUB", MaxRedemptions: 5, Redemptions: 5, Active: true},
+		"BIG": {ID: 4, Code: "BIG", Kind: domain.DiscountPercent, Scope: domain.DiscountScopeOrder, Value: 10,
+			Currency: "RUB", MinOrder: 1000, Active: true},
+	}
+
+	tests := []struct {
+		name            string
+		code            string
+		userRedemptions map[domain.ID]int
+		wantErrIs       error
+		wantAdjustments []domain.Adjustment
+		wantTotal       domain.Money
+	}{
+		{"no code", "", nil, nil, nil, rub(200)},
+		{"percent off the order", " ten ", nil, nil,
+			[]domain.Adjustment{{PromotionID: 1, Code: "TEN", Amount: rub(20)}}, rub(180)},
+		{"fixed off each unit", "ONCE", nil, nil,
+			[]domain.Adjustment{{PromotionID: 2, Code: "ONCE", ProductID: 10, Amount: rub(60)}}, rub(140)},
+		{"used by the user", "ONCE", map[domain.ID]int{2: 1}, domain.ErrPromoCodeUsed, nil, domain.Money{}},
+		{"exhausted", "GONE", nil, domain.ErrPromoCodeExhausted, nil, domain.Money{}},
+		{"below minimum", "BIG", nil, domain.ErrPromoCodeNotApplicable, nil, domain.Money{}},
+		{"unknown", "NOPE", nil, domain.ErrUnknownPromoCode, nil, domain.Money{}},
+		{"too long", strings.Repeat("A", domain.MaxPromoCodeLen+1), nil, domain.ErrInvalidPromoCode, nil, domain.Money{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{
+				createOrderID:   42,
+				getOrder:        &domain.Order{ID: 42, UserID: 1, Status: domain.StatusNew, Version: 1},
+				promotions:      promotions,
+				userRedemptions: tt.userRedemptions,
+			}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
+
+			_, err := svc.CreateOrder(context.Background(), dto2.CreateOrderInput{
+				UserID:    1,
+				Items:     []dto2.CreateOrderItem{{ProductID: 10, Quantity: 2}},
+				PromoCode: tt.code,
+			})
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if tt.wantErrIs != nil {
+				if mock.createCalled != 0 || len(mock.redeemed) != 0 {
+					t.Fatalf("CreateOrder calls: got %d, redemptions: got %v, want none", mock.createCalled, mock.redeemed)
+				}
+				return
+			}
+			if mock.createTotal != tt.wantTotal {
+				t.Fatalf("CreateOrder total: got %s, want %s", mock.createTotal, tt.wantTotal)
+			}
+			if !slices.Equal(mock.createAdjusts, tt.wantAdjustments) {
+				t.Fatalf("CreateOrder adjustments: got %+v, want %+v", mock.createAdjusts, tt.wantAdjustments)
+			}
+			if len(tt.wantAdjustments) > 0 && !slices.Equal(mock.redeemed, []int64{int64(tt.wantAdjustments[0].PromotionID)}) {
+				t.Fatalf("redemptions: got %v, want promotion %d", mock.redeemed, tt.wantAdjustments[0].PromotionID)
+			}
+			if len(tt.wantAdjustments) == 0 && len(mock.redeemed) != 0 {
+				t.Fatalf("redemptions: got %v, want none", mock.redeemed)
+			}
 		})
 	}
 }
@@ -118,7 +417,7 @@ func TestOrdersService_Get(t *testing.T) {
 				getErr:   tt.mockErr,
 			}
 			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-			svc := New(log, mock)
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
 
 			_, err := svc.GetOrder(context.Background(), tt.input)
 			if tt.wantErrIs != nil {
@@ -139,22 +438,785 @@ func TestOrdersService_Get(t *testing.T) {
 	}
 }
 
+func TestOrdersService_List(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	stored := make([]*domain.Order, 0, 3)
+	for i := 3; i >= 1; i-- {
+		stored = append(stored, &domain.Order{ID: domain.ID(i), UserID: 1, Status: domain.StatusNew, CreatedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+
+	tests := []struct {
+		name       string
+		input      dto2.ListOrdersInput
+		wantErrIs  error
+		wantLimit  int
+		wantIDs    []domain.ID
+		wantCursor *domain.OrderCursor
+	}{
+		{
+			name:      "invalid user",
+			input:     dto2.ListOrdersInput{},
+			wantErrIs: domain.ErrInvalidUserID,
+		},
+		{
+			name:      "negative page size",
+			input:     dto2.ListOrdersInput{UserID: 1, PageSize: -1},
+			wantErrIs: domain.ErrInvalidPageSize,
+		},
+		{
+			name:      "unknown status",
+			input:     dto2.ListOrdersInput{UserID: 1, Statuses: []domain.Status{"shipped"}},
+			wantErrIs: domain.ErrInvalidStatus,
+		},
+		{
+			name:      "inverted range",
+			input:     dto2.ListOrdersInput{UserID: 1, CreatedFrom: base, CreatedTo: base.Add(-time.Hour)},
+			wantErrIs: domain.ErrInvalidTimeRange,
+		},
+		{
+			name:      "bad cursor",
+			input:     dto2.ListOrdersInput{UserID: 1, Cursor: "%%%"},
+			wantErrIs: domain.ErrInvalidCursor,
+		},
+		{
+			name:      "default page size",
+			input:     dto2.ListOrdersInput{UserID: 1},
+			wantLimit: domain.DefaultPageSize + 1,
+			wantIDs:   []domain.ID{3, 2, 1},
+		},
+		{
+			name:       "next page",
+			input:      dto2.ListOrdersInput{UserID: 1, PageSize: 2},
+			wantLimit:  3,
+			wantIDs:    []domain.ID{3, 2},
+			wantCursor: &domain.OrderCursor{CreatedAt: base.Add(2 * time.Minute), ID: 2},
+		},
+		{
+			name:      "page size capped",
+			input:     dto2.ListOrdersInput{UserID: 1, PageSize: 1000},
+			wantLimit: domain.MaxPageSize + 1,
+			wantIDs:   []domain.ID{3, 2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{listOrders: stored}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
+
+			got, err := svc.ListOrders(context.Background(), tt.input)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mock.listFilter.Limit != tt.wantLimit {
+				t.Fatalf("limit: got %d, want %d", mock.listFilter.Limit, tt.wantLimit)
+			}
+			if len(got.Orders) != len(tt.wantIDs) {
+				t.Fatalf("orders: got %d, want %d", len(got.Orders), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got.Orders[i].ID != id {
+					t.Fatalf("order %d: got id %d, want %d", i, got.Orders[i].ID, id)
+				}
+			}
+
+			if tt.wantCursor == nil {
+				if got.NextCursor != "" {
+					t.Fatalf("next cursor: got %q, want none", got.NextCursor)
+				}
+				return
+			}
+			if got.NextCursor != tt.wantCursor.Encode() {
+				t.Fatalf("next cursor: got %q, want %q", got.NextCursor, tt.wantCursor.Encode())
+			}
+		})
+	}
+}
+
+func TestOrdersService_Cancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      dto2.CancelOrderInput
+		mockOrder  *domain.Order
+		mockErr    error
+		wantErrIs  error
+		wantEvents []string
+	}{
+		{
+			name:       "paid order",
+			input:      dto2.CancelOrderInput{ID: 10, Reason: "changed my mind"},
+			mockOrder:  &domain.Order{ID: 10, UserID: 1, Status: domain.StatusPaid, Version: 3},
+			wantEvents: []string{events.TypeOrderStatusChanged, events.TypeOrderCancelled},
+		},
+		{
+			name:       "awaiting payment",
+			input:      dto2.CancelOrderInput{ID: 10},
+			mockOrder:  &domain.Order{ID: 10, UserID: 1, Status: domain.StatusAwaitingPayment},
+			wantEvents: []string{events.TypeOrderStatusChanged, events.TypeOrderCancelled},
+		},
+		{
+			name:      "fulfilled",
+			input:     dto2.CancelOrderInput{ID: 10},
+			mockOrder: &domain.Order{ID: 10, UserID: 1, Status: domain.StatusFulfilled},
+			wantErrIs: domain.ErrInvalidTransition,
+		},
+		{
+			name:      "invalid id",
+			input:     dto2.CancelOrderInput{ID: 0},
+			wantErrIs: domain.ErrInvalidOrderID,
+		},
+		{
+			name:      "not found",
+			input:     dto2.CancelOrderInput{ID: 11},
+			mockErr:   pgx.ErrNoRows,
+			wantErrIs: domain.ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{
+				getOrder: tt.mockOrder,
+				getErr:   tt.mockErr,
+			}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
+
+			_, err := svc.CancelOrder(context.Background(), tt.input)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+				if len(mock.savedEvents) != 0 {
+					t.Fatalf("unexpected events: %v", mock.savedEvents)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mock.updateStatus != domain.StatusCancelled {
+				t.Fatalf("status: got %q, want %q", mock.updateStatus, domain.StatusCancelled)
+			}
+			if !slices.Equal(mock.savedEvents, tt.wantEvents) {
+				t.Fatalf("events: got %v, want %v", mock.savedEvents, tt.wantEvents)
+			}
+
+			var event events.OrderCancelled
+			if err := json.Unmarshal(mock.savedPayloads[1], &event); err != nil {
+				t.Fatalf("decode event: %v", err)
+			}
+			if event.OrderID != 10 || event.From != tt.mockOrder.Status || event.Reason != tt.input.Reason {
+				t.Fatalf("unexpected event: %+v", event)
+			}
+		})
+	}
+}
+
+func TestOrdersService_HandlePaymentStatus(t *testing.T) {
+	errDB := errors.New("db")
+	awaiting := &domain.Order{ID: 10, UserID: 1, Status: domain.StatusAwaitingPayment, Version: 2}
+	paid := &domain.Order{ID: 10, UserID: 1, Status: domain.StatusPaid, Version: 3}
+
+	tests := []struct {
+		name        string
+		input       dto2.PaymentStatusInput
+		order       *domain.Order
+		duplicate   bool
+		getErr      error
+		updateErr   error
+		wantErrIs   error
+		wantTxCalls int
+		wantUpdates int
+		wantStatus  domain.Status
+	}{
+		{
+			name:        "paid",
+			input:       dto2.PaymentStatusInput{EventID: 1, OrderID: 10, UserID: 1, Status: domain.PaymentStatusSucceeded},
+			order:       awaiting,
+			wantTxCalls: 1,
+			wantUpdates: 1,
+			wantStatus:  domain.StatusPaid,
+		},
+		{
+			name:        "payment failed",
+			input:       dto2.PaymentStatusInput{EventID: 2, OrderID: 10, UserID: 1, Status: domain.PaymentStatusFailed},
+			order:       awaiting,
+			wantTxCalls: 1,
+			wantUpdates: 1,
+			wantStatus:  domain.StatusPaymentFailed,
+		},
+		{
+			name:        "already processed",
+			input:       dto2.PaymentStatusInput{EventID: 3, OrderID: 10, UserID: 1, Status: domain.PaymentStatusSucceeded},
+			order:       awaiting,
+			duplicate:   true,
+			wantTxCalls: 1,
+		},
+		{
+			name:        "transition not allowed",
+			input:       dto2.PaymentStatusInput{EventID: 4, OrderID: 10, UserID: 1, Status: domain.PaymentStatusFailed},
+			order:       paid,
+			wantTxCalls: 1,
+		},
+		{
+			name:      "invalid event id",
+			input:     dto2.PaymentStatusInput{EventID: 0, OrderID: 10, Status: domain.PaymentStatusSucceeded},
+			wantErrIs: domain.ErrInvalidEventID,
+		},
+		{
+			name:      "unknown status",
+			input:     dto2.PaymentStatusInput{EventID: 5, OrderID: 10, Status: "pending"},
+			wantErrIs: domain.ErrInvalidPaymentStatus,
+		},
+		{
+			name:        "order not found",
+			input:       dto2.PaymentStatusInput{EventID: 6, OrderID: 11, Status: domain.PaymentStatusFailed},
+			getErr:      pgx.ErrNoRows,
+			wantErrIs:   domain.ErrOrderNotFound,
+			wantTxCalls: 1,
+		},
+		{
+			name:        "version conflict retried",
+			input:       dto2.PaymentStatusInput{EventID: 7, OrderID: 10, Status: domain.PaymentStatusSucceeded},
+			order:       awaiting,
+			updateErr:   domain.ErrVersionConflict,
+			wantErrIs:   domain.ErrVersionConflict,
+			wantTxCalls: maxConflictRetries,
+			wantUpdates: maxConflictRetries,
+		},
+		{
+			name:        "repo error",
+			input:       dto2.PaymentStatusInput{EventID: 8, OrderID: 12, Status: domain.PaymentStatusFailed},
+			order:       awaiting,
+			updateErr:   errDB,
+			wantErrIs:   errDB,
+			wantTxCalls: 1,
+			wantUpdates: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{
+				getOrder:  tt.order,
+				getErr:    tt.getErr,
+				duplicate: tt.duplicate,
+				updateErr: tt.updateErr,
+			}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
+
+			err := svc.HandlePaymentStatus(context.Background(), tt.input)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mock.txCalled != tt.wantTxCalls {
+				t.Fatalf("RunInTx calls: got %d, want %d", mock.txCalled, tt.wantTxCalls)
+			}
+			if mock.updateCalled != tt.wantUpdates {
+				t.Fatalf("UpdateOrderStatus calls: got %d, want %d", mock.updateCalled, tt.wantUpdates)
+			}
+			if tt.wantStatus != "" {
+				if mock.updateStatus != tt.wantStatus {
+					t.Fatalf("status: got %s, want %s", mock.updateStatus, tt.wantStatus)
+				}
+				if len(mock.savedEvents) != 1 || mock.savedEvents[0] != events.TypeOrderStatusChanged {
+					t.Fatalf("saved events: got %v, want [%s]", mock.savedEvents, events.TypeOrderStatusChanged)
+				}
+			}
+		})
+	}
+}
+
+func TestOrdersService_HandleStockResult(t *testing.T) {
+	errDB := errors.New("db")
+	awaiting := &domain.Order{
+		ID: 10, UserID: 1, Status: domain.StatusAwaitingStock, Version: 2,
+		TotalAmount: domain.Money{Amount: 300, Currency: "RUB"},
+	}
+	free := &domain.Order{
+		ID: 10, UserID: 1, Status: domain.StatusAwaitingStock, Version: 2,
+		TotalAmount: domain.Money{Currency: "RUB"},
+	}
+	cancelled := &domain.Order{ID: 10, UserID: 1, Status: domain.StatusCancelled, Version: 3}
+	reserving := domain.NewSaga(10, time.Now(), time.Minute)
+
+	tests := []struct {
+		name         string
+		input        dto2.StockResultInput
+		order        *domain.Order
+		saga         *domain.Saga
+		getErr       error
+		updateErr    error
+		wantErrIs    error
+		wantTxCalls  int
+		wantStatus   domain.Status
+		wantEvents   []string
+		wantSagaStep domain.SagaStep
+	}{
+		{
+			name:         "reserved",
+			input:        dto2.StockResultInput{OrderID: 10, Reserved: true},
+			order:        awaiting,
+			saga:         &reserving,
+			wantTxCalls:  1,
+			wantStatus:   domain.StatusAwaitingPayment,
+			wantEvents:   []string{events.TypeOrderStatusChanged, events.TypePaymentRequested},
+			wantSagaStep: domain.SagaStepChargePayment,
+		},
+		{
+			name:        "reserved with nothing to charge",
+			input:       dto2.StockResultInput{OrderID: 10, Reserved: true},
+			order:       free,
+			saga:        &reserving,
+			wantTxCalls: 1,
+			wantStatus:  domain.StatusPaid,
+			wantEvents:  []string{events.TypeOrderStatusChanged, events.TypeOrderStatusChanged},
+		},
+		{
+			name:         "rejected",
+			input:        dto2.StockResultInput{OrderID: 10, ProductID: 11, Reason: "insufficient stock"},
+			order:        awaiting,
+			saga:         &reserving,
+			wantTxCalls:  1,
+			wantStatus:   domain.StatusOutOfStock,
+			wantEvents:   []string{events.TypeOrderStatusChanged},
+			wantSagaStep: domain.SagaStepCompensated,
+		},
+		{
+			name:        "order without saga",
+			input:       dto2.StockResultInput{OrderID: 10, Reserved: true},
+			order:       awaiting,
+			wantTxCalls: 1,
+			wantStatus:  domain.StatusAwaitingPayment,
+			wantEvents:  []string{events.TypeOrderStatusChanged, events.TypePaymentRequested},
+		},
+		{
+			name:        "order cancelled meanwhile",
+			input:       dto2.StockResultInput{OrderID: 10, Reserved: true},
+			order:       cancelled,
+			wantTxCalls: 1,
+		},
+		{
+			name:      "invalid order id",
+			input:     dto2.StockResultInput{OrderID: 0, Reserved: true},
+			wantErrIs: domain.ErrInvalidOrderID,
+		},
+		{
+			name:        "order not found",
+			input:       dto2.StockResultInput{OrderID: 11, Reserved: true},
+			getErr:      pgx.ErrNoRows,
+			wantErrIs:   domain.ErrOrderNotFound,
+			wantTxCalls: 1,
+		},
+		{
+			name:        "repo error",
+			input:       dto2.StockResultInput{OrderID: 10, Reserved: true},
+			order:       awaiting,
+			updateErr:   errDB,
+			wantErrIs:   errDB,
+			wantTxCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{
+				getOrder:      tt.order,
+				getErr:        tt.getErr,
+				updateErr:     tt.updateErr,
+				persistStatus: true,
+				saga:          tt.saga,
+			}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{StepTimeout: time.Minute, MaxAttempts: 3})
+
+			err := svc.HandleStockResult(context.Background(), tt.input)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mock.txCalled != tt.wantTxCalls {
+				t.Fatalf("RunInTx calls: got %d, want %d", mock.txCalled, tt.wantTxCalls)
+			}
+			if tt.wantStatus != "" && mock.updateStatus != tt.wantStatus {
+				t.Fatalf("status: got %s, want %s", mock.updateStatus, tt.wantStatus)
+			}
+			if tt.wantErrIs == nil && !slices.Equal(mock.savedEvents, tt.wantEvents) {
+				t.Fatalf("saved events: got %v, want %v", mock.savedEvents, tt.wantEvents)
+			}
+			if tt.wantSagaStep != "" && (len(mock.updatedSagas) != 1 || mock.updatedSagas[0].Step != tt.wantSagaStep) {
+				t.Fatalf("sagas updated: got %+v, want one at %s", mock.updatedSagas, tt.wantSagaStep)
+			}
+		})
+	}
+}
+
+func TestOrdersService_ExpireSagas(t *testing.T) {
+	due := func(step domain.SagaStep, attempts int) domain.Saga {
+		return domain.Saga{
+			OrderID: 10, Step: step, Attempts: attempts,
+			DeadlineAt: time.Now().Add(-time.Second),
+		}
+	}
+	order := func(status domain.Status) *domain.Order {
+		return &domain.Order{
+			ID: 10, UserID: 1, Status: status, Version: 2,
+			Items:       []domain.OrderItem{{ProductID: 5, Quantity: 2, Price: domain.Money{Amount: 100, Currency: "RUB"}}},
+			TotalAmount: domain.Money{Amount: 200, Currency: "RUB"},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		due           []domain.Saga
+		order         *domain.Order
+		wantHandled   int
+		wantEvents    []string
+		wantStatus    domain.Status
+		wantSagaStep  domain.SagaStep
+		wantAttempts  int
+		wantLastError string
+	}{
+		{
+			name:         "reservation retried",
+			due:          []domain.Saga{due(domain.SagaStepReserveStock, 1)},
+			order:        order(domain.StatusAwaitingStock),
+			wantHandled:  1,
+			wantEvents:   []string{events.TypeOrderCreated},
+			wantSagaStep: domain.SagaStepReserveStock,
+			wantAttempts: 2,
+		},
+		{
+			name:          "reservation attempts exhausted",
+			due:           []domain.Saga{due(domain.SagaStepReserveStock, 3)},
+			order:         order(domain.StatusAwaitingStock),
+			wantHandled:   1,
+			wantEvents:    []string{events.TypeOrderStatusChanged, events.TypeOrderCancelled},
+			wantStatus:    domain.StatusCancelled,
+			wantSagaStep:  domain.SagaStepCompensated,
+			wantAttempts:  3,
+			wantLastError: "reserve_stock timed out after 3 attempts",
+		},
+		{
+			name:          "payment never retried",
+			due:           []domain.Saga{due(domain.SagaStepChargePayment, 1)},
+			order:         order(domain.StatusAwaitingPayment),
+			wantHandled:   1,
+			wantEvents:    []string{events.TypeOrderStatusChanged, events.TypeOrderCancelled},
+			wantStatus:    domain.StatusCancelled,
+			wantSagaStep:  domain.SagaStepCompensated,
+			wantAttempts:  1,
+			wantLastError: "charge_payment timed out after 1 attempts",
+		},
+		{
+			name:          "order already ended",
+			due:           []domain.Saga{due(domain.SagaStepReserveStock, 3)},
+			order:         order(domain.StatusOutOfStock),
+			wantHandled:   1,
+			wantSagaStep:  domain.SagaStepCompensated,
+			wantAttempts:  3,
+			wantLastError: "reserve_stock timed out after 3 attempts",
+		},
+		{
+			name: "nothing due",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{getOrder: tt.order, dueSagas: tt.due}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{StepTimeout: time.Minute, MaxAttempts: 3})
+
+			handled, err := svc.ExpireSagas(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if handled != tt.wantHandled {
+				t.Fatalf("handled: got %d, want %d", handled, tt.wantHandled)
+			}
+			if !slices.Equal(mock.savedEvents, tt.wantEvents) {
+				t.Fatalf("saved events: got %v, want %v", mock.savedEvents, tt.wantEvents)
+			}
+			if mock.updateStatus != tt.wantStatus {
+				t.Fatalf("status: got %q, want %q", mock.updateStatus, tt.wantStatus)
+			}
+			if tt.wantHandled == 0 {
+				return
+			}
+
+			if len(mock.updatedSagas) != 1 {
+				t.Fatalf("sagas updated: got %+v, want one", mock.updatedSagas)
+			}
+			saga := mock.updatedSagas[0]
+			if saga.Step != tt.wantSagaStep || saga.Attempts != tt.wantAttempts || saga.LastError != tt.wantLastError {
+				t.Fatalf("saga: got step %s attempts %d error %q, want step %s attempts %d error %q",
+					saga.Step, saga.Attempts, saga.LastError, tt.wantSagaStep, tt.wantAttempts, tt.wantLastError)
+			}
+			if saga.Step.Final() != saga.DeadlineAt.IsZero() {
+				t.Fatalf("saga deadline: got %s for step %s", saga.DeadlineAt, saga.Step)
+			}
+		})
+	}
+}
+
+func TestOrdersService_ExpireOrders(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	awaiting := &domain.Order{ID: 10, UserID: 1, Status: domain.StatusAwaitingPayment, Version: 3}
+	charging := domain.Saga{OrderID: 10, Step: domain.SagaStepChargePayment, Attempts: 1}
+
+	tests := []struct {
+		name         string
+		ttl          time.Duration
+		unpaid       []int64
+		order        *domain.Order
+		saga         *domain.Saga
+		wantExpired  int
+		wantEvents   []string
+		wantStatus   domain.Status
+		wantSagaStep domain.SagaStep
+	}{
+		{
+			name:         "unpaid order expired",
+			ttl:          time.Hour,
+			unpaid:       []int64{10},
+			order:        awaiting,
+			saga:         &charging,
+			wantExpired:  1,
+			wantEvents:   []string{events.TypeOrderStatusChanged, events.TypeOrderExpired},
+			wantStatus:   domain.StatusExpired,
+			wantSagaStep: domain.SagaStepCompensated,
+		},
+		{
+			name:        "order without saga",
+			ttl:         time.Hour,
+			unpaid:      []int64{10},
+			order:       awaiting,
+			wantExpired: 1,
+			wantEvents:  []string{events.TypeOrderStatusChanged, events.TypeOrderExpired},
+			wantStatus:  domain.StatusExpired,
+		},
+		{
+			name: "nothing unpaid",
+			ttl:  time.Hour,
+		},
+		{
+			name:   "expiration disabled",
+			unpaid: []int64{10},
+			order:  awaiting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saga *domain.Saga
+			if tt.saga != nil {
+				stored := *tt.saga
+				saga = &stored
+			}
+			mock := &postgresMock{getOrder: tt.order, saga: saga, unpaidOrders: tt.unpaid, unpaidCreatedAt: createdAt}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{StepTimeout: time.Minute, MaxAttempts: 3, PaymentTTL: tt.ttl})
+
+			before := time.Now().UTC()
+			expired, err := svc.ExpireOrders(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expired != tt.wantExpired {
+				t.Fatalf("expired: got %d, want %d", expired, tt.wantExpired)
+			}
+			if !slices.Equal(mock.savedEvents, tt.wantEvents) {
+				t.Fatalf("saved events: got %v, want %v", mock.savedEvents, tt.wantEvents)
+			}
+			if mock.updateStatus != tt.wantStatus {
+				t.Fatalf("status: got %q, want %q", mock.updateStatus, tt.wantStatus)
+			}
+			if tt.ttl > 0 && mock.unpaidBefore.Before(before.Add(-tt.ttl)) {
+				t.Fatalf("created before: got %s, want at least %s", mock.unpaidBefore, before.Add(-tt.ttl))
+			}
+			if tt.wantSagaStep != "" && (len(mock.updatedSagas) != 1 || mock.updatedSagas[0].Step != tt.wantSagaStep ||
+				mock.updatedSagas[0].LastError != "order expired") {
+				t.Fatalf("sagas updated: got %+v, want one at %s", mock.updatedSagas, tt.wantSagaStep)
+			}
+			if tt.wantExpired == 0 {
+				return
+			}
+
+			var event events.OrderExpired
+			if err := json.Unmarshal(mock.savedPayloads[1], &event); err != nil {
+				t.Fatalf("unmarshal event: %v", err)
+			}
+			if event.OrderID != 10 || event.UserID != 1 || !event.DeadlineAt.Equal(createdAt.Add(tt.ttl)) || event.ExpiredAt.IsZero() {
+				t.Fatalf("event: got %+v", event)
+			}
+		})
+	}
+}
+
+func TestOrdersService_PaymentTTLReplacesStepTimeout(t *testing.T) {
+	reserving := domain.NewSaga(10, time.Now(), time.Minute)
+	mock := &postgresMock{
+		getOrder: &domain.Order{
+			ID: 10, UserID: 1, Status: domain.StatusAwaitingStock, Version: 2,
+			TotalAmount: domain.Money{Amount: 200, Currency: "RUB"},
+		},
+		saga: &reserving,
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(log, mock, testCatalog(), nil, SagaPolicy{StepTimeout: time.Minute, MaxAttempts: 3, PaymentTTL: time.Hour})
+
+	if err := svc.HandleStockResult(context.Background(), dto2.StockResultInput{OrderID: 10, Reserved: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.updatedSagas) != 1 {
+		t.Fatalf("sagas updated: got %+v, want one", mock.updatedSagas)
+	}
+	if saga := mock.updatedSagas[0]; saga.Step != domain.SagaStepChargePayment || !saga.DeadlineAt.IsZero() {
+		t.Fatalf("saga: got step %s deadline %s, want %s without deadline", saga.Step, saga.DeadlineAt, domain.SagaStepChargePayment)
+	}
+}
+
+func TestOrdersService_GetOrderSaga(t *testing.T) {
+	errDB := errors.New("db")
+	saga := domain.NewSaga(10, time.Now(), time.Minute)
+
+	tests := []struct {
+		name      string
+		input     dto2.GetOrderSagaInput
+		saga      *domain.Saga
+		sagaErr   error
+		wantErrIs error
+	}{
+		{"ok", dto2.GetOrderSagaInput{OrderID: 10}, &saga, nil, nil},
+		{"invalid order id", dto2.GetOrderSagaInput{OrderID: 0}, nil, nil, domain.ErrInvalidOrderID},
+		{"not found", dto2.GetOrderSagaInput{OrderID: 11}, nil, nil, domain.ErrSagaNotFound},
+		{"repo error", dto2.GetOrderSagaInput{OrderID: 10}, nil, errDB, errDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &postgresMock{saga: tt.saga, sagaErr: tt.sagaErr}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(log, mock, testCatalog(), nil, SagaPolicy{})
+
+			got, err := svc.GetOrderSaga(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if tt.wantErrIs == nil && got.Saga != *tt.saga {
+				t.Fatalf("saga: got %+v, want %+v", got.Saga, *tt.saga)
+			}
+		})
+	}
+}
+
+type catalogMock struct {
+	products map[domain.ID]domain.Product
+	err      error
+	calls    int
+}
+
+// testCatalog knows product 10 for 100 RUB, product 11 for 5 USD and the
+// inactive product 12.
+func testCatalog() *catalogMock {
+	return &catalogMock{products: map[domain.ID]domain.Product{
+		10: {ID: 10, Price: domain.Money{Amount: 100, Currency: "RUB"}, Active: true},
+		11: {ID: 11, Price: domain.Money{Amount: 5, Currency: "USD"}, Active: true},
+		12: {ID: 12, Price: domain.Money{Amount: 50, Currency: "RUB"}},
+	}}
+}
+
+func (m *catalogMock) GetProducts(_ context.Context, ids []int64) (map[domain.ID]domain.Product, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	res := make(map[domain.ID]domain.Product, len(ids))
+	for _, id := range ids {
+		if p, ok := m.products[domain.ID(id)]; ok {
+			res[p.ID] = p
+		}
+	}
+	return res, nil
+}
+
 type postgresMock struct {
+	txCalled int
+
 	createCalled  int
 	createUserID  int64
 	createItems   []domain.OrderItem
+	createAdjusts []domain.Adjustment
+	createTotal   domain.Money
 	createErr     error
 	createOrderID int64
 
 	getCalled int
 	getOrder  *domain.Order
 	getErr    error
+
+	updateCalled int
+	updateStatus domain.Status
+	updateErr    error
+	// persistStatus makes updates visible to later GetOrderByID calls.
+	persistStatus bool
+
+	duplicate     bool
+	savedEvents   []string
+	savedPayloads [][]byte
+
+	idempotencyKeys map[idempotencyKey]*domain.IdempotencyKey
+
+	listFilter domain.OrderFilter
+	listOrders []*domain.Order
+
+	saga         *domain.Saga
+	sagaErr      error
+	createdSagas []domain.Saga
+	updatedSagas []domain.Saga
+	dueSagas     []domain.Saga
+
+	unpaidOrders    []int64
+	unpaidCreatedAt time.Time
+	unpaidBefore    time.Time
+
+	promotions      map[string]*domain.Promotion
+	userRedemptions map[domain.ID]int
+	redeemed        []int64
+}
+
+func (m *postgresMock) RunInTx(ctx context.Context, fn func(tx postgres.TxRepository) error) error {
+	m.txCalled++
+	return fn(m)
 }
 
-func (m *postgresMock) CreateOrder(ctx context.Context, userID int64, items []domain.OrderItem) (int64, error) {
+func (m *postgresMock) CreateOrder(ctx context.Context, userID int64, items []domain.OrderItem, adjustments []domain.Adjustment, total domain.Money) (int64, error) {
 	m.createCalled++
 	m.createUserID = userID
 	m.createItems = items
+	m.createAdjusts = adjustments
+	m.createTotal = total
 	if m.createErr != nil {
 		return 0, m.createErr
 	}
@@ -166,17 +1228,151 @@ func (m *postgresMock) GetOrderByID(ctx context.Context, id int64) (*domain.Orde
 	if m.getErr != nil {
 		return nil, m.getErr
 	}
-	return m.getOrder, nil
+	if m.getOrder == nil {
+		return nil, nil
+	}
+	order := *m.getOrder
+	return &order, nil
 }
 
-func (m *postgresMock) GetNewEvent(ctx context.Context) (events.OrderCreated, int64, error) {
-	return events.OrderCreated{}, 0, nil
+func (m *postgresMock) ListOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, error) {
+	m.listFilter = filter
+	if len(m.listOrders) > filter.Limit {
+		return m.listOrders[:filter.Limit], nil
+	}
+	return m.listOrders, nil
 }
 
-func (m *postgresMock) MarkSent(ctx context.Context, eventID int64) error {
+func (m *postgresMock) UpdateOrderStatus(ctx context.Context, orderID int64, status domain.Status, expectedVersion int64) (int64, error) {
+	m.updateCalled++
+	m.updateStatus = status
+	if m.updateErr != nil {
+		return 0, m.updateErr
+	}
+	if m.persistStatus && m.getOrder != nil {
+		order := *m.getOrder
+		order.Status, order.Version = status, expectedVersion+1
+		m.getOrder = &order
+	}
+	return expectedVersion + 1, nil
+}
+
+func (m *postgresMock) TryMarkProcessed(ctx context.Context, eventID int64) (bool, error) {
+	return !m.duplicate, nil
+}
+
+func (m *postgresMock) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
+	m.savedEvents = append(m.savedEvents, eventType)
+	m.savedPayloads = append(m.savedPayloads, payload)
 	return nil
 }
 
+// idempotencyKey scopes a client key to its user, as the idempotency_keys
+// primary key does.
+type idempotencyKey struct {
+	userID int64
+	key    string
+}
+
+func (m *postgresMock) ClaimIdempotencyKey(ctx context.Context, userID int64, key, requestHash string) (*domain.IdempotencyKey, error) {
+	if stored, ok := m.idempotencyKeys[idempotencyKey{userID, key}]; ok {
+		return stored, nil
+	}
+	if m.idempotencyKeys == nil {
+		m.idempotencyKeys = make(map[idempotencyKey]*domain.IdempotencyKey)
+	}
+	m.idempotencyKeys[idempotencyKey{userID, key}] = &domain.IdempotencyKey{UserID: domain.ID(userID), Key: key, RequestHash: requestHash}
+	return nil, nil
+}
+
+func (m *postgresMock) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*domain.IdempotencyKey, error) {
+	return m.idempotencyKeys[idempotencyKey{userID, key}], nil
+}
+
+func (m *postgresMock) SaveIdempotencyResponse(ctx context.Context, userID int64, key string, orderID int64, response []byte) error {
+	stored := m.idempotencyKeys[idempotencyKey{userID, key}]
+	stored.OrderID = domain.ID(orderID)
+	stored.Response = response
+	return nil
+}
+
+func (m *postgresMock) CreateSaga(ctx context.Context, saga domain.Saga) error {
+	m.createdSagas = append(m.createdSagas, saga)
+	m.saga = &saga
+	return nil
+}
+
+func (m *postgresMock) GetSagaForUpdate(ctx context.Context, orderID int64) (*domain.Saga, error) {
+	return m.GetSaga(ctx, orderID)
+}
+
+func (m *postgresMock) UpdateSaga(ctx context.Context, saga domain.Saga) error {
+	m.updatedSagas = append(m.updatedSagas, saga)
+	m.saga = &saga
+	return nil
+}
+
+func (m *postgresMock) GetDueSaga(ctx context.Context, now time.Time) (domain.Saga, error) {
+	if len(m.dueSagas) == 0 {
+		return domain.Saga{}, nil
+	}
+	saga := m.dueSagas[0]
+	m.dueSagas = m.dueSagas[1:]
+	m.saga = &saga
+	return saga, nil
+}
+
+func (m *postgresMock) GetUnpaidOrder(ctx context.Context, createdBefore time.Time) (int64, time.Time, error) {
+	m.unpaidBefore = createdBefore
+	if len(m.unpaidOrders) == 0 {
+		return 0, time.Time{}, nil
+	}
+	orderID := m.unpaidOrders[0]
+	m.unpaidOrders = m.unpaidOrders[1:]
+	return orderID, m.unpaidCreatedAt, nil
+}
+
+func (m *postgresMock) GetPromotionForUpdate(ctx context.Context, code string) (*domain.Promotion, error) {
+	promotion, ok := m.promotions[code]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	p := *promotion
+	return &p, nil
+}
+
+func (m *postgresMock) CountUserRedemptions(ctx context.Context, promotionID, userID int64) (int, error) {
+	return m.userRedemptions[domain.ID(promotionID)], nil
+}
+
+func (m *postgresMock) RedeemPromotion(ctx context.Context, promotionID, orderID, userID int64) error {
+	m.redeemed = append(m.redeemed, promotionID)
+	return nil
+}
+
+func (m *postgresMock) GetSaga(ctx context.Context, orderID int64) (*domain.Saga, error) {
+	if m.sagaErr != nil {
+		return nil, m.sagaErr
+	}
+	if m.saga == nil {
+		return nil, pgx.ErrNoRows
+	}
+	saga := *m.saga
+	return &saga, nil
+}
+
+func (m *postgresMock) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	return nil, nil
+}
+
+func (m *postgresMock) MarkSent(ctx context.Context, eventIDs []int64) error {
+	return nil
+}
+
+func (m *postgresMock) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
 func (m *postgresMock) Close() error {
 	return nil
 }
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/controller/dto"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// HandleStockResult advances the inventory step of the order saga: a
+// reserved order moves on to payment, for which the saga requests the charge,
+// a rejected one ends out of stock. A reserved order with nothing to charge
+// is paid right away. Redelivered and replayed results find the
+// order already moved on and are ignored, so no processed_events mark is
+// needed.
+func (o *Order) HandleStockResult(ctx context.Context, input dto.StockResultInput) error {
+	const op = "services.Order.HandleStockResult"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("order_id", input.OrderID),
+		slog.Bool("reserved", input.Reserved))
+
+	if input.OrderID <= 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidOrderID)
+	}
+
+	status := domain.StatusOutOfStock
+	if input.Reserved {
+		status = domain.StatusAwaitingPayment
+	}
+
+	var applied, settled bool
+	err := o.runWithRetry(ctx, func(tx postgres.TxRepository) error {
+		applied, settled = false, false
+
+		if _, err := o.transition(ctx, tx, input.OrderID, status); err != nil {
+			if errors.Is(err, domain.ErrInvalidTransition) {
+				log.Warn("stock result ignored", slog.Any("err", err))
+				return nil
+			}
+			return err
+		}
+		applied = true
+
+		if !input.Reserved {
+			return nil
+		}
+		var err error
+		settled, err = o.settleFree(ctx, tx, input.OrderID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, domain.ErrOrderNotFound)
+		}
+		log.Error("apply stock result failed", slog.Any("err", err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !applied {
+		return nil
+	}
+
+	switch {
+	case settled:
+		log.Info("stock reserved, nothing to charge, order paid")
+	case input.Reserved:
+		log.Info("stock reserved, payment requested")
+	default:
+		log.Info("order out of stock",
+			slog.Int64("product_id", input.ProductID),
+			slog.String("reason", input.Reason))
+	}
+	return nil
+}
+
+// settleFree marks an order awaiting payment paid when its total is zero,
+// as after a 100% promo code: payments rejects a zero charge, so none is
+// requested. It reports whether the order was paid.
+func (o *Order) settleFree(ctx context.Context, tx postgres.TxRepository, orderID int64) (bool, error) {
+	const op = "services.Order.settleFree"
+
+	order, err := tx.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if order.TotalAmount.Amount != 0 {
+		return false, nil
+	}
+
+	if _, err := o.transition(ctx, tx, orderID, domain.StatusPaid); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return true, nil
+}
+
+// requestPayment writes PaymentRequested for the order total to the outbox.
+// A zero total is not charged; settleFree pays the order instead.
+func (o *Order) requestPayment(ctx context.Context, tx postgres.TxRepository, orderID int64) error {
+	const op = "services.Order.requestPayment"
+
+	order, err := tx.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if order.TotalAmount.Amount == 0 {
+		return nil
+	}
+
+	payload, err := paymentRequestedPayload(order)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.SaveEvent(ctx, events.TypePaymentRequested, payload, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// paymentRequestedPayload is the outbox payload asking payments to charge
+// order; the sender fills in event_id on publish.
+func paymentRequestedPayload(order *domain.Order) ([]byte, error) {
+	return json.Marshal(events.PaymentRequested{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		TotalAmount: order.TotalAmount.Amount,
+		Currency:    string(order.TotalAmount.Currency),
+		RequestedAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/storage/postgres"
+)
+
+const maxConflictRetries = 3
+
+// transition moves the order to status to, writes OrderStatusChanged to the
+// outbox and advances the order saga. The written event is returned for
+// callers that publish more.
+func (o *Order) transition(ctx context.Context, tx postgres.TxRepository, orderID int64, to domain.Status) (events.OrderStatusChanged, error) {
+	const op = "services.Order.transition"
+
+	order, err := tx.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return events.OrderStatusChanged{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	from := order.Status
+	if err := order.Transition(to); err != nil {
+		return events.OrderStatusChanged{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	version, err := tx.UpdateOrderStatus(ctx, orderID, order.Status, order.Version)
+	if err != nil {
+		return events.OrderStatusChanged{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The saga row is locked after the order row, in the same order as the
+	// saga watcher and the order expirer lock them.
+	saga, err := o.lockSaga(ctx, tx, orderID, to)
+	if err != nil {
+		return events.OrderStatusChanged{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	event := events.OrderStatusChanged{
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		From:      from,
+		To:        order.Status,
+		Version:   version,
+		ChangedAt: time.Now().UTC(),
+	}
+
+	payload, err := json.Marshal(&event)
+	if err != nil {
+		return events.OrderStatusChanged{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.SaveEvent(ctx, events.TypeOrderStatusChanged, payload, orderID); err != nil {
+		return events.OrderStatusChanged{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.advanceSaga(ctx, tx, saga, event); err != nil {
+		return events.OrderStatusChanged{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return event, nil
+}
+
+// runWithRetry repeats the whole transaction when an optimistic version
+// check fails, so the transition is re-evaluated against fresh state.
+func (o *Order) runWithRetry(ctx context.Context, fn func(tx postgres.TxRepository) error) error {
+	var err error
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		err = o.repo.RunInTx(ctx, fn)
+		if !errors.Is(err, domain.ErrVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
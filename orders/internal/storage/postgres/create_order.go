@@ -6,77 +6,102 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
 	"github.com/jackc/pgx/v5"
 )
 
-const (
-	orderCreated string = "order created"
-)
-
 func (s *Storage) CreateOrder(
 	ctx context.Context,
 	userID int64,
 	items []domain.OrderItem) (orderID int64, err error) {
+	total, err := domain.TotalOf(items)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.RunInTx(ctx, func(tx TxRepository) error {
+		orderID, err = tx.CreateOrder(ctx, userID, items, nil, total)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return orderID, nil
+}
+
+// CreateOrder inserts the order with total, the domain.TotalOf its items less
+// adjustments, which is stored as is and not recomputed on read.
+func (s *TxStorage) CreateOrder(
+	ctx context.Context,
+	userID int64,
+	items []domain.OrderItem,
+	adjustments []domain.Adjustment,
+	total domain.Money) (orderID int64, err error) {
 	const op = "storage.postgres.CreateOrder"
 
 	const insertOrder = `
-		INSERT INTO orders (user_id, status) VALUES ($1, $2)
+		INSERT INTO orders (user_id, status, total_amount, currency) VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at
 	`
 
 	var createdAt time.Time
 
 	const insertOrderItem = `
-		INSERT INTO order_items (order_id, product_id, quantity, price)
-		VALUES ($1,$2, $3, $4);
+		INSERT INTO order_items (order_id, product_id, quantity, price, currency)
+		VALUES ($1,$2, $3, $4, $5);
 	`
-	if err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		if err := tx.QueryRow(ctx, insertOrder, userID, domain.StatusNew).Scan(&orderID, &createdAt); err != nil {
-			return fmt.Errorf("%s: %w", op, err)
-		}
-
-		for i := 0; i < len(items); i++ {
-			_, err := tx.Exec(
-				ctx, insertOrderItem, orderID, items[i].ProductID,
-				items[i].Quantity, items[i].Price)
-			if err != nil {
-				return fmt.Errorf("%s: %w", op, err)
-			}
-		}
 
-		var totalAmount int64
-		const querySum = `SELECT SUM(price *quantity) FROM order_items WHERE order_id = $1`
-		if err := tx.QueryRow(ctx, querySum, orderID).Scan(&totalAmount); err != nil {
-			return fmt.Errorf("%s: %w", op, err)
-		}
-
-		evt := events.OrderCreated{
-			OrderID:     domain.ID(orderID),
-			UserID:      domain.ID(userID),
-			TotalAmount: domain.Money(totalAmount),
-			CreatedAt:   createdAt,
-		}
+	const insertAdjustment = `
+		INSERT INTO order_adjustments (order_id, promotion_id, code, product_id, amount, currency)
+		VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6)
+	`
+	if err := s.tx.QueryRow(ctx, insertOrder, userID, domain.StatusNew, total.Amount, total.Currency).Scan(&orderID, &createdAt); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
 
-		payload, err := json.Marshal(evt)
+	for i := 0; i < len(items); i++ {
+		_, err := s.tx.Exec(
+			ctx, insertOrderItem, orderID, items[i].ProductID,
+			items[i].Quantity, items[i].Price.Amount, items[i].Price.Currency)
 		if err != nil {
-			return fmt.Errorf("%s: %w", op, err)
+			return 0, fmt.Errorf("%s: %w", op, err)
 		}
+	}
 
-		if err := s.saveEvent(ctx, tx, orderCreated, payload, int64(evt.OrderID), createdAt); err != nil {
-			return fmt.Errorf("%s: %w", op, err)
+	for _, adjustment := range adjustments {
+		if _, err := s.tx.Exec(
+			ctx, insertAdjustment, orderID, adjustment.PromotionID, adjustment.Code,
+			int64(adjustment.ProductID), adjustment.Amount.Amount, adjustment.Amount.Currency); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
 		}
+	}
 
-		return nil
-	}); err != nil {
-		return 0, err
+	payload, err := json.Marshal(events.OrderCreatedOf(&domain.Order{
+		ID:          domain.ID(orderID),
+		UserID:      domain.ID(userID),
+		Items:       items,
+		TotalAmount: total,
+		CreatedAt:   createdAt,
+		Adjustments: adjustments,
+	}))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveEvent(ctx, s.tx, events.TypeOrderCreated, payload, orderID, createdAt); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return orderID, nil
 }
 
-func (s *Storage) saveEvent(
+func (s *TxStorage) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
+	return saveEvent(ctx, s.tx, eventType, payload, aggregateID, time.Now())
+}
+
+func saveEvent(
 	ctx context.Context,
 	tx pgx.Tx,
 	eventType string,
@@ -86,14 +111,25 @@ func (s *Storage) saveEvent(
 	const op = "storage.postgres.saveEvent"
 
 	const query = `
-		INSERT INTO events (event_type, payload, aggregate_id, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO events (event_type, payload, aggregate_id, created_at, correlation_id, causation_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
 	`
 
-	_, err := tx.Exec(ctx, query, eventType, payload, aggregateID, createdAt)
+	meta := envelope.MetaFrom(ctx)
+	if meta.CorrelationID == "" {
+		meta.CorrelationID = envelope.DefaultCorrelationID(aggregateID)
+	}
+
+	_, err := tx.Exec(ctx, query, eventType, payload, aggregateID, createdAt, meta.CorrelationID, meta.CausationID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	// Delivered on commit; identical notifications within a transaction are
+	// collapsed by postgres, so a multi-event transaction wakes the sender once.
+	if _, err := tx.Exec(ctx, `SELECT pg_notify($1, '')`, EventsChannel); err != nil {
+		return fmt.Errorf("%s: notify: %w", op, err)
+	}
+
 	return nil
 }
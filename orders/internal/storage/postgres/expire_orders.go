@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetUnpaidOrder locks the oldest order still awaiting payment that was
+// created before createdBefore, skipping orders locked by another instance or
+// by a transition in flight. A zero id means there is none.
+func (s *TxStorage) GetUnpaidOrder(ctx context.Context, createdBefore time.Time) (orderID int64, createdAt time.Time, err error) {
+	const op = "storage.postgres.GetUnpaidOrder"
+
+	const query = `
+		SELECT id, created_at
+		FROM orders
+		WHERE status = 'awaiting_payment' AND created_at <= $1
+		ORDER BY created_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	err = s.tx.QueryRow(ctx, query, createdBefore).Scan(&orderID, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return orderID, createdAt, nil
+}
@@ -10,20 +10,32 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
 func (s *Storage) GetOrderByID(ctx context.Context, id int64) (*domain.Order, error) {
+	return getOrderByID(ctx, s.db, id)
+}
+
+func (s *TxStorage) GetOrderByID(ctx context.Context, id int64) (*domain.Order, error) {
+	return getOrderByID(ctx, s.tx, id)
+}
+
+func getOrderByID(ctx context.Context, q querier, id int64) (*domain.Order, error) {
 	const op = "storage.postgres.GetOrderByID"
 
 	const query = `
 	SELECT 
-	    o.id, o.user_id, o.status, o.created_at, o.updated_at,
-	    i.product_id, i.quantity, i.price
+	    o.id, o.user_id, o.status, o.version, o.total_amount, o.currency, o.created_at, o.updated_at,
+	    i.product_id, i.quantity, i.price, i.currency
 	FROM orders AS o
 	LEFT JOIN order_items AS i ON o.id = i.order_id
 	WHERE o.id = $1
 	ORDER BY i.id;
 	`
 
-	rows, err := s.db.Query(ctx, query, id)
+	rows, err := q.Query(ctx, query, id)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -35,11 +47,14 @@ func (s *Storage) GetOrderByID(ctx context.Context, id int64) (*domain.Order, er
 		orderID   int64
 		userID    int64
 		status    string
+		version   int64
+		total     domain.Money
 		createdAt time.Time
 		updatedAt time.Time
 		productID pgtype.Int8
 		quantity  pgtype.Int4
 		price     pgtype.Int8
+		currency  pgtype.Text
 		find      bool
 	)
 
@@ -48,13 +63,15 @@ func (s *Storage) GetOrderByID(ctx context.Context, id int64) (*domain.Order, er
 	for rows.Next() {
 		find = true
 		if err := rows.Scan(
-			&orderID, &userID, &status, &createdAt,
-			&updatedAt, &productID, &quantity, &price); err != nil {
+			&orderID, &userID, &status, &version, &total.Amount, &total.Currency, &createdAt,
+			&updatedAt, &productID, &quantity, &price, &currency); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
 		if productID.Valid {
-			item, err := domain.NewOrderItem(productID.Int64, quantity.Int32, price.Int64)
+			item, err := domain.NewOrderItem(
+				productID.Int64, quantity.Int32,
+				domain.Money{Amount: price.Int64, Currency: domain.Currency(currency.String)})
 			if err != nil {
 				return nil, fmt.Errorf("%s: %w", op, err)
 			}
@@ -70,9 +87,19 @@ func (s *Storage) GetOrderByID(ctx context.Context, id int64) (*domain.Order, er
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	order, err := domain.NewOrder(
+	order, err := domain.RestoreOrder(
 		orderID, userID, status,
-		items, createdAt, updatedAt)
+		items, total, createdAt, updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	order.Version = version
+
+	adjustments, err := listOrderAdjustments(ctx, q, []int64{orderID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	order.Adjustments = adjustments[orderID]
 
-	return order, err
+	return order, nil
 }
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetIdempotencyKey reads the record userID stored for key without claiming
+// it. It returns nil when the key is unknown; a record still being claimed
+// has a nil Response.
+func (s *Storage) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*domain.IdempotencyKey, error) {
+	const op = "storage.postgres.GetIdempotencyKey"
+
+	const query = `
+		SELECT request_hash, COALESCE(order_id, 0), response
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2
+	`
+
+	stored := &domain.IdempotencyKey{UserID: domain.ID(userID), Key: key}
+	var orderID int64
+	err := s.db.QueryRow(ctx, query, userID, key).Scan(&stored.RequestHash, &orderID, &stored.Response)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	stored.OrderID = domain.ID(orderID)
+
+	return stored, nil
+}
+
+// ClaimIdempotencyKey reserves key of userID for the current transaction.
+// Keys are scoped per user, so two users never see each other's responses.
+// It returns nil when the key is new; otherwise the stored record. A
+// concurrent request with the same key blocks on the insert until the first
+// one commits or rolls back, so the record it reads is always complete.
+func (s *TxStorage) ClaimIdempotencyKey(ctx context.Context, userID int64, key, requestHash string) (*domain.IdempotencyKey, error) {
+	const op = "storage.postgres.ClaimIdempotencyKey"
+
+	const insert = `
+		INSERT INTO idempotency_keys (user_id, key, request_hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, key) DO NOTHING
+	`
+
+	res, err := s.tx.Exec(ctx, insert, userID, key, requestHash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == rowsInserted {
+		return nil, nil
+	}
+
+	const query = `
+		SELECT request_hash, COALESCE(order_id, 0), COALESCE(response, 'null'::jsonb)
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2
+	`
+
+	stored := &domain.IdempotencyKey{UserID: domain.ID(userID), Key: key}
+	var orderID int64
+	if err := s.tx.QueryRow(ctx, query, userID, key).Scan(&stored.RequestHash, &orderID, &stored.Response); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	stored.OrderID = domain.ID(orderID)
+
+	return stored, nil
+}
+
+func (s *TxStorage) SaveIdempotencyResponse(ctx context.Context, userID int64, key string, orderID int64, response []byte) error {
+	const op = "storage.postgres.SaveIdempotencyResponse"
+
+	const query = `
+		UPDATE idempotency_keys
+		SET order_id = $3, response = $4
+		WHERE user_id = $1 AND key = $2
+	`
+
+	if _, err := s.tx.Exec(ctx, query, userID, key, orderID, response); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
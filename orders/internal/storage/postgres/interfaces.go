@@ -2,16 +2,39 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
 )
 
+type TxRepository interface {
+	CreateOrder(ctx context.Context, userID int64, items []domain.OrderItem, adjustments []domain.Adjustment, total domain.Money) (orderID int64, err error)
+	GetOrderByID(ctx context.Context, id int64) (*domain.Order, error)
+	UpdateOrderStatus(ctx context.Context, orderID int64, status domain.Status, expectedVersion int64) (version int64, err error)
+	TryMarkProcessed(ctx context.Context, eventID int64) (bool, error)
+	SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error
+	ClaimIdempotencyKey(ctx context.Context, userID int64, key, requestHash string) (*domain.IdempotencyKey, error)
+	SaveIdempotencyResponse(ctx context.Context, userID int64, key string, orderID int64, response []byte) error
+	CreateSaga(ctx context.Context, saga domain.Saga) error
+	GetSagaForUpdate(ctx context.Context, orderID int64) (*domain.Saga, error)
+	UpdateSaga(ctx context.Context, saga domain.Saga) error
+	GetDueSaga(ctx context.Context, now time.Time) (domain.Saga, error)
+	GetUnpaidOrder(ctx context.Context, createdBefore time.Time) (orderID int64, createdAt time.Time, err error)
+	GetPromotionForUpdate(ctx context.Context, code string) (*domain.Promotion, error)
+	CountUserRedemptions(ctx context.Context, promotionID, userID int64) (int, error)
+	RedeemPromotion(ctx context.Context, promotionID, orderID, userID int64) error
+}
+
 type Repository interface {
-	CreateOrder(ctx context.Context, userID int64, items []domain.OrderItem) (orderID int64, err error)
+	RunInTx(ctx context.Context, fn func(tx TxRepository) error) error
 	GetOrderByID(ctx context.Context, id int64) (*domain.Order, error)
-	GetNewEvent(ctx context.Context) (events.OrderCreated, int64, error)
-	MarkSent(ctx context.Context, eventID int64) error
+	ListOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, error)
+	GetSaga(ctx context.Context, orderID int64) (*domain.Saga, error)
+	GetIdempotencyKey(ctx context.Context, userID int64, key string) (*domain.IdempotencyKey, error)
+	GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error)
+	MarkSent(ctx context.Context, eventIDs []int64) error
+	MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (dead bool, err error)
 	Ping(ctx context.Context) error
 	Close() error
 }
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+// ListOrders returns up to filter.Limit orders matching filter, newest first.
+// Paging is keyset based on (created_at, id), served by idx_orders_user_created.
+func (s *Storage) ListOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, error) {
+	const op = "storage.postgres.ListOrders"
+
+	const query = `
+	SELECT id, user_id, status, version, total_amount, currency, created_at, updated_at
+	FROM orders
+	WHERE user_id = $1
+	  AND ($2::text[] IS NULL OR status = ANY($2))
+	  AND ($3::timestamp IS NULL OR created_at >= $3)
+	  AND ($4::timestamp IS NULL OR created_at < $4)
+	  AND ($5::timestamp IS NULL OR (created_at, id) < ($5, $6))
+	ORDER BY created_at DESC, id DESC
+	LIMIT $7
+	`
+
+	var statuses []string
+	for _, status := range filter.Statuses {
+		statuses = append(statuses, string(status))
+	}
+
+	var afterAt *time.Time
+	var afterID int64
+	if filter.After != nil {
+		afterAt = &filter.After.CreatedAt
+		afterID = int64(filter.After.ID)
+	}
+
+	rows, err := s.db.Query(ctx, query,
+		int64(filter.UserID), statuses, optionalTime(filter.CreatedFrom), optionalTime(filter.CreatedTo),
+		afterAt, afterID, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	type header struct {
+		id, userID, version  int64
+		status               string
+		total                domain.Money
+		createdAt, updatedAt time.Time
+	}
+
+	var headers []header
+	ids := make([]int64, 0, filter.Limit)
+	for rows.Next() {
+		var h header
+		if err := rows.Scan(&h.id, &h.userID, &h.status, &h.version, &h.total.Amount, &h.total.Currency, &h.createdAt, &h.updatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		headers = append(headers, h)
+		ids = append(ids, h.id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	items, err := s.listOrderItems(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	adjustments, err := listOrderAdjustments(ctx, s.db, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orders := make([]*domain.Order, 0, len(headers))
+	for _, h := range headers {
+		order, err := domain.RestoreOrder(h.id, h.userID, h.status, items[h.id], h.total, h.createdAt, h.updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		order.Version = h.version
+		order.Adjustments = adjustments[h.id]
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func (s *Storage) listOrderItems(ctx context.Context, orderIDs []int64) (map[int64][]domain.OrderItem, error) {
+	const query = `
+	SELECT order_id, product_id, quantity, price, currency
+	FROM order_items
+	WHERE order_id = ANY($1)
+	ORDER BY id
+	`
+
+	rows, err := s.db.Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[int64][]domain.OrderItem, len(orderIDs))
+	for rows.Next() {
+		var (
+			orderID, productID, price int64
+			quantity                  int32
+			currency                  string
+		)
+		if err := rows.Scan(&orderID, &productID, &quantity, &price, &currency); err != nil {
+			return nil, err
+		}
+		item, err := domain.NewOrderItem(productID, quantity, domain.Money{Amount: price, Currency: domain.Currency(currency)})
+		if err != nil {
+			return nil, err
+		}
+		items[orderID] = append(items[orderID], item)
+	}
+
+	return items, rows.Err()
+}
+
+func listOrderAdjustments(ctx context.Context, q querier, orderIDs []int64) (map[int64][]domain.Adjustment, error) {
+	const query = `
+	SELECT order_id, promotion_id, code, COALESCE(product_id, 0), amount, currency
+	FROM order_adjustments
+	WHERE order_id = ANY($1)
+	ORDER BY id
+	`
+
+	rows, err := q.Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	adjustments := make(map[int64][]domain.Adjustment, len(orderIDs))
+	for rows.Next() {
+		var (
+			orderID    int64
+			adjustment domain.Adjustment
+		)
+		if err := rows.Scan(
+			&orderID, &adjustment.PromotionID, &adjustment.Code, &adjustment.ProductID,
+			&adjustment.Amount.Amount, &adjustment.Amount.Currency); err != nil {
+			return nil, err
+		}
+		adjustments[orderID] = append(adjustments[orderID], adjustment)
+	}
+
+	return adjustments, rows.Err()
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -6,14 +6,42 @@ import (
 	"time"
 )
 
-func (s *Storage) MarkSent(ctx context.Context, eventID int64) error {
+func (s *Storage) MarkSent(ctx context.Context, eventIDs []int64) error {
 	const op = "storage.postgres.MarkSent"
-	const query = `UPDATE events SET sent_at = $1, locked_at = NULL WHERE id = $2`
+	const query = `UPDATE events SET sent_at = $1, locked_at = NULL WHERE id = ANY($2)`
+
+	if len(eventIDs) == 0 {
+		return nil
+	}
 
 	currentTime := time.Now()
-	if _, err := s.db.Exec(ctx, query, currentTime, eventID); err != nil {
+	if _, err := s.db.Exec(ctx, query, currentTime, eventIDs); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
+
+// MarkFailed records a failed attempt to publish an event that cannot be
+// encoded. The lock is kept, so the event is retried once the lock expires.
+// Once the event has failed maxAttempts times it is dead-lettered: failed_at
+// is set and it stays in the outbox for an operator instead of holding back
+// its aggregate. It reports whether the event was dead-lettered.
+func (s *Storage) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	const op = "storage.postgres.MarkFailed"
+	const query = `
+		UPDATE events
+		SET attempts = attempts + 1,
+			last_error = $2,
+			failed_at = CASE WHEN attempts + 1 >= $3 THEN now() END
+		WHERE id = $1
+		RETURNING failed_at IS NOT NULL
+	`
+
+	var dead bool
+	if err := s.db.QueryRow(ctx, query, eventID, reason, maxAttempts).Scan(&dead); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dead, nil
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+// GetPromotionForUpdate locks the promotion with code until the transaction
+// ends, so concurrent orders redeeming it are checked against its limits one
+// at a time; pgx.ErrNoRows means there is no such code.
+func (s *TxStorage) GetPromotionForUpdate(ctx context.Context, code string) (*domain.Promotion, error) {
+	const op = "storage.postgres.GetPromotionForUpdate"
+
+	const query = `
+		SELECT id, code, kind, scope, value, COALESCE(product_id, 0), currency, min_order_amount,
+		       starts_at, ends_at, max_redemptions, max_per_user, redemptions, active
+		FROM promotions
+		WHERE code = $1
+		FOR UPDATE
+	`
+
+	var (
+		p                domain.Promotion
+		startsAt, endsAt *time.Time
+	)
+	if err := s.tx.QueryRow(ctx, query, code).Scan(
+		&p.ID, &p.Code, &p.Kind, &p.Scope, &p.Value, &p.ProductID, &p.Currency, &p.MinOrder,
+		&startsAt, &endsAt, &p.MaxRedemptions, &p.MaxPerUser, &p.Redemptions, &p.Active); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if startsAt != nil {
+		p.StartsAt = *startsAt
+	}
+	if endsAt != nil {
+		p.EndsAt = *endsAt
+	}
+	return &p, nil
+}
+
+// CountUserRedemptions counts the orders of userID that redeemed the
+// promotion.
+func (s *TxStorage) CountUserRedemptions(ctx context.Context, promotionID, userID int64) (int, error) {
+	const op = "storage.postgres.CountUserRedemptions"
+
+	const query = `SELECT count(*) FROM promotion_redemptions WHERE promotion_id = $1 AND user_id = $2`
+
+	var count int
+	if err := s.tx.QueryRow(ctx, query, promotionID, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return count, nil
+}
+
+// RedeemPromotion records that the order of userID redeemed the promotion.
+func (s *TxStorage) RedeemPromotion(ctx context.Context, promotionID, orderID, userID int64) error {
+	const op = "storage.postgres.RedeemPromotion"
+
+	const insertRedemption = `
+		INSERT INTO promotion_redemptions (promotion_id, order_id, user_id) VALUES ($1, $2, $3)
+	`
+	const countRedemption = `UPDATE promotions SET redemptions = redemptions + 1 WHERE id = $1`
+
+	if _, err := s.tx.Exec(ctx, insertRedemption, promotionID, orderID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := s.tx.Exec(ctx, countRedemption, promotionID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
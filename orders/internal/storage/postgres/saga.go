@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const sagaColumns = `order_id, step, attempts, deadline_at, last_error, created_at, updated_at`
+
+func (s *TxStorage) CreateSaga(ctx context.Context, saga domain.Saga) error {
+	const op = "storage.postgres.CreateSaga"
+
+	const query = `
+		INSERT INTO order_sagas (order_id, step, attempts, deadline_at, last_error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if _, err := s.tx.Exec(ctx, query,
+		saga.OrderID, saga.Step, saga.Attempts, nullTime(saga.DeadlineAt),
+		saga.LastError, saga.CreatedAt, saga.UpdatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetSagaForUpdate locks the saga of an order; pgx.ErrNoRows means the order
+// has none.
+func (s *TxStorage) GetSagaForUpdate(ctx context.Context, orderID int64) (*domain.Saga, error) {
+	const op = "storage.postgres.GetSagaForUpdate"
+
+	query := `SELECT ` + sagaColumns + ` FROM order_sagas WHERE order_id = $1 FOR UPDATE`
+
+	saga, err := scanSaga(s.tx.QueryRow(ctx, query, orderID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return saga, nil
+}
+
+func (s *TxStorage) UpdateSaga(ctx context.Context, saga domain.Saga) error {
+	const op = "storage.postgres.UpdateSaga"
+
+	const query = `
+		UPDATE order_sagas
+		SET step = $1,
+		    attempts = $2,
+		    deadline_at = $3,
+		    last_error = $4,
+		    updated_at = $5
+		WHERE order_id = $6
+	`
+
+	if _, err := s.tx.Exec(ctx, query,
+		saga.Step, saga.Attempts, nullTime(saga.DeadlineAt),
+		saga.LastError, saga.UpdatedAt, saga.OrderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetDueSaga locks one saga whose step deadline has passed together with its
+// order, skipping sagas locked by another instance. Orders are locked ahead of
+// their sagas everywhere else, so both rows are taken without waiting. A zero
+// Saga means nothing is due.
+func (s *TxStorage) GetDueSaga(ctx context.Context, now time.Time) (domain.Saga, error) {
+	const op = "storage.postgres.GetDueSaga"
+
+	query := `
+		SELECT s.order_id, s.step, s.attempts, s.deadline_at, s.last_error, s.created_at, s.updated_at
+		FROM order_sagas s
+		JOIN orders o ON o.id = s.order_id
+		WHERE s.deadline_at IS NOT NULL AND s.deadline_at <= $1
+		ORDER BY s.deadline_at
+		LIMIT 1
+		FOR UPDATE OF s, o SKIP LOCKED
+	`
+
+	saga, err := scanSaga(s.tx.QueryRow(ctx, query, now))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Saga{}, nil
+	}
+	if err != nil {
+		return domain.Saga{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return *saga, nil
+}
+
+// GetSaga reads the saga of an order; pgx.ErrNoRows means the order has none.
+func (s *Storage) GetSaga(ctx context.Context, orderID int64) (*domain.Saga, error) {
+	const op = "storage.postgres.GetSaga"
+
+	query := `SELECT ` + sagaColumns + ` FROM order_sagas WHERE order_id = $1`
+
+	saga, err := scanSaga(s.db.QueryRow(ctx, query, orderID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return saga, nil
+}
+
+func scanSaga(row pgx.Row) (*domain.Saga, error) {
+	var (
+		saga       domain.Saga
+		deadlineAt *time.Time
+	)
+	if err := row.Scan(
+		&saga.OrderID, &saga.Step, &saga.Attempts, &deadlineAt,
+		&saga.LastError, &saga.CreatedAt, &saga.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if deadlineAt != nil {
+		saga.DeadlineAt = *deadlineAt
+	}
+	return &saga, nil
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -5,12 +5,16 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain/events"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
@@ -27,7 +31,7 @@ func getDSN(t *testing.T) string {
 
 func cleanupTables(t *testing.T, db *pgxpool.Pool) {
 	const query = `
-	TRUNCATE TABLE events, order_items, orders 
+	TRUNCATE TABLE events, events_archive, order_items, orders, processed_events, idempotency_keys, promotions
     RESTART IDENTITY CASCADE
     `
 
@@ -60,9 +64,9 @@ func TestCreate_Integration(t *testing.T) {
 	// arrange
 	var userID int64 = 1
 	items := []domain.OrderItem{
-		domain.OrderItem{ProductID: 1, Price: 100, Quantity: 1},
-		domain.OrderItem{ProductID: 2, Price: 200, Quantity: 1},
-		domain.OrderItem{ProductID: 3, Price: 300, Quantity: 1},
+		domain.OrderItem{ProductID: 1, Price: rub(100), Quantity: 1},
+		domain.OrderItem{ProductID: 2, Price: rub(200), Quantity: 1},
+		domain.OrderItem{ProductID: 3, Price: rub(300), Quantity: 1},
 	}
 
 	totalAmount := 600
@@ -91,9 +95,21 @@ func TestCreate_Integration(t *testing.T) {
 		t.Fatalf("len order's items not equal test's items")
 	}
 
-	if int(order.TotalAmount) != totalAmount {
+	if order.TotalAmount != rub(int64(totalAmount)) {
 		t.Fatalf("total amout not equal db total amount")
 	}
+
+	// The stored total is returned as is, not summed from the items again.
+	if _, err := db.Exec(ctx, `UPDATE orders SET total_amount = 1 WHERE id = $1`, orderID); err != nil {
+		t.Fatal(err)
+	}
+	order, err = storage.GetOrderByID(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.TotalAmount != rub(1) {
+		t.Fatalf("total amount: got %s, want stored %s", order.TotalAmount, rub(1))
+	}
 }
 
 func TestOutbox_Integration(t *testing.T) {
@@ -118,7 +134,7 @@ func TestOutbox_Integration(t *testing.T) {
 	ctx := context.Background()
 
 	items := []domain.OrderItem{
-		{ProductID: 1, Price: 100, Quantity: 1},
+		{ProductID: 1, Price: domain.Money{Amount: 100, Currency: "USD"}, Quantity: 1},
 	}
 
 	orderID, err := storage.CreateOrder(ctx, 1, items)
@@ -126,31 +142,46 @@ func TestOutbox_Integration(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	event, eventID, err := storage.GetNewEvent(ctx)
+	batch, err := storage.GetNewEvents(ctx, 10)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if eventID <= 0 {
+	if len(batch) != 1 {
+		t.Fatalf("events: got %d, want %d", len(batch), 1)
+	}
+	event := batch[0]
+	if event.EventID <= 0 {
 		t.Fatalf("eventID must be positive")
 	}
-	if int64(event.OrderID) != orderID {
+	if event.EventType != events.TypeOrderCreated {
+		t.Fatalf("event type: got %s, want %s", event.EventType, events.TypeOrderCreated)
+	}
+	if event.AggregateID != orderID {
+		t.Fatalf("event aggregate id mismatch")
+	}
+
+	var payload events.OrderCreated
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if int64(payload.OrderID) != orderID {
 		t.Fatalf("event order id mismatch")
 	}
+	if payload.TotalAmount != 100 || payload.Currency != "USD" {
+		t.Fatalf("event total: got %d %s, want 100 USD", payload.TotalAmount, payload.Currency)
+	}
 
-	if err := storage.MarkSent(ctx, eventID); err != nil {
+	if err := storage.MarkSent(ctx, []int64{event.EventID}); err != nil {
 		t.Fatal(err)
 	}
 
-	event, eventID, err = storage.GetNewEvent(ctx)
+	batch, err = storage.GetNewEvents(ctx, 10)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if eventID != 0 {
+	if len(batch) != 0 {
 		t.Fatalf("expected no new events")
 	}
-	if event.OrderID != 0 {
-		t.Fatalf("expected empty event")
-	}
 }
 
 func TestMultipleOrders_Integration(t *testing.T) {
@@ -175,11 +206,11 @@ func TestMultipleOrders_Integration(t *testing.T) {
 	ctx := context.Background()
 
 	items1 := []domain.OrderItem{
-		{ProductID: 10, Price: 120, Quantity: 2},
+		{ProductID: 10, Price: rub(120), Quantity: 2},
 	}
 	items2 := []domain.OrderItem{
-		{ProductID: 11, Price: 200, Quantity: 1},
-		{ProductID: 12, Price: 50, Quantity: 3},
+		{ProductID: 11, Price: rub(200), Quantity: 1},
+		{ProductID: 12, Price: rub(50), Quantity: 3},
 	}
 
 	orderID1, err := storage.CreateOrder(ctx, 5, items1)
@@ -201,8 +232,8 @@ func TestMultipleOrders_Integration(t *testing.T) {
 	if len(order1.Items) != len(items1) {
 		t.Fatalf("order1 items: got %d, want %d", len(order1.Items), len(items1))
 	}
-	if order1.TotalAmount != 240 {
-		t.Fatalf("order1 total: got %d, want %d", order1.TotalAmount, 240)
+	if order1.TotalAmount != rub(240) {
+		t.Fatalf("order1 total: got %s, want %d", order1.TotalAmount, 240)
 	}
 
 	order2, err := storage.GetOrderByID(ctx, orderID2)
@@ -215,8 +246,8 @@ func TestMultipleOrders_Integration(t *testing.T) {
 	if len(order2.Items) != len(items2) {
 		t.Fatalf("order2 items: got %d, want %d", len(order2.Items), len(items2))
 	}
-	if order2.TotalAmount != 350 {
-		t.Fatalf("order2 total: got %d, want %d", order2.TotalAmount, 350)
+	if order2.TotalAmount != rub(350) {
+		t.Fatalf("order2 total: got %s, want %d", order2.TotalAmount, 350)
 	}
 }
 
@@ -241,31 +272,624 @@ func TestOutbox_Locking_Integration(t *testing.T) {
 
 	ctx := context.Background()
 
-	if _, err := storage.CreateOrder(ctx, 1, []domain.OrderItem{{ProductID: 1, Price: 10, Quantity: 1}}); err != nil {
+	if _, err := storage.CreateOrder(ctx, 1, []domain.OrderItem{{ProductID: 1, Price: rub(10), Quantity: 1}}); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := storage.CreateOrder(ctx, 2, []domain.OrderItem{{ProductID: 2, Price: 20, Quantity: 1}}); err != nil {
+	if _, err := storage.CreateOrder(ctx, 2, []domain.OrderItem{{ProductID: 2, Price: rub(20), Quantity: 1}}); err != nil {
 		t.Fatal(err)
 	}
 
-	_, eventID1, err := storage.GetNewEvent(ctx)
+	batch1, err := storage.GetNewEvents(ctx, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if eventID1 == 0 {
-		t.Fatalf("expected first event id")
+	if len(batch1) != 1 {
+		t.Fatalf("expected first event")
 	}
 
-	_, eventID2, err := storage.GetNewEvent(ctx)
+	batch2, err := storage.GetNewEvents(ctx, 10)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if eventID2 == 0 {
-		t.Fatalf("expected second event id")
+	if len(batch2) != 1 {
+		t.Fatalf("expected only the unlocked event, got %d", len(batch2))
 	}
-	if eventID1 == eventID2 {
+	if batch1[0].EventID == batch2[0].EventID {
 		t.Fatalf("expected different event ids")
 	}
+
+	if err := storage.MarkSent(ctx, []int64{batch1[0].EventID, batch2[0].EventID}); err != nil {
+		t.Fatal(err)
+	}
+
+	batch3, err := storage.GetNewEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch3) != 0 {
+		t.Fatalf("expected no events after mark sent, got %d", len(batch3))
+	}
+}
+
+func TestOutbox_PerAggregateOrder_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// ids 1 and 2 belong to aggregate 10, id 3 to aggregate 20
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		for _, aggregateID := range []int64{10, 10, 20} {
+			if err := tx.SaveEvent(ctx, events.TypeOrderStatusChanged, []byte(`{}`), aggregateID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := storage.GetNewEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 2 || batch[0].EventID != 1 || batch[1].EventID != 3 {
+		t.Fatalf("first batch: got %+v, want events 1 and 3", batch)
+	}
+
+	// event 2 must wait while event 1 is in flight, even after its lock expires
+	if _, err := db.Exec(ctx, `UPDATE events SET locked_at = now() - interval '2 minutes' WHERE id = 3`); err != nil {
+		t.Fatal(err)
+	}
+	batch, err = storage.GetNewEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 1 || batch[0].EventID != 3 {
+		t.Fatalf("second batch: got %+v, want only event 3", batch)
+	}
+
+	if err := storage.MarkSent(ctx, []int64{1, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err = storage.GetNewEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 1 || batch[0].EventID != 2 {
+		t.Fatalf("third batch: got %+v, want event 2", batch)
+	}
+}
+
+func TestOutbox_DeadLetter_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// ids 1 and 2 belong to aggregate 10; event 1 cannot be encoded
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		for range 2 {
+			if err := tx.SaveEvent(ctx, events.TypeOrderStatusChanged, []byte(`{}`), 10); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if _, err := db.Exec(ctx, `UPDATE events SET locked_at = now() - interval '2 minutes' WHERE id = 1`); err != nil {
+			t.Fatal(err)
+		}
+		batch, err := storage.GetNewEvents(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(batch) != 1 || batch[0].EventID != 1 {
+			t.Fatalf("attempt %d: got %+v, want only event 1", attempt, batch)
+		}
+
+		dead, err := storage.MarkFailed(ctx, 1, "bad payload", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dead != (attempt == 2) {
+			t.Fatalf("attempt %d: dead-lettered %v", attempt, dead)
+		}
+
+		// the failed event keeps its lock, so nothing is handed out until it expires
+		if attempt == 1 {
+			batch, err = storage.GetNewEvents(ctx, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(batch) != 0 {
+				t.Fatalf("after a failed attempt: got %+v, want none", batch)
+			}
+		}
+	}
+
+	var (
+		attempts  int
+		lastError string
+	)
+	if err := db.QueryRow(ctx, `SELECT attempts, last_error FROM events WHERE id = 1 AND failed_at IS NOT NULL`).Scan(&attempts, &lastError); err != nil {
+		t.Fatalf("dead-lettered event: %v", err)
+	}
+	if attempts != 2 || lastError != "bad payload" {
+		t.Fatalf("dead-lettered event: got attempts=%d last_error=%q", attempts, lastError)
+	}
+
+	batch, err := storage.GetNewEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 1 || batch[0].EventID != 2 {
+		t.Fatalf("after dead-lettering: got %+v, want event 2", batch)
+	}
+}
+
+func TestListenEvents_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wake := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() { done <- storage.ListenEvents(ctx, wake) }()
+
+	select {
+	case <-wake:
+	case err := <-done:
+		t.Fatalf("listen: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("listener did not subscribe")
+	}
+
+	if _, err := storage.CreateOrder(ctx, 1, []domain.OrderItem{{ProductID: 1, Price: rub(10), Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-wake:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected notification after commit")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("listen after cancel: %v", err)
+	}
+}
+
+func TestPruneSentEvents_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	for userID := int64(1); userID <= 3; userID++ {
+		if _, err := storage.CreateOrder(ctx, userID, []domain.OrderItem{{ProductID: 1, Price: rub(10), Quantity: 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// two old sent events, one recent sent event, the rest unsent
+	if _, err := db.Exec(ctx, `UPDATE events SET sent_at = now() - interval '2 days' WHERE id IN (1, 2)`); err != nil {
+		t.Fatalf("age events: %v", err)
+	}
+	if _, err := db.Exec(ctx, `UPDATE events SET sent_at = now() WHERE id = 3`); err != nil {
+		t.Fatalf("send event: %v", err)
+	}
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+
+	removed, err := storage.PruneSentEvents(ctx, cutoff, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Fatalf("first batch: got %d, want %d", removed, 1)
+	}
+
+	removed, err = storage.PruneSentEvents(ctx, cutoff, 10, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Fatalf("second batch: got %d, want %d", removed, 1)
+	}
+
+	var archived, left int
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM events_archive`).Scan(&archived); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM events WHERE id IN (1, 2, 3)`).Scan(&left); err != nil {
+		t.Fatal(err)
+	}
+	if archived != 2 || left != 1 {
+		t.Fatalf("archived=%d left=%d, want 2 and 1", archived, left)
+	}
+
+	if _, err := db.Exec(ctx, `INSERT INTO processed_events (event_id, processed_at) VALUES (1, now() - interval '40 days'), (2, now())`); err != nil {
+		t.Fatalf("insert processed: %v", err)
+	}
+
+	removed, err = storage.PruneProcessedEvents(ctx, time.Now().Add(-30*24*time.Hour), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Fatalf("processed_events pruned: got %d, want %d", removed, 1)
+	}
+}
+
+func TestUpdateOrderStatus_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	orderID, err := storage.CreateOrder(ctx, 1, []domain.OrderItem{{ProductID: 1, Price: rub(10), Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		ok, err := tx.TryMarkProcessed(ctx, 100)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			t.Fatalf("expected event to be marked")
+		}
+
+		version, err := tx.UpdateOrderStatus(ctx, orderID, domain.StatusAwaitingPayment, 1)
+		if err != nil {
+			return err
+		}
+		if version != 2 {
+			t.Fatalf("version: got %d, want %d", version, 2)
+		}
+
+		return tx.SaveEvent(ctx, events.TypeOrderStatusChanged, []byte(`{}`), orderID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		ok, err := tx.TryMarkProcessed(ctx, 100)
+		if err != nil {
+			return err
+		}
+		if ok {
+			t.Fatalf("expected duplicate event to be skipped")
+		}
+
+		_, err = tx.UpdateOrderStatus(ctx, orderID, domain.StatusPaid, 1)
+		return err
+	})
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+
+	order, err := storage.GetOrderByID(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != domain.StatusAwaitingPayment {
+		t.Fatalf("order status: got %s, want %s", order.Status, domain.StatusAwaitingPayment)
+	}
+	if order.Version != 2 {
+		t.Fatalf("order version: got %d, want %d", order.Version, 2)
+	}
+	if !order.UpdatedAt.After(order.CreatedAt) {
+		t.Fatalf("expected updated_at to be bumped")
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		_, err := tx.GetOrderByID(ctx, orderID+1)
+		return err
+	})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected no rows error, got %v", err)
+	}
+}
+
+func TestIdempotencyKey_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+	items := []domain.OrderItem{{ProductID: 1, Price: rub(100), Quantity: 1}}
+
+	var orderID int64
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		stored, err := tx.ClaimIdempotencyKey(ctx, 1, "key-1", "hash-1")
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			t.Fatalf("new key: got %+v, want nil", stored)
+		}
+		if orderID, err = tx.CreateOrder(ctx, 1, items, nil, rub(100)); err != nil {
+			return err
+		}
+		return tx.SaveIdempotencyResponse(ctx, 1, "key-1", orderID, []byte(`{"id":1}`))
+	})
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		stored, err := tx.ClaimIdempotencyKey(ctx, 1, "key-1", "hash-2")
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			t.Fatalf("claimed key returned nil record")
+		}
+		if stored.RequestHash != "hash-1" || int64(stored.OrderID) != orderID {
+			t.Fatalf("stored: got %+v, want hash-1 and order %d", stored, orderID)
+		}
+		var response struct {
+			ID int64 `json:"id"`
+		}
+		if err := json.Unmarshal(stored.Response, &response); err != nil || response.ID != 1 {
+			t.Fatalf("response: got %s (%v)", stored.Response, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+
+	// keys are scoped per user
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		stored, err := tx.ClaimIdempotencyKey(ctx, 2, "key-1", "hash-3")
+		if stored != nil {
+			t.Fatalf("key of another user: got %+v, want nil", stored)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("other user: %v", err)
+	}
+
+	stored, err := storage.GetIdempotencyKey(ctx, 1, "key-1")
+	if err != nil || stored == nil || int64(stored.OrderID) != orderID {
+		t.Fatalf("get key: got %+v (%v), want order %d", stored, err, orderID)
+	}
+	if pending, err := storage.GetIdempotencyKey(ctx, 2, "key-1"); err != nil || pending == nil || pending.Response != nil {
+		t.Fatalf("claimed key without response: got %+v (%v)", pending, err)
+	}
+
+	removed, err := storage.PruneIdempotencyKeys(ctx, time.Now().Add(time.Minute), 10)
+	if err != nil || removed != 2 {
+		t.Fatalf("prune keys: removed %d (%v), want 2", removed, err)
+	}
+	if stored, err := storage.GetIdempotencyKey(ctx, 1, "key-1"); err != nil || stored != nil {
+		t.Fatalf("pruned key: got %+v (%v), want nil", stored, err)
+	}
+
+	// a rolled back transaction releases its claim
+	errAbort := errors.New("abort")
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		if _, err := tx.ClaimIdempotencyKey(ctx, 1, "key-2", "hash-1"); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("aborted run: got %v, want %v", err, errAbort)
+	}
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		stored, err := tx.ClaimIdempotencyKey(ctx, 1, "key-2", "hash-1")
+		if stored != nil {
+			t.Fatalf("released key: got %+v, want nil", stored)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("reclaim: %v", err)
+	}
+}
+
+func TestListOrders_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+	items := []domain.OrderItem{{ProductID: 1, Price: rub(100), Quantity: 2}}
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		id, err := storage.CreateOrder(ctx, 1, items)
+		if err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if _, err := storage.CreateOrder(ctx, 2, items); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	// same created_at for every order makes id the tie breaker
+	if _, err := db.Exec(ctx, `UPDATE orders SET created_at = '2025-03-01 12:00:00'`); err != nil {
+		t.Fatalf("align created_at: %v", err)
+	}
+	if _, err := db.Exec(ctx, `UPDATE orders SET status = 'paid' WHERE id = $1`, ids[0]); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+
+	var got []int64
+	filter := domain.OrderFilter{UserID: 1, Limit: 2}
+	for {
+		page, err := storage.ListOrders(ctx, filter)
+		if err != nil {
+			t.Fatalf("list orders: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, order := range page {
+			if order.TotalAmount != rub(200) || len(order.Items) != 1 {
+				t.Fatalf("order %d: items %v, total %s", order.ID, order.Items, order.TotalAmount)
+			}
+			got = append(got, int64(order.ID))
+		}
+		last := page[len(page)-1]
+		filter.After = &domain.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	if len(got) != len(ids) {
+		t.Fatalf("listed %v, want %d orders", got, len(ids))
+	}
+	for i := range got {
+		if got[i] != ids[len(ids)-1-i] {
+			t.Fatalf("listed %v, want newest first %v", got, ids)
+		}
+	}
+
+	paid, err := storage.ListOrders(ctx, domain.OrderFilter{
+		UserID:   1,
+		Statuses: []domain.Status{domain.StatusPaid},
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatalf("list paid: %v", err)
+	}
+	if len(paid) != 1 || int64(paid[0].ID) != ids[0] {
+		t.Fatalf("paid orders: got %v, want [%d]", paid, ids[0])
+	}
+
+	later, err := storage.ListOrders(ctx, domain.OrderFilter{
+		UserID:      1,
+		CreatedFrom: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatalf("list by range: %v", err)
+	}
+	if len(later) != 0 {
+		t.Fatalf("orders after range start: got %d, want 0", len(later))
+	}
+}
+
+func rub(amount int64) domain.Money {
+	return domain.Money{Amount: amount, Currency: "RUB"}
 }
 
 func configForTest(dsn string) config.DBConfig {
@@ -277,3 +901,267 @@ func configForTest(dsn string) config.DBConfig {
 		HealthCheckPeriod: time.Second,
 	}
 }
+
+func TestOrderSaga_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	orderID, err := storage.CreateOrder(ctx, 1, []domain.OrderItem{{ProductID: 1, Price: rub(10), Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	saga := domain.NewSaga(orderID, now, time.Minute)
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		if err := tx.CreateSaga(ctx, saga); err != nil {
+			return err
+		}
+
+		due, err := tx.GetDueSaga(ctx, now)
+		if err != nil {
+			return err
+		}
+		if due.OrderID != 0 {
+			t.Fatalf("saga due before its deadline: %+v", due)
+		}
+
+		due, err = tx.GetDueSaga(ctx, now.Add(time.Minute))
+		if err != nil {
+			return err
+		}
+		if due.OrderID != domain.ID(orderID) || due.Step != domain.SagaStepReserveStock || due.Attempts != 1 {
+			t.Fatalf("due saga: got %+v", due)
+		}
+
+		due.Compensate("reserve_stock timed out after 1 attempts", now.Add(time.Minute))
+		return tx.UpdateSaga(ctx, due)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := storage.GetSaga(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Step != domain.SagaStepCompensated || !got.DeadlineAt.IsZero() || got.LastError == "" {
+		t.Fatalf("saga: got %+v", got)
+	}
+
+	if _, err := storage.GetSaga(ctx, orderID+1); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("missing saga: got %v, want %v", err, pgx.ErrNoRows)
+	}
+}
+
+func TestGetUnpaidOrder_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		orderID, err := storage.CreateOrder(ctx, 1, []domain.OrderItem{{ProductID: 1, Price: rub(10), Quantity: 1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, orderID)
+	}
+
+	// the first two orders await payment for an hour, the third is paid
+	const query = `UPDATE orders SET status = $1, created_at = now() - interval '1 hour' WHERE id = $2`
+	for i, status := range []domain.Status{domain.StatusAwaitingPayment, domain.StatusAwaitingPayment, domain.StatusPaid} {
+		if _, err := db.Exec(ctx, query, status, ids[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	createdBefore := time.Now().Add(-30 * time.Minute)
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		if orderID, _, err := tx.GetUnpaidOrder(ctx, time.Now().Add(-2*time.Hour)); err != nil || orderID != 0 {
+			t.Fatalf("order unpaid before its deadline: got %d, %v", orderID, err)
+		}
+
+		locked, createdAt, err := tx.GetUnpaidOrder(ctx, createdBefore)
+		if err != nil {
+			return err
+		}
+		if locked != ids[0] && locked != ids[1] {
+			t.Fatalf("unpaid order: got %d, want one of %v", locked, ids[:2])
+		}
+		if createdAt.After(createdBefore) {
+			t.Fatalf("created at: got %s, want before %s", createdAt, createdBefore)
+		}
+
+		// another instance skips the locked order
+		return storage.RunInTx(ctx, func(other TxRepository) error {
+			orderID, _, err := other.GetUnpaidOrder(ctx, createdBefore)
+			if err != nil {
+				return err
+			}
+			if orderID == locked || orderID == 0 || orderID == ids[2] {
+				t.Fatalf("second instance: got %d, locked %d", orderID, locked)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromotion_Integration(t *testing.T) {
+	dsn := getDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	const insertPromotion = `
+		INSERT INTO promotions (code, kind, scope, value, product_id, max_redemptions, max_per_user, ends_at)
+		VALUES ('SPRING', 'fixed', 'item', 30, 10, 100, 1, now() + interval '1 day')
+	`
+	if _, err := db.Exec(ctx, insertPromotion); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []domain.OrderItem{
+		{ProductID: 10, Price: rub(100), Quantity: 2},
+		{ProductID: 11, Price: rub(50), Quantity: 1},
+	}
+
+	var orderID int64
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		if _, err := tx.GetPromotionForUpdate(ctx, "AUTUMN"); !errors.Is(err, pgx.ErrNoRows) {
+			t.Fatalf("unknown code: got %v, want %v", err, pgx.ErrNoRows)
+		}
+
+		promotion, err := tx.GetPromotionForUpdate(ctx, "SPRING")
+		if err != nil {
+			return err
+		}
+		if promotion.Kind != domain.DiscountFixed || promotion.ProductID != 10 || promotion.Currency != "RUB" ||
+			promotion.MaxPerUser != 1 || promotion.EndsAt.IsZero() || !promotion.StartsAt.IsZero() {
+			t.Fatalf("promotion: got %+v", promotion)
+		}
+
+		adjustments, err := promotion.Redeem(items, 0, time.Now())
+		if err != nil {
+			return err
+		}
+		subtotal, _ := domain.TotalOf(items)
+		total, err := domain.Discount(subtotal, adjustments)
+		if err != nil {
+			return err
+		}
+
+		if orderID, err = tx.CreateOrder(ctx, 7, items, adjustments, total); err != nil {
+			return err
+		}
+		return tx.RedeemPromotion(ctx, int64(promotion.ID), orderID, 7)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := storage.GetOrderByID(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.Adjustment{PromotionID: 1, Code: "SPRING", ProductID: 10, Amount: rub(60)}
+	if len(order.Adjustments) != 1 || order.Adjustments[0] != want || order.TotalAmount != rub(190) {
+		t.Fatalf("order: got total %s adjustments %+v", order.TotalAmount, order.Adjustments)
+	}
+
+	listed, err := storage.ListOrders(ctx, domain.OrderFilter{UserID: 7, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || len(listed[0].Adjustments) != 1 || listed[0].Adjustments[0] != want {
+		t.Fatalf("listed orders: got %+v", listed)
+	}
+
+	var payload []byte
+	if err := db.QueryRow(ctx, `SELECT payload FROM events WHERE aggregate_id = $1 AND event_type = $2`,
+		orderID, events.TypeOrderCreated).Scan(&payload); err != nil {
+		t.Fatal(err)
+	}
+	var created events.OrderCreated
+	if err := json.Unmarshal(payload, &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.TotalAmount != 190 || len(created.Adjustments) != 1 ||
+		created.Adjustments[0] != (events.OrderCreatedAdjustment{Code: "SPRING", ProductID: 10, Amount: 60}) {
+		t.Fatalf("order created: got %+v", created)
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		promotion, err := tx.GetPromotionForUpdate(ctx, "SPRING")
+		if err != nil {
+			return err
+		}
+		if promotion.Redemptions != 1 {
+			t.Fatalf("redemptions: got %d, want 1", promotion.Redemptions)
+		}
+		redeemed, err := tx.CountUserRedemptions(ctx, int64(promotion.ID), 7)
+		if err != nil {
+			return err
+		}
+		if _, err := promotion.Redeem(items, redeemed, time.Now()); !errors.Is(err, domain.ErrPromoCodeUsed) {
+			t.Fatalf("second redemption: got %v, want %v", err, domain.ErrPromoCodeUsed)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
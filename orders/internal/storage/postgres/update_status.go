@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ChernykhITMO/order-processing-platform/orders/internal/domain"
+)
+
+func (s *TxStorage) UpdateOrderStatus(
+	ctx context.Context,
+	orderID int64,
+	status domain.Status,
+	expectedVersion int64) (version int64, err error) {
+	const op = "storage.postgres.UpdateOrderStatus"
+
+	const query = `
+		UPDATE orders
+		SET status = $1, version = version + 1, updated_at = NOW()
+		WHERE id = $2 AND version = $3
+		RETURNING version
+	`
+
+	rows, err := s.tx.Query(ctx, query, status, orderID, expectedVersion)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		return 0, fmt.Errorf("%s: %w", op, domain.ErrVersionConflict)
+	}
+
+	if err := rows.Scan(&version); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS processed_events
+(
+    event_id     BIGINT PRIMARY KEY,
+    processed_at TIMESTAMPTZ DEFAULT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS processed_events CASCADE;
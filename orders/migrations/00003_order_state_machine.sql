@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('new', 'awaiting_payment', 'paid', 'payment_failed',
+                          'fulfilled', 'cancelled', 'refunded'));
+
+-- +goose Down
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders DROP COLUMN IF EXISTS version;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS idx_events_unsent_aggregate
+    ON events (aggregate_id, id) WHERE sent_at IS NULL;
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS attempts   INT         NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT        DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS failed_at  TIMESTAMPTZ DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_failed ON events (failed_at) WHERE failed_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_failed;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS failed_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts;
+
+DROP INDEX IF EXISTS idx_events_unsent_aggregate;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS total_amount BIGINT NOT NULL DEFAULT 0;
+
+UPDATE orders AS o
+SET total_amount = t.total
+FROM (SELECT order_id, SUM(price * quantity) AS total FROM order_items GROUP BY order_id) AS t
+WHERE t.order_id = o.id;
+
+ALTER TABLE orders
+    ADD CONSTRAINT orders_total_amount_check CHECK (total_amount >= 0);
+
+-- +goose Down
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_total_amount_check;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_amount;
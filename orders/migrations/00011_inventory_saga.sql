@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('new', 'awaiting_stock', 'out_of_stock', 'awaiting_payment', 'paid',
+                          'payment_failed', 'fulfilled', 'cancelled', 'refunded'));
+
+-- +goose Down
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('new', 'awaiting_payment', 'paid', 'payment_failed',
+                          'fulfilled', 'cancelled', 'refunded'));
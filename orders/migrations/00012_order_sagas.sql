@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS order_sagas
+(
+    order_id    BIGINT PRIMARY KEY REFERENCES orders (id) ON DELETE CASCADE,
+    step        TEXT        NOT NULL
+        CHECK (step IN ('reserve_stock', 'charge_payment', 'completed', 'compensated')),
+    attempts    INT         NOT NULL DEFAULT 1 CHECK (attempts >= 0),
+    deadline_at TIMESTAMPTZ,
+    last_error  TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_sagas_deadline ON order_sagas (deadline_at) WHERE deadline_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS order_sagas;
@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('new', 'awaiting_stock', 'out_of_stock', 'awaiting_payment', 'paid',
+                          'payment_failed', 'fulfilled', 'cancelled', 'refunded', 'expired'));
+
+CREATE INDEX IF NOT EXISTS idx_orders_awaiting_payment_created
+    ON orders (created_at) WHERE status = 'awaiting_payment';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_orders_awaiting_payment_created;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('new', 'awaiting_stock', 'out_of_stock', 'awaiting_payment', 'paid',
+                          'payment_failed', 'fulfilled', 'cancelled', 'refunded'));
@@ -0,0 +1,50 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS promotions
+(
+    id               BIGSERIAL PRIMARY KEY,
+    code             TEXT        NOT NULL UNIQUE CHECK (code = upper(btrim(code)) AND code <> ''),
+    kind             TEXT        NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    scope            TEXT        NOT NULL CHECK (scope IN ('order', 'item')),
+    value            BIGINT      NOT NULL CHECK (value > 0),
+    product_id       BIGINT,
+    currency         CHAR(3)     NOT NULL DEFAULT 'RUB',
+    min_order_amount BIGINT      NOT NULL DEFAULT 0 CHECK (min_order_amount >= 0),
+    starts_at        TIMESTAMPTZ,
+    ends_at          TIMESTAMPTZ,
+    max_redemptions  INT         NOT NULL DEFAULT 0 CHECK (max_redemptions >= 0),
+    max_per_user     INT         NOT NULL DEFAULT 0 CHECK (max_per_user >= 0),
+    redemptions      INT         NOT NULL DEFAULT 0 CHECK (redemptions >= 0),
+    active           BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (kind <> 'percent' OR value <= 100),
+    CHECK (scope = 'item' OR product_id IS NULL)
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions
+(
+    promotion_id BIGINT      NOT NULL REFERENCES promotions (id),
+    order_id     BIGINT      NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    user_id      BIGINT      NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (promotion_id, order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_user ON promotion_redemptions (promotion_id, user_id);
+
+CREATE TABLE IF NOT EXISTS order_adjustments
+(
+    id           BIGSERIAL PRIMARY KEY,
+    order_id     BIGINT  NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    promotion_id BIGINT  NOT NULL REFERENCES promotions (id),
+    code         TEXT    NOT NULL,
+    product_id   BIGINT,
+    amount       BIGINT  NOT NULL CHECK (amount > 0),
+    currency     CHAR(3) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_adjustments_order ON order_adjustments (order_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS order_adjustments;
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
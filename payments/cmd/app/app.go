@@ -4,24 +4,38 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"sync"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/admin"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/controller"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/kafka_consume"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/kafka_produce"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/provider"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services/event_sender"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services/janitor"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services/retry_scheduler"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
 )
 
 type App struct {
 	log          *slog.Logger
 	consumer     *kafka_consume.Consumer
+	refunds      *kafka_consume.Consumer
+	admin        *admin.Refunds
 	producer     *kafka.Producer
 	sender       *event_sender.Sender
 	senderPeriod time.Duration
+	scheduler    *retry_scheduler.Scheduler
+	retryPeriod  time.Duration
+	janitor      *janitor.Janitor
+	janitorEvery time.Duration
 	storage      postgres.Repository
 }
 
@@ -38,25 +52,90 @@ func New(log *slog.Logger, cfg config.Config) (*App, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	service := services.New(storage, log, cfg.EventType)
-	ctrl := controller.NewController(*service, log)
-	consumer, err := kafka_consume.NewConsumer(ctrl, cfg.KafkaBrokers, cfg.TopicOrder, cfg.ConsumerGroup, log)
+	paymentProvider, err := newProvider(cfg.Provider)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	sender := event_sender.New(storage, producer, log, cfg.TopicStatus)
+	retryPolicy := services.RetryPolicy{
+		MaxAttempts:    cfg.Retry.MaxAttempts,
+		BaseDelay:      cfg.Retry.BaseDelay,
+		MaxDelay:       cfg.Retry.MaxDelay,
+		AttemptTimeout: cfg.Retry.AttemptTimeout,
+	}
+	var schemas *schema.Registry
+	if cfg.SchemaDir != "" {
+		schemas, err = schema.Load(cfg.SchemaDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	service := services.New(storage, paymentProvider, retryPolicy, log, cfg.EventType)
+	ctrl := controller.NewController(*service, log, schemas)
+	dlq := deadletter.Config{
+		Topic:       cfg.DLQ.Topic,
+		MaxAttempts: cfg.DLQ.MaxAttempts,
+		Backoff:     cfg.DLQ.Backoff,
+	}
+	consumer, err := kafka_consume.NewConsumer(ctrl, producer, dlq, cfg.KafkaBrokers, cfg.TopicOrder, cfg.ConsumerGroup, log)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	refundDLQ := deadletter.Config{
+		Topic:       cfg.RefundDLQ.Topic,
+		MaxAttempts: cfg.RefundDLQ.MaxAttempts,
+		Backoff:     cfg.RefundDLQ.Backoff,
+	}
+	refundCtrl := controller.NewRefundController(*service, log, schemas)
+	refunds, err := kafka_consume.NewConsumer(refundCtrl, producer, refundDLQ, cfg.KafkaBrokers, cfg.TopicRefund, cfg.ConsumerGroup, log)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sender := event_sender.New(storage, producer, storage, log, cfg.TopicStatus, cfg.SenderBatchSize, cfg.SenderMaxAttempts, cfg.ContentType, schemas)
+	scheduler := retry_scheduler.New(service, log, cfg.Retry.BatchSize)
+	outboxJanitor := janitor.New(storage, log, janitor.Config{
+		Retention:   cfg.Outbox.Retention,
+		DedupWindow: cfg.Outbox.DedupWindow,
+		Archive:     cfg.Outbox.Archive,
+		BatchSize:   cfg.Outbox.BatchSize,
+	})
 
 	return &App{
 		log:          log,
 		consumer:     consumer,
+		refunds:      refunds,
+		admin:        admin.NewRefunds(service, cfg.AdminToken, log),
 		producer:     producer,
 		sender:       sender,
 		senderPeriod: cfg.SenderPeriod,
+		scheduler:    scheduler,
+		retryPeriod:  cfg.Retry.Period,
+		janitor:      outboxJanitor,
+		janitorEvery: cfg.Outbox.Period,
 		storage:      storage,
 	}, nil
 }
 
+func newProvider(cfg config.ProviderConfig) (services.PaymentProvider, error) {
+	switch cfg.Kind {
+	case config.ProviderFake:
+		return provider.NewFake(), nil
+	case config.ProviderSimulator:
+		return provider.NewSimulator(provider.SimulatorRules{
+			MaxAmount:      cfg.MaxAmount,
+			BlockedUsers:   cfg.BlockedUsers,
+			FailureRatio:   cfg.FailureRatio,
+			TransientRatio: cfg.TransientRatio,
+			Seed:           cfg.Seed,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownProvider, cfg.Kind)
+	}
+}
+
 func (a *App) Run(ctx context.Context) error {
 	const op = "app.run"
 
@@ -64,9 +143,10 @@ func (a *App) Run(ctx context.Context) error {
 
 	log.Info("starting application")
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(5)
 
 	go func() { defer wg.Done(); a.consumer.Start(ctx) }()
+	go func() { defer wg.Done(); a.refunds.Start(ctx) }()
 
 	period := a.senderPeriod
 	if period <= 0 {
@@ -82,11 +162,28 @@ func (a *App) Run(ctx context.Context) error {
 		}
 	}()
 
+	retryPeriod := a.retryPeriod
+	if retryPeriod <= 0 {
+		retryPeriod = time.Second
+	}
+
+	go func() { defer wg.Done(); a.scheduler.Start(ctx, retryPeriod) }()
+
+	janitorPeriod := a.janitorEvery
+	if janitorPeriod <= 0 {
+		janitorPeriod = time.Minute
+	}
+
+	go func() { defer wg.Done(); a.janitor.Start(ctx, janitorPeriod) }()
+
 	<-ctx.Done()
 
 	if err := a.consumer.Stop(); err != nil {
 		log.Error("consumer stopping", slog.Any("err", err))
 	}
+	if err := a.refunds.Stop(); err != nil {
+		log.Error("refund consumer stopping", slog.Any("err", err))
+	}
 
 	wg.Wait()
 
@@ -107,6 +204,11 @@ func (a *App) Stop() {
 		Info("stopping payments server")
 }
 
+// RefundsHandler serves the admin refunds endpoint.
+func (a *App) RefundsHandler() http.Handler {
+	return a.admin
+}
+
 func (a *App) CheckReadiness(ctx context.Context) error {
 	if a.storage == nil {
 		return nil
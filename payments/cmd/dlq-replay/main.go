@@ -0,0 +1,192 @@
+// Command dlq-replay re-drives messages from a dead-letter topic written by
+// the payments or notifications consumers back to their original topic.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dlq"
+	kafkaproduce "github.com/ChernykhITMO/order-processing-platform/payments/internal/kafka_produce"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func main() {
+	var (
+		brokers  = flag.String("brokers", os.Getenv("KAFKA_BROKERS"), "comma separated kafka brokers")
+		topic    = flag.String("topic", "", "dead-letter topic to read, e.g. order-topic.dlq")
+		group    = flag.String("group", "dlq-replay", "consumer group used to track replayed offsets")
+		from     = flag.String("from", "", "replay messages dead-lettered at or after this RFC3339 time")
+		to       = flag.String("to", "", "replay messages dead-lettered before this RFC3339 time")
+		errMatch = flag.String("error", "", "replay messages whose error contains this substring")
+		orderID  = flag.Int64("order-id", 0, "replay messages for this order only")
+		dryRun   = flag.Bool("dry-run", false, "print matching messages without publishing or committing")
+		idle     = flag.Duration("idle", 10*time.Second, "stop after no messages arrive for this long")
+		limit    = flag.Int("limit", 0, "stop after replaying this many messages, 0 means no limit")
+	)
+	flag.Parse()
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	if *brokers == "" || *topic == "" {
+		log.Error("brokers and topic are required")
+		os.Exit(2)
+	}
+
+	filter, err := buildFilter(*from, *to, *errMatch, *orderID)
+	if err != nil {
+		log.Error("invalid filter", slog.Any("err", err))
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	r := replayer{
+		log:     log.With(slog.String("topic", *topic), slog.Bool("dry_run", *dryRun)),
+		filter:  filter,
+		dryRun:  *dryRun,
+		idle:    *idle,
+		limit:   *limit,
+		brokers: strings.Split(*brokers, ","),
+	}
+
+	if err := r.run(ctx, *topic, *group); err != nil {
+		log.Error("replay failed", slog.Any("err", err))
+		os.Exit(1)
+	}
+}
+
+func buildFilter(from, to, errMatch string, orderID int64) (dlq.Filter, error) {
+	filter := dlq.Filter{ErrorContains: errMatch, OrderID: orderID}
+	if from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return dlq.Filter{}, fmt.Errorf("from: %w", err)
+		}
+		filter.From = parsed
+	}
+	if to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return dlq.Filter{}, fmt.Errorf("to: %w", err)
+		}
+		filter.To = parsed
+	}
+	return filter, nil
+}
+
+type replayer struct {
+	log     *slog.Logger
+	filter  dlq.Filter
+	dryRun  bool
+	idle    time.Duration
+	limit   int
+	brokers []string
+}
+
+func (r *replayer) run(ctx context.Context, topic, group string) error {
+	const op = "dlq-replay.run"
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  strings.Join(r.brokers, ","),
+		"group.id":           group,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = consumer.Close() }()
+
+	if err := consumer.Subscribe(topic, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var producer *kafkaproduce.Producer
+	if !r.dryRun {
+		producer, err = kafkaproduce.NewProducer(r.brokers)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		defer func() { _ = producer.Close() }()
+	}
+
+	var scanned, replayed int
+	lastMessage := time.Now()
+	progress := dlq.NewProgress()
+
+	for ctx.Err() == nil {
+		if r.limit > 0 && replayed >= r.limit {
+			break
+		}
+		if time.Since(lastMessage) >= r.idle {
+			break
+		}
+
+		msg, err := consumer.ReadMessage(time.Second)
+		if err != nil {
+			if kerr, ok := err.(kafka.Error); ok && kerr.Code() == kafka.ErrTimedOut {
+				continue
+			}
+			return fmt.Errorf("%s: read: %w", op, err)
+		}
+		lastMessage = time.Now()
+		scanned++
+
+		log := r.log.With(
+			slog.Int("partition", int(msg.TopicPartition.Partition)),
+			slog.Int64("offset", int64(msg.TopicPartition.Offset)))
+
+		partition := msg.TopicPartition.Partition
+
+		entry, err := dlq.Parse(msg)
+		if err != nil {
+			log.Warn("skip message without dlq metadata", slog.Any("err", err))
+			progress.Skip(partition)
+			continue
+		}
+		if !r.filter.Match(entry) {
+			progress.Skip(partition)
+			continue
+		}
+
+		log = log.With(
+			slog.String("original_topic", entry.OriginalTopic),
+			slog.Int64("original_offset", entry.OriginalOffset),
+			slog.Int64("order_id", entry.OrderID),
+			slog.String("error", entry.Error),
+			slog.Time("failed_at", entry.FailedAt))
+
+		if r.dryRun {
+			log.Info("would replay", slog.String("payload", string(msg.Value)))
+			replayed++
+			continue
+		}
+
+		if err := producer.ProduceMessage(ctx, dlq.Replay(entry, time.Now())); err != nil {
+			return fmt.Errorf("%s: republish: %w", op, err)
+		}
+		log.Info("replayed")
+		replayed++
+
+		// committed offsets keep a later run with the same group from replaying
+		// twice, but never move past a message this run left in the topic
+		if !progress.Committable(partition) {
+			continue
+		}
+		if _, err := consumer.CommitMessage(msg); err != nil {
+			return fmt.Errorf("%s: commit: %w", op, err)
+		}
+	}
+
+	r.log.Info("replay finished", slog.Int("scanned", scanned), slog.Int("replayed", replayed))
+	return nil
+}
@@ -10,8 +10,10 @@ import (
 	"syscall"
 
 	"github.com/ChernykhITMO/order-processing-platform/payments/cmd/app"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/admin"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/health"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/metrics"
 	"github.com/joho/godotenv"
 )
 
@@ -44,6 +46,8 @@ func main() {
 		os.Exit(1)
 	}
 
+	metrics.Register()
+
 	application, err := app.New(log, cfg)
 
 	log.Info("config",
@@ -52,6 +56,9 @@ func main() {
 		slog.Int64("db_min_conns", int64(cfg.DB.MinConns)),
 		slog.Any("brokers", cfg.KafkaBrokers),
 		slog.String("health_addr", cfg.HealthAddr),
+		slog.String("payment_provider", cfg.Provider.Kind),
+		slog.String("dlq_topic", cfg.DLQ.Topic),
+		slog.String("refund_topic", cfg.TopicRefund),
 	)
 
 	if err != nil {
@@ -65,6 +72,11 @@ func main() {
 
 	go func() {
 		healthSrv := health.NewServer(cfg.HealthAddr, log, application.CheckReadiness)
+		if cfg.AdminToken != "" {
+			healthSrv.Handle(admin.RefundsPath, application.RefundsHandler())
+		} else {
+			log.Warn("PAYMENTS_ADMIN_TOKEN is empty, admin endpoints are disabled")
+		}
 		if err := healthSrv.Run(ctx); err != nil {
 			log.Error("health server stopped with error", slog.Any("err", err))
 			cancel()
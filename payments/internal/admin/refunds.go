@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+)
+
+// RefundsPath is where the refunds endpoint is mounted on the health server.
+const RefundsPath = "/admin/refunds"
+
+const requestTimeout = 5 * time.Second
+
+type Refunder interface {
+	Refund(ctx context.Context, input dto.RefundRequest) (domain.Refund, error)
+}
+
+// Refunds serves POST /admin/refunds for operators. Every request must carry
+// "Authorization: Bearer <token>".
+type Refunds struct {
+	service Refunder
+	token   string
+	log     *slog.Logger
+}
+
+func NewRefunds(service Refunder, token string, log *slog.Logger) *Refunds {
+	return &Refunds{service: service, token: token, log: log}
+}
+
+type refundResponse struct {
+	ID        int64     `json:"id"`
+	RequestID string    `json:"request_id"`
+	OrderID   int64     `json:"order_id"`
+	Amount    int64     `json:"amount"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP answers 200 with a succeeded refund and 409 with a failed one; a
+// retry with the same request_id returns the stored outcome.
+func (h *Refunds) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.Refunds.ServeHTTP"
+	log := h.log.With(slog.String("op", op))
+
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{Error: "method is not post"})
+		return
+	}
+	if !h.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, apiError{Error: "invalid admin token"})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<16)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	var input dto.RefundRequest
+	if err := dec.Decode(&input); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	refund, err := h.service.Refund(ctx, input)
+	if err != nil {
+		status := httpStatus(err)
+		if status == http.StatusInternalServerError || status == http.StatusServiceUnavailable {
+			log.Error("refund failed", slog.String("request_id", input.RequestID), slog.Any("err", err))
+		}
+		writeJSON(w, status, apiError{Error: err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if !refund.Succeeded() {
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, refundResponse{
+		ID:        refund.ID,
+		RequestID: refund.RequestID,
+		OrderID:   refund.OrderID,
+		Amount:    refund.Amount,
+		Status:    refund.Status,
+		Reason:    refund.Reason,
+		Error:     refund.Error,
+		CreatedAt: refund.CreatedAt,
+	})
+}
+
+func (h *Refunds) authorized(r *http.Request) bool {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) || header[:len(prefix)] != prefix {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header[len(prefix):]), []byte(h.token)) == 1
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRequestID),
+		errors.Is(err, domain.ErrInvalidOrderID),
+		errors.Is(err, domain.ErrInvalidRefundAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrRefundRequestReused):
+		return http.StatusConflict
+	case domain.IsTransient(err):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+)
+
+type refunderMock struct {
+	refund domain.Refund
+	err    error
+
+	calls int
+	input dto.RefundRequest
+}
+
+func (m *refunderMock) Refund(ctx context.Context, input dto.RefundRequest) (domain.Refund, error) {
+	m.calls++
+	m.input = input
+	return m.refund, m.err
+}
+
+func TestRefunds_ServeHTTP(t *testing.T) {
+	succeeded := domain.Refund{ID: 1, RequestID: "r-1", OrderID: 2, Amount: 30, Status: domain.StatusSucceeded}
+	failed := domain.Refund{ID: 2, RequestID: "r-1", OrderID: 2, Amount: 300, Status: domain.StatusFailed, Error: domain.ErrRefundExceedsPayment.Error()}
+
+	tests := []struct {
+		name      string
+		method    string
+		token     string
+		body      string
+		refund    domain.Refund
+		err       error
+		want      int
+		wantCalls int
+	}{
+		{name: "succeeded", method: http.MethodPost, token: "secret", body: `{"request_id":"r-1","order_id":2,"amount":30}`, refund: succeeded, want: http.StatusOK, wantCalls: 1},
+		{name: "failed", method: http.MethodPost, token: "secret", body: `{"request_id":"r-1","order_id":2,"amount":300}`, refund: failed, want: http.StatusConflict, wantCalls: 1},
+		{name: "invalid input", method: http.MethodPost, token: "secret", body: `{"order_id":2}`, err: fmt.Errorf("services.Refund: %w", domain.ErrInvalidRequestID), want: http.StatusBadRequest, wantCalls: 1},
+		{name: "transient", method: http.MethodPost, token: "secret", body: `{"request_id":"r-1","order_id":2}`, err: domain.ErrTransient, want: http.StatusServiceUnavailable, wantCalls: 1},
+		{name: "unknown field", method: http.MethodPost, token: "secret", body: `{"request_id":"r-1","order":2}`, want: http.StatusBadRequest},
+		{name: "wrong token", method: http.MethodPost, token: "other", body: `{"request_id":"r-1","order_id":2}`, want: http.StatusUnauthorized},
+		{name: "no token", method: http.MethodPost, body: `{"request_id":"r-1","order_id":2}`, want: http.StatusUnauthorized},
+		{name: "wrong method", method: http.MethodGet, token: "secret", want: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &refunderMock{refund: tt.refund, err: tt.err}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			handler := NewRefunds(service, "secret", log)
+
+			req := httptest.NewRequest(tt.method, RefundsPath, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status: got %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if service.calls != tt.wantCalls {
+				t.Fatalf("Refund calls: got %d, want %d", service.calls, tt.wantCalls)
+			}
+			if tt.err != nil || tt.wantCalls == 0 {
+				return
+			}
+
+			var resp refundResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.ID != tt.refund.ID || resp.Status != tt.refund.Status || resp.Error != tt.refund.Error {
+				t.Fatalf("response: got %+v, want %+v", resp, tt.refund)
+			}
+		})
+	}
+}
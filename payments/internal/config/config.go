@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
 )
 
 type Config struct {
@@ -14,9 +16,65 @@ type Config struct {
 	KafkaBrokers  []string
 	TopicOrder    string
 	TopicStatus   string
+	TopicRefund   string
 	EventType     string
 	ConsumerGroup string
 	SenderPeriod  time.Duration
+	// SenderBatchSize caps how many outbox events are published per round trip.
+	SenderBatchSize int
+	// SenderMaxAttempts is how many times an outbox event that cannot be
+	// encoded is retried before it is dead-lettered.
+	SenderMaxAttempts int
+	// ContentType is the encoding of published events.
+	ContentType string
+	// SchemaDir is the event schema registry; validation is off when empty.
+	SchemaDir string
+	Provider  ProviderConfig
+	Retry     RetryConfig
+	DLQ       DLQConfig
+	RefundDLQ DLQConfig
+	Outbox    OutboxConfig
+	// AdminToken guards the admin endpoints of the health server; they are
+	// not served when it is empty.
+	AdminToken string
+}
+
+// OutboxConfig drives the retention janitor for events and processed_events.
+type OutboxConfig struct {
+	Retention   time.Duration
+	DedupWindow time.Duration
+	Archive     bool
+	BatchSize   int
+	Period      time.Duration
+}
+
+type DLQConfig struct {
+	Topic       string
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+type RetryConfig struct {
+	MaxAttempts    int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	Period         time.Duration
+	BatchSize      int
+	AttemptTimeout time.Duration
+}
+
+const (
+	ProviderFake      = "fake"
+	ProviderSimulator = "simulator"
+)
+
+type ProviderConfig struct {
+	Kind           string
+	MaxAmount      int64
+	BlockedUsers   []int64
+	FailureRatio   float64
+	TransientRatio float64
+	Seed           uint64
 }
 
 type DBConfig struct {
@@ -48,6 +106,7 @@ func Load() (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
+	topicRefund := getEnvOrDefault("KAFKA_TOPIC_REFUND", "refund-topic")
 	eventType, err := getEnv("KAFKA_EVENT_TYPE")
 	if err != nil {
 		return Config{}, err
@@ -77,6 +136,40 @@ func Load() (Config, error) {
 		return Config{}, err
 	}
 
+	senderBatchSize, err := getEnvInt32WithDefault("KAFKA_SENDER_BATCH_SIZE", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	senderMaxAttempts, err := getEnvInt32WithDefault("OUTBOX_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	contentType := getEnvOrDefault("KAFKA_CONTENT_TYPE", envelope.ContentTypeJSON)
+	if contentType != envelope.ContentTypeJSON && contentType != envelope.ContentTypeProtobuf {
+		return Config{}, errors.New("KAFKA_CONTENT_TYPE must be " + envelope.ContentTypeJSON + " or " + envelope.ContentTypeProtobuf)
+	}
+
+	provider, err := loadProvider()
+	if err != nil {
+		return Config{}, err
+	}
+	retry, err := loadRetry()
+	if err != nil {
+		return Config{}, err
+	}
+	outbox, err := loadOutbox()
+	if err != nil {
+		return Config{}, err
+	}
+	dlqAttempts, err := getEnvInt32WithDefault("KAFKA_CONSUMER_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return Config{}, err
+	}
+	dlqBackoff, err := getEnvDurationWithDefault("KAFKA_CONSUMER_BACKOFF", 500*time.Millisecond)
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
 		DB: DBConfig{
 			DSN:               dsn,
@@ -85,13 +178,135 @@ func Load() (Config, error) {
 			MaxConnIdleTime:   maxConnIdleTime,
 			HealthCheckPeriod: healthCheckPeriod,
 		},
-		HealthAddr:    getEnvOrDefault("PAYMENTS_HEALTH_ADDR", ":8082"),
-		KafkaBrokers:  kafkaBrokers,
-		TopicOrder:    topicOrder,
-		TopicStatus:   topicStatus,
-		EventType:     eventType,
-		ConsumerGroup: consumerGroup,
-		SenderPeriod:  senderPeriod,
+		HealthAddr:        getEnvOrDefault("PAYMENTS_HEALTH_ADDR", ":8082"),
+		KafkaBrokers:      kafkaBrokers,
+		TopicOrder:        topicOrder,
+		TopicStatus:       topicStatus,
+		TopicRefund:       topicRefund,
+		EventType:         eventType,
+		ConsumerGroup:     consumerGroup,
+		SenderPeriod:      senderPeriod,
+		SenderBatchSize:   int(senderBatchSize),
+		SenderMaxAttempts: int(senderMaxAttempts),
+		ContentType:       contentType,
+		SchemaDir:         getEnvOrDefault("EVENT_SCHEMA_DIR", ""),
+		Provider:          provider,
+		Retry:             retry,
+		DLQ: DLQConfig{
+			Topic:       getEnvOrDefault("KAFKA_TOPIC_ORDER_DLQ", topicOrder+".dlq"),
+			MaxAttempts: int(dlqAttempts),
+			Backoff:     dlqBackoff,
+		},
+		RefundDLQ: DLQConfig{
+			Topic:       getEnvOrDefault("KAFKA_TOPIC_REFUND_DLQ", topicRefund+".dlq"),
+			MaxAttempts: int(dlqAttempts),
+			Backoff:     dlqBackoff,
+		},
+		Outbox:     outbox,
+		AdminToken: os.Getenv("PAYMENTS_ADMIN_TOKEN"),
+	}, nil
+}
+
+func loadProvider() (ProviderConfig, error) {
+	kind := getEnvOrDefault("PAYMENT_PROVIDER", ProviderSimulator)
+	if kind != ProviderFake && kind != ProviderSimulator {
+		return ProviderConfig{}, errors.New("PAYMENT_PROVIDER must be fake or simulator")
+	}
+	maxAmount, err := getEnvInt64WithDefault("PAYMENT_SIM_MAX_AMOUNT", 0)
+	if err != nil {
+		return ProviderConfig{}, err
+	}
+	blockedUsers, err := parseInt64List("PAYMENT_SIM_BLOCKED_USERS")
+	if err != nil {
+		return ProviderConfig{}, err
+	}
+	failureRatio, err := getEnvFloatWithDefault("PAYMENT_SIM_FAILURE_RATIO", 0)
+	if err != nil {
+		return ProviderConfig{}, err
+	}
+	transientRatio, err := getEnvFloatWithDefault("PAYMENT_SIM_TRANSIENT_RATIO", 0)
+	if err != nil {
+		return ProviderConfig{}, err
+	}
+	seed, err := getEnvInt64WithDefault("PAYMENT_SIM_SEED", 1)
+	if err != nil {
+		return ProviderConfig{}, err
+	}
+
+	return ProviderConfig{
+		Kind:           kind,
+		MaxAmount:      maxAmount,
+		BlockedUsers:   blockedUsers,
+		FailureRatio:   failureRatio,
+		TransientRatio: transientRatio,
+		Seed:           uint64(seed),
+	}, nil
+}
+
+func loadRetry() (RetryConfig, error) {
+	maxAttempts, err := getEnvInt32WithDefault("PAYMENT_RETRY_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return RetryConfig{}, err
+	}
+	baseDelay, err := getEnvDurationWithDefault("PAYMENT_RETRY_BASE_DELAY", time.Second)
+	if err != nil {
+		return RetryConfig{}, err
+	}
+	maxDelay, err := getEnvDurationWithDefault("PAYMENT_RETRY_MAX_DELAY", time.Minute)
+	if err != nil {
+		return RetryConfig{}, err
+	}
+	period, err := getEnvDurationWithDefault("PAYMENT_RETRY_PERIOD", time.Second)
+	if err != nil {
+		return RetryConfig{}, err
+	}
+	batchSize, err := getEnvInt32WithDefault("PAYMENT_RETRY_BATCH_SIZE", 10)
+	if err != nil {
+		return RetryConfig{}, err
+	}
+	attemptTimeout, err := getEnvDurationWithDefault("PAYMENT_ATTEMPT_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return RetryConfig{}, err
+	}
+
+	return RetryConfig{
+		MaxAttempts:    int(maxAttempts),
+		BaseDelay:      baseDelay,
+		MaxDelay:       maxDelay,
+		Period:         period,
+		BatchSize:      int(batchSize),
+		AttemptTimeout: attemptTimeout,
+	}, nil
+}
+
+func loadOutbox() (OutboxConfig, error) {
+	retention, err := getEnvDurationWithDefault("OUTBOX_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	dedupWindow, err := getEnvDurationWithDefault("OUTBOX_DEDUP_WINDOW", 30*24*time.Hour)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	archive, err := getEnvBoolWithDefault("OUTBOX_ARCHIVE", true)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	batchSize, err := getEnvInt32WithDefault("OUTBOX_CLEANUP_BATCH_SIZE", 1000)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+	period, err := getEnvDurationWithDefault("OUTBOX_CLEANUP_PERIOD", time.Minute)
+	if err != nil {
+		return OutboxConfig{}, err
+	}
+
+	return OutboxConfig{
+		Retention:   retention,
+		DedupWindow: dedupWindow,
+		Archive:     archive,
+		BatchSize:   int(batchSize),
+		Period:      period,
 	}, nil
 }
 
@@ -136,6 +351,61 @@ func getEnvInt32WithDefault(key string, def int32) (int32, error) {
 	return int32(parsed), nil
 }
 
+func getEnvInt64WithDefault(key string, def int64) (int64, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, errors.New(key + " is invalid int: " + err.Error())
+	}
+
+	return parsed, nil
+}
+
+func getEnvFloatWithDefault(key string, def float64) (float64, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, errors.New(key + " is invalid float: " + err.Error())
+	}
+
+	return parsed, nil
+}
+
+func getEnvBoolWithDefault(key string, def bool) (bool, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, errors.New(key + " is invalid bool: " + err.Error())
+	}
+
+	return parsed, nil
+}
+
+func parseInt64List(key string) ([]int64, error) {
+	parts := parseKafkaBrokers(os.Getenv(key))
+	out := make([]int64, 0, len(parts))
+	for _, p := range parts {
+		parsed, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return nil, errors.New(key + " is invalid int list: " + err.Error())
+		}
+		out = append(out, parsed)
+	}
+	return out, nil
+}
+
 func getEnvDurationWithDefault(key string, def time.Duration) (time.Duration, error) {
 	val := os.Getenv(key)
 	if val == "" {
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/provider"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+)
+
+const contractsDir = "../../../contracts"
+
+// paymentRequestedContract lists the payment requested fields payments
+// relies on.
+var paymentRequestedContract = contract.Contract{
+	Consumer:  "payments",
+	Provider:  "orders",
+	EventType: events.TypePaymentRequested,
+	Examples: []contract.Example{
+		{
+			Description: "reserved order to charge",
+			Payload:     json.RawMessage(`{"event_id":11,"order_id":42,"user_id":7,"total_amount":1500}`),
+		},
+		{
+			Description: "order priced in another currency",
+			Payload:     json.RawMessage(`{"event_id":13,"order_id":43,"user_id":7,"total_amount":2500,"currency":"USD"}`),
+		},
+	},
+}
+
+// orderCancelledContract lists the order cancelled fields payments relies on.
+var orderCancelledContract = contract.Contract{
+	Consumer:  "payments",
+	Provider:  "orders",
+	EventType: events.TypeOrderCancelled,
+	Examples: []contract.Example{
+		{
+			Description: "order cancelled before payment",
+			Payload:     json.RawMessage(`{"event_id":12,"order_id":42,"user_id":7}`),
+		},
+	},
+}
+
+// orderExpiredContract lists the order expired fields payments relies on.
+var orderExpiredContract = contract.Contract{
+	Consumer:  "payments",
+	Provider:  "orders",
+	EventType: events.TypeOrderExpired,
+	Examples: []contract.Example{
+		{
+			Description: "order left unpaid past its deadline",
+			Payload:     json.RawMessage(`{"event_id":14,"order_id":42,"user_id":7}`),
+		},
+	},
+}
+
+func TestController_PaymentRequestedContract(t *testing.T) {
+	for _, example := range paymentRequestedContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			tx := &txMock{}
+			st := &storageMock{tx: tx}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+			ctrl := NewController(*svc, log, nil)
+
+			message, _ := json.Marshal(envelope.Envelope{
+				ID:      "orders:11",
+				Type:    paymentRequestedContract.EventType,
+				Version: events.SchemaVersion(paymentRequestedContract.EventType),
+				Source:  paymentRequestedContract.Provider,
+				Payload: example.Payload,
+			})
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			var want struct {
+				EventID     int64  `json:"event_id"`
+				OrderID     int64  `json:"order_id"`
+				UserID      int64  `json:"user_id"`
+				TotalAmount int64  `json:"total_amount"`
+				Currency    string `json:"currency"`
+			}
+			_ = json.Unmarshal(example.Payload, &want)
+			if want.Currency == "" {
+				want.Currency = string(domain.DefaultCurrency)
+			}
+			if tx.processedEventID != want.EventID {
+				t.Fatalf("event id: got %d, want %d", tx.processedEventID, want.EventID)
+			}
+			if tx.upserted != [3]int64{want.OrderID, want.UserID, want.TotalAmount} {
+				t.Fatalf("payment: got %v, want order=%d user=%d amount=%d", tx.upserted, want.OrderID, want.UserID, want.TotalAmount)
+			}
+			if string(tx.upsertedCurrency) != want.Currency {
+				t.Fatalf("currency: got %s, want %s", tx.upsertedCurrency, want.Currency)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, paymentRequestedContract)
+}
+
+func TestController_OrderCancelledContract(t *testing.T) {
+	for _, example := range orderCancelledContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			tx := &txMock{}
+			st := &storageMock{tx: tx}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+			ctrl := NewController(*svc, log, nil)
+
+			message, _ := json.Marshal(envelope.Envelope{
+				ID:      "orders:12",
+				Type:    orderCancelledContract.EventType,
+				Version: events.SchemaVersion(orderCancelledContract.EventType),
+				Source:  orderCancelledContract.Provider,
+				Payload: example.Payload,
+			})
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			var want struct {
+				EventID int64 `json:"event_id"`
+				OrderID int64 `json:"order_id"`
+				UserID  int64 `json:"user_id"`
+			}
+			_ = json.Unmarshal(example.Payload, &want)
+			if tx.processedEventID != want.EventID {
+				t.Fatalf("event id: got %d, want %d", tx.processedEventID, want.EventID)
+			}
+			if tx.upserted != [3]int64{want.OrderID, want.UserID, 0} || tx.savedType != events.TypePaymentCancelled {
+				t.Fatalf("payment: got %v type %q, want order=%d user=%d", tx.upserted, tx.savedType, want.OrderID, want.UserID)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, orderCancelledContract)
+}
+
+func TestController_OrderExpiredContract(t *testing.T) {
+	for _, example := range orderExpiredContract.Examples {
+		t.Run(example.Description, func(t *testing.T) {
+			tx := &txMock{}
+			st := &storageMock{tx: tx}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+			ctrl := NewController(*svc, log, nil)
+
+			message, _ := json.Marshal(envelope.Envelope{
+				ID:      "orders:14",
+				Type:    orderExpiredContract.EventType,
+				Version: events.SchemaVersion(orderExpiredContract.EventType),
+				Source:  orderExpiredContract.Provider,
+				Payload: example.Payload,
+			})
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+				t.Fatalf("handle message: %v", err)
+			}
+
+			var want struct {
+				EventID int64 `json:"event_id"`
+				OrderID int64 `json:"order_id"`
+				UserID  int64 `json:"user_id"`
+			}
+			_ = json.Unmarshal(example.Payload, &want)
+			if tx.processedEventID != want.EventID {
+				t.Fatalf("event id: got %d, want %d", tx.processedEventID, want.EventID)
+			}
+			if tx.upserted != [3]int64{want.OrderID, want.UserID, 0} || tx.savedType != events.TypePaymentCancelled {
+				t.Fatalf("payment: got %v type %q, want order=%d user=%d", tx.upserted, tx.savedType, want.OrderID, want.UserID)
+			}
+		})
+	}
+
+	contract.Record(t, contractsDir, orderExpiredContract)
+}
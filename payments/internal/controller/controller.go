@@ -2,48 +2,146 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services"
 )
 
+// ErrUntypedMessage is returned for a message without an event type: payments
+// does not charge for it and leaves it to the dead letter topic.
+var ErrUntypedMessage = errors.New("message without event type")
+
 type Producer interface {
-	Produce(ctx context.Context, message []byte, topic string) error
+	Produce(ctx context.Context, key, message []byte, topic string) error
+}
+
+// Validator checks a consumed envelope against the schema registry.
+type Validator interface {
+	ValidateEnvelope(env envelope.Envelope) error
 }
 
 type Controller struct {
 	service services.Service
 	log     *slog.Logger
+	schemas Validator
 }
 
-func NewController(service services.Service, log *slog.Logger) *Controller {
+// NewController builds the handler of the order topic: payment requested,
+// order cancelled and order expired events. schemas may be nil to skip
+// payload validation.
+func NewController(service services.Service, log *slog.Logger, schemas Validator) *Controller {
 	return &Controller{
 		service: service,
 		log:     log,
+		schemas: schemas,
 	}
 }
 
-func (h *Controller) HandleMessage(parentCtx context.Context, message []byte) error {
+func (h *Controller) HandleMessage(parentCtx context.Context, contentType string, message []byte) error {
 	const op = "controller.HandleMessage"
 	log := h.log.With(slog.String("op", op))
 
-	var input dto.OrderCreated
-	if err := json.Unmarshal(message, &input); err != nil {
-		log.Error("decode message", slog.Any("err", err))
-		return fmt.Errorf("%s: decode message: %w", op, err)
+	env, err := events.DecodeEnvelope(contentType, message)
+	if err != nil {
+		log.Error("decode envelope", slog.Any("err", err))
+		return fmt.Errorf("%s: decode envelope: %w", op, err)
+	}
+	if h.schemas != nil {
+		if err := h.schemas.ValidateEnvelope(env); err != nil {
+			log.Error("validate message", slog.Any("err", err))
+			return fmt.Errorf("%s: validate message: %w", op, err)
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(envelope.WithMeta(parentCtx, envelope.CausedBy(env)), 5*time.Second)
 	defer cancel()
 
-	if err := h.service.HandleOrderCreated(ctx, input); err != nil {
-		log.Error("handle failed", slog.Any("err", err))
-		return fmt.Errorf("%s: handle message: %w", op, err)
+	switch env.Type {
+	case events.TypeOrderCreated:
+		// orders asks for the payment once inventory has reserved the stock
+		log.Debug("order created skipped, waiting for payment request", slog.String("event_id", env.ID))
+		return nil
+	case events.TypePaymentRequested:
+		err = h.handlePaymentRequested(ctx, env)
+	case "":
+		log.Error("untyped message rejected", slog.String("event_id", env.ID))
+		return fmt.Errorf("%s: %w", op, ErrUntypedMessage)
+	case events.TypeOrderCancelled:
+		err = h.handleOrderCancelled(ctx, env)
+	case events.TypeOrderExpired:
+		err = h.handleOrderExpired(ctx, env)
+	default:
+		log.Warn("unexpected event type skipped", slog.String("type", env.Type))
+		return nil
 	}
+	if err != nil {
+		log.Error("handle failed", slog.String("type", env.Type), slog.Any("err", err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
 
+func (h *Controller) handlePaymentRequested(ctx context.Context, env envelope.Envelope) error {
+	var event events.PaymentRequested
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+
+	input := dto.PaymentRequested{
+		EventID:     event.EventID,
+		OrderID:     int64(event.OrderID),
+		UserID:      int64(event.UserID),
+		TotalAmount: event.TotalAmount,
+		Currency:    event.Currency,
+		RequestedAt: event.RequestedAt,
+	}
+
+	if err := h.service.HandlePaymentRequested(ctx, input); err != nil {
+		return fmt.Errorf("handle message: %w", err)
+	}
+	return nil
+}
+
+func (h *Controller) handleOrderCancelled(ctx context.Context, env envelope.Envelope) error {
+	var event events.OrderCancelled
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+
+	input := dto.OrderCancelled{
+		EventID: event.EventID,
+		OrderID: int64(event.OrderID),
+		UserID:  int64(event.UserID),
+		Reason:  event.Reason,
+	}
+
+	if err := h.service.HandleOrderCancelled(ctx, input); err != nil {
+		return fmt.Errorf("handle message: %w", err)
+	}
+	return nil
+}
+
+func (h *Controller) handleOrderExpired(ctx context.Context, env envelope.Envelope) error {
+	var event events.OrderExpired
+	if err := envelope.UnmarshalPayload(env, &event); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+
+	input := dto.OrderExpired{
+		EventID: event.EventID,
+		OrderID: int64(event.OrderID),
+		UserID:  int64(event.UserID),
+	}
+
+	if err := h.service.HandleOrderExpired(ctx, input); err != nil {
+		return fmt.Errorf("handle message: %w", err)
+	}
 	return nil
 }
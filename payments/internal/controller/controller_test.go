@@ -7,10 +7,13 @@ import (
 	"io"
 	"log/slog"
 	"testing"
+	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/provider"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
 )
@@ -23,14 +26,18 @@ func (m *storageMock) RunInTx(ctx context.Context, fn func(tx postgres.TxReposit
 	return fn(m.tx)
 }
 
-func (m *storageMock) GetNewEvent(ctx context.Context) (events.PaymentStatus, error) {
-	return events.PaymentStatus{}, nil
+func (m *storageMock) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	return nil, nil
 }
 
-func (m *storageMock) MarkSent(ctx context.Context, id int64) error {
+func (m *storageMock) MarkSent(ctx context.Context, ids []int64) error {
 	return nil
 }
 
+func (m *storageMock) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
 func (m *storageMock) Close() error {
 	return nil
 }
@@ -46,36 +53,114 @@ type txMock struct {
 	saveCalled    int
 
 	savedPayload []byte
+	savedMeta    envelope.Meta
+
+	processedEventID int64
+	upserted         [3]int64
+	upsertedCurrency domain.Currency
+
+	record      domain.PaymentRecord
+	savedType   string
+	savedStatus string
+
+	savedRefund domain.Refund
 }
 
-func (m *txMock) UpsertPayment(ctx context.Context, orderID, userID, totalAmount int64, status string) error {
+func (m *txMock) UpsertPayment(ctx context.Context, orderID, userID int64, total domain.Money, status string) error {
 	m.upsertCalled++
+	m.upserted = [3]int64{orderID, userID, total.Amount}
+	m.upsertedCurrency = total.Currency
+	m.record = domain.PaymentRecord{
+		Payment: domain.Payment{OrderID: orderID, UserID: userID, Amount: total.Amount, Currency: total.Currency},
+		Status:  status,
+	}
+	return nil
+}
+
+func (m *txMock) GetRefund(ctx context.Context, requestID string) (domain.Refund, error) {
+	if m.savedRefund.RequestID == requestID {
+		return m.savedRefund, nil
+	}
+	return domain.Refund{}, nil
+}
+
+func (m *txMock) SaveRefund(ctx context.Context, refund domain.Refund) (domain.Refund, error) {
+	refund.ID = 1
+	m.savedRefund = refund
+	return refund, nil
+}
+
+func (m *txMock) GetPendingRefund(ctx context.Context, orderID int64) (domain.Refund, error) {
+	return domain.Refund{}, nil
+}
+
+func (m *txMock) CompleteRefund(ctx context.Context, refund domain.Refund) error {
+	m.savedRefund = refund
+	return nil
+}
+
+func (m *txMock) AddRefundedAmount(ctx context.Context, orderID, amount int64) error {
 	return nil
 }
 
 func (m *txMock) UpdatePaymentStatus(ctx context.Context, orderID int64, status string) error {
 	m.updateCalled++
+	m.savedStatus = status
+	m.record.Status = status
+	return nil
+}
+
+func (m *txMock) GetPaymentForUpdate(ctx context.Context, orderID int64) (domain.PaymentRecord, error) {
+	return m.record, nil
+}
+
+func (m *txMock) SaveAuthorization(ctx context.Context, orderID int64, authID string) error {
+	return nil
+}
+
+func (m *txMock) LeaseAttempt(ctx context.Context, orderID int64, leaseUntil time.Time) error {
+	m.record.Status = domain.StatusPaymentPending
+	return nil
+}
+
+func (m *txMock) ScheduleRetry(ctx context.Context, orderID int64, nextAttemptAt time.Time, lastErr string) error {
+	return nil
+}
+
+func (m *txMock) LeaseRelease(ctx context.Context, orderID int64, leaseUntil time.Time) error {
+	m.record.Status = domain.StatusCancelling
 	return nil
 }
 
+func (m *txMock) GetDuePayment(ctx context.Context, now time.Time) (domain.PaymentRecord, error) {
+	return domain.PaymentRecord{}, nil
+}
+
+func (m *txMock) IsProcessed(ctx context.Context, eventId int64) (bool, error) {
+	return false, nil
+}
+
 func (m *txMock) TryMarkProcessed(ctx context.Context, eventId int64) (bool, error) {
 	m.tryMarkCalled++
+	m.processedEventID = eventId
 	return true, nil
 }
 
 func (m *txMock) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
 	m.saveCalled++
+	m.savedType = eventType
 	m.savedPayload = payload
+	m.savedMeta = envelope.MetaFrom(ctx)
 	return nil
 }
 
 func TestController_HandleMessage_InvalidJSON(t *testing.T) {
 	st := &storageMock{tx: &txMock{}}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := services.New(st, log, "event-status")
-	ctrl := NewController(*svc, log)
+	svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+	ctrl := NewController(*svc, log, nil)
 
-	if err := ctrl.HandleMessage(context.Background(), []byte("{")); err == nil {
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, []byte("{")); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -84,13 +169,12 @@ func TestController_HandleMessage_Success(t *testing.T) {
 	tx := &txMock{}
 	st := &storageMock{tx: tx}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := services.New(st, log, "event-status")
-	ctrl := NewController(*svc, log)
+	svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+	ctrl := NewController(*svc, log, nil)
 
-	input := dto.OrderCreated{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100}
-	payload, _ := json.Marshal(input)
+	message := wrap(events.TypePaymentRequested, dto.PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100})
 
-	if err := ctrl.HandleMessage(context.Background(), payload); err != nil {
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -107,16 +191,240 @@ func TestController_HandleMessage_Success(t *testing.T) {
 	}
 }
 
+func TestController_HandleMessage_Untyped(t *testing.T) {
+	tx := &txMock{}
+	st := &storageMock{tx: tx}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+	ctrl := NewController(*svc, log, nil)
+
+	payload, _ := json.Marshal(dto.PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100})
+
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, payload); !errors.Is(err, ErrUntypedMessage) {
+		t.Fatalf("expected untyped message error, got %v", err)
+	}
+	if tx.tryMarkCalled != 0 || tx.saveCalled != 0 {
+		t.Fatalf("unexpected calls: try=%d save=%d", tx.tryMarkCalled, tx.saveCalled)
+	}
+}
+
+func TestController_HandleMessage_EventTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		payload   any
+		wantSaved string
+	}{
+		{"payment requested", events.TypePaymentRequested, dto.PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100}, "event-status"},
+		{"order created", events.TypeOrderCreated, dto.PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100}, ""},
+		{"order cancelled", events.TypeOrderCancelled, events.OrderCancelled{EventID: 2, OrderID: 2, UserID: 3, From: "awaiting_payment", CancelledAt: time.Now()}, events.TypePaymentCancelled},
+		{"order expired", events.TypeOrderExpired, events.OrderExpired{EventID: 4, OrderID: 2, UserID: 3, DeadlineAt: time.Now(), ExpiredAt: time.Now()}, events.TypePaymentCancelled},
+		{"unknown type", "order shipped", dto.PaymentRequested{EventID: 3, OrderID: 2, UserID: 3, TotalAmount: 100}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &txMock{}
+			st := &storageMock{tx: tx}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+			ctrl := NewController(*svc, log, nil)
+
+			body, _ := json.Marshal(tt.payload)
+			message, _ := json.Marshal(envelope.Envelope{
+				ID:         "orders:1",
+				Type:       tt.eventType,
+				Version:    events.SchemaVersion(tt.eventType),
+				OccurredAt: time.Now(),
+				Source:     "orders",
+				Payload:    body,
+			})
+
+			if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tx.savedType != tt.wantSaved {
+				t.Fatalf("saved event type: got %q, want %q", tx.savedType, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestRefundController_HandleMessage(t *testing.T) {
+	record := domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"}
+
+	tests := []struct {
+		name        string
+		contentType string
+		eventType   string
+		command     events.RefundRequested
+		wantErr     bool
+		wantSaved   string
+	}{
+		{"json", envelope.ContentTypeJSON, events.TypeRefundRequested, events.RefundRequested{RequestID: "r-1", OrderID: 2, Amount: 40}, false, events.TypeRefundSucceeded},
+		{"protobuf", envelope.ContentTypeProtobuf, events.TypeRefundRequested, events.RefundRequested{RequestID: "r-1", OrderID: 2, Amount: 40}, false, events.TypeRefundSucceeded},
+		{"rejected", envelope.ContentTypeJSON, events.TypeRefundRequested, events.RefundRequested{RequestID: "r-1", OrderID: 2, Amount: 500}, false, events.TypeRefundFailed},
+		{"invalid command", envelope.ContentTypeJSON, events.TypeRefundRequested, events.RefundRequested{OrderID: 2}, true, ""},
+		{"unknown type", envelope.ContentTypeJSON, "refund cancelled", events.RefundRequested{RequestID: "r-1", OrderID: 2}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			authID, _ := fake.Authorize(context.Background(), "authorize:2:0", record.Payment)
+			_ = fake.Capture(context.Background(), "capture:"+authID, authID, record.Amount)
+
+			tx := &txMock{record: record}
+			st := &storageMock{tx: tx}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := services.New(st, fake, services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+			ctrl := NewRefundController(*svc, log, nil)
+
+			body, _ := envelope.MarshalPayload(tt.contentType, &tt.command)
+			env := envelope.Envelope{
+				ID:      "admin:1",
+				Type:    tt.eventType,
+				Version: events.SchemaVersion(tt.eventType),
+				Source:  "admin",
+				Payload: body,
+			}
+			message, _ := json.Marshal(env)
+			if tt.contentType == envelope.ContentTypeProtobuf {
+				message = env.MarshalProto()
+			}
+
+			err := ctrl.HandleMessage(context.Background(), tt.contentType, message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error: got %v, want error %v", err, tt.wantErr)
+			}
+			if tx.savedType != tt.wantSaved {
+				t.Fatalf("saved event type: got %q, want %q", tx.savedType, tt.wantSaved)
+			}
+			if tt.wantSaved != "" && (tx.savedRefund.RequestID != tt.command.RequestID || tx.savedRefund.Amount != tt.command.Amount) {
+				t.Fatalf("saved refund: got %+v", tx.savedRefund)
+			}
+		})
+	}
+}
+
+func TestController_HandleMessage_Envelope(t *testing.T) {
+	tx := &txMock{}
+	st := &storageMock{tx: tx}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+	ctrl := NewController(*svc, log, nil)
+
+	body, _ := json.Marshal(dto.PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100})
+	message, _ := json.Marshal(envelope.Envelope{
+		ID:            "orders:1",
+		Type:          "payment requested",
+		Version:       events.SchemaVersion("payment requested"),
+		OccurredAt:    time.Now(),
+		Source:        "orders",
+		CorrelationID: "order-2",
+		Payload:       body,
+	})
+
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.saveCalled != 1 {
+		t.Fatalf("save calls: got %d, want %d", tx.saveCalled, 1)
+	}
+	if tx.savedMeta.CorrelationID != "order-2" || tx.savedMeta.CausationID != "orders:1" {
+		t.Fatalf("meta: got %+v", tx.savedMeta)
+	}
+
+	future, _ := json.Marshal(envelope.Envelope{
+		ID:      "orders:2",
+		Type:    "payment requested",
+		Version: events.SchemaVersion("payment requested") + 1,
+		Payload: body,
+	})
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, future); !errors.Is(err, envelope.ErrUnsupportedVersion) {
+		t.Fatalf("expected unsupported version error, got %v", err)
+	}
+}
+
+func TestController_HandleMessage_Protobuf(t *testing.T) {
+	tx := &txMock{}
+	st := &storageMock{tx: tx}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+	ctrl := NewController(*svc, log, nil)
+
+	body := events.PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100, RequestedAt: time.Now()}
+	env := envelope.Envelope{
+		ID:            "orders:1",
+		Type:          "payment requested",
+		Version:       events.SchemaVersion("payment requested"),
+		Source:        "orders",
+		CorrelationID: "order-2",
+		Payload:       body.MarshalProto(),
+	}
+
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeProtobuf, env.MarshalProto()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.saveCalled != 1 {
+		t.Fatalf("save calls: got %d, want %d", tx.saveCalled, 1)
+	}
+	if tx.savedMeta.CausationID != "orders:1" {
+		t.Fatalf("meta: got %+v", tx.savedMeta)
+	}
+
+	if err := ctrl.HandleMessage(context.Background(), "text/xml", env.MarshalProto()); !errors.Is(err, envelope.ErrUnsupportedContentType) {
+		t.Fatalf("expected unsupported content type error, got %v", err)
+	}
+}
+
+type schemasMock struct {
+	err error
+}
+
+func (m *schemasMock) ValidateEnvelope(env envelope.Envelope) error {
+	return m.err
+}
+
+func TestController_HandleMessage_SchemaMismatch(t *testing.T) {
+	tx := &txMock{}
+	st := &storageMock{tx: tx}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+	schemaErr := errors.New("payload does not match schema")
+	ctrl := NewController(*svc, log, &schemasMock{err: schemaErr})
+
+	payload, _ := json.Marshal(dto.PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100})
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, payload); !errors.Is(err, schemaErr) {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+	if tx.saveCalled != 0 {
+		t.Fatalf("save calls: got %d, want %d", tx.saveCalled, 0)
+	}
+}
+
 func TestController_HandleMessage_ServiceError(t *testing.T) {
 	st := &storageMock{tx: &txMock{}}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := services.New(st, log, "event-status")
-	ctrl := NewController(*svc, log)
+	svc := services.New(st, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, "event-status")
+	ctrl := NewController(*svc, log, nil)
 
-	input := dto.OrderCreated{EventID: 0, OrderID: 2, UserID: 3, TotalAmount: 100}
-	payload, _ := json.Marshal(input)
+	message := wrap(events.TypePaymentRequested, dto.PaymentRequested{EventID: 0, OrderID: 2, UserID: 3, TotalAmount: 100})
 
-	if err := ctrl.HandleMessage(context.Background(), payload); err == nil || !errors.Is(err, domain.ErrInvalidEventID) {
+	if err := ctrl.HandleMessage(context.Background(), envelope.ContentTypeJSON, message); err == nil || !errors.Is(err, domain.ErrInvalidEventID) {
 		t.Fatalf("expected invalid event id error, got %v", err)
 	}
 }
+
+func wrap(eventType string, payload any) []byte {
+	body, _ := json.Marshal(payload)
+	message, _ := json.Marshal(envelope.Envelope{
+		ID:         "orders:1",
+		Type:       eventType,
+		Version:    events.SchemaVersion(eventType),
+		OccurredAt: time.Now(),
+		Source:     "orders",
+		Payload:    body,
+	})
+	return message
+}
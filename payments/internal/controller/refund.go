@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services"
+)
+
+// RefundController handles refund requested commands from the refund topic.
+type RefundController struct {
+	service services.Service
+	log     *slog.Logger
+	schemas Validator
+}
+
+// NewRefundController builds the handler of the refund topic. schemas may be
+// nil to skip payload validation.
+func NewRefundController(service services.Service, log *slog.Logger, schemas Validator) *RefundController {
+	return &RefundController{
+		service: service,
+		log:     log,
+		schemas: schemas,
+	}
+}
+
+// HandleMessage applies a refund command. A refund rejected for business
+// reasons is already published as RefundFailed and is not an error here.
+func (h *RefundController) HandleMessage(parentCtx context.Context, contentType string, message []byte) error {
+	const op = "controller.RefundController.HandleMessage"
+	log := h.log.With(slog.String("op", op))
+
+	env, err := events.DecodeEnvelope(contentType, message)
+	if err != nil {
+		log.Error("decode envelope", slog.Any("err", err))
+		return fmt.Errorf("%s: decode envelope: %w", op, err)
+	}
+	if env.Type != "" && env.Type != events.TypeRefundRequested {
+		log.Warn("unexpected event type skipped", slog.String("type", env.Type))
+		return nil
+	}
+	if h.schemas != nil {
+		if err := h.schemas.ValidateEnvelope(env); err != nil {
+			log.Error("validate message", slog.Any("err", err))
+			return fmt.Errorf("%s: validate message: %w", op, err)
+		}
+	}
+
+	var command events.RefundRequested
+	if err := envelope.UnmarshalPayload(env, &command); err != nil {
+		log.Error("decode message", slog.Any("err", err))
+		return fmt.Errorf("%s: decode message: %w", op, err)
+	}
+
+	ctx, cancel := context.WithTimeout(envelope.WithMeta(parentCtx, envelope.CausedBy(env)), 5*time.Second)
+	defer cancel()
+
+	refund, err := h.service.Refund(ctx, dto.RefundRequest{
+		RequestID: command.RequestID,
+		OrderID:   command.OrderID,
+		Amount:    command.Amount,
+		Reason:    command.Reason,
+	})
+	if err != nil {
+		log.Error("handle failed", slog.Any("err", err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Debug("refund handled", slog.String("request_id", refund.RequestID), slog.String("status", refund.Status))
+	return nil
+}
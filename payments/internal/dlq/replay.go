@@ -0,0 +1,206 @@
+package dlq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/kafka_consume"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// HeaderReplayedAt marks messages re-driven from a dead-letter topic.
+const HeaderReplayedAt = "x-replayed-at"
+
+var dlqHeaders = map[string]struct{}{
+	deadletter.HeaderOriginalTopic:     {},
+	deadletter.HeaderOriginalPartition: {},
+	deadletter.HeaderOriginalOffset:    {},
+	deadletter.HeaderError:             {},
+	deadletter.HeaderAttempts:          {},
+	deadletter.HeaderFailedAt:          {},
+	HeaderReplayedAt:                   {},
+}
+
+// Filter selects dead-lettered messages; zero fields match everything.
+type Filter struct {
+	From          time.Time
+	To            time.Time
+	ErrorContains string
+	OrderID       int64
+}
+
+// Entry is a dead-lettered message with its metadata decoded from headers.
+type Entry struct {
+	OriginalTopic     string
+	OriginalPartition int32
+	OriginalOffset    int64
+	Error             string
+	Attempts          int
+	FailedAt          time.Time
+	OrderID           int64
+	Message           *kafka.Message
+}
+
+func Parse(msg *kafka.Message) (Entry, error) {
+	const op = "dlq.Parse"
+
+	entry := Entry{Message: msg}
+	for _, h := range msg.Headers {
+		value := string(h.Value)
+		switch h.Key {
+		case deadletter.HeaderOriginalTopic:
+			entry.OriginalTopic = value
+		case deadletter.HeaderOriginalPartition:
+			partition, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return Entry{}, fmt.Errorf("%s: partition: %w", op, err)
+			}
+			entry.OriginalPartition = int32(partition)
+		case deadletter.HeaderOriginalOffset:
+			offset, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return Entry{}, fmt.Errorf("%s: offset: %w", op, err)
+			}
+			entry.OriginalOffset = offset
+		case deadletter.HeaderError:
+			entry.Error = value
+		case deadletter.HeaderAttempts:
+			attempts, err := strconv.Atoi(value)
+			if err != nil {
+				return Entry{}, fmt.Errorf("%s: attempts: %w", op, err)
+			}
+			entry.Attempts = attempts
+		case deadletter.HeaderFailedAt:
+			failedAt, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return Entry{}, fmt.Errorf("%s: failed at: %w", op, err)
+			}
+			entry.FailedAt = failedAt
+		}
+	}
+
+	if entry.OriginalTopic == "" {
+		return Entry{}, fmt.Errorf("%s: header %s is missing", op, deadletter.HeaderOriginalTopic)
+	}
+
+	// poison messages are often malformed, so order_id is best effort
+	if env, err := events.DecodeEnvelope(kafka_consume.ContentType(msg.Headers), msg.Value); err == nil {
+		if orderID, err := orderIDOf(env); err == nil {
+			entry.OrderID = orderID
+		}
+	}
+
+	return entry, nil
+}
+
+// orderIDOf decodes the order id of a dead-lettered payload by its event
+// type. The payments DLQ holds the commands of the order topic; the
+// notifications DLQ holds payment cancellations, refund results and payment
+// statuses, whose type is configured per deployment and which are the default.
+func orderIDOf(env envelope.Envelope) (int64, error) {
+	switch env.Type {
+	case events.TypePaymentRequested:
+		var e events.PaymentRequested
+		err := envelope.UnmarshalPayload(env, &e)
+		return int64(e.OrderID), err
+	case events.TypeOrderCancelled:
+		var e events.OrderCancelled
+		err := envelope.UnmarshalPayload(env, &e)
+		return int64(e.OrderID), err
+	case events.TypeOrderExpired:
+		var e events.OrderExpired
+		err := envelope.UnmarshalPayload(env, &e)
+		return int64(e.OrderID), err
+	case events.TypePaymentCancelled:
+		var e events.PaymentCancelled
+		err := envelope.UnmarshalPayload(env, &e)
+		return e.OrderID, err
+	case events.TypeRefundRequested:
+		var e events.RefundRequested
+		err := envelope.UnmarshalPayload(env, &e)
+		return e.OrderID, err
+	case events.TypeRefundSucceeded:
+		var e events.RefundSucceeded
+		err := envelope.UnmarshalPayload(env, &e)
+		return e.OrderID, err
+	case events.TypeRefundFailed:
+		var e events.RefundFailed
+		err := envelope.UnmarshalPayload(env, &e)
+		return e.OrderID, err
+	default:
+		var e events.PaymentStatus
+		err := envelope.UnmarshalPayload(env, &e)
+		return e.OrderID, err
+	}
+}
+
+func (f Filter) Match(entry Entry) bool {
+	if !f.From.IsZero() && entry.FailedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !entry.FailedAt.Before(f.To) {
+		return false
+	}
+	if f.ErrorContains != "" && !strings.Contains(entry.Error, f.ErrorContains) {
+		return false
+	}
+	if f.OrderID != 0 && entry.OrderID != f.OrderID {
+		return false
+	}
+	return true
+}
+
+// Progress decides which offsets of the dead-letter topic a replay may commit.
+// A skipped message, one left out by the filter or without dlq metadata, is
+// never committed, and neither is anything after it in its partition: the
+// committed offset stays in front of it, so a later run with the same group
+// still sees it. Messages replayed past it are seen again too; consumers
+// drop the duplicates by event id.
+type Progress struct {
+	held map[int32]bool
+}
+
+func NewProgress() *Progress {
+	return &Progress{held: make(map[int32]bool)}
+}
+
+// Skip records a message of partition left in the topic.
+func (p *Progress) Skip(partition int32) {
+	p.held[partition] = true
+}
+
+// Committable reports whether a message of partition replayed just now may
+// be committed.
+func (p *Progress) Committable(partition int32) bool {
+	return !p.held[partition]
+}
+
+// Replay builds the message to republish: original topic, key and payload,
+// without the dead-letter metadata.
+func Replay(entry Entry, now time.Time) *kafka.Message {
+	topic := entry.OriginalTopic
+
+	headers := make([]kafka.Header, 0, len(entry.Message.Headers)+1)
+	for _, h := range entry.Message.Headers {
+		if _, ok := dlqHeaders[h.Key]; ok {
+			continue
+		}
+		headers = append(headers, h)
+	}
+	headers = append(headers, kafka.Header{Key: HeaderReplayedAt, Value: []byte(now.UTC().Format(time.RFC3339Nano))})
+
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny,
+		},
+		Key:     entry.Message.Key,
+		Value:   entry.Message.Value,
+		Headers: headers,
+	}
+}
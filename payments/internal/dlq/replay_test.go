@@ -0,0 +1,172 @@
+package dlq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func dlqMessage(value, errText string, failedAt time.Time) *kafka.Message {
+	topic := "order-topic.dlq"
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic},
+		Key:            []byte("42"),
+		Value:          []byte(value),
+		Headers: []kafka.Header{
+			{Key: "trace-id", Value: []byte("abc")},
+			{Key: deadletter.HeaderOriginalTopic, Value: []byte("order-topic")},
+			{Key: deadletter.HeaderOriginalPartition, Value: []byte("1")},
+			{Key: deadletter.HeaderOriginalOffset, Value: []byte("99")},
+			{Key: deadletter.HeaderError, Value: []byte(errText)},
+			{Key: deadletter.HeaderAttempts, Value: []byte("3")},
+			{Key: deadletter.HeaderFailedAt, Value: []byte(failedAt.Format(time.RFC3339Nano))},
+		},
+	}
+}
+
+func TestParse(t *testing.T) {
+	failedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	entry, err := Parse(dlqMessage(`{"order_id":42}`, "decode message", failedAt))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if entry.OriginalTopic != "order-topic" || entry.OriginalPartition != 1 || entry.OriginalOffset != 99 {
+		t.Fatalf("unexpected origin: %+v", entry)
+	}
+	if entry.Attempts != 3 || entry.Error != "decode message" || !entry.FailedAt.Equal(failedAt) {
+		t.Fatalf("unexpected metadata: %+v", entry)
+	}
+	if entry.OrderID != 42 {
+		t.Fatalf("order id: got %d, want %d", entry.OrderID, 42)
+	}
+
+	enveloped := `{"id":"orders:1","type":"order created","version":1,"payload":{"order_id":43}}`
+	entry, err = Parse(dlqMessage(enveloped, "decode message", failedAt))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.OrderID != 43 {
+		t.Fatalf("enveloped order id: got %d, want %d", entry.OrderID, 43)
+	}
+
+	if _, err := Parse(&kafka.Message{Value: []byte("{}")}); err == nil {
+		t.Fatalf("expected error for message without dlq headers")
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	failedAt := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	entry, err := Parse(dlqMessage(`{"order_id":42}`, "decode message: invalid character", failedAt))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty filter", Filter{}, true},
+		{"inside range", Filter{From: failedAt.Add(-time.Hour), To: failedAt.Add(time.Hour)}, true},
+		{"before range", Filter{From: failedAt.Add(time.Minute)}, false},
+		{"range end is exclusive", Filter{To: failedAt}, false},
+		{"error substring", Filter{ErrorContains: "invalid character"}, true},
+		{"error mismatch", Filter{ErrorContains: "timeout"}, false},
+		{"order id", Filter{OrderID: 42}, true},
+		{"other order", Filter{OrderID: 7}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(entry); got != tt.want {
+				t.Fatalf("Match: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_MatchNotificationsPayload(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		eventType   string
+		payload     envelope.Message
+	}{
+		{"payment status", envelope.ContentTypeJSON, "event-status", &events.PaymentStatus{EventID: 1, OrderID: 42, UserID: 7, OrderStatus: "paid"}},
+		{"payment cancelled", envelope.ContentTypeJSON, events.TypePaymentCancelled, &events.PaymentCancelled{EventID: 1, OrderID: 42, UserID: 7}},
+		{"refund succeeded", envelope.ContentTypeProtobuf, events.TypeRefundSucceeded, &events.RefundSucceeded{EventID: 1, RefundID: 3, OrderID: 42, UserID: 7}},
+		{"refund failed", envelope.ContentTypeProtobuf, events.TypeRefundFailed, &events.RefundFailed{EventID: 1, OrderID: 42, UserID: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := envelope.MarshalPayload(tt.contentType, tt.payload)
+			if err != nil {
+				t.Fatalf("marshal payload: %v", err)
+			}
+			value, err := envelope.Marshal(tt.contentType, envelope.Envelope{
+				ID: "payments:1", Type: tt.eventType, Version: 1, Source: "payments", Payload: payload,
+			})
+			if err != nil {
+				t.Fatalf("marshal envelope: %v", err)
+			}
+
+			msg := dlqMessage(string(value), "save notification", time.Now())
+			msg.Headers = append(msg.Headers, kafka.Header{Key: envelope.HeaderContentType, Value: []byte(tt.contentType)})
+
+			entry, err := Parse(msg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !(Filter{OrderID: 42}).Match(entry) || (Filter{OrderID: 7}).Match(entry) {
+				t.Fatalf("order id filter: got order id %d, want %d", entry.OrderID, 42)
+			}
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	progress := NewProgress()
+	if !progress.Committable(0) {
+		t.Fatalf("replayed message must be committable before any skip")
+	}
+
+	progress.Skip(0)
+	if progress.Committable(0) {
+		t.Fatalf("commit must not move past a skipped message")
+	}
+	if !progress.Committable(1) {
+		t.Fatalf("a skip must not hold other partitions")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	entry, err := Parse(dlqMessage("{", "decode message", time.Now()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := Replay(entry, time.Now())
+	if *msg.TopicPartition.Topic != "order-topic" {
+		t.Fatalf("topic: got %s, want %s", *msg.TopicPartition.Topic, "order-topic")
+	}
+	if string(msg.Value) != "{" || string(msg.Key) != "42" {
+		t.Fatalf("payload and key must be preserved")
+	}
+
+	keys := make(map[string]bool, len(msg.Headers))
+	for _, h := range msg.Headers {
+		keys[h.Key] = true
+	}
+	if keys[deadletter.HeaderError] || keys[deadletter.HeaderOriginalTopic] {
+		t.Fatalf("dlq headers must be stripped: %v", keys)
+	}
+	if !keys["trace-id"] || !keys[HeaderReplayedAt] {
+		t.Fatalf("expected trace-id and %s headers: %v", HeaderReplayedAt, keys)
+	}
+}
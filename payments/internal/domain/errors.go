@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"errors"
 )
 
@@ -10,9 +11,33 @@ var (
 	ErrInvalidProductID = errors.New("product id must be positive")
 	ErrInvalidQuantity  = errors.New("quantity must be at least one")
 	ErrInvalidPrice     = errors.New("price must be positive")
+	ErrInvalidCurrency  = errors.New("currency must be an ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+	ErrAmountOverflow   = errors.New("amount is out of range")
 
 	ErrInvalidEventID = errors.New("event id must not be zero value")
 	ErrInvalidItems   = errors.New("items must not be empty")
 	ErrOrderNotFound  = errors.New("order not found")
 	ErrUnknownType    = errors.New("unknown type")
+
+	ErrPaymentDeclined      = errors.New("payment declined")
+	ErrInvalidAmount        = errors.New("amount must be positive")
+	ErrUnknownAuthorization = errors.New("unknown authorization")
+	ErrUnknownProvider      = errors.New("unknown payment provider")
+
+	ErrInvalidRequestID     = errors.New("request id must not be empty")
+	ErrInvalidRefundAmount  = errors.New("refund amount must not be negative")
+	ErrPaymentNotFound      = errors.New("payment not found")
+	ErrPaymentNotRefundable = errors.New("payment is not succeeded")
+	ErrRefundExceedsPayment = errors.New("refund exceeds the refundable amount")
+	ErrRefundRequestReused  = errors.New("request id is already used for another order")
+
+	// ErrTransient marks provider failures worth retrying (timeouts,
+	// unavailability); every other provider error is terminal.
+	ErrTransient = errors.New("transient provider error")
 )
+
+// IsTransient reports whether a provider error may succeed on retry.
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrTransient) || errors.Is(err, context.DeadlineExceeded)
+}
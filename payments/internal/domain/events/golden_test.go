@@ -0,0 +1,37 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/testkit/golden"
+)
+
+// goldenDir holds the protoc encoding of every message in
+// proto/opp/events/v1; make proto-golden regenerates it from the .txtpb files.
+const goldenDir = "../../../../proto/opp/events/v1/testdata"
+
+func TestProto_Golden(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 500000000, time.UTC)
+
+	golden.Check(t, goldenDir, []golden.Case{
+		{Message: "OrderCancelled", Got: &OrderCancelled{}, Want: &OrderCancelled{
+			EventID: 42, OrderID: 7, UserID: 3, From: "paid", Reason: "duplicate", CancelledAt: at,
+		}},
+		{Message: "OrderExpired", Got: &OrderExpired{}, Want: &OrderExpired{
+			EventID: 42, OrderID: 7, UserID: 3, DeadlineAt: time.Date(2025, 12, 31, 23, 50, 0, 0, time.UTC), ExpiredAt: at,
+		}},
+		{Message: "PaymentRequested", Got: &PaymentRequested{}, Want: &PaymentRequested{
+			EventID: 42, OrderID: 7, UserID: 3, TotalAmount: 12500, Currency: "RUB", RequestedAt: at,
+		}},
+		{Message: "PaymentStatus", Got: &PaymentStatus{}, Want: &PaymentStatus{EventID: 42, OrderID: 7, UserID: 3, OrderStatus: "succeeded", Currency: "RUB"}},
+		{Message: "PaymentCancelled", Got: &PaymentCancelled{}, Want: &PaymentCancelled{EventID: 42, OrderID: 7, UserID: 3, Outcome: "refunded", Amount: 12500}},
+		{Message: "RefundRequested", Got: &RefundRequested{}, Want: &RefundRequested{RequestID: "rf-1", OrderID: 7, Amount: 500, Reason: "damaged"}},
+		{Message: "RefundSucceeded", Got: &RefundSucceeded{}, Want: &RefundSucceeded{
+			EventID: 42, RefundID: 9, RequestID: "rf-1", OrderID: 7, UserID: 3, Amount: 500, RefundedTotal: 1500,
+		}},
+		{Message: "RefundFailed", Got: &RefundFailed{}, Want: &RefundFailed{
+			EventID: 42, RefundID: 9, RequestID: "rf-1", OrderID: 7, UserID: 3, Amount: 500, Reason: "exceeds captured amount",
+		}},
+	})
+}
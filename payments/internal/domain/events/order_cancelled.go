@@ -0,0 +1,49 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+)
+
+type OrderCancelled struct {
+	EventID     int64     `json:"event_id"`
+	OrderID     domain.ID `json:"order_id"`
+	UserID      domain.ID `json:"user_id"`
+	From        string    `json:"from"`
+	Reason      string    `json:"reason,omitempty"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+func (e *OrderCancelled) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendString(b, 4, e.From)
+	b = wire.AppendString(b, 5, e.Reason)
+	b = wire.AppendTime(b, 6, e.CancelledAt)
+	return b
+}
+
+func (e *OrderCancelled) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.From = f.Text()
+		case 5:
+			e.Reason = f.Text()
+		case 6:
+			e.CancelledAt, err = f.Time()
+		}
+		return err
+	})
+}
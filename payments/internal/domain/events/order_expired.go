@@ -0,0 +1,45 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+)
+
+type OrderExpired struct {
+	EventID    int64     `json:"event_id"`
+	OrderID    domain.ID `json:"order_id"`
+	UserID     domain.ID `json:"user_id"`
+	DeadlineAt time.Time `json:"deadline_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
+
+func (e *OrderExpired) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, int64(e.OrderID))
+	b = wire.AppendInt64(b, 3, int64(e.UserID))
+	b = wire.AppendTime(b, 4, e.DeadlineAt)
+	b = wire.AppendTime(b, 5, e.ExpiredAt)
+	return b
+}
+
+func (e *OrderExpired) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		var err error
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = domain.ID(f.Int64())
+		case 3:
+			e.UserID = domain.ID(f.Int64())
+		case 4:
+			e.DeadlineAt, err = f.Time()
+		case 5:
+			e.ExpiredAt, err = f.Time()
+		}
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package events
+
+import "time"
+
+// Event types exchanged with orders and notifications. Payment results keep
+// the type configured by KAFKA_EVENT_TYPE.
+const (
+	TypeOrderCreated     = "order created"
+	TypePaymentRequested = "payment requested"
+	TypeOrderCancelled   = "order cancelled"
+	TypeOrderExpired     = "order expired"
+	TypePaymentCancelled = "payment cancelled"
+	TypeRefundRequested  = "refund requested"
+	TypeRefundSucceeded  = "refund succeeded"
+	TypeRefundFailed     = "refund failed"
+
+	// TypePaymentStatus is the default KAFKA_EVENT_TYPE of payment results.
+	TypePaymentStatus = "event-status"
+)
+
+// Outbox is a row of the events table as handed to the sender.
+type Outbox struct {
+	EventID     int64
+	EventType   string
+	AggregateID int64
+	Payload     []byte
+
+	CreatedAt     time.Time
+	CorrelationID string
+	CausationID   string
+}
@@ -0,0 +1,42 @@
+package events
+
+import "github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+
+// PaymentCancelled reports how the payment of a cancelled order was undone:
+// Outcome is domain.StatusVoided when nothing was captured, otherwise
+// domain.StatusRefunded with the refunded Amount.
+type PaymentCancelled struct {
+	EventID int64  `json:"event_id"`
+	OrderID int64  `json:"order_id"`
+	UserID  int64  `json:"user_id"`
+	Outcome string `json:"outcome"`
+	Amount  int64  `json:"amount"`
+}
+
+func (e *PaymentCancelled) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, e.OrderID)
+	b = wire.AppendInt64(b, 3, e.UserID)
+	b = wire.AppendString(b, 4, e.Outcome)
+	b = wire.AppendInt64(b, 5, e.Amount)
+	return b
+}
+
+func (e *PaymentCancelled) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.OrderID = f.Int64()
+		case 3:
+			e.UserID = f.Int64()
+		case 4:
+			e.Outcome = f.Text()
+		case 5:
+			e.Amount = f.Int64()
+		}
+		return nil
+	})
+}
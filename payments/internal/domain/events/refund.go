@@ -0,0 +1,95 @@
+package events
+
+import "github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+
+// RefundSucceeded reports money returned for an order. RefundedTotal is the
+// amount refunded for the order so far, this refund included.
+type RefundSucceeded struct {
+	EventID       int64  `json:"event_id"`
+	RefundID      int64  `json:"refund_id"`
+	RequestID     string `json:"request_id"`
+	OrderID       int64  `json:"order_id"`
+	UserID        int64  `json:"user_id"`
+	Amount        int64  `json:"amount"`
+	RefundedTotal int64  `json:"refunded_total"`
+}
+
+func (e *RefundSucceeded) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, e.RefundID)
+	b = wire.AppendString(b, 3, e.RequestID)
+	b = wire.AppendInt64(b, 4, e.OrderID)
+	b = wire.AppendInt64(b, 5, e.UserID)
+	b = wire.AppendInt64(b, 6, e.Amount)
+	b = wire.AppendInt64(b, 7, e.RefundedTotal)
+	return b
+}
+
+func (e *RefundSucceeded) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.RefundID = f.Int64()
+		case 3:
+			e.RequestID = f.Text()
+		case 4:
+			e.OrderID = f.Int64()
+		case 5:
+			e.UserID = f.Int64()
+		case 6:
+			e.Amount = f.Int64()
+		case 7:
+			e.RefundedTotal = f.Int64()
+		}
+		return nil
+	})
+}
+
+// RefundFailed reports a refund request that was rejected or declined by the
+// provider; Reason says why.
+type RefundFailed struct {
+	EventID   int64  `json:"event_id"`
+	RefundID  int64  `json:"refund_id"`
+	RequestID string `json:"request_id"`
+	OrderID   int64  `json:"order_id"`
+	UserID    int64  `json:"user_id"`
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason"`
+}
+
+func (e *RefundFailed) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendInt64(b, 1, e.EventID)
+	b = wire.AppendInt64(b, 2, e.RefundID)
+	b = wire.AppendString(b, 3, e.RequestID)
+	b = wire.AppendInt64(b, 4, e.OrderID)
+	b = wire.AppendInt64(b, 5, e.UserID)
+	b = wire.AppendInt64(b, 6, e.Amount)
+	b = wire.AppendString(b, 7, e.Reason)
+	return b
+}
+
+func (e *RefundFailed) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			e.EventID = f.Int64()
+		case 2:
+			e.RefundID = f.Int64()
+		case 3:
+			e.RequestID = f.Text()
+		case 4:
+			e.OrderID = f.Int64()
+		case 5:
+			e.UserID = f.Int64()
+		case 6:
+			e.Amount = f.Int64()
+		case 7:
+			e.Reason = f.Text()
+		}
+		return nil
+	})
+}
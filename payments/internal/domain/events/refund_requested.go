@@ -0,0 +1,37 @@
+package events
+
+import "github.com/ChernykhITMO/order-processing-platform/eventkit/wire"
+
+// RefundRequested is the command read from the refund topic. Amount zero
+// refunds everything not refunded yet.
+type RefundRequested struct {
+	RequestID string `json:"request_id"`
+	OrderID   int64  `json:"order_id"`
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (e *RefundRequested) MarshalProto() []byte {
+	var b []byte
+	b = wire.AppendString(b, 1, e.RequestID)
+	b = wire.AppendInt64(b, 2, e.OrderID)
+	b = wire.AppendInt64(b, 3, e.Amount)
+	b = wire.AppendString(b, 4, e.Reason)
+	return b
+}
+
+func (e *RefundRequested) UnmarshalProto(b []byte) error {
+	return wire.ReadFields(b, func(f wire.Field) error {
+		switch f.Num {
+		case 1:
+			e.RequestID = f.Text()
+		case 2:
+			e.OrderID = f.Int64()
+		case 3:
+			e.Amount = f.Int64()
+		case 4:
+			e.Reason = f.Text()
+		}
+		return nil
+	})
+}
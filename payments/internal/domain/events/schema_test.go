@@ -0,0 +1,38 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/schema"
+)
+
+const registryDir = "../../../../schemas/events"
+
+func TestSchema_Payloads(t *testing.T) {
+	r, err := schema.Load(registryDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	status, _ := json.Marshal(PaymentStatus{EventID: 1, OrderID: 2, UserID: 3, OrderStatus: "succeeded", Currency: "RUB"})
+	requested, _ := json.Marshal(PaymentRequested{EventID: 1, OrderID: 2, UserID: 3, TotalAmount: 100, Currency: "RUB", RequestedAt: time.Now()})
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   []byte
+	}{
+		{"published payment status", TypePaymentStatus, status},
+		{"consumed payment requested", TypePaymentRequested, requested},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Validate(tt.eventType, SchemaVersion(tt.eventType), tt.payload); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultCurrency is assumed for amounts recorded before currencies were
+// tracked and for order events that do not name one.
+const DefaultCurrency Currency = "RUB"
+
+// Currency is an ISO 4217 alphabetic code.
+type Currency string
+
+func (c Currency) Valid() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for i := 0; i < len(c); i++ {
+		if c[i] < 'A' || c[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Money is an amount in minor units (kopecks, cents) of Currency.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// NewMoney builds an amount in currency; an empty currency means
+// DefaultCurrency.
+func NewMoney(amount int64, currency string) (Money, error) {
+	const op = "domain.Money.New"
+
+	m := Money{Amount: amount, Currency: Currency(currency)}
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+
+	if !m.Currency.Valid() {
+		return Money{}, fmt.Errorf("%s: %w", op, ErrInvalidCurrency)
+	}
+
+	return m, nil
+}
+
+// Add sums two amounts of the same currency. The zero Money takes the
+// currency of the other operand, so it can seed a running total.
+func (m Money) Add(other Money) (Money, error) {
+	const op = "domain.Money.Add"
+
+	switch {
+	case m.Currency == "":
+		m.Currency = other.Currency
+	case other.Currency != "" && other.Currency != m.Currency:
+		return Money{}, fmt.Errorf("%s: %w: %s and %s", op, ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%s: %w", op, ErrAmountOverflow)
+	}
+
+	m.Amount = sum
+	return m, nil
+}
+
+// Times multiplies the amount by quantity, keeping the currency.
+func (m Money) Times(quantity int32) (Money, error) {
+	const op = "domain.Money.Times"
+
+	q := int64(quantity)
+	product := m.Amount * q
+	// MinInt64 * -1 wraps to itself, so the division check alone misses it.
+	if q != 0 && (product/q != m.Amount || (m.Amount == math.MinInt64 && q == -1)) {
+		return Money{}, fmt.Errorf("%s: %w", op, ErrAmountOverflow)
+	}
+
+	m.Amount = product
+	return m, nil
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		name      string
+		currency  string
+		want      Money
+		wantErrIs error
+	}{
+		{"explicit currency", "USD", Money{Amount: 100, Currency: "USD"}, nil},
+		{"default currency", "", Money{Amount: 100, Currency: DefaultCurrency}, nil},
+		{"lower case", "usd", Money{}, ErrInvalidCurrency},
+		{"too long", "USDT", Money{}, ErrInvalidCurrency},
+		{"digits", "840", Money{}, ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMoney(100, tt.currency)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if got != tt.want {
+				t.Fatalf("money: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Money
+		want      Money
+		wantErrIs error
+	}{
+		{"same currency", Money{100, "RUB"}, Money{50, "RUB"}, Money{150, "RUB"}, nil},
+		{"zero value takes currency", Money{}, Money{50, "EUR"}, Money{50, "EUR"}, nil},
+		{"mixed currencies", Money{100, "RUB"}, Money{50, "USD"}, Money{}, ErrCurrencyMismatch},
+		{"up to max", Money{math.MaxInt64 - 1, "RUB"}, Money{1, "RUB"}, Money{math.MaxInt64, "RUB"}, nil},
+		{"overflow", Money{math.MaxInt64, "RUB"}, Money{1, "RUB"}, Money{}, ErrAmountOverflow},
+		{"underflow", Money{math.MinInt64, "RUB"}, Money{-1, "RUB"}, Money{}, ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if got != tt.want {
+				t.Fatalf("sum: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Times(t *testing.T) {
+	tests := []struct {
+		name      string
+		m         Money
+		quantity  int32
+		want      Money
+		wantErrIs error
+	}{
+		{"regular", Money{150, "RUB"}, 3, Money{450, "RUB"}, nil},
+		{"zero quantity", Money{math.MaxInt64, "RUB"}, 0, Money{0, "RUB"}, nil},
+		{"large", Money{math.MaxInt64 / 2, "RUB"}, 2, Money{math.MaxInt64 - 1, "RUB"}, nil},
+		{"overflow", Money{math.MaxInt64/2 + 1, "RUB"}, 2, Money{}, ErrAmountOverflow},
+		{"max quantity overflow", Money{math.MaxInt64 / 1000, "RUB"}, math.MaxInt32, Money{}, ErrAmountOverflow},
+		{"min times minus one", Money{math.MinInt64, "RUB"}, -1, Money{}, ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Times(tt.quantity)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if got != tt.want {
+				t.Fatalf("product: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
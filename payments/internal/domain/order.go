@@ -31,7 +31,9 @@ func NewOrder(
 		UpdatedAt: updatedAt,
 	}
 
-	o.calculate()
+	if err := o.calculate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
 	if err := o.validate(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -54,9 +56,17 @@ func (o *Order) validate() error {
 	return nil
 }
 
-func (o *Order) calculate() {
-	o.TotalAmount = 0
+func (o *Order) calculate() error {
+	var total Money
 	for _, it := range o.Items {
-		o.TotalAmount += it.Price * Money(it.Quantity)
+		line, err := it.Price.Times(it.Quantity)
+		if err != nil {
+			return err
+		}
+		if total, err = total.Add(line); err != nil {
+			return err
+		}
 	}
+	o.TotalAmount = total
+	return nil
 }
@@ -0,0 +1,51 @@
+package domain
+
+import "fmt"
+
+// Payment is a charge request passed to a payment provider.
+type Payment struct {
+	OrderID int64
+	UserID  int64
+	Amount  int64
+	// Currency is the ISO 4217 code Amount is expressed in.
+	Currency Currency
+	// Attempts counts authorizations that already failed transiently.
+	Attempts int
+}
+
+// PaymentRecord is the stored state of an order's payment.
+type PaymentRecord struct {
+	Payment
+	Status string
+	AuthID string
+	// Refunded is the part of Amount already returned to the customer.
+	Refunded int64
+}
+
+// Cancelled reports whether the payment was voided or refunded in full, so
+// nothing is left to charge or refund.
+func (r PaymentRecord) Cancelled() bool {
+	return r.Status == StatusVoided || r.Status == StatusRefunded
+}
+
+// Refundable is the captured amount that has not been refunded yet.
+func (r PaymentRecord) Refundable() int64 {
+	if r.Status != StatusSucceeded {
+		return 0
+	}
+	return r.Amount - r.Refunded
+}
+
+// DeclineError is returned by a provider when it refuses the payment.
+// It is a business outcome rather than a technical failure.
+type DeclineError struct {
+	Reason string
+}
+
+func (e *DeclineError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPaymentDeclined, e.Reason)
+}
+
+func (e *DeclineError) Unwrap() error {
+	return ErrPaymentDeclined
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Refund returns money of a succeeded payment back to the customer. Status is
+// StatusPaymentPending while the provider is being called, then
+// StatusSucceeded or StatusFailed; a failed refund keeps the rejection in
+// Error. RequestID is chosen by the caller and makes retries idempotent.
+type Refund struct {
+	ID        int64
+	RequestID string
+	OrderID   int64
+	Amount    int64
+	Status    string
+	Reason    string
+	Error     string
+	CreatedAt time.Time
+}
+
+// Succeeded reports whether the money was returned.
+func (r Refund) Succeeded() bool {
+	return r.Status == StatusSucceeded
+}
+
+// Pending reports whether the provider outcome is not stored yet.
+func (r Refund) Pending() bool {
+	return r.Status == StatusPaymentPending
+}
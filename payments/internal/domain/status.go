@@ -6,4 +6,11 @@ const (
 	StatusPaymentPending string = "pending"
 	StatusSucceeded      string = "succeeded"
 	StatusFailed         string = "failed"
+	StatusRetrying       string = "retrying"
+	// StatusVoided and StatusRefunded are final states of a cancelled order.
+	StatusVoided   string = "voided"
+	StatusRefunded string = "refunded"
+	// StatusCancelling marks a payment cancelled while a charge was in
+	// flight; it is voided or refunded once that charge is given back.
+	StatusCancelling string = "cancelling"
 )
@@ -2,10 +2,27 @@ package dto
 
 import "time"
 
-type OrderCreated struct {
+// PaymentRequested asks payments to charge an order whose stock is reserved.
+type PaymentRequested struct {
 	EventID     int64     `json:"event_id"`
 	OrderID     int64     `json:"order_id"`
 	UserID      int64     `json:"user_id"`
 	TotalAmount int64     `json:"total_amount"`
-	CreatedAt   time.Time `json:"created_at"`
+	Currency    string    `json:"currency"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+type OrderCancelled struct {
+	EventID int64  `json:"event_id"`
+	OrderID int64  `json:"order_id"`
+	UserID  int64  `json:"user_id"`
+	Reason  string `json:"reason"`
+}
+
+// OrderExpired tells payments that an order stayed unpaid past its payment
+// deadline.
+type OrderExpired struct {
+	EventID int64 `json:"event_id"`
+	OrderID int64 `json:"order_id"`
+	UserID  int64 `json:"user_id"`
 }
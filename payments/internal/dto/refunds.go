@@ -0,0 +1,10 @@
+package dto
+
+// RefundRequest asks to return Amount of the order's payment; zero refunds
+// everything not refunded yet. RequestID makes retries idempotent.
+type RefundRequest struct {
+	RequestID string `json:"request_id"`
+	OrderID   int64  `json:"order_id"`
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason"`
+}
@@ -7,18 +7,27 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type Checker func(context.Context) error
 
 type Server struct {
-	addr    string
-	log     *slog.Logger
-	checker Checker
+	addr     string
+	log      *slog.Logger
+	checker  Checker
+	handlers map[string]http.Handler
 }
 
 func NewServer(addr string, log *slog.Logger, checker Checker) *Server {
-	return &Server{addr: addr, log: log, checker: checker}
+	return &Server{addr: addr, log: log, checker: checker, handlers: make(map[string]http.Handler)}
+}
+
+// Handle mounts an extra handler, such as the admin endpoints, next to the
+// probes. It must be called before Run.
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.handlers[pattern] = h
 }
 
 func (s *Server) Run(ctx context.Context) error {
@@ -26,6 +35,7 @@ func (s *Server) Run(ctx context.Context) error {
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	})
+	mux.Handle("/metrics", promhttp.Handler())
 	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
 		checkCtx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 		defer cancel()
@@ -41,6 +51,10 @@ func (s *Server) Run(ctx context.Context) error {
 		writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
 	})
 
+	for pattern, h := range s.handlers {
+		mux.Handle(pattern, h)
+	}
+
 	srv := &http.Server{
 		Addr:              s.addr,
 		Handler:           mux,
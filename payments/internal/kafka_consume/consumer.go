@@ -7,6 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/metrics"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
@@ -15,19 +18,26 @@ const (
 	readTimeout      = time.Second
 )
 
+// serviceName labels the dead-letter metric.
+const serviceName = "payments"
+
 type Handler interface {
-	HandleMessage(ctx context.Context, message []byte) error
+	HandleMessage(ctx context.Context, contentType string, message []byte) error
 }
 
 type Consumer struct {
 	consumer *kafka.Consumer
 	sender   Handler
+	producer deadletter.Producer
+	dlq      deadletter.Config
 	log      *slog.Logger
 	topic    string
 	group    string
 }
 
-func NewConsumer(sender Handler, address []string, topic, consumerGroup string, log *slog.Logger) (*Consumer, error) {
+func NewConsumer(
+	sender Handler, producer deadletter.Producer, dlq deadletter.Config,
+	address []string, topic, consumerGroup string, log *slog.Logger) (*Consumer, error) {
 	const op = "kafka_produce.Consumer.New"
 	cfg := &kafka.ConfigMap{
 		"bootstrap.servers":  strings.Join(address, ","),
@@ -46,7 +56,19 @@ func NewConsumer(sender Handler, address []string, topic, consumerGroup string,
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Consumer{consumer: c, sender: sender, log: log, topic: topic, group: consumerGroup}, nil
+	if dlq.Topic == "" {
+		dlq.Topic = deadletter.Topic(topic)
+	}
+
+	return &Consumer{
+		consumer: c,
+		sender:   sender,
+		producer: producer,
+		dlq:      dlq,
+		log:      log,
+		topic:    topic,
+		group:    consumerGroup,
+	}, nil
 }
 
 func (c *Consumer) Start(ctx context.Context) {
@@ -87,8 +109,18 @@ func (c *Consumer) Start(ctx context.Context) {
 			continue
 		}
 
-		if err := c.sender.HandleMessage(ctx, kafkaMsg.Value); err != nil {
-			log.Error("handle message failed", slog.Any("err", err))
+		msgLog := log.With(
+			slog.Int("partition", int(kafkaMsg.TopicPartition.Partition)),
+			slog.Int64("offset", int64(kafkaMsg.TopicPartition.Offset)))
+
+		if err := c.handle(ctx, kafkaMsg, msgLog); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			msgLog.Error("message not handled, rewinding", slog.Any("err", err))
+			if err := c.consumer.Seek(kafkaMsg.TopicPartition, int(readTimeout.Milliseconds())); err != nil {
+				msgLog.Error("seek failed", slog.Any("err", err))
+			}
 			continue
 		}
 
@@ -98,6 +130,29 @@ func (c *Consumer) Start(ctx context.Context) {
 	}
 }
 
+// handle runs the handler under the consumer's dead-letter policy. A nil
+// error means the offset may be committed.
+func (c *Consumer) handle(ctx context.Context, msg *kafka.Message, log *slog.Logger) error {
+	dead, err := deadletter.Handle(ctx, msg, c.dlq, c.producer, log, func(ctx context.Context) error {
+		return c.sender.HandleMessage(ctx, ContentType(msg.Headers), msg.Value)
+	})
+	if dead {
+		metrics.DLQMessagesTotal.WithLabelValues(serviceName, c.topic).Inc()
+	}
+	return err
+}
+
+// ContentType returns the content-type header value; producers that predate
+// the header send JSON.
+func ContentType(headers []kafka.Header) string {
+	for _, h := range headers {
+		if h.Key == envelope.HeaderContentType {
+			return string(h.Value)
+		}
+	}
+	return envelope.ContentTypeJSON
+}
+
 func (c *Consumer) Stop() error {
 	c.log.Info("consumer stopping")
 	return c.consumer.Close()
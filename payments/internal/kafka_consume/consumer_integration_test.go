@@ -10,15 +10,20 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/deadletter"
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/controller"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
 	kafkaproduce "github.com/ChernykhITMO/order-processing-platform/payments/internal/kafka_produce"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/provider"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/services"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -113,10 +118,16 @@ func TestPaymentsKafkaConsumer_Integration(t *testing.T) {
 	}()
 
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := services.New(storage, log, eventType)
-	ctrl := controller.NewController(*svc, log)
+	svc := services.New(storage, provider.NewFake(), services.RetryPolicy{MaxAttempts: 1}, log, eventType)
+	ctrl := controller.NewController(*svc, log, nil)
 
-	consumer, err := NewConsumer(ctrl, brokers, topic, "test-group-"+time.Now().Format("150405.000"), log)
+	dlqProducer, err := kafkaproduce.NewProducer(brokers)
+	if err != nil {
+		t.Fatalf("new dlq producer: %v", err)
+	}
+	defer dlqProducer.Close()
+
+	consumer, err := NewConsumer(ctrl, dlqProducer, deadletter.Config{MaxAttempts: 1}, brokers, topic, "test-group-"+time.Now().Format("150405.000"), log)
 	if err != nil {
 		t.Fatalf("new consumer: %v", err)
 	}
@@ -136,21 +147,32 @@ func TestPaymentsKafkaConsumer_Integration(t *testing.T) {
 	defer producer.Close()
 
 	orderID := int64(200)
-	event := dto.OrderCreated{
+	event := dto.PaymentRequested{
 		EventID:     time.Now().UnixNano(),
 		OrderID:     orderID,
 		UserID:      10,
 		TotalAmount: 999,
-		CreatedAt:   time.Now(),
+		RequestedAt: time.Now(),
 	}
 
-	payload, err := json.Marshal(event)
+	body, err := json.Marshal(event)
 	if err != nil {
 		t.Fatalf("marshal event: %v", err)
 	}
+	payload, err := json.Marshal(envelope.Envelope{
+		ID:         envelope.ID("orders", event.EventID),
+		Type:       events.TypePaymentRequested,
+		Version:    events.SchemaVersion(events.TypePaymentRequested),
+		OccurredAt: event.RequestedAt,
+		Source:     "orders",
+		Payload:    body,
+	})
+	if err != nil {
+		t.Fatalf("marshal envelope: %v", err)
+	}
 
 	produceCtx, cancelProduce := context.WithTimeout(context.Background(), 5*time.Second)
-	if err := producer.Produce(produceCtx, payload, topic); err != nil {
+	if err := producer.Produce(produceCtx, []byte(strconv.FormatInt(orderID, 10)), payload, topic); err != nil {
 		cancelProduce()
 		t.Fatalf("produce: %v", err)
 	}
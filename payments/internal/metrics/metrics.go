@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	DLQMessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "kafka",
+			Name:      "dlq_messages_total",
+			Help:      "Messages published to a dead-letter topic",
+		}, []string{"service", "topic"})
+
+	OutboxPrunedRowsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "outbox",
+			Name:      "pruned_rows_total",
+			Help:      "Rows removed by the outbox retention janitor",
+		}, []string{"service", "table"})
+
+	OutboxDeadEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "opp",
+			Subsystem: "outbox",
+			Name:      "dead_events_total",
+			Help:      "Outbox events dead-lettered after failing to encode too many times",
+		}, []string{"service", "event_type"})
+)
+
+func Register() {
+	prometheus.MustRegister(
+		DLQMessagesTotal,
+		OutboxPrunedRowsTotal,
+		OutboxDeadEventsTotal)
+}
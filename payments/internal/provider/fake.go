@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+)
+
+// Fake is a deterministic in-process provider. It approves every payment
+// unless the order was registered with DeclineOrder, and keeps the state of
+// each authorization so tests can assert on captures, refunds and voids.
+// A call repeated with the idempotency key of a succeeded one returns its
+// outcome without touching the authorization again.
+type Fake struct {
+	mu       sync.Mutex
+	declines map[int64]string
+	err      error
+	auths    map[string]*fakeAuth
+	// keys maps idempotency keys of succeeded calls to their authorization.
+	keys           map[string]string
+	authorizations int
+}
+
+type fakeAuth struct {
+	amount   int64
+	captured int64
+	refunded int64
+	voided   bool
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		declines: make(map[int64]string),
+		auths:    make(map[string]*fakeAuth),
+		keys:     make(map[string]string),
+	}
+}
+
+// DeclineOrder makes Authorize decline the given order with reason.
+func (f *Fake) DeclineOrder(orderID int64, reason string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.declines[orderID] = reason
+}
+
+// FailWith makes every subsequent call return err; nil restores normal behaviour.
+func (f *Fake) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *Fake) Authorize(ctx context.Context, key string, payment domain.Payment) (string, error) {
+	const op = "provider.Fake.Authorize"
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if authID, ok := f.keys[key]; ok {
+		return authID, nil
+	}
+	if f.err != nil {
+		return "", fmt.Errorf("%s: %w", op, f.err)
+	}
+	if payment.Amount <= 0 {
+		return "", fmt.Errorf("%s: %w", op, domain.ErrInvalidAmount)
+	}
+	if reason, ok := f.declines[payment.OrderID]; ok {
+		return "", fmt.Errorf("%s: %w", op, &domain.DeclineError{Reason: reason})
+	}
+
+	authID := fmt.Sprintf("fake-%d", payment.OrderID)
+	f.auths[authID] = &fakeAuth{amount: payment.Amount}
+	f.keys[key] = authID
+	f.authorizations++
+
+	return authID, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, key, authID string, amount int64) error {
+	const op = "provider.Fake.Capture"
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.keys[key]; ok {
+		return nil
+	}
+
+	auth, err := f.lookup(authID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if amount <= 0 || auth.captured+amount > auth.amount {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidAmount)
+	}
+
+	auth.captured += amount
+	f.keys[key] = authID
+	return nil
+}
+
+func (f *Fake) Refund(ctx context.Context, key, authID string, amount int64) error {
+	const op = "provider.Fake.Refund"
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.keys[key]; ok {
+		return nil
+	}
+
+	auth, err := f.lookup(authID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if amount <= 0 || auth.refunded+amount > auth.captured {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidAmount)
+	}
+
+	auth.refunded += amount
+	f.keys[key] = authID
+	return nil
+}
+
+func (f *Fake) Void(ctx context.Context, key, authID string) error {
+	const op = "provider.Fake.Void"
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.keys[key]; ok {
+		return nil
+	}
+
+	auth, err := f.lookup(authID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	auth.voided = true
+	f.keys[key] = authID
+	return nil
+}
+
+// Authorizations returns how many authorizations the provider has issued;
+// calls replayed by idempotency key are not counted.
+func (f *Fake) Authorizations() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.authorizations
+}
+
+// Captured returns the amount captured for authID.
+func (f *Fake) Captured(authID string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if auth, ok := f.auths[authID]; ok {
+		return auth.captured
+	}
+	return 0
+}
+
+// Refunded returns the amount refunded for authID.
+func (f *Fake) Refunded(authID string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if auth, ok := f.auths[authID]; ok {
+		return auth.refunded
+	}
+	return 0
+}
+
+// Voided reports whether authID was voided.
+func (f *Fake) Voided(authID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if auth, ok := f.auths[authID]; ok {
+		return auth.voided
+	}
+	return false
+}
+
+func (f *Fake) lookup(authID string) (*fakeAuth, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	auth, ok := f.auths[authID]
+	if !ok {
+		return nil, domain.ErrUnknownAuthorization
+	}
+	return auth, nil
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+)
+
+const (
+	reasonAmountLimit = "amount exceeds limit"
+	reasonBlocked     = "user is blocked"
+	reasonRandom      = "declined by issuer"
+)
+
+// SimulatorRules configures which payments the simulator declines.
+type SimulatorRules struct {
+	// MaxAmount declines payments above the threshold; zero disables the rule.
+	MaxAmount int64
+	// BlockedUsers are always declined.
+	BlockedUsers []int64
+	// FailureRatio is the share of remaining payments declined at random.
+	FailureRatio float64
+	// TransientRatio is the share of attempts failing with a retryable error.
+	TransientRatio float64
+	// Seed makes the random decisions reproducible.
+	Seed uint64
+}
+
+// Simulator is a rule-based provider that decides the outcome from the
+// payment itself instead of calling a real acquirer. An authorization
+// repeated with the same idempotency key returns the first authorization;
+// captures, refunds and voids keep no state, so repeating them is harmless.
+type Simulator struct {
+	rules   SimulatorRules
+	blocked map[int64]struct{}
+
+	mu   sync.Mutex
+	rng  *rand.Rand
+	seq  int64
+	keys map[string]string
+}
+
+func NewSimulator(rules SimulatorRules) (*Simulator, error) {
+	const op = "provider.NewSimulator"
+
+	if rules.FailureRatio < 0 || rules.FailureRatio > 1 {
+		return nil, fmt.Errorf("%s: failure ratio must be within [0, 1]", op)
+	}
+	if rules.TransientRatio < 0 || rules.TransientRatio > 1 {
+		return nil, fmt.Errorf("%s: transient ratio must be within [0, 1]", op)
+	}
+	if rules.MaxAmount < 0 {
+		return nil, fmt.Errorf("%s: max amount must not be negative", op)
+	}
+
+	blocked := make(map[int64]struct{}, len(rules.BlockedUsers))
+	for _, id := range rules.BlockedUsers {
+		blocked[id] = struct{}{}
+	}
+
+	return &Simulator{
+		rules:   rules,
+		blocked: blocked,
+		rng:     rand.New(rand.NewPCG(rules.Seed, rules.Seed)),
+		keys:    make(map[string]string),
+	}, nil
+}
+
+func (s *Simulator) Authorize(ctx context.Context, key string, payment domain.Payment) (string, error) {
+	const op = "provider.Simulator.Authorize"
+
+	if payment.Amount <= 0 {
+		return "", fmt.Errorf("%s: %w", op, domain.ErrInvalidAmount)
+	}
+	if s.rules.MaxAmount > 0 && payment.Amount > s.rules.MaxAmount {
+		return "", fmt.Errorf("%s: %w", op, &domain.DeclineError{Reason: reasonAmountLimit})
+	}
+	if _, ok := s.blocked[payment.UserID]; ok {
+		return "", fmt.Errorf("%s: %w", op, &domain.DeclineError{Reason: reasonBlocked})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if authID, ok := s.keys[key]; ok {
+		return authID, nil
+	}
+	if s.rules.TransientRatio > 0 && s.rng.Float64() < s.rules.TransientRatio {
+		return "", fmt.Errorf("%s: %w", op, domain.ErrTransient)
+	}
+	if s.rules.FailureRatio > 0 && s.rng.Float64() < s.rules.FailureRatio {
+		return "", fmt.Errorf("%s: %w", op, &domain.DeclineError{Reason: reasonRandom})
+	}
+
+	s.seq++
+	authID := fmt.Sprintf("sim-%d-%d", payment.OrderID, s.seq)
+	s.keys[key] = authID
+	return authID, nil
+}
+
+func (s *Simulator) Capture(ctx context.Context, key, authID string, amount int64) error {
+	const op = "provider.Simulator.Capture"
+
+	if err := checkOperation(authID, amount); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Simulator) Refund(ctx context.Context, key, authID string, amount int64) error {
+	const op = "provider.Simulator.Refund"
+
+	if err := checkOperation(authID, amount); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Simulator) Void(ctx context.Context, key, authID string) error {
+	const op = "provider.Simulator.Void"
+
+	if authID == "" {
+		return fmt.Errorf("%s: %w", op, domain.ErrUnknownAuthorization)
+	}
+	return nil
+}
+
+func checkOperation(authID string, amount int64) error {
+	if authID == "" {
+		return domain.ErrUnknownAuthorization
+	}
+	if amount <= 0 {
+		return domain.ErrInvalidAmount
+	}
+	return nil
+}
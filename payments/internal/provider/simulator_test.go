@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+)
+
+func TestSimulator_Authorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    SimulatorRules
+		payment  domain.Payment
+		wantErr  error
+		wantAuth bool
+	}{
+		{
+			name:     "approved",
+			rules:    SimulatorRules{MaxAmount: 1000},
+			payment:  domain.Payment{OrderID: 1, UserID: 1, Amount: 500},
+			wantAuth: true,
+		},
+		{
+			name:    "amount over limit",
+			rules:   SimulatorRules{MaxAmount: 1000},
+			payment: domain.Payment{OrderID: 2, UserID: 1, Amount: 1001},
+			wantErr: domain.ErrPaymentDeclined,
+		},
+		{
+			name:    "blocked user",
+			rules:   SimulatorRules{BlockedUsers: []int64{7}},
+			payment: domain.Payment{OrderID: 3, UserID: 7, Amount: 10},
+			wantErr: domain.ErrPaymentDeclined,
+		},
+		{
+			name:    "always fail",
+			rules:   SimulatorRules{FailureRatio: 1},
+			payment: domain.Payment{OrderID: 4, UserID: 1, Amount: 10},
+			wantErr: domain.ErrPaymentDeclined,
+		},
+		{
+			name:    "invalid amount",
+			payment: domain.Payment{OrderID: 5, UserID: 1, Amount: 0},
+			wantErr: domain.ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim, err := NewSimulator(tt.rules)
+			if err != nil {
+				t.Fatalf("new simulator: %v", err)
+			}
+
+			authID, err := sim.Authorize(context.Background(), "authorize-1", tt.payment)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantAuth && authID == "" {
+				t.Fatalf("expected authorization id")
+			}
+		})
+	}
+}
+
+func TestSimulator_SeedIsReproducible(t *testing.T) {
+	rules := SimulatorRules{FailureRatio: 0.5, Seed: 42}
+
+	outcomes := func() []bool {
+		sim, err := NewSimulator(rules)
+		if err != nil {
+			t.Fatalf("new simulator: %v", err)
+		}
+		out := make([]bool, 0, 50)
+		for i := int64(1); i <= 50; i++ {
+			_, err := sim.Authorize(context.Background(), fmt.Sprintf("authorize-%d", i), domain.Payment{OrderID: i, UserID: 1, Amount: 10})
+			out = append(out, err == nil)
+		}
+		return out
+	}
+
+	first, second := outcomes(), outcomes()
+	declined := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("outcome %d differs between runs with the same seed", i)
+		}
+		if !first[i] {
+			declined++
+		}
+	}
+	if declined == 0 || declined == len(first) {
+		t.Fatalf("expected a mix of outcomes, got %d declined of %d", declined, len(first))
+	}
+}
+
+func TestSimulator_AuthorizeIdempotencyKey(t *testing.T) {
+	sim, err := NewSimulator(SimulatorRules{})
+	if err != nil {
+		t.Fatalf("new simulator: %v", err)
+	}
+	payment := domain.Payment{OrderID: 1, UserID: 1, Amount: 10}
+
+	first, err := sim.Authorize(context.Background(), "authorize-1", payment)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	repeated, err := sim.Authorize(context.Background(), "authorize-1", payment)
+	if err != nil {
+		t.Fatalf("repeated authorize: %v", err)
+	}
+	if repeated != first {
+		t.Fatalf("repeated authorization: got %q, want %q", repeated, first)
+	}
+
+	other, err := sim.Authorize(context.Background(), "authorize-2", payment)
+	if err != nil {
+		t.Fatalf("authorize with another key: %v", err)
+	}
+	if other == first {
+		t.Fatalf("another key must issue a new authorization")
+	}
+}
+
+func TestNewSimulator_InvalidRatio(t *testing.T) {
+	if _, err := NewSimulator(SimulatorRules{FailureRatio: 1.5}); err == nil {
+		t.Fatalf("expected error for ratio above one")
+	}
+}
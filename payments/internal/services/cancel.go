@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
+)
+
+// HandleOrderCancelled undoes the payment of a cancelled order: whatever is
+// left of a captured charge is refunded, anything else is voided, and
+// PaymentCancelled is written to the outbox. An order payments has not
+// charged yet is recorded as voided so that a late PaymentRequested does not
+// charge it.
+func (s *Service) HandleOrderCancelled(ctx context.Context, input dto.OrderCancelled) error {
+	const op = "services.HandleOrderCancelled"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("order_id", input.OrderID),
+		slog.Int64("event_id", input.EventID),
+	)
+
+	if input.EventID == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidEventID)
+	}
+
+	cancellation := cancellation{
+		OrderID:         input.OrderID,
+		UserID:          input.UserID,
+		Reason:          input.Reason,
+		RefundRequestID: fmt.Sprintf("order-cancelled:%d", input.OrderID),
+	}
+	if err := s.cancelPayment(ctx, input.EventID, cancellation, log); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// HandleOrderExpired voids the pending payment of an order that stayed
+// unpaid past its payment deadline, the same way as a cancellation. A charge
+// captured just before the deadline is refunded, since orders no longer
+// accepts it.
+func (s *Service) HandleOrderExpired(ctx context.Context, input dto.OrderExpired) error {
+	const op = "services.HandleOrderExpired"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("order_id", input.OrderID),
+		slog.Int64("event_id", input.EventID),
+	)
+
+	if input.EventID == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidEventID)
+	}
+
+	cancellation := cancellation{
+		OrderID:         input.OrderID,
+		UserID:          input.UserID,
+		Reason:          "order expired",
+		RefundRequestID: fmt.Sprintf("order-expired:%d", input.OrderID),
+	}
+	if err := s.cancelPayment(ctx, input.EventID, cancellation, log); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// cancellation describes an order whose payment is to be undone.
+type cancellation struct {
+	OrderID int64
+	UserID  int64
+	Reason  string
+	// RefundRequestID keys the refund of a captured charge.
+	RefundRequestID string
+}
+
+// cancelPayment refunds or voids the payment of an order and writes
+// PaymentCancelled. The provider is called outside of a transaction: a
+// refund is stored as pending first, and eventID is marked processed only
+// together with the outcome, so an interrupted cancellation is redelivered
+// and repeats the call under the same idempotency key. A payment with a
+// charge in flight is marked cancelling and released by the attempt once
+// the charge ends. A payment cancelled already is left as is.
+func (s *Service) cancelPayment(ctx context.Context, eventID int64, c cancellation, log *slog.Logger) error {
+	var (
+		record domain.PaymentRecord
+		refund domain.Refund
+		done   bool
+	)
+	err := s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		processed, err := tx.IsProcessed(ctx, eventID)
+		if err != nil {
+			log.Error("check processed failed", slog.Any("err", err))
+			return err
+		}
+		if processed {
+			done = true
+			return nil
+		}
+
+		record, err = tx.GetPaymentForUpdate(ctx, c.OrderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+
+		switch {
+		case record.Cancelled(), record.Status == domain.StatusCancelling:
+			log.Debug("payment already cancelled", slog.String("status", record.Status))
+		case record.Status == domain.StatusPaymentPending:
+			if err := tx.LeaseRelease(ctx, c.OrderID, s.leaseUntil()); err != nil {
+				log.Error("lease release failed", slog.Any("err", err))
+				return err
+			}
+			log.Info("charge in flight, release deferred to the attempt")
+		case record.Status == domain.StatusSucceeded:
+			refund, err = pendingRefund(ctx, tx, domain.Refund{
+				RequestID: c.RefundRequestID,
+				OrderID:   c.OrderID,
+				Amount:    record.Refundable(),
+				Reason:    c.Reason,
+			})
+			if err != nil {
+				log.Error("save pending refund failed", slog.Any("err", err))
+			}
+			return err
+		case record.AuthID != "" && record.Status != domain.StatusFailed:
+			return nil
+		default:
+			if err := s.finishCancel(ctx, tx, record, domain.Refund{}, c, log); err != nil {
+				return err
+			}
+		}
+
+		done = true
+		_, err = tx.TryMarkProcessed(ctx, eventID)
+		return err
+	})
+	if err != nil || done {
+		return err
+	}
+
+	if refund.ID != 0 {
+		if err := s.provider.Refund(ctx, "refund:"+refund.RequestID, record.AuthID, refund.Amount); err != nil {
+			log.Error("refund failed", slog.String("auth_id", record.AuthID), slog.Any("err", err))
+			return fmt.Errorf("refund: %w", err)
+		}
+	} else {
+		if err := s.provider.Void(ctx, "void:"+record.AuthID, record.AuthID); err != nil {
+			log.Error("void failed", slog.String("auth_id", record.AuthID), slog.Any("err", err))
+			return fmt.Errorf("void: %w", err)
+		}
+	}
+
+	return s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		ok, err := tx.TryMarkProcessed(ctx, eventID)
+		if err != nil {
+			log.Error("try mark processed failed", slog.Any("err", err))
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		current, err := tx.GetPaymentForUpdate(ctx, c.OrderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+		if current.Status != record.Status || current.Refunded != record.Refunded {
+			return fmt.Errorf("%w: payment changed to %s while cancelling", domain.ErrTransient, current.Status)
+		}
+
+		return s.finishCancel(ctx, tx, current, refund, c, log)
+	})
+}
+
+// finishCancel stores the outcome of a cancellation and writes
+// PaymentCancelled. refund is the pending refund the provider has made, if
+// any.
+func (s *Service) finishCancel(
+	ctx context.Context,
+	tx postgres.TxRepository,
+	record domain.PaymentRecord,
+	refund domain.Refund,
+	c cancellation,
+	log *slog.Logger) error {
+	event := events.PaymentCancelled{
+		OrderID: c.OrderID,
+		UserID:  c.UserID,
+		Outcome: domain.StatusVoided,
+	}
+
+	switch {
+	case record.OrderID == 0:
+		if err := tx.UpsertPayment(ctx, c.OrderID, c.UserID, domain.Money{Currency: domain.DefaultCurrency}, domain.StatusVoided); err != nil {
+			log.Error("record voided payment failed", slog.Any("err", err))
+			return fmt.Errorf("persist payment: %w", err)
+		}
+	case refund.ID != 0:
+		refund.Status = domain.StatusSucceeded
+		if err := tx.CompleteRefund(ctx, refund); err != nil {
+			log.Error("complete refund failed", slog.Any("err", err))
+			return fmt.Errorf("complete refund: %w", err)
+		}
+		if err := tx.AddRefundedAmount(ctx, c.OrderID, refund.Amount); err != nil {
+			log.Error("update refunded amount failed", slog.Any("err", err))
+			return fmt.Errorf("update refunded amount: %w", err)
+		}
+		event.Outcome, event.Amount = domain.StatusRefunded, refund.Amount
+	}
+
+	if record.OrderID != 0 {
+		if err := tx.UpdatePaymentStatus(ctx, c.OrderID, event.Outcome); err != nil {
+			log.Error("update payment status failed", slog.Any("err", err))
+			return fmt.Errorf("update payment status: %w", err)
+		}
+	}
+
+	payload, err := json.Marshal(&event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	if err := tx.SaveEvent(ctx, events.TypePaymentCancelled, payload, c.OrderID); err != nil {
+		log.Error("save event failed", slog.Any("err", err))
+		return fmt.Errorf("save event: %w", err)
+	}
+
+	log.Info("payment cancelled", slog.String("outcome", event.Outcome), slog.Int64("amount", event.Amount))
+	return nil
+}
+
+// pendingRefund stores refund as pending before the provider is called. A
+// pending refund of the same request is resumed; one of another request is
+// reported as transient, since the refunds of an order are made one at a
+// time.
+func pendingRefund(ctx context.Context, tx postgres.TxRepository, refund domain.Refund) (domain.Refund, error) {
+	pending, err := tx.GetPendingRefund(ctx, refund.OrderID)
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("get pending refund: %w", err)
+	}
+	if pending.ID != 0 {
+		if pending.RequestID != refund.RequestID {
+			return domain.Refund{}, fmt.Errorf("%w: refund %s of the order is in progress", domain.ErrTransient, pending.RequestID)
+		}
+		return pending, nil
+	}
+
+	refund.Status = domain.StatusPaymentPending
+	refund, err = tx.SaveRefund(ctx, refund)
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("save refund: %w", err)
+	}
+	return refund, nil
+}
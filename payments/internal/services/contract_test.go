@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/provider"
+	"github.com/ChernykhITMO/order-processing-platform/testkit/contract"
+)
+
+func TestService_PaymentStatusContracts(t *testing.T) {
+	tests := []struct {
+		name    string
+		decline bool
+	}{
+		{name: "succeeded"},
+		{name: "failed", decline: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &storageMock{tx: &txMock{tryMarkOK: true}}
+			fake := provider.NewFake()
+			if tt.decline {
+				fake.DeclineOrder(42, "insufficient funds")
+			}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(st, fake, testRetry, log, "event-status")
+
+			err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+				EventID:     11,
+				OrderID:     42,
+				UserID:      7,
+				TotalAmount: 1500,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			contract.Verify(t, "../../../contracts", "payments", "event-status", st.tx.savedPayload)
+		})
+	}
+}
+
+func TestService_PaymentCancelledContract(t *testing.T) {
+	st := &storageMock{tx: &txMock{tryMarkOK: true}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, provider.NewFake(), testRetry, log, "event-status")
+
+	err := svc.HandleOrderCancelled(context.Background(), dto.OrderCancelled{
+		EventID: 12,
+		OrderID: 44,
+		UserID:  7,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	contract.Verify(t, "../../../contracts", "payments", events.TypePaymentCancelled, st.tx.savedPayload)
+}
@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/config"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
@@ -124,7 +125,7 @@ func TestPaymentsOutboxSender_Integration(t *testing.T) {
 	defer producer.Close()
 
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	sender := New(storage, producer, log, topic)
+	sender := New(storage, producer, storage, log, topic, 10, 5, envelope.ContentTypeJSON, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -165,10 +166,17 @@ func TestPaymentsOutboxSender_Integration(t *testing.T) {
 			continue
 		}
 
+		env, err := events.DecodeEnvelope(envelope.ContentTypeJSON, msg.Value)
+		if err != nil {
+			continue
+		}
 		var got events.PaymentStatus
-		if err := json.Unmarshal(msg.Value, &got); err != nil {
+		if err := json.Unmarshal(env.Payload, &got); err != nil {
 			continue
 		}
+		if env.Source != "payments" || env.Type != "event-status" {
+			t.Fatalf("envelope: got source=%s type=%s", env.Source, env.Type)
+		}
 		if got.OrderID != orderID {
 			continue
 		}
@@ -0,0 +1,117 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/metrics"
+)
+
+const serviceName = "payments"
+
+type Repository interface {
+	PruneSentEvents(ctx context.Context, before time.Time, limit int, archive bool) (int64, error)
+	PruneProcessedEvents(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type Config struct {
+	// Retention is how long sent outbox events are kept.
+	Retention time.Duration
+	// DedupWindow is how long processed_events remembers a consumed event id.
+	DedupWindow time.Duration
+	// Archive moves pruned events to events_archive instead of dropping them.
+	Archive bool
+	// BatchSize bounds the rows touched by a single statement.
+	BatchSize int
+}
+
+// Janitor prunes sent outbox events and stale dedup records in bounded batches.
+type Janitor struct {
+	repo Repository
+	log  *slog.Logger
+	cfg  Config
+	now  func() time.Time
+}
+
+func New(repo Repository, log *slog.Logger, cfg Config) *Janitor {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1
+	}
+	return &Janitor{
+		repo: repo,
+		log:  log,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+func (j *Janitor) Start(ctx context.Context, period time.Duration) {
+	const op = "services.janitor.Start"
+
+	log := j.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(period)
+
+	for {
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			log.Info("stopping outbox janitor")
+			return
+		case <-ticker.C:
+		}
+
+		if err := j.RunOnce(ctx); err != nil {
+			log.Error("outbox cleanup failed", slog.Any("err", err))
+		}
+	}
+}
+
+// RunOnce prunes everything past its cutoff, one batch per statement.
+func (j *Janitor) RunOnce(ctx context.Context) error {
+	const op = "services.janitor.RunOnce"
+
+	now := j.now()
+
+	events, err := j.prune(ctx, "events", func(ctx context.Context) (int64, error) {
+		return j.repo.PruneSentEvents(ctx, now.Add(-j.cfg.Retention), j.cfg.BatchSize, j.cfg.Archive)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	processed, err := j.prune(ctx, "processed_events", func(ctx context.Context) (int64, error) {
+		return j.repo.PruneProcessedEvents(ctx, now.Add(-j.cfg.DedupWindow), j.cfg.BatchSize)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if events > 0 || processed > 0 {
+		j.log.Debug("outbox pruned",
+			slog.Int64("events", events),
+			slog.Int64("processed_events", processed),
+			slog.Bool("archive", j.cfg.Archive))
+	}
+
+	return nil
+}
+
+func (j *Janitor) prune(ctx context.Context, table string, batch func(context.Context) (int64, error)) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		removed, err := batch(ctx)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", table, err)
+		}
+		total += removed
+		metrics.OutboxPrunedRowsTotal.WithLabelValues(serviceName, table).Add(float64(removed))
+
+		if removed < int64(j.cfg.BatchSize) {
+			break
+		}
+	}
+	return total, nil
+}
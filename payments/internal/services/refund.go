@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
+)
+
+// Refund returns money of a succeeded payment, in full or in part, and never
+// more than its total_amount. A repeated RequestID returns the stored outcome
+// without calling the provider again. The provider is called outside of a
+// transaction: the refund is stored as pending first and completed with the
+// outcome, and a repeated RequestID of a pending refund repeats the call
+// under the same idempotency key. Rejected requests and provider declines
+// are stored as failed refunds and published as RefundFailed; transient
+// provider errors are returned so the request is retried.
+func (s *Service) Refund(ctx context.Context, input dto.RefundRequest) (domain.Refund, error) {
+	const op = "services.Refund"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("request_id", input.RequestID),
+		slog.Int64("order_id", input.OrderID),
+	)
+
+	if err := validateRefund(input); err != nil {
+		return domain.Refund{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		record domain.PaymentRecord
+		refund domain.Refund
+	)
+	err := s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		var err error
+		record, err = tx.GetPaymentForUpdate(ctx, input.OrderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+
+		existing, err := tx.GetRefund(ctx, input.RequestID)
+		if err != nil {
+			log.Error("get refund failed", slog.Any("err", err))
+			return err
+		}
+		if existing.ID != 0 {
+			if existing.OrderID != input.OrderID {
+				return domain.ErrRefundRequestReused
+			}
+			log.Debug("refund request already handled", slog.String("status", existing.Status))
+			refund = existing
+			return nil
+		}
+
+		refund = domain.Refund{
+			RequestID: input.RequestID,
+			OrderID:   input.OrderID,
+			Amount:    input.Amount,
+			Reason:    input.Reason,
+		}
+		if rejection := checkRefundable(record, &refund); rejection != nil {
+			refund.Status, refund.Error = domain.StatusFailed, rejection.Error()
+			refund, err = tx.SaveRefund(ctx, refund)
+			if err != nil {
+				log.Error("save refund failed", slog.Any("err", err))
+				return fmt.Errorf("save refund: %w", err)
+			}
+			return s.publishRefund(ctx, tx, record, refund, log)
+		}
+
+		refund, err = pendingRefund(ctx, tx, refund)
+		if err != nil {
+			log.Error("save pending refund failed", slog.Any("err", err))
+		}
+		return err
+	})
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !refund.Pending() {
+		return refund, nil
+	}
+
+	refund.Status = domain.StatusSucceeded
+	if err := s.provider.Refund(ctx, "refund:"+refund.RequestID, record.AuthID, refund.Amount); err != nil {
+		if domain.IsTransient(err) {
+			log.Warn("refund attempt failed", slog.Any("err", err))
+			return domain.Refund{}, fmt.Errorf("%s: refund: %w", op, err)
+		}
+		refund.Status, refund.Error = domain.StatusFailed, err.Error()
+	}
+
+	err = s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		stored, err := tx.GetRefund(ctx, refund.RequestID)
+		if err != nil {
+			log.Error("get refund failed", slog.Any("err", err))
+			return err
+		}
+		if !stored.Pending() {
+			log.Debug("refund completed concurrently", slog.String("status", stored.Status))
+			refund = stored
+			return nil
+		}
+
+		record, err := tx.GetPaymentForUpdate(ctx, refund.OrderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+		if err := tx.CompleteRefund(ctx, refund); err != nil {
+			log.Error("complete refund failed", slog.Any("err", err))
+			return fmt.Errorf("complete refund: %w", err)
+		}
+		return s.publishRefund(ctx, tx, record, refund, log)
+	})
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return refund, nil
+}
+
+// publishRefund applies a completed refund to the payment and writes
+// RefundSucceeded or RefundFailed.
+func (s *Service) publishRefund(
+	ctx context.Context,
+	tx postgres.TxRepository,
+	record domain.PaymentRecord,
+	refund domain.Refund,
+	log *slog.Logger) error {
+	var (
+		eventType string
+		event     any
+	)
+	if refund.Succeeded() {
+		if err := s.recordRefunded(ctx, tx, record, refund.Amount); err != nil {
+			log.Error("update refunded amount failed", slog.Any("err", err))
+			return err
+		}
+		eventType, event = events.TypeRefundSucceeded, &events.RefundSucceeded{
+			RefundID:      refund.ID,
+			RequestID:     refund.RequestID,
+			OrderID:       refund.OrderID,
+			UserID:        record.UserID,
+			Amount:        refund.Amount,
+			RefundedTotal: record.Refunded + refund.Amount,
+		}
+	} else {
+		eventType, event = events.TypeRefundFailed, &events.RefundFailed{
+			RefundID:  refund.ID,
+			RequestID: refund.RequestID,
+			OrderID:   refund.OrderID,
+			UserID:    record.UserID,
+			Amount:    refund.Amount,
+			Reason:    refund.Error,
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	if err := tx.SaveEvent(ctx, eventType, payload, refund.OrderID); err != nil {
+		log.Error("save event failed", slog.Any("err", err))
+		return fmt.Errorf("save event: %w", err)
+	}
+
+	if refund.Succeeded() {
+		log.Info("refund succeeded", slog.Int64("amount", refund.Amount))
+	} else {
+		log.Warn("refund failed", slog.Int64("amount", refund.Amount), slog.String("error", refund.Error))
+	}
+	return nil
+}
+
+// recordRefunded adds amount to the refunded total and marks the payment
+// refunded once nothing is left.
+func (s *Service) recordRefunded(ctx context.Context, tx postgres.TxRepository, record domain.PaymentRecord, amount int64) error {
+	if err := tx.AddRefundedAmount(ctx, record.OrderID, amount); err != nil {
+		return fmt.Errorf("add refunded amount: %w", err)
+	}
+	if record.Refunded+amount < record.Amount {
+		return nil
+	}
+	if err := tx.UpdatePaymentStatus(ctx, record.OrderID, domain.StatusRefunded); err != nil {
+		return fmt.Errorf("update payment status: %w", err)
+	}
+	return nil
+}
+
+// checkRefundable resolves a zero amount to the refundable rest and rejects
+// refunds the payment cannot cover.
+func checkRefundable(record domain.PaymentRecord, refund *domain.Refund) error {
+	switch {
+	case record.OrderID == 0:
+		return domain.ErrPaymentNotFound
+	case record.Status != domain.StatusSucceeded:
+		return fmt.Errorf("%w: status %s", domain.ErrPaymentNotRefundable, record.Status)
+	}
+
+	refundable := record.Refundable()
+	if refund.Amount == 0 {
+		refund.Amount = refundable
+	}
+	if refund.Amount > refundable {
+		return fmt.Errorf("%w: requested %d, refundable %d", domain.ErrRefundExceedsPayment, refund.Amount, refundable)
+	}
+	return nil
+}
+
+func validateRefund(input dto.RefundRequest) error {
+	switch {
+	case strings.TrimSpace(input.RequestID) == "":
+		return domain.ErrInvalidRequestID
+	case input.OrderID <= 0:
+		return domain.ErrInvalidOrderID
+	case input.Amount < 0:
+		return domain.ErrInvalidRefundAmount
+	}
+	return nil
+}
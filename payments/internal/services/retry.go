@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
+)
+
+// RetryPolicy bounds how transiently failed payments are re-attempted.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// AttemptTimeout bounds the provider calls of one attempt. An attempt
+	// not stored within it is picked up again by RetryDuePayments.
+	AttemptTimeout time.Duration
+}
+
+// Backoff returns the delay before the next attempt after attempt failures:
+// BaseDelay doubled per failure and capped by MaxDelay.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// RetryDuePayments re-attempts up to limit payments whose next attempt is
+// due and returns how many were processed. Each payment is leased in its own
+// transaction and charged outside of it. A cancelled payment whose charge is
+// known is released instead; one whose attempt was interrupted is charged
+// again under the same key first, to learn what to give back.
+func (s *Service) RetryDuePayments(ctx context.Context, limit int) (int, error) {
+	const op = "services.RetryDuePayments"
+
+	processed := 0
+	for processed < limit {
+		var record domain.PaymentRecord
+		err := s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+			var err error
+			record, err = tx.GetDuePayment(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+			switch {
+			case record.OrderID == 0:
+				return nil
+			case record.Status == domain.StatusCancelling:
+				return tx.LeaseRelease(ctx, record.OrderID, s.leaseUntil())
+			default:
+				return tx.LeaseAttempt(ctx, record.OrderID, s.leaseUntil())
+			}
+		})
+		if err != nil {
+			return processed, fmt.Errorf("%s: %w", op, err)
+		}
+		if record.OrderID == 0 {
+			break
+		}
+
+		log := s.log.With(
+			slog.String("op", op),
+			slog.Int64("order_id", record.OrderID),
+			slog.Int64("user_id", record.UserID),
+			slog.Int("attempts", record.Attempts),
+		)
+
+		if record.Status == domain.StatusCancelling && record.AuthID != "" {
+			err = s.release(ctx, record.OrderID, log)
+		} else {
+			err = s.attempt(ctx, record.Payment, 0, log)
+		}
+		if err != nil {
+			return processed, fmt.Errorf("%s: %w", op, err)
+		}
+		processed++
+	}
+
+	return processed, nil
+}
@@ -0,0 +1,55 @@
+package retry_scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Retrier interface {
+	RetryDuePayments(ctx context.Context, limit int) (int, error)
+}
+
+type Scheduler struct {
+	retrier   Retrier
+	log       *slog.Logger
+	batchSize int
+}
+
+func New(retrier Retrier, log *slog.Logger, batchSize int) *Scheduler {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &Scheduler{
+		retrier:   retrier,
+		log:       log,
+		batchSize: batchSize,
+	}
+}
+
+func (s *Scheduler) Start(ctx context.Context, period time.Duration) {
+	const op = "services.retry_scheduler.Start"
+
+	log := s.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(period)
+
+	for {
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			log.Info("stopping payment retries")
+			return
+		case <-ticker.C:
+		}
+
+		processed, err := s.retrier.RetryDuePayments(ctx, s.batchSize)
+		if err != nil {
+			log.Error("retry due payments failed", slog.Any("err", err))
+			continue
+		}
+		if processed > 0 {
+			log.Debug("payments retried", slog.Int("count", processed))
+		}
+	}
+}
@@ -3,8 +3,10 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
@@ -12,22 +14,42 @@ import (
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
 )
 
+// PaymentProvider charges the customer. Every call carries an idempotency
+// key: a call repeated with the key of a completed one returns its outcome
+// instead of charging, refunding or voiding again. Authorize declines are
+// reported as domain.ErrPaymentDeclined, any other error is a technical
+// failure.
+type PaymentProvider interface {
+	Authorize(ctx context.Context, key string, payment domain.Payment) (authID string, err error)
+	Capture(ctx context.Context, key, authID string, amount int64) error
+	Refund(ctx context.Context, key, authID string, amount int64) error
+	Void(ctx context.Context, key, authID string) error
+}
+
 type Service struct {
 	repo      postgres.Repository
+	provider  PaymentProvider
+	retry     RetryPolicy
 	log       *slog.Logger
 	eventType string
 }
 
-func New(repo postgres.Repository, log *slog.Logger, eventType string) *Service {
+func New(repo postgres.Repository, provider PaymentProvider, retry RetryPolicy, log *slog.Logger, eventType string) *Service {
 	return &Service{
 		repo:      repo,
+		provider:  provider,
+		retry:     retry,
 		log:       log,
 		eventType: eventType,
 	}
 }
 
-func (s *Service) HandleOrderCreated(ctx context.Context, input dto.OrderCreated) error {
-	const op = "services.HandleOrderCreated"
+// HandlePaymentRequested charges an order whose stock orders has reserved.
+// The provider is called outside of a database transaction: the payment is
+// first stored as pending under an attempt lease, then charged, and the
+// outcome is stored together with the processed event.
+func (s *Service) HandlePaymentRequested(ctx context.Context, input dto.PaymentRequested) error {
+	const op = "services.HandlePaymentRequested"
 
 	log := s.log.With(
 		slog.String("op", op),
@@ -36,67 +58,336 @@ func (s *Service) HandleOrderCreated(ctx context.Context, input dto.OrderCreated
 		slog.Int64("event_id", input.EventID),
 	)
 
-	return s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
-		if input.EventID == 0 {
-			return fmt.Errorf("%s: %w", op, domain.ErrInvalidEventID)
-		}
+	if input.EventID == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidEventID)
+	}
+
+	total, err := domain.NewMoney(input.TotalAmount, input.Currency)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	payment := domain.Payment{
+		OrderID:  input.OrderID,
+		UserID:   input.UserID,
+		Amount:   total.Amount,
+		Currency: total.Currency,
+	}
 
-		ok, err := tx.TryMarkProcessed(ctx, input.EventID)
+	started := false
+	err = s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		processed, err := tx.IsProcessed(ctx, input.EventID)
 		if err != nil {
-			log.Error("try mark processed failed", slog.Any("err", err))
-			return fmt.Errorf("%s: %w", op, err)
+			log.Error("check processed failed", slog.Any("err", err))
+			return err
 		}
-
-		if !ok {
+		if processed {
 			return nil
 		}
 
-		if err := tx.UpsertPayment(ctx, input.OrderID, input.UserID, input.TotalAmount, domain.StatusPaymentPending); err != nil {
+		record, err := tx.GetPaymentForUpdate(ctx, input.OrderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+		switch {
+		case record.Cancelled():
+			log.Info("order cancelled before payment, skipped")
+			_, err := tx.TryMarkProcessed(ctx, input.EventID)
+			return err
+		case record.OrderID != 0 && record.Status != domain.StatusPaymentPending:
+			log.Debug("payment already handled", slog.String("status", record.Status))
+			_, err := tx.TryMarkProcessed(ctx, input.EventID)
+			return err
+		}
+
+		if err := tx.UpsertPayment(ctx, input.OrderID, input.UserID, total, domain.StatusPaymentPending); err != nil {
 			log.Error("upsert payment failed", slog.Any("err", err))
-			return fmt.Errorf("%s: persist payment: %w", op, err)
+			return fmt.Errorf("persist payment: %w", err)
 		}
+		if err := tx.LeaseAttempt(ctx, input.OrderID, s.leaseUntil()); err != nil {
+			log.Error("lease attempt failed", slog.Any("err", err))
+			return fmt.Errorf("lease attempt: %w", err)
+		}
+		payment.Attempts = record.Attempts
+		started = true
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !started {
+		return nil
+	}
 
-		if input.OrderID%2 == 0 {
-			if err := tx.UpdatePaymentStatus(ctx, input.OrderID, domain.StatusSucceeded); err != nil {
-				log.Error("update payment status failed", slog.Any("err", err))
-				return fmt.Errorf("%s: update payment status: %w", op, err)
-			}
-			event := events.PaymentStatus{
-				OrderID:     input.OrderID,
-				UserID:      input.UserID,
-				OrderStatus: domain.StatusSucceeded,
-			}
-			payload, err := json.Marshal(&event)
-			if err != nil {
-				log.Error("marshal event failed", slog.Any("err", err))
-				return fmt.Errorf("%s: encode success event: %w", op, err)
-			}
+	if err := s.attempt(ctx, payment, input.EventID, log); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
 
-			if err := tx.SaveEvent(ctx, s.eventType, payload, input.OrderID); err != nil {
-				log.Error("save event failed", slog.Any("err", err))
-				return fmt.Errorf("%s: kafka_produce produce: %w", op, err)
-			}
-		} else {
-			if err := tx.UpdatePaymentStatus(ctx, input.OrderID, domain.StatusFailed); err != nil {
-				log.Error("update payment status failed", slog.Any("err", err))
-				return fmt.Errorf("%s: update payment status: %w", op, err)
-			}
-			event := events.PaymentStatus{
-				OrderID:     input.OrderID,
-				UserID:      input.UserID,
-				OrderStatus: domain.StatusFailed,
-			}
-			payload, err := json.Marshal(&event)
+// chargeResult is the outcome of a charge made outside of a transaction.
+type chargeResult struct {
+	status string
+	authID string
+	err    error
+}
+
+// attempt charges a payment leased by the caller and stores the outcome.
+// A non-zero eventID is marked processed in the same transaction; if it
+// already is, a concurrent delivery has stored the outcome and nothing is
+// written. A payment cancelled while it was being charged is released.
+func (s *Service) attempt(ctx context.Context, payment domain.Payment, eventID int64, log *slog.Logger) error {
+	result := s.charge(ctx, payment, log)
+
+	release := false
+	err := s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		release = false
+
+		if eventID != 0 {
+			ok, err := tx.TryMarkProcessed(ctx, eventID)
 			if err != nil {
-				log.Error("marshal event failed", slog.Any("err", err))
-				return fmt.Errorf("%s: encode failed event: %w", op, err)
+				log.Error("try mark processed failed", slog.Any("err", err))
+				return err
+			}
+			if !ok {
+				return nil
 			}
+		}
+
+		record, err := tx.GetPaymentForUpdate(ctx, payment.OrderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+		switch record.Status {
+		case domain.StatusPaymentPending:
+			return s.process(ctx, tx, payment, result, log)
+		case domain.StatusCancelling:
+			release = result.authID != ""
+			return s.holdRelease(ctx, tx, record, result, log)
+		default:
+			log.Info("payment changed while charging", slog.String("status", record.Status))
+			return nil
+		}
+	})
+	if err != nil {
+		return err
+	}
 
-			if err := tx.SaveEvent(ctx, s.eventType, payload, input.OrderID); err != nil {
-				log.Error("save event failed", slog.Any("err", err))
-				return fmt.Errorf("%s: save event: %w", op, err)
+	if release {
+		return s.release(ctx, payment.OrderID, log)
+	}
+	return nil
+}
+
+// process stores the outcome of one payment attempt. Transient provider
+// failures are rescheduled while attempts remain; otherwise the final status
+// is stored and PaymentStatus is written to the outbox.
+func (s *Service) process(ctx context.Context, tx postgres.TxRepository, payment domain.Payment, result chargeResult, log *slog.Logger) error {
+	const op = "services.process"
+
+	status, authID := result.status, result.authID
+	if err := result.err; err != nil {
+		attempts := payment.Attempts + 1
+		switch {
+		case !domain.IsTransient(err):
+			log.Error("payment failed", slog.Any("err", err))
+		case attempts < s.retry.MaxAttempts:
+			next := time.Now().Add(s.retry.Backoff(attempts))
+			log.Warn("payment attempt failed, retry scheduled",
+				slog.Int("attempt", attempts),
+				slog.Time("next_attempt_at", next),
+				slog.Any("err", err))
+			if err := tx.ScheduleRetry(ctx, payment.OrderID, next, err.Error()); err != nil {
+				log.Error("schedule retry failed", slog.Any("err", err))
+				return fmt.Errorf("%s: schedule retry: %w", op, err)
 			}
+			return nil
+		default:
+			log.Error("payment retries exhausted", slog.Int("attempt", attempts), slog.Any("err", err))
+		}
+		status = domain.StatusFailed
+	}
+
+	if authID != "" {
+		if err := tx.SaveAuthorization(ctx, payment.OrderID, authID); err != nil {
+			log.Error("save authorization failed", slog.Any("err", err))
+			return fmt.Errorf("%s: save authorization: %w", op, err)
 		}
+	}
+
+	if err := tx.UpdatePaymentStatus(ctx, payment.OrderID, status); err != nil {
+		log.Error("update payment status failed", slog.Any("err", err))
+		return fmt.Errorf("%s: update payment status: %w", op, err)
+	}
+
+	event := events.PaymentStatus{
+		OrderID:     payment.OrderID,
+		UserID:      payment.UserID,
+		OrderStatus: status,
+		Currency:    string(payment.Currency),
+	}
+	payload, err := json.Marshal(&event)
+	if err != nil {
+		log.Error("marshal event failed", slog.Any("err", err))
+		return fmt.Errorf("%s: encode %s event: %w", op, status, err)
+	}
+
+	if err := tx.SaveEvent(ctx, s.eventType, payload, payment.OrderID); err != nil {
+		log.Error("save event failed", slog.Any("err", err))
+		return fmt.Errorf("%s: save event: %w", op, err)
+	}
+
+	return nil
+}
+
+// charge authorizes and captures the payment. A decline yields StatusFailed
+// with a nil error; technical provider errors are returned for process to
+// classify. The authorization is keyed by the attempt, so a redelivered or
+// recovered attempt reuses it while a scheduled retry gets a new one.
+func (s *Service) charge(ctx context.Context, payment domain.Payment, log *slog.Logger) chargeResult {
+	const op = "services.charge"
+
+	if s.retry.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.retry.AttemptTimeout)
+		defer cancel()
+	}
+
+	authID, err := s.provider.Authorize(ctx, authorizeKey(payment), payment)
+	if errors.Is(err, domain.ErrPaymentDeclined) {
+		log.Info("payment declined", slog.Any("reason", err))
+		return chargeResult{status: domain.StatusFailed}
+	}
+	if err != nil {
+		return chargeResult{err: fmt.Errorf("%s: authorize: %w", op, err)}
+	}
+
+	if err := s.provider.Capture(ctx, "capture:"+authID, authID, payment.Amount); err != nil {
+		if voidErr := s.provider.Void(ctx, "void:"+authID, authID); voidErr != nil {
+			log.Warn("void after failed capture", slog.String("auth_id", authID), slog.Any("err", voidErr))
+		}
+		if errors.Is(err, domain.ErrPaymentDeclined) {
+			log.Info("capture declined", slog.Any("reason", err))
+			return chargeResult{status: domain.StatusFailed, authID: authID}
+		}
+		return chargeResult{err: fmt.Errorf("%s: capture: %w", op, err)}
+	}
+
+	return chargeResult{status: domain.StatusSucceeded, authID: authID}
+}
+
+// holdRelease stores the outcome of a charge made for a payment cancelled
+// while it was in flight. A captured charge is stored as a pending refund,
+// an authorization is kept for release to void; with nothing charged the
+// payment is voided right away.
+func (s *Service) holdRelease(ctx context.Context, tx postgres.TxRepository, record domain.PaymentRecord, result chargeResult, log *slog.Logger) error {
+	const op = "services.holdRelease"
+
+	if result.authID == "" {
+		return s.finishCancel(ctx, tx, record, domain.Refund{}, cancellation{OrderID: record.OrderID, UserID: record.UserID}, log)
+	}
+
+	if err := tx.SaveAuthorization(ctx, record.OrderID, result.authID); err != nil {
+		log.Error("save authorization failed", slog.Any("err", err))
+		return fmt.Errorf("%s: save authorization: %w", op, err)
+	}
+	if result.status == domain.StatusSucceeded {
+		_, err := pendingRefund(ctx, tx, domain.Refund{
+			RequestID: "release:" + result.authID,
+			OrderID:   record.OrderID,
+			Amount:    record.Amount,
+			Reason:    "order cancelled while charging",
+		})
+		if err != nil {
+			log.Error("save pending refund failed", slog.Any("err", err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return nil
+}
+
+// release gives back the charge of a payment cancelled while it was in
+// flight the way cancelPayment does: the pending refund of a captured charge
+// is made, an authorization is voided, and PaymentCancelled is written only
+// once the provider call succeeds. A failed release stays leased and is
+// retried by RetryDuePayments.
+func (s *Service) release(ctx context.Context, orderID int64, log *slog.Logger) error {
+	const op = "services.release"
+
+	var (
+		record domain.PaymentRecord
+		refund domain.Refund
+	)
+	err := s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		var err error
+		record, err = tx.GetPaymentForUpdate(ctx, orderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+		if record.Status != domain.StatusCancelling {
+			return nil
+		}
+
+		refund, err = tx.GetPendingRefund(ctx, orderID)
+		if err != nil {
+			log.Error("get pending refund failed", slog.Any("err", err))
+			return err
+		}
+		return tx.LeaseRelease(ctx, orderID, s.leaseUntil())
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if record.Status != domain.StatusCancelling {
 		return nil
+	}
+
+	log = log.With(slog.String("auth_id", record.AuthID))
+	if refund.ID != 0 {
+		err = s.provider.Refund(ctx, "refund:"+refund.RequestID, record.AuthID, refund.Amount)
+	} else {
+		err = s.provider.Void(ctx, "void:"+record.AuthID, record.AuthID)
+	}
+	if err != nil {
+		next := time.Now().Add(s.retry.Backoff(s.retry.MaxAttempts))
+		log.Error("release charge of cancelled order failed, retry scheduled",
+			slog.Time("next_attempt_at", next),
+			slog.Any("err", err))
+		err = s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+			return tx.LeaseRelease(ctx, orderID, next)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: schedule release: %w", op, err)
+		}
+		return nil
+	}
+
+	err = s.repo.RunInTx(ctx, func(tx postgres.TxRepository) error {
+		current, err := tx.GetPaymentForUpdate(ctx, orderID)
+		if err != nil {
+			log.Error("get payment failed", slog.Any("err", err))
+			return err
+		}
+		if current.Status != domain.StatusCancelling {
+			return nil
+		}
+		return s.finishCancel(ctx, tx, current, refund, cancellation{OrderID: orderID, UserID: current.UserID}, log)
 	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Warn("charge of cancelled order released")
+	return nil
+}
+
+// leaseUntil is when an attempt started now is considered abandoned.
+func (s *Service) leaseUntil() time.Time {
+	return time.Now().Add(s.retry.AttemptTimeout)
+}
+
+func authorizeKey(payment domain.Payment) string {
+	return fmt.Sprintf("authorize:%d:%d", payment.OrderID, payment.Attempts)
 }
@@ -6,20 +6,23 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/dto"
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/provider"
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/storage/postgres"
 )
 
-func TestService_HandleOrderCreated_InvalidEventID(t *testing.T) {
+func TestService_HandlePaymentRequested_InvalidEventID(t *testing.T) {
 	st := &storageMock{tx: &txMock{}}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := New(st, log, "payment-status")
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
 
-	err := svc.HandleOrderCreated(context.Background(), dto.OrderCreated{
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
 		EventID: 0,
 		OrderID: 1,
 		UserID:  2,
@@ -35,12 +38,12 @@ func TestService_HandleOrderCreated_InvalidEventID(t *testing.T) {
 	}
 }
 
-func TestService_HandleOrderCreated_AlreadyProcessed(t *testing.T) {
-	st := &storageMock{tx: &txMock{tryMarkOK: false}}
+func TestService_HandlePaymentRequested_AlreadyProcessed(t *testing.T) {
+	st := &storageMock{tx: &txMock{processed: true}}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := New(st, log, "payment-status")
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
 
-	err := svc.HandleOrderCreated(context.Background(), dto.OrderCreated{
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
 		EventID:     1,
 		OrderID:     2,
 		UserID:      3,
@@ -49,28 +52,36 @@ func TestService_HandleOrderCreated_AlreadyProcessed(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if st.tx.upsertCalled != 0 || st.tx.updateCalled != 0 || st.tx.saveEventCalled != 0 {
+	if st.tx.upsertCalled != 0 || st.tx.updateCalled != 0 || st.tx.saveEventCalled != 0 || st.tx.tryMarkCalled != 0 {
 		t.Fatalf("no writes expected when event already processed")
 	}
 }
 
-func TestService_HandleOrderCreated_SuccessEven(t *testing.T) {
+func TestService_HandlePaymentRequested_Approved(t *testing.T) {
 	st := &storageMock{tx: &txMock{tryMarkOK: true}}
+	fake := provider.NewFake()
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := New(st, log, "payment-status")
+	svc := New(st, fake, testRetry, log, "payment-status")
 
-	err := svc.HandleOrderCreated(context.Background(), dto.OrderCreated{
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
 		EventID:     10,
-		OrderID:     2,
+		OrderID:     3,
 		UserID:      3,
 		TotalAmount: 100,
+		Currency:    "USD",
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if st.tx.upsertCalled != 1 {
-		t.Fatalf("UpsertPayment calls: got %d, want %d", st.tx.upsertCalled, 1)
+	if st.tx.upsertCalled != 1 || st.tx.leaseCalled != 1 {
+		t.Fatalf("UpsertPayment/LeaseAttempt calls: got %d/%d, want 1/1", st.tx.upsertCalled, st.tx.leaseCalled)
+	}
+	if st.tx.tryMarkCalled != 1 {
+		t.Fatalf("TryMarkProcessed calls: got %d, want %d", st.tx.tryMarkCalled, 1)
+	}
+	if st.tx.upserted != (domain.Money{Amount: 100, Currency: "USD"}) {
+		t.Fatalf("upserted total: got %s, want %s", st.tx.upserted, "100 USD")
 	}
 	if st.tx.updateCalled != 1 {
 		t.Fatalf("UpdatePaymentStatus calls: got %d, want %d", st.tx.updateCalled, 1)
@@ -78,6 +89,12 @@ func TestService_HandleOrderCreated_SuccessEven(t *testing.T) {
 	if st.tx.saveEventCalled != 1 {
 		t.Fatalf("SaveEvent calls: got %d, want %d", st.tx.saveEventCalled, 1)
 	}
+	if st.tx.authID == "" {
+		t.Fatalf("expected authorization to be saved")
+	}
+	if got := fake.Captured(st.tx.authID); got != 100 {
+		t.Fatalf("captured: got %d, want %d", got, 100)
+	}
 
 	var ev events.PaymentStatus
 	if err := json.Unmarshal(st.tx.savedPayload, &ev); err != nil {
@@ -86,22 +103,721 @@ func TestService_HandleOrderCreated_SuccessEven(t *testing.T) {
 	if ev.OrderStatus != domain.StatusSucceeded {
 		t.Fatalf("expected status %s, got %s", domain.StatusSucceeded, ev.OrderStatus)
 	}
+	if ev.Currency != "USD" {
+		t.Fatalf("event currency: got %q, want %q", ev.Currency, "USD")
+	}
 }
 
-func TestService_HandleOrderCreated_SuccessOdd(t *testing.T) {
+func TestService_HandlePaymentRequested_InvalidCurrency(t *testing.T) {
 	st := &storageMock{tx: &txMock{tryMarkOK: true}}
 	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-	svc := New(st, log, "payment-status")
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
 
-	err := svc.HandleOrderCreated(context.Background(), dto.OrderCreated{
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
 		EventID:     10,
 		OrderID:     3,
 		UserID:      3,
 		TotalAmount: 100,
+		Currency:    "usd",
+	})
+	if !errors.Is(err, domain.ErrInvalidCurrency) {
+		t.Fatalf("expected error %v, got %v", domain.ErrInvalidCurrency, err)
+	}
+	if st.tx.upsertCalled != 0 || st.tx.saveEventCalled != 0 {
+		t.Fatalf("unexpected writes: upsert=%d save=%d", st.tx.upsertCalled, st.tx.saveEventCalled)
+	}
+}
+
+func TestService_HandlePaymentRequested_Declined(t *testing.T) {
+	st := &storageMock{tx: &txMock{tryMarkOK: true}}
+	fake := provider.NewFake()
+	fake.DeclineOrder(2, "insufficient funds")
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, fake, testRetry, log, "payment-status")
+
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+		EventID:     10,
+		OrderID:     2,
+		UserID:      3,
+		TotalAmount: 100,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.tx.authID != "" {
+		t.Fatalf("declined payment must not store authorization")
+	}
+
+	var ev events.PaymentStatus
+	if err := json.Unmarshal(st.tx.savedPayload, &ev); err != nil {
+		t.Fatalf("unmarshal saved payload: %v", err)
+	}
+	if ev.OrderStatus != domain.StatusFailed {
+		t.Fatalf("expected status %s, got %s", domain.StatusFailed, ev.OrderStatus)
+	}
+}
+
+func TestService_HandlePaymentRequested_CancelledBeforePayment(t *testing.T) {
+	st := &storageMock{tx: &txMock{
+		tryMarkOK: true,
+		record:    domain.PaymentRecord{Payment: domain.Payment{OrderID: 2}, Status: domain.StatusVoided},
+	}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
+
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+		EventID:     10,
+		OrderID:     2,
+		UserID:      3,
+		TotalAmount: 100,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.tx.upsertCalled != 0 || st.tx.saveEventCalled != 0 {
+		t.Fatalf("cancelled order must not be charged")
+	}
+}
+
+func TestService_HandlePaymentRequested_RedeliveredAttempt(t *testing.T) {
+	payment := domain.Payment{OrderID: 2, UserID: 3, Amount: 100, Currency: "RUB"}
+	fake := provider.NewFake()
+	authID, err := fake.Authorize(context.Background(), "authorize:2:0", payment)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if err := fake.Capture(context.Background(), "capture:"+authID, authID, payment.Amount); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+
+	st := &storageMock{tx: &txMock{
+		tryMarkOK: true,
+		record:    domain.PaymentRecord{Payment: payment, Status: domain.StatusPaymentPending},
+	}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, fake, testRetry, log, "payment-status")
+
+	err = svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+		EventID:     10,
+		OrderID:     2,
+		UserID:      3,
+		TotalAmount: 100,
+		Currency:    "RUB",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fake.Authorizations(); got != 1 {
+		t.Fatalf("authorizations: got %d, want 1", got)
+	}
+	if got := fake.Captured(authID); got != 100 {
+		t.Fatalf("captured: got %d, want 100", got)
+	}
+	if st.tx.status != domain.StatusSucceeded || st.tx.authID != authID {
+		t.Fatalf("payment: got %s/%q, want %s/%q", st.tx.status, st.tx.authID, domain.StatusSucceeded, authID)
+	}
+}
+
+func TestService_HandlePaymentRequested_CancelledWhileCharging(t *testing.T) {
+	st := &storageMock{tx: &txMock{tryMarkOK: true}}
+	fake := provider.NewFake()
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, &cancellingProvider{Fake: fake, tx: st.tx}, testRetry, log, "payment-status")
+
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+		EventID:     10,
+		OrderID:     2,
+		UserID:      3,
+		TotalAmount: 100,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fake.Refunded("fake-2"); got != 100 {
+		t.Fatalf("refunded: got %d, want 100", got)
+	}
+	if len(st.tx.savedRefunds) != 1 || !st.tx.savedRefunds[0].Succeeded() || st.tx.refundedAdded != 100 {
+		t.Fatalf("refund record: got %+v, added %d", st.tx.savedRefunds, st.tx.refundedAdded)
+	}
+	assertPaymentCancelled(t, st.tx, domain.StatusRefunded, 100)
+}
+
+func TestService_HandlePaymentRequested_ReleaseFailure(t *testing.T) {
+	st := &storageMock{tx: &txMock{tryMarkOK: true}}
+	fake := provider.NewFake()
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	cancelling := &cancellingProvider{Fake: fake, tx: st.tx, refundErr: domain.ErrTransient}
+	svc := New(st, cancelling, testRetry, log, "payment-status")
+
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+		EventID:     10,
+		OrderID:     2,
+		UserID:      3,
+		TotalAmount: 100,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.tx.saveEventCalled != 0 {
+		t.Fatalf("PaymentCancelled must wait for the release, saved %q", st.tx.savedType)
+	}
+	if st.tx.record.Status != domain.StatusCancelling || !st.tx.nextAttemptAt.After(time.Now()) {
+		t.Fatalf("payment: got %s retried at %s, want %s with a release scheduled",
+			st.tx.record.Status, st.tx.nextAttemptAt, domain.StatusCancelling)
+	}
+	if len(st.tx.savedRefunds) != 1 || !st.tx.savedRefunds[0].Pending() {
+		t.Fatalf("refund record: got %+v, want one pending", st.tx.savedRefunds)
+	}
+
+	cancelling.refundErr = nil
+	st.tx.due = []domain.PaymentRecord{st.tx.record}
+	processed, err := svc.RetryDuePayments(context.Background(), 10)
+	if err != nil || processed != 1 {
+		t.Fatalf("retry: processed %d, err %v", processed, err)
+	}
+	if got := fake.Authorizations(); got != 1 {
+		t.Fatalf("authorizations: got %d, want 1", got)
+	}
+	if got := fake.Refunded("fake-2"); got != 100 {
+		t.Fatalf("refunded: got %d, want 100", got)
+	}
+	if len(st.tx.savedRefunds) != 1 || !st.tx.savedRefunds[0].Succeeded() {
+		t.Fatalf("refund record: got %+v, want the pending one completed", st.tx.savedRefunds)
+	}
+	assertPaymentCancelled(t, st.tx, domain.StatusRefunded, 100)
+}
+
+// cancellingProvider cancels the payment in storage while it is being
+// authorized, as a concurrent OrderCancelled would. refundErr fails refunds.
+type cancellingProvider struct {
+	*provider.Fake
+	tx        *txMock
+	refundErr error
+}
+
+func (p *cancellingProvider) Authorize(ctx context.Context, key string, payment domain.Payment) (string, error) {
+	p.tx.record.Status = domain.StatusCancelling
+	return p.Fake.Authorize(ctx, key, payment)
+}
+
+func (p *cancellingProvider) Refund(ctx context.Context, key, authID string, amount int64) error {
+	if p.refundErr != nil {
+		return p.refundErr
+	}
+	return p.Fake.Refund(ctx, key, authID, amount)
+}
+
+func assertPaymentCancelled(t *testing.T, tx *txMock, outcome string, amount int64) {
+	t.Helper()
+
+	if tx.status != outcome || tx.saveEventCalled != 1 || tx.savedType != events.TypePaymentCancelled {
+		t.Fatalf("payment: got status %s, %d events of type %q, want %s and one %q",
+			tx.status, tx.saveEventCalled, tx.savedType, outcome, events.TypePaymentCancelled)
+	}
+	var ev events.PaymentCancelled
+	if err := json.Unmarshal(tx.savedPayload, &ev); err != nil {
+		t.Fatalf("unmarshal saved payload: %v", err)
+	}
+	if ev.Outcome != outcome || ev.Amount != amount {
+		t.Fatalf("event: got %s/%d, want %s/%d", ev.Outcome, ev.Amount, outcome, amount)
+	}
+}
+
+func TestService_HandleOrderCancelled(t *testing.T) {
+	tests := []struct {
+		name        string
+		record      domain.PaymentRecord
+		captured    bool
+		wantOutcome string
+		wantAmount  int64
+		wantUpsert  int
+		wantVoided  bool
+		wantRefund  int64
+	}{
+		{
+			name:        "not charged yet",
+			wantOutcome: domain.StatusVoided,
+			wantUpsert:  1,
+		},
+		{
+			name:        "succeeded",
+			record:      domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"},
+			captured:    true,
+			wantOutcome: domain.StatusRefunded,
+			wantAmount:  100,
+			wantRefund:  100,
+		},
+		{
+			name:        "partially refunded",
+			record:      domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2", Refunded: 40},
+			captured:    true,
+			wantOutcome: domain.StatusRefunded,
+			wantAmount:  60,
+			wantRefund:  100,
+		},
+		{
+			name:        "authorized, retrying capture",
+			record:      domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusRetrying, AuthID: "fake-2"},
+			wantOutcome: domain.StatusVoided,
+			wantVoided:  true,
+		},
+		{
+			name:        "retrying",
+			record:      domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusRetrying},
+			wantOutcome: domain.StatusVoided,
+		},
+		{
+			name:        "failed",
+			record:      domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusFailed},
+			wantOutcome: domain.StatusVoided,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			if tt.record.AuthID != "" {
+				authID, err := fake.Authorize(context.Background(), "authorize:2:0", tt.record.Payment)
+				if err != nil {
+					t.Fatalf("authorize: %v", err)
+				}
+				if tt.captured {
+					if err := fake.Capture(context.Background(), "capture:"+authID, authID, tt.record.Amount); err != nil {
+						t.Fatalf("capture: %v", err)
+					}
+				}
+				if tt.record.Refunded > 0 {
+					if err := fake.Refund(context.Background(), "refund:earlier", authID, tt.record.Refunded); err != nil {
+						t.Fatalf("refund: %v", err)
+					}
+				}
+			}
+			st := &storageMock{tx: &txMock{tryMarkOK: true, record: tt.record}}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(st, fake, testRetry, log, "payment-status")
+
+			err := svc.HandleOrderCancelled(context.Background(), dto.OrderCancelled{EventID: 20, OrderID: 2, UserID: 3})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if st.tx.upsertCalled != tt.wantUpsert {
+				t.Fatalf("UpsertPayment calls: got %d, want %d", st.tx.upsertCalled, tt.wantUpsert)
+			}
+			if st.tx.savedType != events.TypePaymentCancelled {
+				t.Fatalf("saved event type: got %q, want %q", st.tx.savedType, events.TypePaymentCancelled)
+			}
+			var ev events.PaymentCancelled
+			if err := json.Unmarshal(st.tx.savedPayload, &ev); err != nil {
+				t.Fatalf("unmarshal saved payload: %v", err)
+			}
+			if ev.Outcome != tt.wantOutcome || ev.Amount != tt.wantAmount {
+				t.Fatalf("event: got %s/%d, want %s/%d", ev.Outcome, ev.Amount, tt.wantOutcome, tt.wantAmount)
+			}
+			if tt.record.OrderID != 0 && st.tx.status != tt.wantOutcome {
+				t.Fatalf("payment status: got %s, want %s", st.tx.status, tt.wantOutcome)
+			}
+			if got := fake.Voided("fake-2"); got != tt.wantVoided {
+				t.Fatalf("voided: got %v, want %v", got, tt.wantVoided)
+			}
+			if got := fake.Refunded("fake-2"); got != tt.wantRefund {
+				t.Fatalf("refunded: got %d, want %d", got, tt.wantRefund)
+			}
+			if tt.wantOutcome == domain.StatusRefunded {
+				if len(st.tx.savedRefunds) != 1 || st.tx.savedRefunds[0].Amount != tt.wantAmount || st.tx.refundedAdded != tt.wantAmount {
+					t.Fatalf("refund record: got %+v, added %d", st.tx.savedRefunds, st.tx.refundedAdded)
+				}
+				if !st.tx.savedRefunds[0].Succeeded() {
+					t.Fatalf("refund status: got %s, want %s", st.tx.savedRefunds[0].Status, domain.StatusSucceeded)
+				}
+			}
+			if st.tx.tryMarkCalled != 1 {
+				t.Fatalf("TryMarkProcessed calls: got %d, want 1", st.tx.tryMarkCalled)
+			}
+		})
+	}
+}
+
+func TestService_HandleOrderCancelled_ProviderFailure(t *testing.T) {
+	record := domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"}
+	fake := provider.NewFake()
+	authID, err := fake.Authorize(context.Background(), "authorize:2:0", record.Payment)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if err := fake.Capture(context.Background(), "capture:"+authID, authID, record.Amount); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	st := &storageMock{tx: &txMock{tryMarkOK: true, record: record}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, fake, testRetry, log, "payment-status")
+	input := dto.OrderCancelled{EventID: 20, OrderID: 2, UserID: 3}
+
+	fake.FailWith(domain.ErrTransient)
+	if err := svc.HandleOrderCancelled(context.Background(), input); !errors.Is(err, domain.ErrTransient) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	if st.tx.tryMarkCalled != 0 || st.tx.saveEventCalled != 0 {
+		t.Fatalf("a failed cancellation must stay unprocessed")
+	}
+	if len(st.tx.savedRefunds) != 1 || !st.tx.savedRefunds[0].Pending() {
+		t.Fatalf("refund record: got %+v, want one pending", st.tx.savedRefunds)
+	}
+
+	fake.FailWith(nil)
+	if err := svc.HandleOrderCancelled(context.Background(), input); err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if len(st.tx.savedRefunds) != 1 || !st.tx.savedRefunds[0].Succeeded() {
+		t.Fatalf("refund record: got %+v, want the pending one completed", st.tx.savedRefunds)
+	}
+	if got := fake.Refunded(authID); got != 100 {
+		t.Fatalf("refunded: got %d, want 100", got)
+	}
+	if st.tx.status != domain.StatusRefunded || st.tx.tryMarkCalled != 1 {
+		t.Fatalf("payment: got status %s, processed %d times", st.tx.status, st.tx.tryMarkCalled)
+	}
+}
+
+func TestService_HandleOrderCancelled_ChargeInFlight(t *testing.T) {
+	record := domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusPaymentPending}
+	st := &storageMock{tx: &txMock{tryMarkOK: true, record: record}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
+
+	if err := svc.HandleOrderCancelled(context.Background(), dto.OrderCancelled{EventID: 20, OrderID: 2, UserID: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.tx.releaseLeaseCalled != 1 || st.tx.record.Status != domain.StatusCancelling {
+		t.Fatalf("payment: got status %s after %d release leases, want %s",
+			st.tx.record.Status, st.tx.releaseLeaseCalled, domain.StatusCancelling)
+	}
+	if st.tx.saveEventCalled != 0 {
+		t.Fatalf("PaymentCancelled must wait for the charge in flight, saved %q", st.tx.savedType)
+	}
+	if st.tx.tryMarkCalled != 1 {
+		t.Fatalf("TryMarkProcessed calls: got %d, want 1", st.tx.tryMarkCalled)
+	}
+}
+
+func TestService_HandleOrderCancelled_AlreadyCancelled(t *testing.T) {
+	st := &storageMock{tx: &txMock{
+		tryMarkOK: true,
+		record:    domain.PaymentRecord{Payment: domain.Payment{OrderID: 2}, Status: domain.StatusRefunded},
+	}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
+
+	if err := svc.HandleOrderCancelled(context.Background(), dto.OrderCancelled{EventID: 20, OrderID: 2, UserID: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.tx.saveEventCalled != 0 || st.tx.updateCalled != 0 {
+		t.Fatalf("no writes expected for an already cancelled payment")
+	}
+}
+
+func TestService_HandleOrderExpired(t *testing.T) {
+	tests := []struct {
+		name        string
+		record      domain.PaymentRecord
+		wantOutcome string
+		wantUpsert  int
+		wantVoided  bool
+	}{
+		{
+			name:        "payment never requested",
+			wantOutcome: domain.StatusVoided,
+			wantUpsert:  1,
+		},
+		{
+			name:        "authorized, retrying capture",
+			record:      domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusRetrying, AuthID: "fake-2"},
+			wantOutcome: domain.StatusVoided,
+			wantVoided:  true,
+		},
+		{
+			name:        "retrying",
+			record:      domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusRetrying},
+			wantOutcome: domain.StatusVoided,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			if tt.record.AuthID != "" {
+				if _, err := fake.Authorize(context.Background(), "authorize:2:0", tt.record.Payment); err != nil {
+					t.Fatalf("authorize: %v", err)
+				}
+			}
+			st := &storageMock{tx: &txMock{tryMarkOK: true, record: tt.record}}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(st, fake, testRetry, log, "payment-status")
+
+			err := svc.HandleOrderExpired(context.Background(), dto.OrderExpired{EventID: 21, OrderID: 2, UserID: 3})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if st.tx.upsertCalled != tt.wantUpsert {
+				t.Fatalf("UpsertPayment calls: got %d, want %d", st.tx.upsertCalled, tt.wantUpsert)
+			}
+			var ev events.PaymentCancelled
+			if err := json.Unmarshal(st.tx.savedPayload, &ev); err != nil {
+				t.Fatalf("unmarshal saved payload: %v", err)
+			}
+			if st.tx.savedType != events.TypePaymentCancelled || ev.Outcome != tt.wantOutcome {
+				t.Fatalf("event: got %q/%s, want %q/%s", st.tx.savedType, ev.Outcome, events.TypePaymentCancelled, tt.wantOutcome)
+			}
+			if tt.record.OrderID != 0 && st.tx.status != tt.wantOutcome {
+				t.Fatalf("payment status: got %s, want %s", st.tx.status, tt.wantOutcome)
+			}
+			if got := fake.Voided("fake-2"); got != tt.wantVoided {
+				t.Fatalf("voided: got %v, want %v", got, tt.wantVoided)
+			}
+		})
+	}
+}
+
+func TestService_HandleOrderExpired_CapturedBeforeDeadline(t *testing.T) {
+	record := domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"}
+	fake := provider.NewFake()
+	authID, err := fake.Authorize(context.Background(), "authorize:2:0", record.Payment)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if err := fake.Capture(context.Background(), "capture:"+authID, authID, record.Amount); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	st := &storageMock{tx: &txMock{tryMarkOK: true, record: record}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, fake, testRetry, log, "payment-status")
+
+	if err := svc.HandleOrderExpired(context.Background(), dto.OrderExpired{EventID: 21, OrderID: 2, UserID: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fake.Refunded("fake-2"); got != 100 {
+		t.Fatalf("refunded: got %d, want 100", got)
+	}
+	if len(st.tx.savedRefunds) != 1 || st.tx.savedRefunds[0].RequestID != "order-expired:2" || st.tx.savedRefunds[0].Reason != "order expired" {
+		t.Fatalf("refund record: got %+v", st.tx.savedRefunds)
+	}
+	if st.tx.status != domain.StatusRefunded {
+		t.Fatalf("payment status: got %s, want %s", st.tx.status, domain.StatusRefunded)
+	}
+}
+
+func TestService_Refund(t *testing.T) {
+	succeeded := domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"}
+	partially := succeeded
+	partially.Refunded = 70
+
+	tests := []struct {
+		name         string
+		record       domain.PaymentRecord
+		amount       int64
+		wantStatus   string
+		wantErrIs    error
+		wantAmount   int64
+		wantTotal    int64
+		wantRefunded bool
+	}{
+		{name: "full", record: succeeded, wantStatus: domain.StatusSucceeded, wantAmount: 100, wantTotal: 100, wantRefunded: true},
+		{name: "partial", record: succeeded, amount: 30, wantStatus: domain.StatusSucceeded, wantAmount: 30, wantTotal: 30},
+		{name: "rest", record: partially, amount: 30, wantStatus: domain.StatusSucceeded, wantAmount: 30, wantTotal: 100, wantRefunded: true},
+		{name: "rest by zero amount", record: partially, wantStatus: domain.StatusSucceeded, wantAmount: 30, wantTotal: 100, wantRefunded: true},
+		{name: "exceeds refundable", record: partially, amount: 50, wantStatus: domain.StatusFailed, wantErrIs: domain.ErrRefundExceedsPayment, wantAmount: 50},
+		{name: "payment failed", record: domain.PaymentRecord{Payment: succeeded.Payment, Status: domain.StatusFailed}, amount: 10, wantStatus: domain.StatusFailed, wantErrIs: domain.ErrPaymentNotRefundable, wantAmount: 10},
+		{name: "payment not found", amount: 10, wantStatus: domain.StatusFailed, wantErrIs: domain.ErrPaymentNotFound, wantAmount: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			if tt.record.AuthID != "" {
+				authID, _ := fake.Authorize(context.Background(), "authorize:2:0", tt.record.Payment)
+				_ = fake.Capture(context.Background(), "capture:"+authID, authID, tt.record.Amount)
+				if tt.record.Refunded > 0 {
+					_ = fake.Refund(context.Background(), "refund:earlier", authID, tt.record.Refunded)
+				}
+			}
+			st := &storageMock{tx: &txMock{record: tt.record}}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(st, fake, testRetry, log, "payment-status")
+
+			refund, err := svc.Refund(context.Background(), dto.RefundRequest{RequestID: "r-1", OrderID: 2, Amount: tt.amount})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if refund.Status != tt.wantStatus || refund.Amount != tt.wantAmount || refund.ID == 0 {
+				t.Fatalf("refund: got %+v", refund)
+			}
+			if len(st.tx.savedRefunds) != 1 {
+				t.Fatalf("saved refunds: got %d, want 1", len(st.tx.savedRefunds))
+			}
+
+			if tt.wantStatus == domain.StatusFailed {
+				if st.tx.savedType != events.TypeRefundFailed {
+					t.Fatalf("saved event type: got %q", st.tx.savedType)
+				}
+				var ev events.RefundFailed
+				_ = json.Unmarshal(st.tx.savedPayload, &ev)
+				if ev.Reason == "" || ev.Reason != refund.Error {
+					t.Fatalf("event reason: got %q, want %q", ev.Reason, refund.Error)
+				}
+				if !strings.Contains(refund.Error, tt.wantErrIs.Error()) {
+					t.Fatalf("error: got %q, want %v", refund.Error, tt.wantErrIs)
+				}
+				if st.tx.refundedAdded != 0 || fake.Refunded("fake-2") != tt.record.Refunded {
+					t.Fatalf("nothing must be refunded")
+				}
+				return
+			}
+
+			if st.tx.savedType != events.TypeRefundSucceeded {
+				t.Fatalf("saved event type: got %q", st.tx.savedType)
+			}
+			var ev events.RefundSucceeded
+			_ = json.Unmarshal(st.tx.savedPayload, &ev)
+			if ev.Amount != tt.wantAmount || ev.RefundedTotal != tt.wantTotal || ev.UserID != 3 || ev.RefundID != refund.ID {
+				t.Fatalf("event: got %+v", ev)
+			}
+			if st.tx.refundedAdded != tt.wantAmount || fake.Refunded("fake-2") != tt.wantTotal {
+				t.Fatalf("refunded: added %d, provider %d", st.tx.refundedAdded, fake.Refunded("fake-2"))
+			}
+			if tt.wantRefunded != (st.tx.status == domain.StatusRefunded) {
+				t.Fatalf("payment status: got %q", st.tx.status)
+			}
+		})
+	}
+}
+
+func TestService_Refund_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     dto.RefundRequest
+		wantErrIs error
+	}{
+		{"empty request id", dto.RefundRequest{RequestID: " ", OrderID: 2}, domain.ErrInvalidRequestID},
+		{"invalid order", dto.RefundRequest{RequestID: "r-1"}, domain.ErrInvalidOrderID},
+		{"negative amount", dto.RefundRequest{RequestID: "r-1", OrderID: 2, Amount: -1}, domain.ErrInvalidRefundAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &storageMock{tx: &txMock{}}
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
+
+			_, err := svc.Refund(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+			if st.runCalled != 0 {
+				t.Fatalf("RunInTx must not be called")
+			}
+		})
+	}
+}
+
+func TestService_Refund_Replay(t *testing.T) {
+	stored := domain.Refund{ID: 5, RequestID: "r-1", OrderID: 2, Amount: 30, Status: domain.StatusSucceeded}
+	st := &storageMock{tx: &txMock{
+		record:  domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"},
+		refunds: map[string]domain.Refund{"r-1": stored},
+	}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
+
+	refund, err := svc.Refund(context.Background(), dto.RefundRequest{RequestID: "r-1", OrderID: 2, Amount: 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refund != stored {
+		t.Fatalf("refund: got %+v, want %+v", refund, stored)
+	}
+	if len(st.tx.savedRefunds) != 0 || st.tx.saveEventCalled != 0 {
+		t.Fatalf("a replayed request must not write")
+	}
+
+	_, err = svc.Refund(context.Background(), dto.RefundRequest{RequestID: "r-1", OrderID: 9})
+	if !errors.Is(err, domain.ErrRefundRequestReused) {
+		t.Fatalf("expected %v, got %v", domain.ErrRefundRequestReused, err)
+	}
+}
+
+func TestService_Refund_TransientProviderError(t *testing.T) {
+	record := domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"}
+	st := &storageMock{tx: &txMock{record: record}}
+	fake := provider.NewFake()
+	authID, _ := fake.Authorize(context.Background(), "authorize:2:0", record.Payment)
+	_ = fake.Capture(context.Background(), "capture:"+authID, authID, record.Amount)
+	fake.FailWith(domain.ErrTransient)
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, fake, testRetry, log, "payment-status")
+
+	_, err := svc.Refund(context.Background(), dto.RefundRequest{RequestID: "r-1", OrderID: 2})
+	if !errors.Is(err, domain.ErrTransient) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	if st.tx.saveEventCalled != 0 || st.tx.refundedAdded != 0 {
+		t.Fatalf("a transient failure must not be published")
+	}
+	if len(st.tx.savedRefunds) != 1 || !st.tx.savedRefunds[0].Pending() {
+		t.Fatalf("refund record: got %+v, want one pending", st.tx.savedRefunds)
+	}
+
+	fake.FailWith(nil)
+	refund, err := svc.Refund(context.Background(), dto.RefundRequest{RequestID: "r-1", OrderID: 2})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if !refund.Succeeded() || len(st.tx.savedRefunds) != 1 || st.tx.refundedAdded != 100 {
+		t.Fatalf("retry must complete the pending refund: got %+v, saved %d", refund, len(st.tx.savedRefunds))
+	}
+	if got := fake.Refunded("fake-2"); got != 100 {
+		t.Fatalf("refunded: got %d, want 100", got)
+	}
+}
+
+func TestService_Refund_AnotherRefundInProgress(t *testing.T) {
+	record := domain.PaymentRecord{Payment: domain.Payment{OrderID: 2, UserID: 3, Amount: 100}, Status: domain.StatusSucceeded, AuthID: "fake-2"}
+	st := &storageMock{tx: &txMock{
+		record:       record,
+		savedRefunds: []domain.Refund{{ID: 1, RequestID: "r-1", OrderID: 2, Amount: 30, Status: domain.StatusPaymentPending}},
+	}}
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, provider.NewFake(), testRetry, log, "payment-status")
+
+	_, err := svc.Refund(context.Background(), dto.RefundRequest{RequestID: "r-2", OrderID: 2, Amount: 10})
+	if !errors.Is(err, domain.ErrTransient) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	if len(st.tx.savedRefunds) != 1 || st.tx.saveEventCalled != 0 {
+		t.Fatalf("no writes expected while another refund is pending")
+	}
+}
+
+func TestService_HandlePaymentRequested_TerminalProviderError(t *testing.T) {
+	st := &storageMock{tx: &txMock{tryMarkOK: true}}
+	fake := provider.NewFake()
+	fake.FailWith(errors.New("card not supported"))
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, fake, testRetry, log, "payment-status")
+
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+		EventID:     10,
+		OrderID:     2,
+		UserID:      3,
+		TotalAmount: 100,
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if st.tx.retryCalled != 0 {
+		t.Fatalf("terminal error must not be retried")
+	}
 
 	var ev events.PaymentStatus
 	if err := json.Unmarshal(st.tx.savedPayload, &ev); err != nil {
@@ -112,6 +828,121 @@ func TestService_HandleOrderCreated_SuccessOdd(t *testing.T) {
 	}
 }
 
+func TestService_HandlePaymentRequested_TransientProviderError(t *testing.T) {
+	st := &storageMock{tx: &txMock{tryMarkOK: true}}
+	fake := provider.NewFake()
+	fake.FailWith(domain.ErrTransient)
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	svc := New(st, fake, testRetry, log, "payment-status")
+
+	before := time.Now()
+	err := svc.HandlePaymentRequested(context.Background(), dto.PaymentRequested{
+		EventID:     10,
+		OrderID:     2,
+		UserID:      3,
+		TotalAmount: 100,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.tx.retryCalled != 1 {
+		t.Fatalf("ScheduleRetry calls: got %d, want %d", st.tx.retryCalled, 1)
+	}
+	if st.tx.nextAttemptAt.Before(before.Add(testRetry.BaseDelay)) {
+		t.Fatalf("next attempt scheduled too early: %v", st.tx.nextAttemptAt)
+	}
+	if st.tx.updateCalled != 0 || st.tx.saveEventCalled != 0 {
+		t.Fatalf("no final status expected while retries remain")
+	}
+}
+
+func TestService_RetryDuePayments(t *testing.T) {
+	tests := []struct {
+		name        string
+		attempts    int
+		failWith    error
+		wantRetry   int
+		wantStatus  string
+		wantUpdates int
+	}{
+		{
+			name:        "succeeds on retry",
+			attempts:    1,
+			wantStatus:  domain.StatusSucceeded,
+			wantUpdates: 1,
+		},
+		{
+			name:      "rescheduled",
+			attempts:  1,
+			failWith:  domain.ErrTransient,
+			wantRetry: 1,
+		},
+		{
+			name:        "attempts exhausted",
+			attempts:    testRetry.MaxAttempts - 1,
+			failWith:    domain.ErrTransient,
+			wantStatus:  domain.StatusFailed,
+			wantUpdates: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &storageMock{tx: &txMock{
+				due: []domain.PaymentRecord{{
+					Payment: domain.Payment{OrderID: 5, UserID: 1, Amount: 100, Attempts: tt.attempts},
+					Status:  domain.StatusRetrying,
+				}},
+			}}
+			fake := provider.NewFake()
+			fake.FailWith(tt.failWith)
+			log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+			svc := New(st, fake, testRetry, log, "payment-status")
+
+			processed, err := svc.RetryDuePayments(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if processed != 1 {
+				t.Fatalf("processed: got %d, want %d", processed, 1)
+			}
+			if st.tx.retryCalled != tt.wantRetry {
+				t.Fatalf("ScheduleRetry calls: got %d, want %d", st.tx.retryCalled, tt.wantRetry)
+			}
+			if st.tx.updateCalled != tt.wantUpdates {
+				t.Fatalf("UpdatePaymentStatus calls: got %d, want %d", st.tx.updateCalled, tt.wantUpdates)
+			}
+			if tt.wantStatus != "" && st.tx.status != tt.wantStatus {
+				t.Fatalf("status: got %s, want %s", st.tx.status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.attempt); got != tt.want {
+			t.Fatalf("Backoff(%d): got %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+var testRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+
 type storageMock struct {
 	tx        *txMock
 	runCalled int
@@ -122,14 +953,18 @@ func (m *storageMock) RunInTx(ctx context.Context, fn func(tx postgres.TxReposit
 	return fn(m.tx)
 }
 
-func (m *storageMock) GetNewEvent(ctx context.Context) (events.PaymentStatus, error) {
-	return events.PaymentStatus{}, nil
+func (m *storageMock) GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error) {
+	return nil, nil
 }
 
-func (m *storageMock) MarkSent(ctx context.Context, id int64) error {
+func (m *storageMock) MarkSent(ctx context.Context, ids []int64) error {
 	return nil
 }
 
+func (m *storageMock) MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
 func (m *storageMock) Close() error {
 	return nil
 }
@@ -140,25 +975,92 @@ func (m *storageMock) Ping(ctx context.Context) error {
 
 type txMock struct {
 	tryMarkOK bool
+	processed bool
 
 	tryMarkCalled   int
 	upsertCalled    int
 	updateCalled    int
 	saveEventCalled int
 
+	upserted domain.Money
+
 	savedPayload []byte
+	authID       string
+	status       string
+
+	retryCalled        int
+	leaseCalled        int
+	releaseLeaseCalled int
+	nextAttemptAt      time.Time
+	due                []domain.PaymentRecord
+
+	record    domain.PaymentRecord
+	savedType string
+
+	refunds       map[string]domain.Refund
+	savedRefunds  []domain.Refund
+	refundedAdded int64
+}
+
+func (m *txMock) LeaseAttempt(ctx context.Context, orderID int64, leaseUntil time.Time) error {
+	m.leaseCalled++
+	m.record.OrderID = orderID
+	m.record.Status = domain.StatusPaymentPending
+	return nil
 }
 
-func (m *txMock) UpsertPayment(ctx context.Context, orderID, userID, totalAmount int64, status string) error {
+func (m *txMock) LeaseRelease(ctx context.Context, orderID int64, leaseUntil time.Time) error {
+	m.releaseLeaseCalled++
+	m.record.Status = domain.StatusCancelling
+	m.nextAttemptAt = leaseUntil
+	return nil
+}
+
+func (m *txMock) ScheduleRetry(ctx context.Context, orderID int64, nextAttemptAt time.Time, lastErr string) error {
+	m.retryCalled++
+	m.record.Status = domain.StatusRetrying
+	m.nextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (m *txMock) GetDuePayment(ctx context.Context, now time.Time) (domain.PaymentRecord, error) {
+	if len(m.due) == 0 {
+		return domain.PaymentRecord{}, nil
+	}
+	record := m.due[0]
+	m.due = m.due[1:]
+	return record, nil
+}
+
+func (m *txMock) GetPaymentForUpdate(ctx context.Context, orderID int64) (domain.PaymentRecord, error) {
+	return m.record, nil
+}
+
+func (m *txMock) SaveAuthorization(ctx context.Context, orderID int64, authID string) error {
+	m.authID = authID
+	m.record.AuthID = authID
+	return nil
+}
+
+func (m *txMock) UpsertPayment(ctx context.Context, orderID, userID int64, total domain.Money, status string) error {
 	m.upsertCalled++
+	m.upserted = total
+	m.record.Payment = domain.Payment{OrderID: orderID, UserID: userID, Amount: total.Amount, Currency: total.Currency}
+	m.record.Status = status
 	return nil
 }
 
 func (m *txMock) UpdatePaymentStatus(ctx context.Context, orderID int64, status string) error {
 	m.updateCalled++
+	m.status = status
+	m.record.Status = status
 	return nil
 }
 
+func (m *txMock) IsProcessed(ctx context.Context, eventId int64) (bool, error) {
+	return m.processed, nil
+}
+
 func (m *txMock) TryMarkProcessed(ctx context.Context, eventId int64) (bool, error) {
 	m.tryMarkCalled++
 	return m.tryMarkOK, nil
@@ -166,6 +1068,45 @@ func (m *txMock) TryMarkProcessed(ctx context.Context, eventId int64) (bool, err
 
 func (m *txMock) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
 	m.saveEventCalled++
+	m.savedType = eventType
 	m.savedPayload = payload
 	return nil
 }
+
+func (m *txMock) GetRefund(ctx context.Context, requestID string) (domain.Refund, error) {
+	for _, refund := range m.savedRefunds {
+		if refund.RequestID == requestID {
+			return refund, nil
+		}
+	}
+	return m.refunds[requestID], nil
+}
+
+func (m *txMock) SaveRefund(ctx context.Context, refund domain.Refund) (domain.Refund, error) {
+	refund.ID = int64(len(m.savedRefunds) + 1)
+	m.savedRefunds = append(m.savedRefunds, refund)
+	return refund, nil
+}
+
+func (m *txMock) GetPendingRefund(ctx context.Context, orderID int64) (domain.Refund, error) {
+	for _, refund := range m.savedRefunds {
+		if refund.OrderID == orderID && refund.Pending() {
+			return refund, nil
+		}
+	}
+	return domain.Refund{}, nil
+}
+
+func (m *txMock) CompleteRefund(ctx context.Context, refund domain.Refund) error {
+	for i := range m.savedRefunds {
+		if m.savedRefunds[i].ID == refund.ID {
+			m.savedRefunds[i] = refund
+		}
+	}
+	return nil
+}
+
+func (m *txMock) AddRefundedAmount(ctx context.Context, orderID, amount int64) error {
+	m.refundedAdded += amount
+	return nil
+}
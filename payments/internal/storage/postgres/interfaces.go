@@ -2,21 +2,37 @@ package postgres
 
 import (
 	"context"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
 
 	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain/events"
 )
 
 type TxRepository interface {
-	UpsertPayment(ctx context.Context, orderID, userID, totalAmount int64, status string) error
+	UpsertPayment(ctx context.Context, orderID, userID int64, total domain.Money, status string) error
 	UpdatePaymentStatus(ctx context.Context, orderID int64, status string) error
+	SaveAuthorization(ctx context.Context, orderID int64, authID string) error
+	LeaseAttempt(ctx context.Context, orderID int64, leaseUntil time.Time) error
+	LeaseRelease(ctx context.Context, orderID int64, leaseUntil time.Time) error
+	ScheduleRetry(ctx context.Context, orderID int64, nextAttemptAt time.Time, lastErr string) error
+	GetDuePayment(ctx context.Context, now time.Time) (domain.PaymentRecord, error)
+	GetPaymentForUpdate(ctx context.Context, orderID int64) (domain.PaymentRecord, error)
+	GetRefund(ctx context.Context, requestID string) (domain.Refund, error)
+	GetPendingRefund(ctx context.Context, orderID int64) (domain.Refund, error)
+	SaveRefund(ctx context.Context, refund domain.Refund) (domain.Refund, error)
+	CompleteRefund(ctx context.Context, refund domain.Refund) error
+	AddRefundedAmount(ctx context.Context, orderID, amount int64) error
+	IsProcessed(ctx context.Context, eventId int64) (bool, error)
 	TryMarkProcessed(ctx context.Context, eventId int64) (bool, error)
 	SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error
 }
 
 type Repository interface {
 	RunInTx(ctx context.Context, fn func(tx TxRepository) error) error
-	GetNewEvent(ctx context.Context) (events.PaymentStatus, error)
-	MarkSent(ctx context.Context, id int64) error
+	GetNewEvents(ctx context.Context, limit int) ([]events.Outbox, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, eventID int64, reason string, maxAttempts int) (dead bool, err error)
 	Ping(ctx context.Context) error
 	Close() error
 }
@@ -2,7 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -14,7 +18,12 @@ type TxStorage struct {
 func (s *TxStorage) UpdatePaymentStatus(ctx context.Context, orderID int64, status string) error {
 	const op = "storage.postgres.UpdatePaymentStatus"
 
-	const query = `UPDATE payments SET status = $1 WHERE order_id = $2;`
+	const query = `
+		UPDATE payments
+		SET status = $1,
+		    next_attempt_at = NULL
+		WHERE order_id = $2;
+	`
 
 	if _, err := s.tx.Exec(ctx, query, status, orderID); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -22,19 +31,143 @@ func (s *TxStorage) UpdatePaymentStatus(ctx context.Context, orderID int64, stat
 	return nil
 }
 
-func (s *TxStorage) UpsertPayment(ctx context.Context, orderID, userID, totalAmount int64, status string) error {
+func (s *TxStorage) SaveAuthorization(ctx context.Context, orderID int64, authID string) error {
+	const op = "storage.postgres.SaveAuthorization"
+
+	const query = `UPDATE payments SET provider_ref = $1 WHERE order_id = $2;`
+
+	if _, err := s.tx.Exec(ctx, query, authID, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *TxStorage) ScheduleRetry(ctx context.Context, orderID int64, nextAttemptAt time.Time, lastErr string) error {
+	const op = "storage.postgres.ScheduleRetry"
+
+	const query = `
+		UPDATE payments
+		SET status = $1,
+		    attempts = attempts + 1,
+		    next_attempt_at = $2,
+		    last_error = $3
+		WHERE order_id = $4;
+	`
+
+	if _, err := s.tx.Exec(ctx, query, domain.StatusRetrying, nextAttemptAt, lastErr, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// LeaseAttempt marks the payment of orderID pending while a charge attempt
+// is in flight. A payment still pending after leaseUntil is returned by
+// GetDuePayment, so an attempt interrupted before its outcome was stored is
+// picked up again.
+func (s *TxStorage) LeaseAttempt(ctx context.Context, orderID int64, leaseUntil time.Time) error {
+	const op = "storage.postgres.LeaseAttempt"
+
+	const query = `
+		UPDATE payments
+		SET status = $1,
+		    next_attempt_at = $2
+		WHERE order_id = $3;
+	`
+
+	if _, err := s.tx.Exec(ctx, query, domain.StatusPaymentPending, leaseUntil, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// LeaseRelease marks the payment of orderID cancelling until the charge
+// made for it is given back. Like LeaseAttempt it is returned by
+// GetDuePayment after leaseUntil, so a release that failed or was
+// interrupted is retried.
+func (s *TxStorage) LeaseRelease(ctx context.Context, orderID int64, leaseUntil time.Time) error {
+	const op = "storage.postgres.LeaseRelease"
+
+	const query = `
+		UPDATE payments
+		SET status = $1,
+		    next_attempt_at = $2
+		WHERE order_id = $3;
+	`
+
+	if _, err := s.tx.Exec(ctx, query, domain.StatusCancelling, leaseUntil, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetDuePayment locks one payment whose retry or release is due or whose
+// in-flight attempt outlived its lease. A zero PaymentRecord means there is
+// nothing to retry.
+func (s *TxStorage) GetDuePayment(ctx context.Context, now time.Time) (domain.PaymentRecord, error) {
+	const op = "storage.postgres.GetDuePayment"
+
+	const query = `
+		SELECT order_id, user_id, total_amount, currency, attempts, status, COALESCE(provider_ref, '')
+		FROM payments
+		WHERE status IN ($1, $2, $3) AND next_attempt_at <= $4
+		ORDER BY next_attempt_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED;
+	`
+
+	var record domain.PaymentRecord
+	err := s.tx.QueryRow(ctx, query, domain.StatusRetrying, domain.StatusPaymentPending, domain.StatusCancelling, now).Scan(
+		&record.OrderID, &record.UserID, &record.Amount, &record.Currency, &record.Attempts, &record.Status, &record.AuthID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.PaymentRecord{}, nil
+	}
+	if err != nil {
+		return domain.PaymentRecord{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return record, nil
+}
+
+// GetPaymentForUpdate locks the payment of orderID. A zero PaymentRecord
+// means payments has not seen the order.
+func (s *TxStorage) GetPaymentForUpdate(ctx context.Context, orderID int64) (domain.PaymentRecord, error) {
+	const op = "storage.postgres.GetPaymentForUpdate"
+
+	const query = `
+		SELECT order_id, user_id, total_amount, currency, attempts, status, COALESCE(provider_ref, ''), refunded_amount
+		FROM payments
+		WHERE order_id = $1
+		FOR UPDATE;
+	`
+
+	var record domain.PaymentRecord
+	err := s.tx.QueryRow(ctx, query, orderID).Scan(
+		&record.OrderID, &record.UserID, &record.Amount, &record.Currency, &record.Attempts, &record.Status,
+		&record.AuthID, &record.Refunded)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.PaymentRecord{}, nil
+	}
+	if err != nil {
+		return domain.PaymentRecord{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return record, nil
+}
+
+func (s *TxStorage) UpsertPayment(ctx context.Context, orderID, userID int64, total domain.Money, status string) error {
 	const op = "storage.postgres.UpsertPayment"
 
 	const query = `
-		INSERT INTO payments (order_id, user_id, total_amount, status)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO payments (order_id, user_id, total_amount, currency, status)
+		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (order_id)
 		DO UPDATE SET user_id = EXCLUDED.user_id,
 		              total_amount = EXCLUDED.total_amount,
+		              currency = EXCLUDED.currency,
 		              status = EXCLUDED.status;
 	`
 
-	if _, err := s.tx.Exec(ctx, query, orderID, userID, totalAmount, status); err != nil {
+	if _, err := s.tx.Exec(ctx, query, orderID, userID, total.Amount, total.Currency, status); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
@@ -26,6 +26,20 @@ func (s *TxStorage) TryMarkProcessed(ctx context.Context, eventId int64) (bool,
 	return res.RowsAffected() == rowsInserted, nil
 }
 
+// IsProcessed reports whether eventId was marked processed, without marking it.
+func (s *TxStorage) IsProcessed(ctx context.Context, eventId int64) (bool, error) {
+	const op = "storage.postgres.IsProcessed"
+
+	const query = `SELECT EXISTS (SELECT 1 FROM processed_events WHERE event_id = $1);`
+
+	var processed bool
+	if err := s.tx.QueryRow(ctx, query, eventId).Scan(&processed); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return processed, nil
+}
+
 func (s *Storage) RunInTx(ctx context.Context, fn func(tx TxRepository) error) error {
 	const op = "storage.postgres.RunInTx"
 
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PruneSentEvents removes up to limit events sent before the cutoff, copying
+// them to events_archive first when archive is set. It returns the number of
+// rows removed from events.
+func (s *Storage) PruneSentEvents(ctx context.Context, before time.Time, limit int, archive bool) (int64, error) {
+	const op = "storage.postgres.PruneSentEvents"
+
+	const deleteQuery = `
+		DELETE FROM events
+		WHERE id IN (
+			SELECT id
+			FROM events
+			WHERE sent_at IS NOT NULL AND sent_at < $1
+			ORDER BY id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	const archiveQuery = `
+		WITH moved AS (
+			DELETE FROM events
+			WHERE id IN (
+				SELECT id
+				FROM events
+				WHERE sent_at IS NOT NULL AND sent_at < $1
+				ORDER BY id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, event_type, payload, aggregate_id, created_at, sent_at, correlation_id, causation_id
+		), archived AS (
+			INSERT INTO events_archive (id, event_type, payload, aggregate_id, created_at, sent_at, correlation_id, causation_id)
+			SELECT id, event_type, payload, aggregate_id, created_at, sent_at, correlation_id, causation_id
+			FROM moved
+			ON CONFLICT (id) DO NOTHING
+		)
+		SELECT count(*) FROM moved
+	`
+
+	if archive {
+		var removed int64
+		if err := s.db.QueryRow(ctx, archiveQuery, before, limit).Scan(&removed); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		return removed, nil
+	}
+
+	tag, err := s.db.Exec(ctx, deleteQuery, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// PruneProcessedEvents trims up to limit dedup records processed before the cutoff.
+func (s *Storage) PruneProcessedEvents(ctx context.Context, before time.Time, limit int) (int64, error) {
+	const op = "storage.postgres.PruneProcessedEvents"
+
+	const query = `
+		DELETE FROM processed_events
+		WHERE event_id IN (
+			SELECT event_id
+			FROM processed_events
+			WHERE processed_at < $1
+			ORDER BY processed_at
+			LIMIT $2
+		)
+	`
+
+	tag, err := s.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
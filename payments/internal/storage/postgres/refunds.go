@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ChernykhITMO/order-processing-platform/payments/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetRefund returns the refund stored for requestID. A zero Refund means the
+// request has not been seen.
+func (s *TxStorage) GetRefund(ctx context.Context, requestID string) (domain.Refund, error) {
+	const op = "storage.postgres.GetRefund"
+
+	const query = `
+		SELECT id, request_id, order_id, amount, status, reason, error, created_at
+		FROM refunds
+		WHERE request_id = $1;
+	`
+
+	var refund domain.Refund
+	err := s.tx.QueryRow(ctx, query, requestID).Scan(
+		&refund.ID, &refund.RequestID, &refund.OrderID, &refund.Amount,
+		&refund.Status, &refund.Reason, &refund.Error, &refund.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Refund{}, nil
+	}
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return refund, nil
+}
+
+// GetPendingRefund returns the refund of orderID still waiting for the
+// provider. A zero Refund means there is none; idx_refunds_pending_order
+// allows at most one.
+func (s *TxStorage) GetPendingRefund(ctx context.Context, orderID int64) (domain.Refund, error) {
+	const op = "storage.postgres.GetPendingRefund"
+
+	const query = `
+		SELECT id, request_id, order_id, amount, status, reason, error, created_at
+		FROM refunds
+		WHERE order_id = $1 AND status = $2
+		FOR UPDATE;
+	`
+
+	var refund domain.Refund
+	err := s.tx.QueryRow(ctx, query, orderID, domain.StatusPaymentPending).Scan(
+		&refund.ID, &refund.RequestID, &refund.OrderID, &refund.Amount,
+		&refund.Status, &refund.Reason, &refund.Error, &refund.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Refund{}, nil
+	}
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return refund, nil
+}
+
+// SaveRefund stores a refund request, pending or with its outcome, and
+// returns it with the generated id and creation time.
+func (s *TxStorage) SaveRefund(ctx context.Context, refund domain.Refund) (domain.Refund, error) {
+	const op = "storage.postgres.SaveRefund"
+
+	const query = `
+		INSERT INTO refunds (request_id, order_id, amount, status, reason, error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at;
+	`
+
+	err := s.tx.QueryRow(ctx, query,
+		refund.RequestID, refund.OrderID, refund.Amount, refund.Status, refund.Reason, refund.Error,
+	).Scan(&refund.ID, &refund.CreatedAt)
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return refund, nil
+}
+
+// CompleteRefund stores the outcome of a pending refund.
+func (s *TxStorage) CompleteRefund(ctx context.Context, refund domain.Refund) error {
+	const op = "storage.postgres.CompleteRefund"
+
+	const query = `UPDATE refunds SET status = $1, error = $2 WHERE id = $3;`
+
+	if _, err := s.tx.Exec(ctx, query, refund.Status, refund.Error, refund.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// AddRefundedAmount adds amount to the refunded total of the payment. The
+// payments_refunded_amount_check constraint rejects a total above
+// total_amount.
+func (s *TxStorage) AddRefundedAmount(ctx context.Context, orderID, amount int64) error {
+	const op = "storage.postgres.AddRefundedAmount"
+
+	const query = `UPDATE payments SET refunded_amount = refunded_amount + $1 WHERE order_id = $2;`
+
+	if _, err := s.tx.Exec(ctx, query, amount, orderID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -1,12 +1,29 @@
 package postgres
 
-import "context"
+import (
+	"context"
+
+	"github.com/ChernykhITMO/order-processing-platform/eventkit/envelope"
+)
 
 func (s *TxStorage) SaveEvent(ctx context.Context, eventType string, payload []byte, aggregateID int64) error {
 	const query = `
-          INSERT INTO events (event_type, payload, aggregate_id)
-          VALUES ($1, $2, $3)
+          INSERT INTO events (event_type, payload, aggregate_id, correlation_id, causation_id)
+          VALUES ($1, $2, $3, $4, NULLIF($5, ''))
       `
-	_, err := s.tx.Exec(ctx, query, eventType, payload, aggregateID)
+
+	// events saved outside a consumed message (payment retries) still share
+	// the order's correlation id
+	meta := envelope.MetaFrom(ctx)
+	if meta.CorrelationID == "" {
+		meta.CorrelationID = envelope.DefaultCorrelationID(aggregateID)
+	}
+
+	if _, err := s.tx.Exec(ctx, query, eventType, payload, aggregateID, meta.CorrelationID, meta.CausationID); err != nil {
+		return err
+	}
+
+	// delivered on commit, wakes the outbox sender
+	_, err := s.tx.Exec(ctx, `SELECT pg_notify($1, '')`, EventsChannel)
 	return err
 }
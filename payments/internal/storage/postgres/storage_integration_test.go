@@ -32,7 +32,7 @@ func getPaymentsDSN(t *testing.T) string {
 }
 
 func cleanupPaymentsTables(t *testing.T, db *pgxpool.Pool) {
-	const query = `TRUNCATE TABLE payments, events, processed_events RESTART IDENTITY CASCADE`
+	const query = `TRUNCATE TABLE payments, refunds, events, events_archive, processed_events RESTART IDENTITY CASCADE`
 	if _, err := db.Exec(context.Background(), query); err != nil {
 		t.Fatalf("db exec: %v", err)
 	}
@@ -79,7 +79,7 @@ func TestPaymentsStorage_RunInTxAndOutbox_Integration(t *testing.T) {
 			t.Fatalf("expected event to be marked processed")
 		}
 
-		if err := tx.UpsertPayment(ctx, orderID, userID, totalAmount, domain.StatusPaymentPending); err != nil {
+		if err := tx.UpsertPayment(ctx, orderID, userID, domain.Money{Amount: totalAmount, Currency: "EUR"}, domain.StatusPaymentPending); err != nil {
 			return err
 		}
 		if err := tx.UpdatePaymentStatus(ctx, orderID, domain.StatusSucceeded); err != nil {
@@ -113,13 +113,16 @@ func TestPaymentsStorage_RunInTxAndOutbox_Integration(t *testing.T) {
 		t.Fatalf("run in tx: %v", err)
 	}
 
-	var status string
-	if err := db.QueryRow(context.Background(), `SELECT status FROM payments WHERE order_id = $1`, orderID).Scan(&status); err != nil {
+	var status, currency string
+	if err := db.QueryRow(context.Background(), `SELECT status, currency FROM payments WHERE order_id = $1`, orderID).Scan(&status, &currency); err != nil {
 		t.Fatalf("select payment: %v", err)
 	}
 	if status != domain.StatusSucceeded {
 		t.Fatalf("payment status: got %s, want %s", status, domain.StatusSucceeded)
 	}
+	if currency != "EUR" {
+		t.Fatalf("payment currency: got %s, want %s", currency, "EUR")
+	}
 
 	var processedCount int
 	if err := db.QueryRow(context.Background(), `SELECT COUNT(*) FROM processed_events WHERE event_id = $1`, eventID).Scan(&processedCount); err != nil {
@@ -129,26 +132,30 @@ func TestPaymentsStorage_RunInTxAndOutbox_Integration(t *testing.T) {
 		t.Fatalf("processed_events count: got %d, want %d", processedCount, 1)
 	}
 
-	evt, err := storage.GetNewEvent(ctx)
+	batch, err := storage.GetNewEvents(ctx, 10)
 	if err != nil {
-		t.Fatalf("get new event: %v", err)
+		t.Fatalf("get new events: %v", err)
+	}
+	if len(batch) != 1 {
+		t.Fatalf("events: got %d, want %d", len(batch), 1)
 	}
+	evt := batch[0]
 	if evt.EventID == 0 {
 		t.Fatalf("expected event id to be set")
 	}
-	if evt.OrderID != orderID {
-		t.Fatalf("event order id: got %d, want %d", evt.OrderID, orderID)
+	if evt.AggregateID != orderID {
+		t.Fatalf("event aggregate id: got %d, want %d", evt.AggregateID, orderID)
 	}
 
-	if err := storage.MarkSent(ctx, evt.EventID); err != nil {
+	if err := storage.MarkSent(ctx, []int64{evt.EventID}); err != nil {
 		t.Fatalf("mark sent: %v", err)
 	}
 
-	evt, err = storage.GetNewEvent(ctx)
+	batch, err = storage.GetNewEvents(ctx, 10)
 	if err != nil {
-		t.Fatalf("get new event: %v", err)
+		t.Fatalf("get new events: %v", err)
 	}
-	if evt.EventID != 0 {
+	if len(batch) != 0 {
 		t.Fatalf("expected no new events")
 	}
 }
@@ -189,15 +196,388 @@ func TestPaymentsStorage_LockedEvents_Integration(t *testing.T) {
 		t.Fatalf("update locked_at: %v", err)
 	}
 
-	evt, err := storage.GetNewEvent(context.Background())
+	batch, err := storage.GetNewEvents(context.Background(), 10)
 	if err != nil {
-		t.Fatalf("get new event: %v", err)
+		t.Fatalf("get new events: %v", err)
 	}
-	if evt.EventID == 0 {
+	if len(batch) != 1 {
 		t.Fatalf("expected stale locked event to be picked")
 	}
 }
 
+func TestPaymentsStorage_ListenEvents_Integration(t *testing.T) {
+	dsn := getPaymentsDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupPaymentsTables(t, db)
+	defer cleanupPaymentsTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wake := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() { done <- storage.ListenEvents(ctx, wake) }()
+
+	select {
+	case <-wake:
+	case err := <-done:
+		t.Fatalf("listen: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("listener did not subscribe")
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		return tx.SaveEvent(ctx, "event-status", []byte(`{}`), 1)
+	}); err != nil {
+		t.Fatalf("save event: %v", err)
+	}
+
+	select {
+	case <-wake:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected notification after commit")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("listen after cancel: %v", err)
+	}
+}
+
+func TestPaymentsStorage_ScheduleRetry_Integration(t *testing.T) {
+	dsn := getPaymentsDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupPaymentsTables(t, db)
+	defer cleanupPaymentsTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		if err := tx.UpsertPayment(ctx, 1, 10, domain.Money{Amount: 100, Currency: "RUB"}, domain.StatusPaymentPending); err != nil {
+			return err
+		}
+		if err := tx.UpsertPayment(ctx, 2, 10, domain.Money{Amount: 200, Currency: "RUB"}, domain.StatusPaymentPending); err != nil {
+			return err
+		}
+		if err := tx.ScheduleRetry(ctx, 1, now.Add(-time.Second), "timeout"); err != nil {
+			return err
+		}
+		return tx.ScheduleRetry(ctx, 2, now.Add(time.Hour), "timeout")
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		payment, err := tx.GetDuePayment(ctx, now)
+		if err != nil {
+			return err
+		}
+		if payment.OrderID != 1 {
+			t.Fatalf("due payment: got order %d, want %d", payment.OrderID, 1)
+		}
+		if payment.Attempts != 1 {
+			t.Fatalf("attempts: got %d, want %d", payment.Attempts, 1)
+		}
+		return tx.UpdatePaymentStatus(ctx, payment.OrderID, domain.StatusSucceeded)
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		payment, err := tx.GetDuePayment(ctx, now)
+		if err != nil {
+			return err
+		}
+		if payment.OrderID != 0 {
+			t.Fatalf("expected no due payments, got order %d", payment.OrderID)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+}
+
+func TestPaymentsStorage_LeaseAttempt_Integration(t *testing.T) {
+	dsn := getPaymentsDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupPaymentsTables(t, db)
+	defer cleanupPaymentsTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		if err := tx.UpsertPayment(ctx, 1, 10, domain.Money{Amount: 100, Currency: "RUB"}, domain.StatusPaymentPending); err != nil {
+			return err
+		}
+		if err := tx.UpsertPayment(ctx, 2, 10, domain.Money{Amount: 200, Currency: "RUB"}, domain.StatusPaymentPending); err != nil {
+			return err
+		}
+		if err := tx.LeaseAttempt(ctx, 1, now.Add(-time.Second)); err != nil {
+			return err
+		}
+		return tx.LeaseAttempt(ctx, 2, now.Add(time.Hour))
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		payment, err := tx.GetDuePayment(ctx, now)
+		if err != nil {
+			return err
+		}
+		if payment.OrderID != 1 || payment.Attempts != 0 {
+			t.Fatalf("due payment: got order %d attempts %d, want order 1 attempts 0", payment.OrderID, payment.Attempts)
+		}
+
+		processed, err := tx.IsProcessed(ctx, 7)
+		if err != nil {
+			return err
+		}
+		if processed {
+			t.Fatalf("event must not be processed yet")
+		}
+		if _, err := tx.TryMarkProcessed(ctx, 7); err != nil {
+			return err
+		}
+		processed, err = tx.IsProcessed(ctx, 7)
+		if err != nil {
+			return err
+		}
+		if !processed {
+			t.Fatalf("event must be processed")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+}
+
+func TestPaymentsStorage_LeaseRelease_Integration(t *testing.T) {
+	dsn := getPaymentsDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupPaymentsTables(t, db)
+	defer cleanupPaymentsTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		if err := tx.UpsertPayment(ctx, 1, 10, domain.Money{Amount: 100, Currency: "RUB"}, domain.StatusPaymentPending); err != nil {
+			return err
+		}
+		if err := tx.SaveAuthorization(ctx, 1, "auth-1"); err != nil {
+			return err
+		}
+		return tx.LeaseRelease(ctx, 1, now.Add(-time.Second))
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		record, err := tx.GetDuePayment(ctx, now)
+		if err != nil {
+			return err
+		}
+		if record.OrderID != 1 || record.Status != domain.StatusCancelling || record.AuthID != "auth-1" {
+			t.Fatalf("due payment: got order %d status %s auth %q, want order 1 %s auth-1",
+				record.OrderID, record.Status, record.AuthID, domain.StatusCancelling)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+}
+
+func TestPaymentsStorage_Refunds_Integration(t *testing.T) {
+	dsn := getPaymentsDSN(t)
+
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() {
+		db.Close()
+	}()
+
+	cleanupPaymentsTables(t, db)
+	defer cleanupPaymentsTables(t, db)
+
+	storage, err := New(configForTest(dsn))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	defer func() {
+		_ = storage.Close()
+	}()
+
+	ctx := context.Background()
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		if err := tx.UpsertPayment(ctx, 1, 10, domain.Money{Amount: 100, Currency: "RUB"}, domain.StatusSucceeded); err != nil {
+			return err
+		}
+		refund, err := tx.SaveRefund(ctx, domain.Refund{RequestID: "r-1", OrderID: 1, Amount: 60, Status: domain.StatusSucceeded})
+		if err != nil {
+			return err
+		}
+		if refund.ID == 0 || refund.CreatedAt.IsZero() {
+			t.Fatalf("saved refund: got %+v", refund)
+		}
+		return tx.AddRefundedAmount(ctx, 1, 60)
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		record, err := tx.GetPaymentForUpdate(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if record.Refunded != 60 || record.Refundable() != 40 {
+			t.Fatalf("record: refunded %d, refundable %d", record.Refunded, record.Refundable())
+		}
+
+		refund, err := tx.GetRefund(ctx, "r-1")
+		if err != nil {
+			return err
+		}
+		if refund.OrderID != 1 || refund.Amount != 60 || !refund.Succeeded() {
+			t.Fatalf("stored refund: got %+v", refund)
+		}
+
+		missing, err := tx.GetRefund(ctx, "r-2")
+		if err != nil {
+			return err
+		}
+		if missing.ID != 0 {
+			t.Fatalf("expected no refund, got %+v", missing)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		return tx.AddRefundedAmount(ctx, 1, 50)
+	})
+	if err == nil {
+		t.Fatalf("expected refunded_amount above total_amount to be rejected")
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		_, err := tx.SaveRefund(ctx, domain.Refund{RequestID: "r-1", OrderID: 1, Amount: 10, Status: domain.StatusSucceeded})
+		return err
+	})
+	if err == nil {
+		t.Fatalf("expected a duplicate request id to be rejected")
+	}
+
+	if err := storage.RunInTx(ctx, func(tx TxRepository) error {
+		pending, err := tx.SaveRefund(ctx, domain.Refund{RequestID: "r-3", OrderID: 1, Amount: 40, Status: domain.StatusPaymentPending})
+		if err != nil {
+			return err
+		}
+		found, err := tx.GetPendingRefund(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if found.ID != pending.ID {
+			t.Fatalf("pending refund: got %+v, want id %d", found, pending.ID)
+		}
+		pending.Status = domain.StatusSucceeded
+		if err := tx.CompleteRefund(ctx, pending); err != nil {
+			return err
+		}
+		found, err = tx.GetPendingRefund(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if found.ID != 0 {
+			t.Fatalf("expected no pending refund, got %+v", found)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+
+	err = storage.RunInTx(ctx, func(tx TxRepository) error {
+		if _, err := tx.SaveRefund(ctx, domain.Refund{RequestID: "r-4", OrderID: 1, Amount: 1, Status: domain.StatusPaymentPending}); err != nil {
+			return err
+		}
+		_, err := tx.SaveRefund(ctx, domain.Refund{RequestID: "r-5", OrderID: 1, Amount: 1, Status: domain.StatusPaymentPending})
+		return err
+	})
+	if err == nil {
+		t.Fatalf("expected a second pending refund of the order to be rejected")
+	}
+}
+
 func configForTest(dsn string) config.DBConfig {
 	return config.DBConfig{
 		DSN:               dsn,
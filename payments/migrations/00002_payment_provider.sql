@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS provider_ref TEXT DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS provider_ref;
@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS attempts        INT         NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS last_error      TEXT        DEFAULT NULL;
+
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments
+    ADD CONSTRAINT payments_status_check
+        CHECK (status IN ('pending', 'retrying', 'succeeded', 'failed'));
+
+CREATE INDEX IF NOT EXISTS idx_payments_due_retry
+    ON payments (next_attempt_at) WHERE status = 'retrying';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_payments_due_retry;
+
+UPDATE payments SET status = 'failed' WHERE status = 'retrying';
+
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments
+    ADD CONSTRAINT payments_status_check
+        CHECK (status IN ('pending', 'succeeded', 'failed'));
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS events_archive
+(
+    id           BIGINT PRIMARY KEY,
+    event_type   TEXT        NOT NULL,
+    payload      JSONB       NOT NULL,
+    aggregate_id BIGINT      NOT NULL,
+    created_at   TIMESTAMPTZ,
+    sent_at      TIMESTAMPTZ NOT NULL,
+    archived_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_sent ON events (sent_at) WHERE sent_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events (processed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_processed_events_processed_at;
+DROP INDEX IF EXISTS idx_events_sent;
+DROP TABLE IF EXISTS events_archive;
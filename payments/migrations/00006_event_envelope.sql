@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS correlation_id TEXT DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS causation_id   TEXT DEFAULT NULL;
+
+ALTER TABLE events_archive
+    ADD COLUMN IF NOT EXISTS correlation_id TEXT DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS causation_id   TEXT DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE events_archive
+    DROP COLUMN IF EXISTS causation_id,
+    DROP COLUMN IF EXISTS correlation_id;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS causation_id,
+    DROP COLUMN IF EXISTS correlation_id;
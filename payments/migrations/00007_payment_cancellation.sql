@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments
+    ADD CONSTRAINT payments_status_check
+        CHECK (status IN ('pending', 'retrying', 'succeeded', 'failed', 'voided', 'refunded', 'cancelling'));
+
+-- +goose Down
+UPDATE payments SET status = 'failed' WHERE status IN ('voided', 'refunded', 'cancelling');
+
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments
+    ADD CONSTRAINT payments_status_check
+        CHECK (status IN ('pending', 'retrying', 'succeeded', 'failed'));
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS refunded_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payments
+    ADD CONSTRAINT payments_refunded_amount_check
+        CHECK (refunded_amount >= 0 AND refunded_amount <= total_amount);
+
+CREATE TABLE IF NOT EXISTS refunds
+(
+    id         BIGSERIAL PRIMARY KEY,
+    request_id TEXT        NOT NULL UNIQUE,
+    order_id   BIGINT      NOT NULL,
+    amount     BIGINT      NOT NULL CHECK (amount >= 0),
+    status     TEXT        NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    reason     TEXT        NOT NULL DEFAULT '',
+    error      TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_order_id ON refunds (order_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS refunds;
+
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS payments_refunded_amount_check;
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS refunded_amount;
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+-- +goose Down
+ALTER TABLE payments DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,9 @@
+-- +goose Up
+DROP INDEX IF EXISTS idx_payments_due_retry;
+CREATE INDEX IF NOT EXISTS idx_payments_due_retry
+    ON payments (next_attempt_at) WHERE status IN ('pending', 'retrying', 'cancelling');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_payments_due_retry;
+CREATE INDEX IF NOT EXISTS idx_payments_due_retry
+    ON payments (next_attempt_at) WHERE status = 'retrying';
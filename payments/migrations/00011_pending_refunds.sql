@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE refunds
+    DROP CONSTRAINT IF EXISTS refunds_status_check;
+ALTER TABLE refunds
+    ADD CONSTRAINT refunds_status_check
+        CHECK (status IN ('pending', 'succeeded', 'failed'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_pending_order
+    ON refunds (order_id) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refunds_pending_order;
+
+UPDATE refunds SET status = 'failed', error = 'abandoned while pending' WHERE status = 'pending';
+
+ALTER TABLE refunds
+    DROP CONSTRAINT IF EXISTS refunds_status_check;
+ALTER TABLE refunds
+    ADD CONSTRAINT refunds_status_check
+        CHECK (status IN ('succeeded', 'failed'));
@@ -0,0 +1,262 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: opp/catalog/v1/catalog.proto
+
+// Product catalog. Orders resolves authoritative prices through GetProducts
+// when an order is created. make proto generates its Go code into
+// proto/gen/go.
+
+package catalogv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Product struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Price in minor units of currency.
+	Price         int64  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Active        bool   `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_opp_catalog_v1_catalog_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_catalog_v1_catalog_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_opp_catalog_v1_catalog_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Product) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Product) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Product) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Product) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+type GetProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsRequest) Reset() {
+	*x = GetProductsRequest{}
+	mi := &file_opp_catalog_v1_catalog_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsRequest) ProtoMessage() {}
+
+func (x *GetProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_catalog_v1_catalog_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsRequest) Descriptor() ([]byte, []int) {
+	return file_opp_catalog_v1_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetProductsRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsResponse) Reset() {
+	*x = GetProductsResponse{}
+	mi := &file_opp_catalog_v1_catalog_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsResponse) ProtoMessage() {}
+
+func (x *GetProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_catalog_v1_catalog_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsResponse) Descriptor() ([]byte, []int) {
+	return file_opp_catalog_v1_catalog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+var File_opp_catalog_v1_catalog_proto protoreflect.FileDescriptor
+
+const file_opp_catalog_v1_catalog_proto_rawDesc = "" +
+	"\n" +
+	"\x1copp/catalog/v1/catalog.proto\x12\x0eopp.catalog.v1\"w\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x03R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06active\x18\x05 \x01(\bR\x06active\"&\n" +
+	"\x12GetProductsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\"J\n" +
+	"\x13GetProductsResponse\x123\n" +
+	"\bproducts\x18\x01 \x03(\v2\x17.opp.catalog.v1.ProductR\bproducts2h\n" +
+	"\x0eCatalogService\x12V\n" +
+	"\vGetProducts\x12\".opp.catalog.v1.GetProductsRequest\x1a#.opp.catalog.v1.GetProductsResponseBPZNgithub.com/ChernykhITMO/order-processing-proto/gen/go/opp/catalog/v1;catalogv1b\x06proto3"
+
+var (
+	file_opp_catalog_v1_catalog_proto_rawDescOnce sync.Once
+	file_opp_catalog_v1_catalog_proto_rawDescData []byte
+)
+
+func file_opp_catalog_v1_catalog_proto_rawDescGZIP() []byte {
+	file_opp_catalog_v1_catalog_proto_rawDescOnce.Do(func() {
+		file_opp_catalog_v1_catalog_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_opp_catalog_v1_catalog_proto_rawDesc), len(file_opp_catalog_v1_catalog_proto_rawDesc)))
+	})
+	return file_opp_catalog_v1_catalog_proto_rawDescData
+}
+
+var file_opp_catalog_v1_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_opp_catalog_v1_catalog_proto_goTypes = []any{
+	(*Product)(nil),             // 0: opp.catalog.v1.Product
+	(*GetProductsRequest)(nil),  // 1: opp.catalog.v1.GetProductsRequest
+	(*GetProductsResponse)(nil), // 2: opp.catalog.v1.GetProductsResponse
+}
+var file_opp_catalog_v1_catalog_proto_depIdxs = []int32{
+	0, // 0: opp.catalog.v1.GetProductsResponse.products:type_name -> opp.catalog.v1.Product
+	1, // 1: opp.catalog.v1.CatalogService.GetProducts:input_type -> opp.catalog.v1.GetProductsRequest
+	2, // 2: opp.catalog.v1.CatalogService.GetProducts:output_type -> opp.catalog.v1.GetProductsResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_opp_catalog_v1_catalog_proto_init() }
+func file_opp_catalog_v1_catalog_proto_init() {
+	if File_opp_catalog_v1_catalog_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_opp_catalog_v1_catalog_proto_rawDesc), len(file_opp_catalog_v1_catalog_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_opp_catalog_v1_catalog_proto_goTypes,
+		DependencyIndexes: file_opp_catalog_v1_catalog_proto_depIdxs,
+		MessageInfos:      file_opp_catalog_v1_catalog_proto_msgTypes,
+	}.Build()
+	File_opp_catalog_v1_catalog_proto = out.File
+	file_opp_catalog_v1_catalog_proto_goTypes = nil
+	file_opp_catalog_v1_catalog_proto_depIdxs = nil
+}
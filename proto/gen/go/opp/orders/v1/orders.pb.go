@@ -0,0 +1,1172 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: opp/orders/v1/orders.proto
+
+// Orders API served by the orders service and called by the gateway.
+
+package ordersv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Value names match the order status strings of the orders service and the
+// gateway REST API.
+type OrderStatus int32
+
+const (
+	OrderStatus_unspecified      OrderStatus = 0
+	OrderStatus_new              OrderStatus = 1
+	OrderStatus_paid             OrderStatus = 2
+	OrderStatus_payment_failed   OrderStatus = 3
+	OrderStatus_awaiting_payment OrderStatus = 4
+	OrderStatus_fulfilled        OrderStatus = 5
+	OrderStatus_cancelled        OrderStatus = 6
+	OrderStatus_refunded         OrderStatus = 7
+	OrderStatus_awaiting_stock   OrderStatus = 8
+	OrderStatus_out_of_stock     OrderStatus = 9
+	OrderStatus_expired          OrderStatus = 10
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0:  "unspecified",
+		1:  "new",
+		2:  "paid",
+		3:  "payment_failed",
+		4:  "awaiting_payment",
+		5:  "fulfilled",
+		6:  "cancelled",
+		7:  "refunded",
+		8:  "awaiting_stock",
+		9:  "out_of_stock",
+		10: "expired",
+	}
+	OrderStatus_value = map[string]int32{
+		"unspecified":      0,
+		"new":              1,
+		"paid":             2,
+		"payment_failed":   3,
+		"awaiting_payment": 4,
+		"fulfilled":        5,
+		"cancelled":        6,
+		"refunded":         7,
+		"awaiting_stock":   8,
+		"out_of_stock":     9,
+		"expired":          10,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_opp_orders_v1_orders_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_opp_orders_v1_orders_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{0}
+}
+
+type Money struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Amount in minor units of currency.
+	Money int64 `protobuf:"varint,1,opt,name=money,proto3" json:"money,omitempty"`
+	// ISO 4217 code; empty means RUB.
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetMoney() int64 {
+	if x != nil {
+		return x.Money
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type OrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         *Money                 `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+// OrderAdjustment is a discount applied to the order; product_id is 0 for an
+// order-level discount.
+type OrderAdjustment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderAdjustment) Reset() {
+	*x = OrderAdjustment{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderAdjustment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderAdjustment) ProtoMessage() {}
+
+func (x *OrderAdjustment) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderAdjustment.ProtoReflect.Descriptor instead.
+func (*OrderAdjustment) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderAdjustment) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *OrderAdjustment) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderAdjustment) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=opp.orders.v1.OrderStatus" json:"status,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	TotalAmount   *Money                 `protobuf:"bytes,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Adjustments   []*OrderAdjustment     `protobuf:"bytes,8,rep,name=adjustments,proto3" json:"adjustments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Order) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *Order) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Order) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_unspecified
+}
+
+func (x *Order) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetTotalAmount() *Money {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return nil
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetAdjustments() []*OrderAdjustment {
+	if x != nil {
+		return x.Adjustments
+	}
+	return nil
+}
+
+type CreateOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	PromoCode     string                 `protobuf:"bytes,3,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderRequest) Reset() {
+	*x = CreateOrderRequest{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderRequest) ProtoMessage() {}
+
+func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateOrderRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateOrderRequest) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+type CreateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateOrderResponse) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetOrderRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type GetOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderResponse) Reset() {
+	*x = GetOrderResponse{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderResponse) ProtoMessage() {}
+
+func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderResponse) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type ListOrdersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Empty means every status.
+	Statuses    []OrderStatus          `protobuf:"varint,2,rep,packed,name=statuses,proto3,enum=opp.orders.v1.OrderStatus" json:"statuses,omitempty"`
+	CreatedFrom *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	PageSize    int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_cursor of the previous page; empty for the first page.
+	Cursor        string `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListOrdersRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetStatuses() []OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListOrdersResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Orders []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	// Empty on the last page.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelOrderRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *CancelOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CancelOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type OrderSaga struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Step          string                 `protobuf:"bytes,2,opt,name=step,proto3" json:"step,omitempty"`
+	Attempts      int32                  `protobuf:"varint,3,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	DeadlineAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=deadline_at,json=deadlineAt,proto3" json:"deadline_at,omitempty"`
+	LastError     string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderSaga) Reset() {
+	*x = OrderSaga{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderSaga) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderSaga) ProtoMessage() {}
+
+func (x *OrderSaga) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderSaga.ProtoReflect.Descriptor instead.
+func (*OrderSaga) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OrderSaga) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderSaga) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *OrderSaga) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *OrderSaga) GetDeadlineAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeadlineAt
+	}
+	return nil
+}
+
+func (x *OrderSaga) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *OrderSaga) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OrderSaga) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type GetOrderSagaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderSagaRequest) Reset() {
+	*x = GetOrderSagaRequest{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderSagaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderSagaRequest) ProtoMessage() {}
+
+func (x *GetOrderSagaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderSagaRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderSagaRequest) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetOrderSagaRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type GetOrderSagaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Saga          *OrderSaga             `protobuf:"bytes,1,opt,name=saga,proto3" json:"saga,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderSagaResponse) Reset() {
+	*x = GetOrderSagaResponse{}
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderSagaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderSagaResponse) ProtoMessage() {}
+
+func (x *GetOrderSagaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_opp_orders_v1_orders_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderSagaResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderSagaResponse) Descriptor() ([]byte, []int) {
+	return file_opp_orders_v1_orders_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetOrderSagaResponse) GetSaga() *OrderSaga {
+	if x != nil {
+		return x.Saga
+	}
+	return nil
+}
+
+var File_opp_orders_v1_orders_proto protoreflect.FileDescriptor
+
+const file_opp_orders_v1_orders_proto_rawDesc = "" +
+	"\n" +
+	"\x1aopp/orders/v1/orders.proto\x12\ropp.orders.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"9\n" +
+	"\x05Money\x12\x14\n" +
+	"\x05money\x18\x01 \x01(\x03R\x05money\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"r\n" +
+	"\tOrderItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12*\n" +
+	"\x05price\x18\x03 \x01(\v2\x14.opp.orders.v1.MoneyR\x05price\"r\n" +
+	"\x0fOrderAdjustment\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12,\n" +
+	"\x06amount\x18\x03 \x01(\v2\x14.opp.orders.v1.MoneyR\x06amount\"\x90\x03\n" +
+	"\x05Order\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x122\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1a.opp.orders.v1.OrderStatusR\x06status\x12.\n" +
+	"\x05items\x18\x04 \x03(\v2\x18.opp.orders.v1.OrderItemR\x05items\x127\n" +
+	"\ftotal_amount\x18\x05 \x01(\v2\x14.opp.orders.v1.MoneyR\vtotalAmount\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12@\n" +
+	"\vadjustments\x18\b \x03(\v2\x1e.opp.orders.v1.OrderAdjustmentR\vadjustments\"|\n" +
+	"\x12CreateOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12.\n" +
+	"\x05items\x18\x02 \x03(\v2\x18.opp.orders.v1.OrderItemR\x05items\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\x03 \x01(\tR\tpromoCode\"0\n" +
+	"\x13CreateOrderResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\",\n" +
+	"\x0fGetOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\">\n" +
+	"\x10GetOrderResponse\x12*\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.opp.orders.v1.OrderR\x05order\"\x93\x02\n" +
+	"\x11ListOrdersRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x126\n" +
+	"\bstatuses\x18\x02 \x03(\x0e2\x1a.opp.orders.v1.OrderStatusR\bstatuses\x12=\n" +
+	"\fcreated_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\"c\n" +
+	"\x12ListOrdersResponse\x12,\n" +
+	"\x06orders\x18\x01 \x03(\v2\x14.opp.orders.v1.OrderR\x06orders\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"G\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"A\n" +
+	"\x13CancelOrderResponse\x12*\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.opp.orders.v1.OrderR\x05order\"\xa8\x02\n" +
+	"\tOrderSaga\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\tR\x04step\x12\x1a\n" +
+	"\battempts\x18\x03 \x01(\x05R\battempts\x12;\n" +
+	"\vdeadline_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"deadlineAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"0\n" +
+	"\x13GetOrderSagaRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\"D\n" +
+	"\x14GetOrderSagaResponse\x12,\n" +
+	"\x04saga\x18\x01 \x01(\v2\x18.opp.orders.v1.OrderSagaR\x04saga*\xba\x01\n" +
+	"\vOrderStatus\x12\x0f\n" +
+	"\vunspecified\x10\x00\x12\a\n" +
+	"\x03new\x10\x01\x12\b\n" +
+	"\x04paid\x10\x02\x12\x12\n" +
+	"\x0epayment_failed\x10\x03\x12\x14\n" +
+	"\x10awaiting_payment\x10\x04\x12\r\n" +
+	"\tfulfilled\x10\x05\x12\r\n" +
+	"\tcancelled\x10\x06\x12\f\n" +
+	"\brefunded\x10\a\x12\x12\n" +
+	"\x0eawaiting_stock\x10\b\x12\x10\n" +
+	"\fout_of_stock\x10\t\x12\v\n" +
+	"\aexpired\x10\n" +
+	"2\xb4\x03\n" +
+	"\rOrdersService\x12T\n" +
+	"\vCreateOrder\x12!.opp.orders.v1.CreateOrderRequest\x1a\".opp.orders.v1.CreateOrderResponse\x12K\n" +
+	"\bGetOrder\x12\x1e.opp.orders.v1.GetOrderRequest\x1a\x1f.opp.orders.v1.GetOrderResponse\x12Q\n" +
+	"\n" +
+	"ListOrders\x12 .opp.orders.v1.ListOrdersRequest\x1a!.opp.orders.v1.ListOrdersResponse\x12T\n" +
+	"\vCancelOrder\x12!.opp.orders.v1.CancelOrderRequest\x1a\".opp.orders.v1.CancelOrderResponse\x12W\n" +
+	"\fGetOrderSaga\x12\".opp.orders.v1.GetOrderSagaRequest\x1a#.opp.orders.v1.GetOrderSagaResponseBNZLgithub.com/ChernykhITMO/order-processing-proto/gen/go/opp/orders/v1;ordersv1b\x06proto3"
+
+var (
+	file_opp_orders_v1_orders_proto_rawDescOnce sync.Once
+	file_opp_orders_v1_orders_proto_rawDescData []byte
+)
+
+func file_opp_orders_v1_orders_proto_rawDescGZIP() []byte {
+	file_opp_orders_v1_orders_proto_rawDescOnce.Do(func() {
+		file_opp_orders_v1_orders_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_opp_orders_v1_orders_proto_rawDesc), len(file_opp_orders_v1_orders_proto_rawDesc)))
+	})
+	return file_opp_orders_v1_orders_proto_rawDescData
+}
+
+var file_opp_orders_v1_orders_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_opp_orders_v1_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_opp_orders_v1_orders_proto_goTypes = []any{
+	(OrderStatus)(0),              // 0: opp.orders.v1.OrderStatus
+	(*Money)(nil),                 // 1: opp.orders.v1.Money
+	(*OrderItem)(nil),             // 2: opp.orders.v1.OrderItem
+	(*OrderAdjustment)(nil),       // 3: opp.orders.v1.OrderAdjustment
+	(*Order)(nil),                 // 4: opp.orders.v1.Order
+	(*CreateOrderRequest)(nil),    // 5: opp.orders.v1.CreateOrderRequest
+	(*CreateOrderResponse)(nil),   // 6: opp.orders.v1.CreateOrderResponse
+	(*GetOrderRequest)(nil),       // 7: opp.orders.v1.GetOrderRequest
+	(*GetOrderResponse)(nil),      // 8: opp.orders.v1.GetOrderResponse
+	(*ListOrdersRequest)(nil),     // 9: opp.orders.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),    // 10: opp.orders.v1.ListOrdersResponse
+	(*CancelOrderRequest)(nil),    // 11: opp.orders.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),   // 12: opp.orders.v1.CancelOrderResponse
+	(*OrderSaga)(nil),             // 13: opp.orders.v1.OrderSaga
+	(*GetOrderSagaRequest)(nil),   // 14: opp.orders.v1.GetOrderSagaRequest
+	(*GetOrderSagaResponse)(nil),  // 15: opp.orders.v1.GetOrderSagaResponse
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+}
+var file_opp_orders_v1_orders_proto_depIdxs = []int32{
+	1,  // 0: opp.orders.v1.OrderItem.price:type_name -> opp.orders.v1.Money
+	1,  // 1: opp.orders.v1.OrderAdjustment.amount:type_name -> opp.orders.v1.Money
+	0,  // 2: opp.orders.v1.Order.status:type_name -> opp.orders.v1.OrderStatus
+	2,  // 3: opp.orders.v1.Order.items:type_name -> opp.orders.v1.OrderItem
+	1,  // 4: opp.orders.v1.Order.total_amount:type_name -> opp.orders.v1.Money
+	16, // 5: opp.orders.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	16, // 6: opp.orders.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 7: opp.orders.v1.Order.adjustments:type_name -> opp.orders.v1.OrderAdjustment
+	2,  // 8: opp.orders.v1.CreateOrderRequest.items:type_name -> opp.orders.v1.OrderItem
+	4,  // 9: opp.orders.v1.GetOrderResponse.order:type_name -> opp.orders.v1.Order
+	0,  // 10: opp.orders.v1.ListOrdersRequest.statuses:type_name -> opp.orders.v1.OrderStatus
+	16, // 11: opp.orders.v1.ListOrdersRequest.created_from:type_name -> google.protobuf.Timestamp
+	16, // 12: opp.orders.v1.ListOrdersRequest.created_to:type_name -> google.protobuf.Timestamp
+	4,  // 13: opp.orders.v1.ListOrdersResponse.orders:type_name -> opp.orders.v1.Order
+	4,  // 14: opp.orders.v1.CancelOrderResponse.order:type_name -> opp.orders.v1.Order
+	16, // 15: opp.orders.v1.OrderSaga.deadline_at:type_name -> google.protobuf.Timestamp
+	16, // 16: opp.orders.v1.OrderSaga.created_at:type_name -> google.protobuf.Timestamp
+	16, // 17: opp.orders.v1.OrderSaga.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 18: opp.orders.v1.GetOrderSagaResponse.saga:type_name -> opp.orders.v1.OrderSaga
+	5,  // 19: opp.orders.v1.OrdersService.CreateOrder:input_type -> opp.orders.v1.CreateOrderRequest
+	7,  // 20: opp.orders.v1.OrdersService.GetOrder:input_type -> opp.orders.v1.GetOrderRequest
+	9,  // 21: opp.orders.v1.OrdersService.ListOrders:input_type -> opp.orders.v1.ListOrdersRequest
+	11, // 22: opp.orders.v1.OrdersService.CancelOrder:input_type -> opp.orders.v1.CancelOrderRequest
+	14, // 23: opp.orders.v1.OrdersService.GetOrderSaga:input_type -> opp.orders.v1.GetOrderSagaRequest
+	6,  // 24: opp.orders.v1.OrdersService.CreateOrder:output_type -> opp.orders.v1.CreateOrderResponse
+	8,  // 25: opp.orders.v1.OrdersService.GetOrder:output_type -> opp.orders.v1.GetOrderResponse
+	10, // 26: opp.orders.v1.OrdersService.ListOrders:output_type -> opp.orders.v1.ListOrdersResponse
+	12, // 27: opp.orders.v1.OrdersService.CancelOrder:output_type -> opp.orders.v1.CancelOrderResponse
+	15, // 28: opp.orders.v1.OrdersService.GetOrderSaga:output_type -> opp.orders.v1.GetOrderSagaResponse
+	24, // [24:29] is the sub-list for method output_type
+	19, // [19:24] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_opp_orders_v1_orders_proto_init() }
+func file_opp_orders_v1_orders_proto_init() {
+	if File_opp_orders_v1_orders_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_opp_orders_v1_orders_proto_rawDesc), len(file_opp_orders_v1_orders_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_opp_orders_v1_orders_proto_goTypes,
+		DependencyIndexes: file_opp_orders_v1_orders_proto_depIdxs,
+		EnumInfos:         file_opp_orders_v1_orders_proto_enumTypes,
+		MessageInfos:      file_opp_orders_v1_orders_proto_msgTypes,
+	}.Build()
+	File_opp_orders_v1_orders_proto = out.File
+	file_opp_orders_v1_orders_proto_goTypes = nil
+	file_opp_orders_v1_orders_proto_depIdxs = nil
+}
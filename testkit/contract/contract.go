@@ -0,0 +1,200 @@
+// Package contract is a consumer-driven contract harness for kafka payloads.
+// Consumers record the example payloads they rely on under
+// contracts/<consumer>/<event type>.json, and producers check that what they
+// publish still satisfies every recorded example. It is meant for tests only.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RecordEnv rewrites the contract files from the consumer tests when set.
+const RecordEnv = "RECORD_CONTRACTS"
+
+type Contract struct {
+	Consumer  string    `json:"consumer"`
+	Provider  string    `json:"provider"`
+	EventType string    `json:"event_type"`
+	Examples  []Example `json:"examples"`
+}
+
+type Example struct {
+	Description string          `json:"description"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// Path is where the contract of consumer for eventType is stored.
+func Path(dir, consumer, eventType string) string {
+	slug := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(eventType)), " ", "-")
+	return filepath.Join(dir, consumer, slug+".json")
+}
+
+// Record is called by the consumer side after its examples passed. With
+// RECORD_CONTRACTS set it writes the contract file, otherwise it fails when
+// the checked-in file differs from the examples the consumer is tested with.
+func Record(t testing.TB, dir string, c Contract) {
+	t.Helper()
+
+	want, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal contract: %v", err)
+	}
+	want = append(want, '\n')
+	path := Path(dir, c.Consumer, c.EventType)
+
+	if os.Getenv(RecordEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("record contract: %v", err)
+		}
+		if err := os.WriteFile(path, want, 0o644); err != nil {
+			t.Fatalf("record contract: %v", err)
+		}
+		return
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read contract: %v (run with %s=1 to record it)", err, RecordEnv)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("contract %s is out of date, run with %s=1 to record it", path, RecordEnv)
+	}
+}
+
+// Verify is called by the producer side with a payload it publishes for
+// eventType. Every example recorded against provider must be satisfied.
+func Verify(t testing.TB, dir, provider, eventType string, payload []byte) {
+	t.Helper()
+
+	files, err := filepath.Glob(Path(dir, "*", eventType))
+	if err != nil {
+		t.Fatalf("find contracts: %v", err)
+	}
+
+	var checked int
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read contract: %v", err)
+		}
+		var c Contract
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.Fatalf("decode contract %s: %v", file, err)
+		}
+		if c.Provider != provider || c.EventType != eventType {
+			continue
+		}
+
+		for _, example := range c.Examples {
+			checked++
+			if err := Satisfies(example.Payload, payload); err != nil {
+				t.Errorf("%s expects %q (%s): %v", c.Consumer, eventType, example.Description, err)
+			}
+		}
+	}
+
+	if checked == 0 {
+		t.Fatalf("no contracts recorded against %s for %q in %s", provider, eventType, dir)
+	}
+}
+
+// Satisfies matches payload against example by shape: every field of the
+// example must be present with the same JSON type, integers must stay
+// integers and timestamps must stay RFC 3339. Extra fields are allowed.
+func Satisfies(example, payload []byte) error {
+	var want, got any
+	if err := decode(example, &want); err != nil {
+		return fmt.Errorf("example: %w", err)
+	}
+	if err := decode(payload, &got); err != nil {
+		return fmt.Errorf("payload: %w", err)
+	}
+	return match("$", want, got)
+}
+
+func decode(data []byte, v *any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func match(path string, want, got any) error {
+	switch want := want.(type) {
+	case map[string]any:
+		obj, ok := got.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: want object, got %s", path, kind(got))
+		}
+		for key, value := range want {
+			field, ok := obj[key]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, key)
+			}
+			if err := match(path+"."+key, value, field); err != nil {
+				return err
+			}
+		}
+	case []any:
+		arr, ok := got.([]any)
+		if !ok {
+			return fmt.Errorf("%s: want array, got %s", path, kind(got))
+		}
+		if len(want) > 0 {
+			for i, item := range arr {
+				if err := match(fmt.Sprintf("%s[%d]", path, i), want[0], item); err != nil {
+					return err
+				}
+			}
+		}
+	case json.Number:
+		num, ok := got.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: want number, got %s", path, kind(got))
+		}
+		if _, err := want.Int64(); err == nil {
+			if _, err := num.Int64(); err != nil {
+				return fmt.Errorf("%s: want integer, got %s", path, num)
+			}
+		}
+	case string:
+		str, ok := got.(string)
+		if !ok {
+			return fmt.Errorf("%s: want string, got %s", path, kind(got))
+		}
+		if _, err := time.Parse(time.RFC3339Nano, want); err == nil {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return fmt.Errorf("%s: want RFC 3339 timestamp, got %q", path, str)
+			}
+		}
+	case bool:
+		if _, ok := got.(bool); !ok {
+			return fmt.Errorf("%s: want boolean, got %s", path, kind(got))
+		}
+	case nil:
+	}
+	return nil
+}
+
+func kind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,40 @@
+package contract
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSatisfies(t *testing.T) {
+	const example = `{"order_id":42,"status":"paid","created_at":"2026-01-02T03:04:05Z","items":[{"price":100}],"gift":false}`
+
+	tests := []struct {
+		name    string
+		payload string
+		wantErr string
+	}{
+		{"same values", example, ""},
+		{"other values and extra fields", `{"order_id":7,"status":"new","created_at":"2026-05-06T07:08:09.123456Z","items":[],"gift":true,"note":"x"}`, ""},
+		{"missing field", `{"status":"paid","created_at":"2026-01-02T03:04:05Z","items":[],"gift":false}`, "$.order_id: missing"},
+		{"number became string", `{"order_id":"42","status":"paid","created_at":"2026-01-02T03:04:05Z","items":[],"gift":false}`, "$.order_id: want number"},
+		{"integer became fraction", `{"order_id":4.2,"status":"paid","created_at":"2026-01-02T03:04:05Z","items":[],"gift":false}`, "$.order_id: want integer"},
+		{"timestamp format changed", `{"order_id":42,"status":"paid","created_at":"02.01.2026","items":[],"gift":false}`, "$.created_at: want RFC 3339"},
+		{"array item shape changed", `{"order_id":42,"status":"paid","created_at":"2026-01-02T03:04:05Z","items":[{"cost":1}],"gift":false}`, "$.items[0].price: missing"},
+		{"not an object", `[]`, "$: want object, got array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Satisfies([]byte(example), []byte(tt.payload))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
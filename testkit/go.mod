@@ -0,0 +1,3 @@
+module github.com/ChernykhITMO/order-processing-platform/testkit
+
+go 1.25.1
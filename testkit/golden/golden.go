@@ -0,0 +1,66 @@
+// Package golden checks the hand-rolled protobuf encoding of each service's
+// event messages against the protoc encodings in
+// proto/opp/events/v1/testdata; make proto-golden regenerates them from the
+// .txtpb files.
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Message is an event message encoded by hand.
+type Message interface {
+	MarshalProto() []byte
+	UnmarshalProto(b []byte) error
+}
+
+// Case checks one message against <Message>.binpb.
+type Case struct {
+	Message string
+	// Got is decoded from the golden encoding and compared with Want.
+	Got, Want Message
+	// Partial marks messages a service keeps only some fields of, so its
+	// encoding is not the golden one.
+	Partial bool
+}
+
+// Check runs every case against the golden encodings in dir.
+func Check(t *testing.T, dir string, cases []Case) {
+	t.Helper()
+
+	for _, c := range cases {
+		t.Run(c.Message, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join(dir, c.Message+".binpb"))
+			if err != nil {
+				t.Fatalf("read golden: %v", err)
+			}
+			if err := Compare(golden, c); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// Compare decodes golden into c.Got and checks it equals c.Want, then, unless
+// the case is partial, that c.Want encodes back to golden.
+func Compare(golden []byte, c Case) error {
+	if err := c.Got.UnmarshalProto(golden); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	if !reflect.DeepEqual(c.Got, c.Want) {
+		return fmt.Errorf("unmarshal: got %+v, want %+v", c.Got, c.Want)
+	}
+
+	if c.Partial {
+		return nil
+	}
+	if encoded := c.Want.MarshalProto(); !bytes.Equal(encoded, golden) {
+		return fmt.Errorf("marshal: got %x, want %x", encoded, golden)
+	}
+	return nil
+}
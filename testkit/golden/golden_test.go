@@ -0,0 +1,54 @@
+package golden
+
+import (
+	"strings"
+	"testing"
+)
+
+// raw keeps the first byte of its encoding and drops the rest.
+type raw struct {
+	First byte
+}
+
+func (r *raw) MarshalProto() []byte {
+	if r.First == 0 {
+		return nil
+	}
+	return []byte{r.First}
+}
+
+func (r *raw) UnmarshalProto(b []byte) error {
+	if len(b) > 0 {
+		r.First = b[0]
+	}
+	return nil
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		golden  []byte
+		c       Case
+		wantErr string
+	}{
+		{"same encoding", []byte{0x08}, Case{Got: &raw{}, Want: &raw{First: 0x08}}, ""},
+		{"other value", []byte{0x08}, Case{Got: &raw{}, Want: &raw{First: 0x10}}, "unmarshal: got"},
+		{"fields dropped", []byte{0x08, 0x01}, Case{Got: &raw{}, Want: &raw{First: 0x08}}, "marshal: got 08, want 0801"},
+		{"fields dropped, partial", []byte{0x08, 0x01}, Case{Got: &raw{}, Want: &raw{First: 0x08}, Partial: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Compare(tt.golden, tt.c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}